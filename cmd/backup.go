@@ -24,9 +24,13 @@ import (
 
 var (
 	// Flags
-	cfgDirs         []string
-	cfgExcludePaths []string
-	cfgResumeBackup bool
+	cfgDirs            []string
+	cfgExcludePaths    []string
+	cfgResumeBackup    bool
+	cfgVerifyContent   bool
+	cfgStdin           bool
+	cfgStdinName       string
+	cfgResetLocalState bool
 
 	// Command
 	backupCmd = &cobra.Command{
@@ -44,10 +48,34 @@ This will read your config.toml configuration file for all information about the
 cloud provider credentials, and master password. It will then perform an incremental backup to
 your cloud provider, uploading only those files that have changed since the last backup. Files
 on the cloud provider will be overwritten by these newer local copies.
+
+Commands to run before and after each directory's backup, such as dumping a database, can be
+set in the [hooks] section of the config file. If a directory's pre_backup hook fails, that
+directory is skipped.
+
+A file counts as changed if its size, inode, change time or modification time differs from the
+last backup. To also catch changes that leave all of those alone, use --verify-content, which
+reads every file to compare a hash of its contents. This is much slower, so it is best done now
+and then rather than on every backup.
+
+To back up the output of a command without writing it to a temp file first, pipe it in with
+--stdin. It is stored as the file named by --name in a new snapshot of its own backup, which
+must not be the name of one of your backup dirs:
+
+	pg_dump mydb | tless backup --stdin --name db/dump.sql mybackup
+
+It can then be restored like any other file:
+
+	tless restore mybackup/2020-01-15_04.56.00 /home/myname/Recovered
 `,
-		Args: cobra.NoArgs,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cfgStdin {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.NoArgs(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
-			backupMain()
+			backupMain(args)
 		},
 	}
 )
@@ -56,16 +84,24 @@ func init() {
 	backupCmd.Flags().StringArrayVarP(&cfgDirs, "dir", "d", nil, "directories to backup (can use multiple times)")
 	backupCmd.Flags().StringArrayVarP(&cfgExcludePaths, "exclude", "x", nil, "paths prefixes to exclude from backup (can use multiple times)")
 	backupCmd.Flags().BoolVar(&cfgResumeBackup, "resume-backup", true, "resume (vs rollback) any previous interrupted run")
+	backupCmd.Flags().BoolVar(&cfgVerifyContent, "verify-content", false, "hash the contents of every file to detect changes that leave its size and times alone")
+	backupCmd.Flags().BoolVar(&cfgStdin, "stdin", false, "back up standard input as a single file in the backup named by the argument")
+	backupCmd.Flags().StringVar(&cfgStdinName, "name", "", "relative path to store standard input as (with --stdin)")
+	backupCmd.Flags().BoolVar(&cfgResetLocalState, "reset-local-state", false, "discard local state encrypted for another bucket (the next backup looks at every file again)")
 	rootCmd.AddCommand(backupCmd)
 }
 
-func backupMain() {
+func backupMain(args []string) {
 	ctx := context.Background()
 
 	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
 
 	// check that cfgDirs is set, and allow cfgExcludePaths to be set from toml file if no arg
-	if err := validateDirs(); err != nil {
+	if cfgStdin {
+		if err := backup.ValidateStreamRelPath(cfgStdinName); err != nil {
+			log.Fatalf("error: invalid --name: %v", err)
+		}
+	} else if err := validateDirs(); err != nil {
 		log.Fatalln("no valid dirs to back up: ", err)
 	}
 	if cfgExcludePaths == nil {
@@ -85,6 +121,16 @@ func backupMain() {
 	if err := db.PerformDbMigrations(vlog); err != nil {
 		log.Fatalf("error: cannot initialize database: %v", err)
 	}
+	if cfgResetLocalState {
+		if err := db.ResetState(); err != nil {
+			log.Fatalf("error: cannot reset local state: %v", err)
+		}
+	}
+	if err := db.UnlockState(hmacKey, vlog); errors.Is(err, database.ErrStateKeyMismatch) {
+		log.Fatalf("error: cannot unlock local state: %v (was the bucket changed? if so, run again with --reset-local-state to discard it)", err)
+	} else if err != nil {
+		log.Fatalf("error: cannot unlock local state: %v", err)
+	}
 
 	// open connection to cloud server
 	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
@@ -97,6 +143,12 @@ func backupMain() {
 		persistUsage(db, true, true, vlog)
 	}
 
+	if cfgStdin {
+		backupStdin(ctx, objst, db, args[0], vlog)
+		onDone()
+		return
+	}
+
 	// initialize progress bar container and its callbacks
 	progressBarContainer := mpb.New()
 	var progressBar *mpb.Bar = nil
@@ -128,6 +180,13 @@ func backupMain() {
 		return
 	}
 
+	// make sure no snapshots have vanished and the bucket has not been rolled back
+	problems, err := snapshots.CheckManifest(ctx, objst, cfgBucket, encKey, hmacKey, nil, db)
+	if err != nil {
+		log.Fatalf("error: could not verify the snapshot manifest: %v", err)
+	}
+	warnManifestProblems(problems)
+
 	// main loop through backup dirs
 	for _, backupDirPath := range cfgDirs {
 		// log what iteration of the loop we're in
@@ -136,14 +195,35 @@ func backupMain() {
 		// init the progress bar to nil
 		progressBar = nil
 
+		// Run the pre_backup hook, skipping this dir if it fails
+		hooks := cfgHooks.HooksForBackup(filepath.Base(backupDirPath))
+		if _, err := backup.RunPreBackupHook(ctx, hooks, backupDirPath, vlog); err != nil {
+			continue
+		}
+
 		// Traverse the FS for changed files and do the journaled backup
 		stats := backup.NewBackupStats()
-		backupReportedEvents, breakFromLoop, continueLoop, fatalError := backup.DoJournaledBackup(ctx, encKey, objst, cfgBucket, nil, db, backupDirPath, cfgExcludePaths, vlog, nil, nil, setBackupInitialProgressFunc, updateBackupProgressFunc, stats, cfgResourceUtilization)
+		backupReportedEvents, breakFromLoop, continueLoop, fatalError := backup.DoJournaledBackup(ctx, encKey, hmacKey, sealKeys, objst, cfgBucket, nil, db, backupDirPath, cfgExcludePaths, cfgVerifyContent, vlog, nil, nil, setBackupInitialProgressFunc, updateBackupProgressFunc, stats, cfgResourceUtilization, cfgPadding)
+		errorCount := int64(0)
 		for _, e := range backupReportedEvents {
 			if e.Kind == util.ERR_OP_NOT_PERMITTED {
 				log.Printf("warning:  insufficient permissions to process path '%s'", e.Path)
+				errorCount += 1
 			}
 		}
+		if fatalError {
+			errorCount += 1
+		}
+
+		// Run the post_backup hook and then on_success or on_failure
+		result := backup.HookResultSuccess
+		if errorCount+stats.Errors() > 0 {
+			result = backup.HookResultFailure
+		} else if breakFromLoop {
+			result = backup.HookResultCanceled
+		}
+		backup.RunPostBackupHooks(ctx, hooks, backupDirPath, stats, errorCount, result, vlog)
+
 		if fatalError {
 			goto done
 		}
@@ -163,9 +243,62 @@ func backupMain() {
 	}
 
 done:
+	// remember the manifest version our snapshots brought the bucket to
+	if _, err := snapshots.CheckManifest(ctx, objst, cfgBucket, encKey, hmacKey, nil, db); err != nil {
+		log.Printf("error: could not verify the snapshot manifest: %v", err)
+	}
 	onDone()
 }
 
+// Backs up stdin as the file cfgStdinName in a new snapshot of backupName
+func backupStdin(ctx context.Context, objst *objstore.ObjStore, db *database.DB, backupName string, vlog *util.VLog) {
+	// Keep stream backups apart from the backups of dirs, whose snapshots are built from the
+	// local state of their files
+	if backupName == "" || backupName != filepath.Base(backupName) || backupName == "." || backupName == ".." {
+		log.Fatalf("error: invalid backup name '%s'", backupName)
+	}
+	for _, dir := range cfgDirs {
+		if filepath.Base(util.StripTrailingSlashes(dir)) == backupName {
+			log.Fatalf("error: '%s' is the name of the backup of '%s'; pick another name", backupName, dir)
+		}
+	}
+	knownPaths, err := db.GetAllKnownPaths(backupName)
+	if err != nil {
+		log.Fatalf("error: could not read local state: %v", err)
+	}
+	if len(knownPaths) > 0 {
+		log.Fatalf("error: '%s' is the name of a backup of a dir; pick another name", backupName)
+	}
+
+	// make sure no snapshots have vanished and the bucket has not been rolled back
+	problems, err := snapshots.CheckManifest(ctx, objst, cfgBucket, encKey, hmacKey, nil, db)
+	if err != nil {
+		log.Fatalf("error: could not verify the snapshot manifest: %v", err)
+	}
+	warnManifestProblems(problems)
+
+	stats := backup.NewBackupStats()
+	snapshotName, err := backup.BackupStream(ctx, encKey, hmacKey, sealKeys, cfgPadding, objst, cfgBucket, db, backupName, cfgStdinName, os.Stdin, stats, vlog)
+	if err != nil {
+		log.Fatalf("error: could not back up stdin: %v", err)
+	}
+	fmt.Printf("Backed up stdin to %s/%s: %s\n", backupName, snapshotName, stats.FinalReport())
+
+	// remember the manifest version our snapshot brought the bucket to
+	if _, err := snapshots.CheckManifest(ctx, objst, cfgBucket, encKey, hmacKey, nil, db); err != nil {
+		log.Printf("error: could not verify the snapshot manifest: %v", err)
+	}
+}
+
+func warnManifestProblems(problems []string) {
+	for _, problem := range problems {
+		log.Printf("warning: %s", problem)
+	}
+	if len(problems) > 0 {
+		log.Println("warning: if you know why the snapshot manifest no longer matches the bucket, run 'tless manifest rebuild'")
+	}
+}
+
 func handleReplay(ctx context.Context, objst *objstore.ObjStore, db *database.DB, vlog *util.VLog, setBackupInitialProgressFunc backup.SetReplayInitialProgressFuncType, updateBackupProgressFunc backup.UpdateProgressFuncType) bool {
 	hasDirtyBackupJournal, err := db.HasDirtyBackupJournal()
 	if err != nil {
@@ -176,7 +309,7 @@ func handleReplay(ctx context.Context, objst *objstore.ObjStore, db *database.DB
 	if hasDirtyBackupJournal {
 		if cfgResumeBackup {
 			fmt.Println("Resuming previous interrupted backup... (--resume-backup=false to roll back)")
-			backup.ReplayBackupJournal(ctx, encKey, objst, cfgBucket, nil, db, vlog, setBackupInitialProgressFunc, nil, updateBackupProgressFunc, cfgResourceUtilization)
+			backup.ReplayBackupJournal(ctx, encKey, hmacKey, sealKeys, objst, cfgBucket, nil, db, vlog, setBackupInitialProgressFunc, nil, updateBackupProgressFunc, cfgResourceUtilization, cfgPadding)
 		} else {
 			fmt.Println("Rolling back previous interrupted backup...")
 
@@ -192,13 +325,13 @@ func handleReplay(ctx context.Context, objst *objstore.ObjStore, db *database.DB
 				BackupDirName: filepath.Base(backupDirPath),
 				SnapshotName:  snapshotName,
 			}
-			err = snapshots.DeleteSnapshots(ctx, encKey, []snapshots.SnapshotForDeletion{ssDel}, objst, cfgBucket, vlog, nil, nil)
+			err = snapshots.DeleteSnapshots(ctx, encKey, hmacKey, sealKeys, []snapshots.SnapshotForDeletion{ssDel}, objst, cfgBucket, false, vlog, nil, nil)
 			if err != nil {
 				// This is ok and just means snapshot index file wasn't writetn to cloud yet
 				vlog.Printf("warning: handleReplay: could not delete partially created snapshot index (probably does not exist yet): %v", err)
 
 				// Garbage collect any orphaned chunks that were written while creating unused snapshot index file
-				if err = snapshots.GCChunks(ctx, objst, cfgBucket, encKey, vlog, nil, nil); err != nil {
+				if err = snapshots.GCChunks(ctx, objst, cfgBucket, encKey, sealKeys, vlog, nil, nil); err != nil {
 					log.Println("error: handleReplay: could not garbage collect chunks: ", err)
 				}
 			}
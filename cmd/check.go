@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	checkCfgReadData string
+
+	checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Verifies the integrity of everything in the cloud",
+		Long: `Reads every snapshot index in the cloud and verifies that every chunk it references exists
+and is not truncated. Also reports index files that cannot be decrypted, and lists orphan chunks
+that no snapshot references separately (orphans are harmless and are removed the next time a
+snapshot is deleted).
+
+With --read-data, a random sample of the referenced chunks is also downloaded and decrypted,
+which authenticates their contents and detects chunks that have been swapped or reordered.
+Reading data costs download bandwidth, so you can check a different sample each time instead
+of everything at once.
+
+Example:
+
+	tless check
+	tless check --read-data=10%
+	tless check --read-data=100%
+
+Exits with status 1 if any problems were found. Orphan chunks do not count as problems.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			readDataPercent, err := parseReadDataPercent(checkCfgReadData)
+			if err != nil {
+				log.Fatalf("error: invalid --read-data '%s': %v", checkCfgReadData, err)
+			}
+			if !checkMain(readDataPercent) {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	checkCmd.Flags().StringVar(&checkCfgReadData, "read-data", "0%", "percentage of chunks to download and authenticate (ex: 10%)")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func parseReadDataPercent(s string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("must be between 0%% and 100%%")
+	}
+	return percent, nil
+}
+
+// Returns true if no problems were found
+func checkMain(readDataPercent float64) bool {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	progressFunc := func(percentDone float64) {
+		vlog.Printf("Checking... %.1f%%", percentDone)
+	}
+	result, err := backup.CheckRepository(ctx, encKey, hmacKey, sealKeys, objst, cfgBucket, readDataPercent, vlog, progressFunc)
+	if err != nil {
+		log.Fatalf("error: check failed: %v", err)
+	}
+
+	fmt.Printf("Checked %d snapshots referencing %d chunks (%d chunks in cloud)\n", result.SnapshotsChecked, result.ChunksReferenced, result.ChunksInBucket)
+	if readDataPercent > 0 {
+		fmt.Printf("Read and authenticated %d chunks (%s)\n", result.ChunksRead, util.FormatBytesAsString(result.BytesRead))
+	}
+	for _, problem := range result.Problems {
+		fmt.Println(problem.String())
+	}
+	for _, orphan := range result.OrphanChunks {
+		fmt.Println(orphan.String())
+	}
+	if len(result.OrphanChunks) > 0 {
+		fmt.Printf("Found %d orphan chunks, which are not a problem (they are removed the next time a snapshot is deleted)\n", len(result.OrphanChunks))
+	}
+	if len(result.Problems) == 0 {
+		fmt.Println("No problems found")
+	} else {
+		fmt.Printf("Found %d problems\n", len(result.Problems))
+	}
+
+	// Persist the bandwidth stored hot in objst module
+	persistUsage(nil, false, true, vlog)
+
+	return len(result.Problems) == 0
+}
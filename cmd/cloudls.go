@@ -20,6 +20,7 @@ var (
 	cloudlsCfgShowChunks         bool
 	cloudlsCfgSnapshot           string
 	cloudlsCfgGreppableSnapshots bool
+	cloudlsCfgTag                string
 
 	// Command
 	cloudlsCmd = &cobra.Command{
@@ -34,8 +35,10 @@ Example:
 	tless cloudls
 	tless cloudls --verbose
 	tless cloudls --snapshot=Documents/2020-01-01_04.56.01
+	tless cloudls --tag="before OS upgrade"
 
-The available snapshot times are displayed in 'tless cloudls' with no arguments.
+The available snapshot times are displayed in 'tless cloudls' with no arguments, along with
+which snapshots are pinned and what tags they have.
 `,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -52,6 +55,7 @@ func init() {
 	cloudlsCmd.Flags().BoolVar(&cloudlsCfgShowChunks, "show-chunks", false, "show the chunk(s) making up each file; implies -v (default: false)")
 	cloudlsCmd.Flags().BoolVar(&cloudlsCfgGreppableSnapshots, "grep", false, "show a grep-friendly snapshot list (default: false)")
 	cloudlsCmd.Flags().StringVar(&cloudlsCfgSnapshot, "snapshot", "", "snapshot to display (eg, 'Documents/2020-01-01_01.02.03'); implies -v")
+	cloudlsCmd.Flags().StringVar(&cloudlsCfgTag, "tag", "", "only show snapshots with this tag")
 	rootCmd.AddCommand(cloudlsCmd)
 }
 
@@ -87,12 +91,17 @@ func cloudlsMain() {
 		}
 	}
 
-	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, encKey, cfgBucket, vlog, setGGSInitialProgress, updateGGSProgress)
+	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, encKey, sealKeys, cfgBucket, vlog, setGGSInitialProgress, updateGGSProgress)
 	if err != nil {
 		log.Fatalf("Could not get grouped snapshots: %v", err)
 	}
 	time.Sleep(time.Millisecond * 100) // let the bar finish drawing
 
+	mAnnotations, err := snapshots.GetAllSnapshotAnnotations(ctx, objst, cfgBucket, encKey)
+	if err != nil {
+		log.Fatalf("Could not get snapshot tags: %v", err)
+	}
+
 	// print out each backup name group
 	if len(groupedObjects) == 0 {
 		fmt.Println("No objects found in cloud")
@@ -105,21 +114,29 @@ func cloudlsMain() {
 	sort.Strings(groupNameKeys)
 
 	for _, groupName := range groupNameKeys {
-		if !cloudlsCfgGreppableSnapshots {
-			fmt.Printf("Backup '%s':\n", groupName)
-		}
-
 		snapshotKeys := make([]string, 0, len(groupedObjects[groupName].Snapshots))
 		for snapshotName := range groupedObjects[groupName].Snapshots {
+			if cloudlsCfgTag != "" {
+				if a, ok := mAnnotations[groupName+"/"+snapshotName]; !ok || !a.HasTag(cloudlsCfgTag) {
+					continue
+				}
+			}
 			snapshotKeys = append(snapshotKeys, snapshotName)
 		}
 		sort.Strings(snapshotKeys)
+		if cloudlsCfgTag != "" && len(snapshotKeys) == 0 {
+			continue
+		}
+
+		if !cloudlsCfgGreppableSnapshots {
+			fmt.Printf("Backup '%s':\n", groupName)
+		}
 
 		for _, snapshotName := range snapshotKeys {
 			if cloudlsCfgGreppableSnapshots {
 				fmt.Printf("%s/%s\n", groupName, snapshotName)
 			} else {
-				fmt.Printf("  %s\n", snapshotName)
+				fmt.Printf("  %s%s\n", snapshotName, formatSnapshotAnnotations(mAnnotations[groupName+"/"+snapshotName]))
 
 				if cfgVerbose || cloudlsCfgShowChunks {
 					relPathKeys := make([]string, 0, len(groupedObjects[groupName].Snapshots[snapshotName].RelPaths))
@@ -156,7 +173,7 @@ func cloudlsMainShowSnapshot() {
 		log.Fatalf("Cannot split '%s' into backupDirName/snapshotTimestamp", cloudrmCfgSnapshot)
 	}
 
-	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, encKey, cfgBucket, vlog, nil, nil)
+	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, encKey, sealKeys, cfgBucket, vlog, nil, nil)
 	if err != nil {
 		log.Fatalf("Could not get grouped snapshots: %v", err)
 	}
@@ -16,7 +16,8 @@ import (
 
 var (
 	// Flags
-	cloudrmCfgSnapshot []string
+	cloudrmCfgSnapshot     []string
+	cloudrmCfgDeletePinned bool
 
 	// Command
 	cloudrmCmd = &cobra.Command{
@@ -31,6 +32,8 @@ Example:
 	tless cloudrm --snapshot=Documents/2020-01-01_04.56.01
 
 The available snapshot times are displayed in 'tless cloudls' with no arguments.
+
+Pinned snapshots (see 'tless pin') are not deleted unless you pass --delete-pinned.
 `,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -45,6 +48,7 @@ The available snapshot times are displayed in 'tless cloudls' with no arguments.
 
 func init() {
 	cloudrmCmd.Flags().StringArrayVarP(&cloudrmCfgSnapshot, "snapshot", "S", []string{}, "snapshot to delete (eg, 'Documents/2020-01-01_01.02.03')")
+	cloudrmCmd.Flags().BoolVar(&cloudrmCfgDeletePinned, "delete-pinned", false, "also delete pinned snapshots")
 	rootCmd.AddCommand(cloudrmCmd)
 }
 
@@ -99,7 +103,7 @@ func cloudrmMain() {
 	for _, ssDel := range ssDeletes {
 		fmt.Printf("Deleting %s/%s\n", ssDel.BackupDirName, ssDel.SnapshotName)
 	}
-	err := snapshots.DeleteSnapshots(ctx, encKey, ssDeletes, objst, cfgBucket, vlog, setGGSInitialProgress, updateGGSProgress)
+	err := snapshots.DeleteSnapshots(ctx, encKey, hmacKey, sealKeys, ssDeletes, objst, cfgBucket, cloudrmCfgDeletePinned, vlog, setGGSInitialProgress, updateGGSProgress)
 	if err != nil {
 		log.Fatalf("Failed to delete snapshot: %v", err)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/snapshots"
@@ -22,6 +23,13 @@ var (
 	encKey  []byte
 	hmacKey []byte
 
+	// Non-nil if the bucket is in write-only mode. Its private key is only unlocked for commands
+	// that read chunks or indexes.
+	sealKeys *cryptography.SealKeys
+
+	// True if the bucket is partway through a key rotation
+	keyRotationInProgress bool
+
 	// Flags
 	cfgEndpoint             string
 	cfgAccessKeyId          string
@@ -29,12 +37,16 @@ var (
 	cfgBucket               string
 	cfgTrustSelfSignedCerts bool
 	cfgMasterPassword       string
+	cfgPrivateKeyPassphrase string
 	cfgSalt                 string
 	cfgVerbose              bool
 	cfgForce                bool
 	cfgCachesPath           string
 	cfgMaxChunkCacheMb      int64
 	cfgResourceUtilization  string
+	cfgPadding              cryptography.Padding
+	cfgRetention            util.RetentionConfig
+	cfgHooks                util.HooksConfig
 
 	// Root command
 	rootCmd = &cobra.Command{
@@ -44,12 +56,15 @@ var (
 tless is a tool for cloud backups for people who don't want to place
 any trust in cloud providers. It encrypts files and filenames locally, with 
 a password that never leaves the local machine.`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if keyRotationInProgress && cmd != rotateKeyCmd && cmd != manifestRebuildCmd {
+				log.Fatalln("error: a key rotation is in progress on this bucket; run 'tless rotate-key' to finish it")
+			}
+		},
 	}
 )
 
 func Execute() {
-	_ = hmacKey // currently unused; this line could go anywhere
-
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -58,7 +73,7 @@ func Execute() {
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVarP(&cfgEndpoint, "endpoint", "e", "", "endpoint (ex: your-cloud.com:5000)")
+	rootCmd.PersistentFlags().StringVarP(&cfgEndpoint, "endpoint", "e", "", "endpoint (ex: your-cloud.com:5000, s3://your-cloud.com:5000/bucket or file:///mnt/backup)")
 	rootCmd.PersistentFlags().StringVarP(&cfgAccessKeyId, "access-key", "a", "", "access key for your cloud account")
 	rootCmd.PersistentFlags().StringVarP(&cfgSecretAccessKey, "access-secret", "s", "", "secret key for your cloud account")
 	rootCmd.PersistentFlags().StringVarP(&cfgBucket, "bucket", "b", "", "name of object store bucket to use")
@@ -100,6 +115,9 @@ func initConfig() {
 
 	// Read viper for any cfg variables not already overridden by CLI args
 	configFallbackToTomlFileOrInteractivePrompt()
+	if isRunningKeylessCommand() {
+		return
+	}
 	if err := validateConfigVars(); err != nil {
 		log.Printf("Error validating config: %v", err)
 		if !cfgForce {
@@ -142,24 +160,36 @@ func configFallbackToTomlFileOrInteractivePrompt() {
 	if cfgAccessKeyId == "" {
 		cfgAccessKeyId = viper.GetString("objectstore.access_key_id")
 	}
+	if cfgSecretAccessKey == "" {
+		cfgSecretAccessKey = resolveSecret(util.AccessSecretSources(viper.GetString("objectstore.access_secret_command"), viper.GetString("objectstore.access_secret_file")), "access secret")
+	}
 	if cfgSecretAccessKey == "" {
 		cfgSecretAccessKey = viper.GetString("objectstore.access_secret")
-		if cfgSecretAccessKey == "" {
+		if cfgSecretAccessKey == "" && objstore.IsCredentialedEndpoint(cfgEndpoint) {
 			cfgSecretAccessKey = promptForSecretKeyId()
 		}
 	}
 	if cfgBucket == "" {
 		cfgBucket = viper.GetString("objectstore.bucket")
+		if cfgBucket == "" {
+			cfgBucket = objstore.BucketFromEndpoint(cfgEndpoint)
+		}
 	}
 	if !cfgTrustSelfSignedCerts {
 		cfgTrustSelfSignedCerts = viper.GetBool("objectstore.trust_self_signed_certs")
 	}
+	if cfgMasterPassword == "" && !isRunningKeylessCommand() {
+		cfgMasterPassword = resolveSecret(util.MasterPasswordSources(viper.GetString("backups.master_password_command"), viper.GetString("backups.master_password_file")), "master password")
+	}
 	if cfgMasterPassword == "" {
 		cfgMasterPassword = viper.GetString("backups.master_password")
-		if cfgMasterPassword == "" {
+		if cfgMasterPassword == "" && !isRunningKeylessCommand() {
 			cfgMasterPassword = promptForMasterPassword()
 		}
 	}
+	if cfgPrivateKeyPassphrase == "" {
+		cfgPrivateKeyPassphrase = viper.GetString("backups.private_key_passphrase")
+	}
 	if len(cfgDirs) == 0 {
 		cfgDirs = viper.GetStringSlice("backups.dirs")
 	}
@@ -175,6 +205,54 @@ func configFallbackToTomlFileOrInteractivePrompt() {
 			cfgResourceUtilization = "high"
 		}
 	}
+	padding, err := cryptography.ParsePadding(viper.GetString("backups.padding"))
+	if err != nil {
+		log.Fatalf("error: invalid padding in config: %v", err)
+	}
+	cfgPadding = padding
+	if err := viper.UnmarshalKey("retention", &cfgRetention); err != nil {
+		log.Printf("error: could not read [retention] section of config: %v", err)
+	}
+	if err := viper.UnmarshalKey("hooks", &cfgHooks); err != nil {
+		log.Fatalf("error: could not read [hooks] section of config: %v", err)
+	}
+	if err := cfgHooks.Validate(); err != nil {
+		log.Fatalf("error: invalid hooks in config: %v", err)
+	}
+}
+
+// Returns true if the command being run does not use the master password or keys (such as
+// 'tless escrow recover', which is how a lost master password gets replaced)
+func isRunningKeylessCommand() bool {
+	c, _, err := rootCmd.Find(os.Args[1:])
+	return err == nil && c == escrowRecoverCmd
+}
+
+// Returns true if the command being run does not read chunks or indexes, and so works with just
+// the public key of a bucket in write-only mode
+func isRunningWriteOnlyCommand() bool {
+	c, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+	for _, writeOnly := range []*cobra.Command{backupCmd, tagCmd, pinCmd, unpinCmd, keySlotsCmd, escrowSplitCmd, writeOnlyCmd, kdfBenchmarkCmd, manifestCmd} {
+		if c == writeOnly || c.Parent() == writeOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads a secret kept outside the config file, returning "" if none of sources is set
+func resolveSecret(sources util.SecretSources, what string) string {
+	secret, source, err := sources.Resolve()
+	if err != nil {
+		log.Fatalf("error: could not read %s: %v", what, err)
+	}
+	if source != "" && cfgVerbose {
+		fmt.Printf("Using %s from %s\n", what, source)
+	}
+	return secret
 }
 
 func promptForMasterPassword() string {
@@ -184,6 +262,13 @@ func promptForMasterPassword() string {
 	return masterPass
 }
 
+func promptForPrivateKeyPassphrase() string {
+	var passphrase string
+	fmt.Println("This bucket is in write-only mode. Enter its private key passphrase: ")
+	fmt.Scanln(&passphrase)
+	return passphrase
+}
+
 func promptForSecretKeyId() string {
 	var secretKeyId string
 	fmt.Println("Enter your cloud Secret Key Id: ")
@@ -196,10 +281,10 @@ func validateConfigVars() error {
 	if cfgEndpoint == "" {
 		return fmt.Errorf("endpoint invalid (value='%s')", cfgEndpoint)
 	}
-	if cfgAccessKeyId == "" {
+	if cfgAccessKeyId == "" && objstore.IsCredentialedEndpoint(cfgEndpoint) {
 		return fmt.Errorf("access key id invalid (value='%s')", cfgAccessKeyId)
 	}
-	if cfgSecretAccessKey == "" {
+	if cfgSecretAccessKey == "" && objstore.IsCredentialedEndpoint(cfgEndpoint) {
 		return fmt.Errorf("secret key invalid (value='%s')", cfgSecretAccessKey)
 	}
 	if cfgBucket == "" {
@@ -238,6 +323,24 @@ func validateConfigVars() error {
 		log.Fatalln(err.Error())
 	}
 
+	// Check for an unfinished key rotation
+	if keyRotationInProgress, err = objst.IsKeyRotationInProgress(ctx, cfgBucket); err != nil {
+		return err
+	}
+
+	// Unlock the private key if the bucket is in write-only mode and the command needs it
+	if sealKeys, err = objst.GetSealKeys(ctx, cfgBucket, ""); err != nil {
+		return err
+	}
+	if sealKeys != nil && !isRunningWriteOnlyCommand() {
+		if cfgPrivateKeyPassphrase == "" {
+			cfgPrivateKeyPassphrase = promptForPrivateKeyPassphrase()
+		}
+		if sealKeys, err = objst.GetSealKeys(ctx, cfgBucket, cfgPrivateKeyPassphrase); err != nil {
+			log.Fatalln("error: could not unlock the write-only private key: ", err)
+		}
+	}
+
 	// Everything is good with crypto parameters
 	vlog.Println("Everything looks good with bucket metadata")
 
@@ -253,24 +356,27 @@ func persistUsage(db *database.DB, doSpaceUsage bool, doBandwidthUsage bool, vlo
 		if err != nil {
 			log.Fatalf("error: making sqlite dir: %v", err)
 		}
-		db, err := database.NewDB(filepath.Join(sqliteDir, "state.db"))
+		db, err = database.NewDB(filepath.Join(sqliteDir, "state.db"))
 		if err != nil {
 			log.Fatalf("error: cannot open database: %v", err)
 		}
 		defer db.Close()
+		if err := db.PerformDbMigrations(vlog); err != nil {
+			log.Fatalf("error: cannot initialize database: %v", err)
+		}
 	}
 
 	if doSpaceUsage {
 		// Cloud space usage
-		cloudSizeUsageBytes, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, cfgBucket, encKey, vlog)
+		cloudSizeUsageBytes, paddingUsageBytes, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, cfgBucket, encKey, nil, db, vlog)
 		if err != nil {
 			log.Println("error: persistUsage: ComputeTotalCloudSpaceUsage failed: ", err)
 		} else {
-			err = db.AddSpaceUsageReport(time.Now().Unix(), cloudSizeUsageBytes)
+			err = db.AddSpaceUsageReport(time.Now().Unix(), cloudSizeUsageBytes, paddingUsageBytes)
 			if err != nil {
 				log.Println("error: persistUsage: AddSpaceUsageReport failed: ", err)
 			} else {
-				vlog.Println("USAGE> persisted cloud space usage of %s", util.FormatBytesAsString(cloudSizeUsageBytes))
+				vlog.Printf("USAGE> persisted cloud space usage of %s (%s of padding)", util.FormatBytesAsString(cloudSizeUsageBytes), util.FormatBytesAsString(paddingUsageBytes))
 			}
 		}
 	}
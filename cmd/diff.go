@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	diffCfgJson bool
+
+	diffCmd = &cobra.Command{
+		Use:   "diff <backup/snapshot> [<backup/snapshot>]",
+		Short: "Shows what changed between two snapshots, or since a snapshot",
+		Long: `Lists the paths added, removed and modified between two snapshots of the same backup. With
+a single snapshot, compares it against the backed up directory as it is right now, which shows
+what the next backup would pick up. Usage:
+
+tless diff <backup/snapshot> [<backup/snapshot>]
+
+For modified paths, what changed (content, size, mtime, mode, xattrs, symlink target or type)
+is shown in parentheses.
+
+Example:
+
+	tless diff Documents/2020-01-15_04.56.00 Documents/2020-01-16_04.56.00
+	tless diff Documents/2020-01-16_04.56.00
+	tless diff Documents/2020-01-16_04.56.00 --json
+`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshotNameB := ""
+			if len(args) == 2 {
+				snapshotNameB = args[1]
+			}
+			diffMain(args[0], snapshotNameB)
+		},
+	}
+)
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffCfgJson, "json", false, "print the differences as JSON")
+	rootCmd.AddCommand(diffCmd)
+}
+
+type diffJsonOutput struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Entries []backup.DiffEntry `json:"entries"`
+}
+
+func diffMain(backupAndSnapshotNameA string, backupAndSnapshotNameB string) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	backupName, snapshotA := getSnapshotOrDie(ctx, objst, backupAndSnapshotNameA)
+
+	// Initialize a chunk cache
+	cc := backup.NewChunkCache(objst, encKey, sealKeys, vlog, -1, -1, cfgCachesPath, cfgMaxChunkCacheMb)
+
+	progressFunc := func(percentDone float64) {
+		vlog.Printf("Comparing... %.1f%%", percentDone)
+	}
+
+	var entries []backup.DiffEntry
+	var to string
+	if backupAndSnapshotNameB != "" {
+		backupNameB, snapshotB := getSnapshotOrDie(ctx, objst, backupAndSnapshotNameB)
+		if backupNameB != backupName {
+			log.Fatalf("error: cannot diff snapshots of different backups ('%s' and '%s')", backupName, backupNameB)
+		}
+		var err error
+		entries, err = backup.DiffSnapshots(ctx, cc, cfgBucket, snapshotA, snapshotB, progressFunc)
+		if err != nil {
+			log.Fatalf("error: diff failed: %v", err)
+		}
+		to = backupAndSnapshotNameB
+	} else {
+		backupDirPath := ""
+		for _, dir := range cfgDirs {
+			if filepath.Base(util.StripTrailingSlashes(dir)) == backupName {
+				backupDirPath = dir
+			}
+		}
+		if backupDirPath == "" {
+			log.Fatalf("error: no directory named '%s' is configured for backup", backupName)
+		}
+		var err error
+		entries, err = backup.DiffSnapshotAgainstLive(ctx, cc, hmacKey, cfgBucket, snapshotA, backupDirPath, cfgExcludePaths, vlog, progressFunc)
+		if err != nil {
+			log.Fatalf("error: diff failed: %v", err)
+		}
+		to = backupDirPath
+	}
+
+	if diffCfgJson {
+		buf, err := json.MarshalIndent(diffJsonOutput{From: backupAndSnapshotNameA, To: to, Entries: entries}, "", "  ")
+		if err != nil {
+			log.Fatalf("error: could not marshal diff: %v", err)
+		}
+		fmt.Println(string(buf))
+	} else {
+		printDiffEntries(entries)
+	}
+
+	// Persist the bandwidth stored hot in objst module
+	persistUsage(nil, false, true, vlog)
+}
+
+func getSnapshotOrDie(ctx context.Context, objst *objstore.ObjStore, backupAndSnapshotName string) (string, *snapshots.Snapshot) {
+	backupName, snapshotName, err := util.SplitSnapshotName(backupAndSnapshotName)
+	if err != nil {
+		log.Fatalf("Cannot split '%s' into backupDirName/snapshotTimestamp", backupAndSnapshotName)
+	}
+	snapshotObj, err := snapshots.GetSnapshot(ctx, objst, cfgBucket, encKey, sealKeys, backupName, snapshotName)
+	if err != nil {
+		log.Fatalf("error: cannot get snapshot '%s': %v", backupAndSnapshotName, err)
+	}
+	return backupName, snapshotObj
+}
+
+func printDiffEntries(entries []backup.DiffEntry) {
+	added, removed, modified := 0, 0, 0
+	for _, entry := range entries {
+		switch entry.Kind {
+		case backup.DiffAdded:
+			fmt.Printf("+ %s\n", entry.RelPath)
+			added += 1
+		case backup.DiffRemoved:
+			fmt.Printf("- %s\n", entry.RelPath)
+			removed += 1
+		case backup.DiffModified:
+			if len(entry.Changes) > 0 {
+				fmt.Printf("M %s (%s)\n", entry.RelPath, strings.Join(entry.Changes, ", "))
+			} else {
+				fmt.Printf("M %s\n", entry.RelPath)
+			}
+			modified += 1
+		}
+	}
+	fmt.Printf("%d added, %d removed, %d modified\n", added, removed, modified)
+}
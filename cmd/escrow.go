@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	escrowCfgShares    int
+	escrowCfgThreshold int
+	escrowCfgOutDir    string
+	escrowCfgName      string
+	escrowCfgRecovery  bool
+
+	escrowCmd = &cobra.Command{
+		Use:   "escrow",
+		Short: "Splits the bucket's keys into shares for disaster recovery",
+		Long: `Splits the bucket's keys into shares that you hand to different people (or store in
+different places), so that a minimum number of them together can regain access to your backups
+if your master password is lost, while fewer than that reveal nothing about the keys.
+`,
+	}
+
+	escrowSplitCmd = &cobra.Command{
+		Use:   "split",
+		Short: "Splits the keys into shares",
+		Long: `Splits the bucket's keys into --shares shares, any --threshold of which can rebuild them with
+'tless escrow recover'. The shares are printed, or written to one file each with --out-dir. Usage:
+
+tless escrow split --shares <n> --threshold <m> [--out-dir <dir>]
+
+Example:
+
+	tless escrow split --shares 5 --threshold 3 --out-dir /media/usb
+
+Anyone holding enough shares has full access to your backups, so treat them like your password.
+Shares stay valid when you run 'tless rotate-key', except for the first rotation of a bucket made
+by an older version of tless.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			escrowSplitMain()
+		},
+	}
+
+	escrowRecoverCmd = &cobra.Command{
+		Use:   "recover <share>...",
+		Short: "Rebuilds the keys from shares and adds a key slot for them",
+		Long: `Rebuilds the bucket's keys from shares made by 'tless escrow split' and adds a key slot (see
+'tless key-slots') with a new passphrase, which you can then use as your master password. Only
+the bucket metadata is changed; none of your backups are touched. Each <share> is either a share
+file or the share text itself. This command does not need a working master password. Usage:
+
+tless escrow recover <share>... [--name <slot name>] [--recovery]
+
+Example:
+
+	tless escrow recover share-1.txt share-4.txt share-5.txt
+
+With --recovery, a recovery key is generated and printed instead of asking for a passphrase.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			escrowRecoverMain(args)
+		},
+	}
+)
+
+func init() {
+	escrowSplitCmd.Flags().IntVar(&escrowCfgShares, "shares", 0, "number of shares to make")
+	escrowSplitCmd.Flags().IntVar(&escrowCfgThreshold, "threshold", 0, "number of shares needed to rebuild the keys")
+	escrowSplitCmd.Flags().StringVar(&escrowCfgOutDir, "out-dir", "", "write each share to a file in this directory")
+	escrowRecoverCmd.Flags().StringVar(&escrowCfgName, "name", "escrow", "name of the key slot to add")
+	escrowRecoverCmd.Flags().BoolVar(&escrowCfgRecovery, "recovery", false, "generate a recovery key instead of asking for a passphrase")
+	escrowCmd.AddCommand(escrowSplitCmd)
+	escrowCmd.AddCommand(escrowRecoverCmd)
+	rootCmd.AddCommand(escrowCmd)
+}
+
+func escrowSplitMain() {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	// The shares hold the key that the key slots wrap rather than encKey, so that they can add a key
+	// slot and survive key rotations
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+	kek, err := objst.GetKeyEncryptionKey(ctx, cfgBucket, cfgMasterPassword, vlog)
+	if err != nil {
+		log.Fatalf("error: could not unlock key slots: %v", err)
+	}
+	shares, err := cryptography.SplitKeysIntoEscrowShares(kek, hmacKey, escrowCfgShares, escrowCfgThreshold)
+	if err != nil {
+		log.Fatalf("error: could not split keys: %v", err)
+	}
+
+	for i, share := range shares {
+		text := fmt.Sprintf("# tless escrow share %d of %d for bucket '%s'\n", i+1, len(shares), cfgBucket)
+		text += fmt.Sprintf("# Any %d shares rebuild the bucket's keys with 'tless escrow recover'. Keep this secret.\n", escrowCfgThreshold)
+		text += share.String() + "\n"
+
+		if escrowCfgOutDir == "" {
+			fmt.Println(text)
+			continue
+		}
+		path := filepath.Join(escrowCfgOutDir, fmt.Sprintf("tless-share-%d-of-%d.txt", i+1, len(shares)))
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			log.Fatalf("error: could not write share file: %v", err)
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+}
+
+func escrowRecoverMain(shareArgs []string) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	shares := make([]*cryptography.EscrowShare, 0, len(shareArgs))
+	for _, shareArg := range shareArgs {
+		text := shareArg
+		if buf, err := os.ReadFile(shareArg); err == nil {
+			text = string(buf)
+		}
+		share, err := cryptography.ParseEscrowShare(text)
+		if err != nil {
+			log.Fatalf("error: could not read share '%s': %v", shareArg, err)
+		}
+		shares = append(shares, share)
+	}
+	recoveredKek, recoveredHmacKey, err := cryptography.RecoverKeysFromEscrowShares(shares)
+	if err != nil {
+		log.Fatalf("error: could not rebuild keys: %v", err)
+	}
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+	if err = objst.VerifyRecoveredKeys(ctx, cfgBucket, recoveredKek); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	var passphrase string
+	if escrowCfgRecovery {
+		passphrase = util.GenerateRecoveryKey()
+	} else {
+		fmt.Println("Enter the new passphrase: ")
+		fmt.Scanln(&passphrase)
+		var passphraseAgain string
+		fmt.Println("Enter it again: ")
+		fmt.Scanln(&passphraseAgain)
+		if passphrase != passphraseAgain {
+			log.Fatalln("error: passphrases do not match")
+		}
+	}
+	if err = objst.AddKeySlot(ctx, cfgBucket, recoveredKek, recoveredHmacKey, escrowCfgName, passphrase, escrowCfgRecovery, vlog); err != nil {
+		log.Fatalf("error: could not add key slot: %v", err)
+	}
+
+	if escrowCfgRecovery {
+		fmt.Printf("Added key slot '%s'. Your recovery key is:\n\n    %s\n\nUse it as your master password.\n", escrowCfgName, passphrase)
+	} else {
+		fmt.Printf("Added key slot '%s'. Use its passphrase as your master password.\n", escrowCfgName)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/util"
 	"github.com/spf13/cobra"
@@ -172,7 +174,24 @@ func wipeCloudMain() {
 		}
 	}
 
-	persistUsage(nil, true, true, vlog)
+	// The manifest is gone too, which is not a sign of tampering
+	sqliteDir, err := util.MkdirUserConfig("", "")
+	if err != nil {
+		log.Fatalf("error: making sqlite dir: %v", err)
+	}
+	db, err := database.NewDB(filepath.Join(sqliteDir, "state.db"))
+	if err != nil {
+		log.Fatalf("error: cannot open database: %v", err)
+	}
+	defer db.Close()
+	if err = db.PerformDbMigrations(vlog); err != nil {
+		log.Fatalf("error: cannot initialize database: %v", err)
+	}
+	if err = db.SetLastSeenManifestVersion(cfgBucket, 0); err != nil {
+		log.Printf("error: wipeCloudMain: could not reset manifest version: %v", err)
+	}
+
+	persistUsage(db, true, true, vlog)
 
 	if !cfgVerbose {
 		// Give progress bar 0.1 sec to draw itself for final time
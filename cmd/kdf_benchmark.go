@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	kdfBenchmarkCfgTarget    time.Duration
+	kdfBenchmarkCfgMemoryMiB uint32
+	kdfBenchmarkCfgThreads   uint8
+	kdfBenchmarkCfgApply     bool
+
+	kdfBenchmarkCmd = &cobra.Command{
+		Use:   "kdf-benchmark",
+		Short: "Picks key derivation parameters that take a target time on this machine",
+		Long: `Your master password is turned into a key with Argon2id, whose cost makes guessing passwords
+slow. This command measures how many passes of Argon2id this machine can do in --target time
+with --memory MiB and --threads threads, and prints the parameters it picked alongside the ones
+your password currently uses. --memory can be from 64 to 4096 MiB, and at most 100 passes are
+used however long --target is. Usage:
+
+tless kdf-benchmark [--target <duration>] [--memory <MiB>] [--threads <n>] [--apply]
+
+Example:
+
+	tless kdf-benchmark --target 3s --memory 512 --apply
+
+With --apply, your master password's key slot is re-encrypted with the new parameters. Every
+machine that unlocks the bucket then needs as much memory and takes about as long, so pick a
+target that suits the slowest of them. Buckets whose parameters were changed can only be opened
+by versions of this program that support them.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			kdfBenchmarkMain()
+		},
+	}
+)
+
+func init() {
+	kdfBenchmarkCmd.Flags().DurationVar(&kdfBenchmarkCfgTarget, "target", 2*time.Second, "how long unlocking should take")
+	kdfBenchmarkCmd.Flags().Uint32Var(&kdfBenchmarkCfgMemoryMiB, "memory", cryptography.DefaultKdfParams.MemoryKiB/1024, "memory to use, in MiB")
+	kdfBenchmarkCmd.Flags().Uint8Var(&kdfBenchmarkCfgThreads, "threads", cryptography.DefaultKdfParams.Threads, "number of threads to use")
+	kdfBenchmarkCmd.Flags().BoolVar(&kdfBenchmarkCfgApply, "apply", false, "re-encrypt your master password's key slot with the new parameters")
+	rootCmd.AddCommand(kdfBenchmarkCmd)
+}
+
+func kdfBenchmarkMain() {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	currentParams, err := objst.GetKdfParams(ctx, cfgBucket, cfgMasterPassword, vlog)
+	if err != nil {
+		log.Fatalf("error: could not read current parameters: %v", err)
+	}
+	fmt.Printf("Current parameters: %s\n", formatKdfParams(currentParams))
+
+	if kdfBenchmarkCfgMemoryMiB > 4096 {
+		log.Fatalf("error: --memory cannot be more than 4096 MiB")
+	}
+	fmt.Printf("Benchmarking (target %v)...\n", kdfBenchmarkCfgTarget)
+	params, took, err := cryptography.BenchmarkKdfParams(kdfBenchmarkCfgTarget, kdfBenchmarkCfgMemoryMiB*1024, kdfBenchmarkCfgThreads)
+	if err != nil {
+		log.Fatalf("error: could not benchmark: %v", err)
+	}
+	fmt.Printf("New parameters:     %s (took %v)\n", formatKdfParams(params), took.Round(time.Millisecond))
+
+	if !kdfBenchmarkCfgApply {
+		fmt.Println("Run again with --apply to use the new parameters.")
+		return
+	}
+	if err = objst.ChangePassword(ctx, cfgBucket, cfgMasterPassword, cfgMasterPassword, &params, vlog); err != nil {
+		log.Fatalf("error: could not apply new parameters: %v", err)
+	}
+	fmt.Println("Your master password now uses the new parameters.")
+}
+
+func formatKdfParams(params cryptography.KdfParams) string {
+	return fmt.Sprintf("%d passes, %d MiB, %d threads", params.Time, params.MemoryKiB/1024, params.Threads)
+}
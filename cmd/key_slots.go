@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	keySlotsCfgRecovery bool
+
+	keySlotsCmd = &cobra.Command{
+		Use:   "key-slots",
+		Short: "Manages additional passphrases and recovery keys",
+		Long: `Besides your master password, a bucket can be unlocked by any number of additional
+passphrases or recovery keys, each in its own key slot. This lets several people share a bucket
+without sharing a password, and lets you keep a recovery key somewhere safe in case you lose
+your password. Any of them can be used as the master password.
+
+Unlocking with anything other than the primary master password tries each slot in turn, which
+takes a few seconds per slot.
+`,
+	}
+
+	keySlotsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists the key slots",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			keySlotsListMain()
+		},
+	}
+
+	keySlotsAddCmd = &cobra.Command{
+		Use:   "add <name>",
+		Short: "Adds a key slot with a new passphrase or recovery key",
+		Long: `Adds a key slot named <name>. You will be asked for the new passphrase. With --recovery, a
+random recovery key is generated and printed instead; write it down and store it somewhere safe,
+because it cannot be shown again. Usage:
+
+tless key-slots add <name> [--recovery]
+
+Example:
+
+	tless key-slots add alice
+	tless key-slots add safe-deposit-box --recovery
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keySlotsAddMain(args[0], keySlotsCfgRecovery)
+		},
+	}
+
+	keySlotsRevokeCmd = &cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Removes a key slot",
+		Long: `Removes key slot <name>, so that its passphrase or recovery key no longer unlocks the bucket.
+The primary master password cannot be revoked (change it instead), and neither can the slot
+whose passphrase you are currently using. Usage:
+
+tless key-slots revoke <name>
+
+Note that anyone who had the revoked passphrase may also have copied your keys. If that is a
+concern, run 'tless rotate-key' as well.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keySlotsRevokeMain(args[0])
+		},
+	}
+)
+
+func init() {
+	keySlotsAddCmd.Flags().BoolVar(&keySlotsCfgRecovery, "recovery", false, "generate a recovery key instead of asking for a passphrase")
+	keySlotsCmd.AddCommand(keySlotsListCmd)
+	keySlotsCmd.AddCommand(keySlotsAddCmd)
+	keySlotsCmd.AddCommand(keySlotsRevokeCmd)
+	rootCmd.AddCommand(keySlotsCmd)
+}
+
+func keySlotsListMain() {
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	infos, err := objst.ListKeySlots(ctx, cfgBucket, encKey)
+	if err != nil {
+		log.Fatalf("error: could not list key slots: %v", err)
+	}
+	for _, info := range infos {
+		details := ""
+		if info.IsRecoveryKey {
+			details += " (recovery key)"
+		}
+		if info.CreatedUnix != 0 {
+			details += fmt.Sprintf(" added %s", time.Unix(info.CreatedUnix, 0).Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("%s%s\n", info.Name, details)
+	}
+}
+
+func keySlotsAddMain(name string, isRecoveryKey bool) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	var passphrase string
+	if isRecoveryKey {
+		passphrase = util.GenerateRecoveryKey()
+	} else {
+		fmt.Println("Enter the new passphrase: ")
+		fmt.Scanln(&passphrase)
+		var passphraseAgain string
+		fmt.Println("Enter it again: ")
+		fmt.Scanln(&passphraseAgain)
+		if passphrase != passphraseAgain {
+			log.Fatalln("error: passphrases do not match")
+		}
+	}
+
+	kek, err := objst.GetKeyEncryptionKey(ctx, cfgBucket, cfgMasterPassword, vlog)
+	if err != nil {
+		log.Fatalf("error: could not unlock key slots: %v", err)
+	}
+	if err := objst.AddKeySlot(ctx, cfgBucket, kek, hmacKey, name, passphrase, isRecoveryKey, vlog); err != nil {
+		log.Fatalf("error: could not add key slot: %v", err)
+	}
+
+	if isRecoveryKey {
+		fmt.Printf("Added key slot '%s'. Your recovery key is:\n\n    %s\n\nStore it somewhere safe; it will not be shown again.\n", name, passphrase)
+	} else {
+		fmt.Printf("Added key slot '%s'\n", name)
+	}
+}
+
+func keySlotsRevokeMain(name string) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	if err := objst.RevokeKeySlot(ctx, cfgBucket, cfgMasterPassword, name, vlog); err != nil {
+		log.Fatalf("error: could not revoke key slot '%s': %v", name, err)
+	}
+	fmt.Printf("Revoked key slot '%s'\n", name)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestCmd = &cobra.Command{
+		Use:   "manifest",
+		Short: "Manages the snapshot manifest",
+		Long: `The snapshot manifest lists every snapshot in the bucket, so that snapshots deleted or rolled
+back behind your back can be noticed. Backups warn about such problems, and 'tless check' reports
+them.
+`,
+	}
+
+	manifestRebuildCmd = &cobra.Command{
+		Use:   "rebuild",
+		Short: "Replaces the snapshot manifest with one listing the snapshots now in the bucket",
+		Long: `Replaces the snapshot manifest with one listing the snapshots that are in the bucket right now.
+Use this when the manifest is missing or fails authentication and backups refuse to write new
+snapshots, or to accept snapshots having been deleted by other means. Usage:
+
+tless manifest rebuild
+
+Only do this once you know why the manifest no longer matches the bucket: afterwards, snapshots
+that went missing before the rebuild can no longer be noticed. You are asked to confirm first.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			manifestRebuildMain()
+		},
+	}
+)
+
+func init() {
+	manifestCmd.AddCommand(manifestRebuildCmd)
+	rootCmd.AddCommand(manifestCmd)
+}
+
+func manifestRebuildMain() {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	// open and prepare sqlite database, which remembers the last manifest version seen
+	sqliteDir, err := util.MkdirUserConfig("", "")
+	if err != nil {
+		log.Fatalf("error: making sqlite dir: %v", err)
+	}
+	db, err := database.NewDB(filepath.Join(sqliteDir, "state.db"))
+	if err != nil {
+		log.Fatalf("error: cannot open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.PerformDbMigrations(vlog); err != nil {
+		log.Fatalf("error: cannot initialize database: %v", err)
+	}
+	lastSeenVersion, err := db.GetLastSeenManifestVersion(cfgBucket)
+	if err != nil {
+		log.Fatalf("error: cannot read the last manifest version seen: %v", err)
+	}
+
+	// Show what is wrong with the manifest, and ask before papering over it
+	_, problems, err := snapshots.VerifyManifest(ctx, objst, cfgBucket, encKey, hmacKey, lastSeenVersion, false)
+	if err != nil {
+		log.Fatalf("error: could not verify the snapshot manifest: %v", err)
+	}
+	if len(problems) == 0 {
+		fmt.Println("The snapshot manifest has no problems.")
+	} else {
+		fmt.Println("The snapshot manifest has these problems:")
+		for _, problem := range problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+	var answer string
+	fmt.Println("Rebuild it from the snapshots now in the bucket? Type 'rebuild' to continue: ")
+	fmt.Scanln(&answer)
+	if answer != "rebuild" {
+		fmt.Println("Not rebuilt")
+		return
+	}
+
+	if err = snapshots.RebuildManifest(ctx, objst, cfgBucket, hmacKey, lastSeenVersion); err != nil {
+		log.Fatalf("error: could not rebuild the snapshot manifest: %v", err)
+	}
+	if _, err = snapshots.CheckManifest(ctx, objst, cfgBucket, encKey, hmacKey, nil, db); err != nil {
+		log.Fatalf("error: could not verify the rebuilt snapshot manifest: %v", err)
+	}
+	fmt.Println("Rebuilt the snapshot manifest")
+
+	persistUsage(db, false, true, vlog)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	mountCfgDebugFuse bool
+
+	mountCmd = &cobra.Command{
+		Use:   "mount [/mount/point]",
+		Short: "Mounts all snapshots as a read-only filesystem",
+		Long: `Mounts every snapshot in the cloud as a read-only FUSE filesystem at the specified
+(existing, empty) directory. Usage:
+
+tless mount [/mount/point]
+
+The filesystem is laid out as backupName/snapshotName/relPath, so you can browse, diff, grep
+and copy files straight out of your backups without restoring them first. Chunks are only
+downloaded and decrypted when the files in them are read.
+
+Example:
+
+	tless mount /mnt/tless
+	cp /mnt/tless/Documents/2020-01-15_04.56.00/Journal/Feb.docx ~/Recovered-Feb.docx
+
+The command keeps running until you press Ctrl-C or unmount the directory (with
+'fusermount -u /mnt/tless' on Linux or 'umount /mnt/tless' on macOS).
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			mountMain(args[0])
+		},
+	}
+)
+
+func init() {
+	mountCmd.Flags().BoolVar(&mountCfgDebugFuse, "debug-fuse", false, "log every FUSE request (default: false)")
+	rootCmd.AddCommand(mountCmd)
+}
+
+func mountMain(mountPoint string) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, encKey, sealKeys, cfgBucket, vlog, nil, nil)
+	if err != nil {
+		log.Fatalf("Could not get grouped snapshots: %v", err)
+	}
+
+	// Initialize a chunk cache
+	cc := backup.NewChunkCache(objst, encKey, sealKeys, vlog, -1, -1, cfgCachesPath, cfgMaxChunkCacheMb)
+
+	server, err := backup.MountSnapshots(mountPoint, groupedObjects, cfgBucket, cc, mountCfgDebugFuse)
+	if err != nil {
+		log.Fatalf("Could not mount snapshots at '%s': %v", mountPoint, err)
+	}
+	fmt.Printf("Mounted snapshots at '%s' (Ctrl-C to unmount)\n", mountPoint)
+
+	// Unmount on Ctrl-C; Wait returns once the filesystem is unmounted by either route
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		if err := server.Unmount(); err != nil {
+			log.Printf("error: could not unmount '%s': %v", mountPoint, err)
+		}
+	}()
+	server.Wait()
+
+	// Print the cache hit rate to vlog for diagnostics
+	cc.PrintCacheStatistics()
+
+	// Persist the bandwidth stored hot in objst module
+	persistUsage(nil, false, true, vlog)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pinCmd = &cobra.Command{
+		Use:   "pin <backup/snapshot>...",
+		Short: "Protects snapshots from prune and cloudrm",
+		Long: `Pins one or more snapshots. Pinned snapshots are always kept by prune (and by the daemon's
+autoprune), and cloudrm refuses to delete them unless given --delete-pinned. Usage:
+
+tless pin <backup/snapshot>...
+
+Example:
+
+	tless pin Documents/2020-01-01_04.56.01
+
+Use 'tless unpin' to remove the pin.
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			pinMain(args, true)
+		},
+	}
+
+	unpinCmd = &cobra.Command{
+		Use:   "unpin <backup/snapshot>...",
+		Short: "Removes the pin from snapshots",
+		Long: `Unpins one or more snapshots pinned with 'tless pin', so that prune and cloudrm can delete
+them again. Usage:
+
+tless unpin <backup/snapshot>...
+
+Example:
+
+	tless unpin Documents/2020-01-01_04.56.01
+`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			pinMain(args, false)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
+
+func pinMain(snapshotRawNames []string, pinned bool) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	for _, snapshotRawName := range snapshotRawNames {
+		backupName, snapshotName, err := util.SplitSnapshotName(snapshotRawName)
+		if err != nil {
+			log.Fatalf("Cannot split '%s' into backupDirName/snapshotTimestamp", snapshotRawName)
+		}
+
+		a, err := snapshots.UpdateSnapshotAnnotations(ctx, objst, cfgBucket, encKey, backupName, snapshotName, func(a *snapshots.SnapshotAnnotations) error {
+			a.Pinned = pinned
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to update pin on %s: %v", snapshotRawName, err)
+		}
+
+		printSnapshotAnnotations(snapshotRawName, a)
+	}
+
+	persistUsage(nil, false, true, vlog)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fsctl/tless/pkg/objstore"
@@ -20,8 +21,9 @@ var (
 		Use:   "prune",
 		Short: "Prunes snapshots from a backup",
 		Long: `Prunes snapshots on server by deleting intermediate snapshots that are no longer necessary.
-Prune keeps every snapshot from the past 24 hours, the oldest and newest from the past 2-7 days,
-the oldest and newest from past 7-30 days and the oldest and newest from the past 2-12 months.
+Which snapshots are kept is set by the [retention] section of the config file, which can have a
+different policy for each backup dir. If it is empty, prune keeps every snapshot from the past day
+plus the oldest and newest snapshots from 1-3, 3-7, 7-30 and 30-360 days ago.
 
 The prune command is specific to a particular backup and will only look at snapshots from that 
 backup. In the examples below, it is imagined that you have backups with names like "Documents"
@@ -33,7 +35,7 @@ Example:
 	tless prune home --dry-run
 
 The --dry-run flag will cause prune to simply print what snapshots it would delete and preserve, 
-but not do any actual deletion.
+and which retention rules preserve each one, but not do any actual deletion.
 `,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -72,7 +74,11 @@ func pruneMain(backupName string, isDryRun bool) {
 	fmt.Printf("Backup '%s'\n", backupName)
 
 	// Mark what is to be kept
-	keeps := snapshots.GetPruneKeepsList(mSnapshots[backupName])
+	keeps, reasons, err := snapshots.GetRetentionKeepsList(mSnapshots[backupName], cfgRetention.PolicyForBackup(backupName))
+	if err != nil {
+		fmt.Printf("error: invalid retention policy for '%s': %v\n", backupName, err)
+		return
+	}
 
 	for _, ss := range mSnapshots[backupName] {
 		if isDryRun {
@@ -87,7 +93,11 @@ func pruneMain(backupName string, isDryRun bool) {
 			tm := time.Unix(ss.TimestampUnix, 0).UTC()
 			formattedTimestamp := tm.Format("Jan 2, 2006 at 3:04pm UTC")
 
-			fmt.Printf("  %s: '%s' from %s\n", verb, ss.Name, formattedTimestamp)
+			if verb == "KEEP" {
+				fmt.Printf("  %s: '%s' from %s (%s)\n", verb, ss.Name, formattedTimestamp, strings.Join(reasons[ss.TimestampUnix], "; "))
+			} else {
+				fmt.Printf("  %s: '%s' from %s\n", verb, ss.Name, formattedTimestamp)
+			}
 		} else {
 			keepCurr := false
 			for _, k := range keeps {
@@ -103,7 +113,7 @@ func pruneMain(backupName string, isDryRun bool) {
 					BackupDirName: backupName,
 					SnapshotName:  ss.Name,
 				}
-				if err = snapshots.DeleteSnapshots(ctx, encKey, []snapshots.SnapshotForDeletion{ssDel}, objst, cfgBucket, vlog, nil, nil); err != nil {
+				if err = snapshots.DeleteSnapshots(ctx, encKey, hmacKey, sealKeys, []snapshots.SnapshotForDeletion{ssDel}, objst, cfgBucket, false, vlog, nil, nil); err != nil {
 					fmt.Printf("error: could not delete '%s': %v\n", ss.RawSnapshotName, err)
 				}
 			} else {
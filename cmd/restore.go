@@ -20,6 +20,9 @@ import (
 var (
 	// Flags
 	cfgPartialRestore string
+	cfgMapUids        []string
+	cfgMapGids        []string
+	cfgNumericOwner   bool
 
 	restoreCmd = &cobra.Command{
 		Use:   "restore [name] [/restore/into/dir]",
@@ -47,6 +50,12 @@ will be restored.
 In the second command, only a single file will be restored: 'Documents/Journal/Feb.docx'.
 
 The available snapshot times are displayed in 'unbackupcloud cloudls'.
+
+When run as root, files get back the owner and group they had when they were backed up, looked
+up by name (or by numeric id with --numeric-owner). To restore onto a machine where users have
+different ids, map them with --map-uid and --map-gid:
+
+	tless restore Documents/2020-01-15_04.56.00 /home/myname/Recovered-Documents --map-uid 501:1000 --map-gid 20:1000
 `,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -57,6 +66,9 @@ The available snapshot times are displayed in 'unbackupcloud cloudls'.
 
 func init() {
 	restoreCmd.Flags().StringVarP(&cfgPartialRestore, "partial", "r", "", "relative path for a partial restore")
+	restoreCmd.Flags().StringSliceVar(&cfgMapUids, "map-uid", nil, "restore files owned by uid FROM as owned by uid TO (FROM:TO, repeatable)")
+	restoreCmd.Flags().StringSliceVar(&cfgMapGids, "map-gid", nil, "restore files in group gid FROM as in group gid TO (FROM:TO, repeatable)")
+	restoreCmd.Flags().BoolVar(&cfgNumericOwner, "numeric-owner", false, "restore owners by numeric id rather than by user and group name")
 	rootCmd.AddCommand(restoreCmd)
 }
 
@@ -71,6 +83,14 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 		log.Fatalf("Cannot split '%s' into backupDirName/snapshotTimestamp", backupAndSnapshotName)
 	}
 
+	// Only root can give files away, so owners are restored when running as root or when asked to
+	var owners *backup.OwnerMap
+	if os.Geteuid() == 0 || len(cfgMapUids) > 0 || len(cfgMapGids) > 0 {
+		if owners, err = backup.ParseOwnerMap(cfgMapUids, cfgMapGids, cfgNumericOwner); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+
 	// initialize progress bar container
 	progressBarContainer := mpb.New()
 
@@ -86,7 +106,7 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 
 	// Get the snapshot index
 	encSsIndexObjName := encBackupName + "/@" + encSnapshotName
-	ssIndexJson, err := snapshots.GetSnapshotIndexFile(ctx, objst, cfgBucket, encKey, encSsIndexObjName)
+	ssIndexJson, err := snapshots.GetSnapshotIndexFile(ctx, objst, cfgBucket, encKey, sealKeys, encSsIndexObjName)
 	if err != nil {
 		log.Fatalf("error: cannot get snapshot index file for '%s': %v", backupAndSnapshotName, err)
 	}
@@ -95,6 +115,12 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 		log.Fatalf("error: cannot get snapshot object for '%s': %v", backupAndSnapshotName, err)
 	}
 
+	// Run the pre_restore hook, not restoring anything if it fails
+	hooks := cfgHooks.HooksForBackup(backupName)
+	if err := backup.RunPreRestoreHook(ctx, hooks, backupName, snapshotName, pathToRestoreInto, vlog); err != nil {
+		log.Fatalf("error: not restoring '%s': %v", backupAndSnapshotName, err)
+	}
+
 	// Filter the rel paths we want to restore
 	selectedRelPaths := []string{cfgPartialRestore}
 	mRelPathsObjsMap := backup.FilterRelPaths(snapshotObj, nil, selectedRelPaths)
@@ -120,7 +146,7 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 	}
 
 	// Initialize a chunk cache
-	cc := backup.NewChunkCache(objst, encKey, vlog, -1, -1, cfgCachesPath, cfgMaxChunkCacheMb)
+	cc := backup.NewChunkCache(objst, encKey, sealKeys, vlog, -1, -1, cfgCachesPath, cfgMaxChunkCacheMb)
 
 	// For locality of reference reasons, we'll get the best cache hit rate if we restore in lexiconigraphical
 	// order of rel paths.
@@ -132,10 +158,13 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 
 	// loop over all the relpaths and restore each
 	dirChmodQueue := make([]backup.DirChmodQueueItem, 0) // all directory mode bits are set at end
+	hardLinks := make(map[string]string)
+	errorCount := int64(0)
 	for _, relPath := range relPathKeys {
-		err = backup.RestoreDirEntry(ctx, encKey, pathToRestoreInto, mRelPathsObjsMap[relPath], backupName, snapshotName, relPath, objst, cfgBucket, vlog, &dirChmodQueue, -1, -1, cc)
+		err = backup.RestoreDirEntry(ctx, encKey, hmacKey, pathToRestoreInto, mRelPathsObjsMap[relPath], backupName, snapshotName, relPath, objst, cfgBucket, vlog, &dirChmodQueue, -1, -1, owners, hardLinks, cc)
 		if err != nil {
 			log.Printf("error: could not restore a dir entry '%s'", relPath)
+			errorCount += 1
 		}
 
 		// Update the progress bar
@@ -151,16 +180,15 @@ func restoreMain(backupAndSnapshotName string, pathToRestoreInto string) {
 	}
 
 	// Do all the queued up directory chmods
-	for _, dirChmodQueueItem := range dirChmodQueue {
-		err := os.Chmod(dirChmodQueueItem.AbsPath, dirChmodQueueItem.FinalMode)
-		if err != nil {
-			log.Printf("error: could not chmod dir '%s' to final %#o\n", dirChmodQueueItem.AbsPath, dirChmodQueueItem.FinalMode)
-		}
-	}
+	backup.ApplyDirChmodQueue(dirChmodQueue)
 
 	// Print the cache hit rate to vlog for diagnostics
 	cc.PrintCacheStatistics()
 
+	if err := backup.RunPostRestoreHook(ctx, hooks, backupName, snapshotName, pathToRestoreInto, errorCount, vlog); err != nil {
+		log.Printf("error: %v", err)
+	}
+
 	// Persist the bandwidth stored hot in objst module
 	persistUsage(nil, false, true, vlog)
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateKeyCmd = &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Re-encrypts everything in the cloud under a new encryption key",
+		Long: `Generates a new encryption key and re-encrypts every backup name, snapshot index and chunk
+in the cloud with it. Use this if you think your encryption key may have been exposed. (If only
+your master password was exposed, changing the password is enough.)
+
+Chunk names are derived from a separate HMAC key, which is not rotated, so deduplication
+against your existing backups keeps working afterwards. The HMAC key is kept next to the
+encryption key, so if one was exposed, assume the other was too. Rotating does not take away
+what the HMAC key lets someone do, now or later:
+
+  - tell whether your backups contain a file or chunk whose contents they already know, by
+    computing its chunk name, and recognize the same chunks in your future backups
+  - forge a snapshot manifest, so that snapshots deleted behind your back are not noticed
+
+It does not let them decrypt anything. If that is not good enough, back up to a new bucket
+instead, which gets fresh keys of both kinds, and delete the old one.
+
+Re-encrypting downloads and uploads your entire backup, so it can take a long time. If it is
+interrupted, run this command again to resume where it left off. All other commands refuse to
+run until the rotation has finished.
+
+Key slots (see 'tless key-slots') keep working, as the new key is stored under a key-encryption
+key that they all unlock. Buckets made by older versions of tless get a key-encryption key at
+their first rotation, which has to be done with the master password after revoking any other key
+slots; add them back afterwards.
+
+In write-only mode, the private key passphrase is needed as well, since the sealed snapshot
+indexes have to be read to be renamed. Sealed chunks are not encrypted with your key and are left
+as they are.
+
+Example:
+
+	tless rotate-key
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			rotateKeyMain()
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(rotateKeyCmd)
+}
+
+func rotateKeyMain() {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	// open and prepare sqlite database, which journals our progress
+	sqliteDir, err := util.MkdirUserConfig("", "")
+	if err != nil {
+		log.Fatalf("error: making sqlite dir: %v", err)
+	}
+	db, err := database.NewDB(filepath.Join(sqliteDir, "state.db"))
+	if err != nil {
+		log.Fatalf("error: cannot open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.PerformDbMigrations(vlog); err != nil {
+		log.Fatalf("error: cannot initialize database: %v", err)
+	}
+
+	oldKey, newKey, keyGeneration, hmacKey, err := objst.BeginKeyRotation(ctx, cfgBucket, cfgMasterPassword, sealKeys, vlog)
+	if err != nil {
+		log.Fatalf("error: could not start key rotation: %v", err)
+	}
+
+	progressFunc := func(percentDone float64) {
+		fmt.Printf("\rRe-encrypting... %.1f%%", percentDone)
+	}
+	err = backup.RotateKey(ctx, objst, cfgBucket, oldKey, newKey, hmacKey, sealKeys, cfgPadding, keyGeneration, nil, db, vlog, progressFunc)
+	fmt.Println()
+	if err != nil {
+		log.Fatalf("error: key rotation failed (run 'tless rotate-key' again to resume): %v", err)
+	}
+	fmt.Printf("Done: the bucket is now encrypted with key generation %d\n", keyGeneration)
+
+	// Persist the bandwidth stored hot in objst module
+	persistUsage(db, false, true, vlog)
+}
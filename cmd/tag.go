@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Flags
+	tagCfgAdd    []string
+	tagCfgRemove []string
+
+	tagCmd = &cobra.Command{
+		Use:   "tag <backup/snapshot>",
+		Short: "Adds or removes tags on a snapshot",
+		Long: `Adds tags to or removes tags from a snapshot, then shows its tags. Tags are stored encrypted
+next to the snapshot and can be used to find it again with 'tless cloudls --tag'. With no flags,
+just shows the snapshot's tags. Usage:
+
+tless tag <backup/snapshot> [--add <tag>]... [--remove <tag>]...
+
+Example:
+
+	tless tag Documents/2020-01-01_04.56.01 --add "before OS upgrade"
+	tless tag Documents/2020-01-01_04.56.01 --remove "before OS upgrade"
+	tless tag Documents/2020-01-01_04.56.01
+
+To keep a snapshot from being pruned or deleted, use 'tless pin'.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tagMain(args[0])
+		},
+	}
+)
+
+func init() {
+	tagCmd.Flags().StringArrayVar(&tagCfgAdd, "add", []string{}, "tag to add (can be repeated)")
+	tagCmd.Flags().StringArrayVar(&tagCfgRemove, "remove", []string{}, "tag to remove (can be repeated)")
+	rootCmd.AddCommand(tagCmd)
+}
+
+func tagMain(snapshotRawName string) {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	backupName, snapshotName, err := util.SplitSnapshotName(snapshotRawName)
+	if err != nil {
+		log.Fatalf("Cannot split '%s' into backupDirName/snapshotTimestamp", snapshotRawName)
+	}
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+
+	a, err := snapshots.UpdateSnapshotAnnotations(ctx, objst, cfgBucket, encKey, backupName, snapshotName, func(a *snapshots.SnapshotAnnotations) error {
+		return a.UpdateTags(tagCfgAdd, tagCfgRemove)
+	})
+	if err != nil {
+		log.Fatalf("Failed to update tags: %v", err)
+	}
+
+	printSnapshotAnnotations(snapshotRawName, a)
+
+	persistUsage(nil, false, true, vlog)
+}
+
+func printSnapshotAnnotations(snapshotRawName string, a *snapshots.SnapshotAnnotations) {
+	fmt.Printf("%s%s\n", snapshotRawName, formatSnapshotAnnotations(a))
+}
+
+// Formats a snapshot's pin and tags for display after its name, eg " [pinned] (tags: a, b)"
+func formatSnapshotAnnotations(a *snapshots.SnapshotAnnotations) string {
+	ret := ""
+	if a == nil {
+		return ret
+	}
+	if a.Pinned {
+		ret += " [pinned]"
+	}
+	if len(a.Tags) > 0 {
+		ret += fmt.Sprintf(" (tags: %s)", strings.Join(a.Tags, ", "))
+	}
+	return ret
+}
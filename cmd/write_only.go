@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	writeOnlyCmd = &cobra.Command{
+		Use:   "write-only",
+		Short: "Manages write-only mode, in which backing up cannot read backups",
+		Long: `In write-only mode, chunks and snapshot indexes are encrypted to a public key stored in the
+bucket, so machines that only back up can do so without being able to read anything back. Its
+private key is protected by a separate passphrase, which is only needed to restore, prune, check
+or browse backups. Keep that passphrase off the machines being backed up; a compromised machine
+then cannot expose your other snapshots.
+
+Backup and snapshot names and snapshot tags are still encrypted with the master password's key.
+Backups made before write-only mode was enabled stay readable with the master password alone.
+`,
+	}
+
+	writeOnlyEnableCmd = &cobra.Command{
+		Use:   "enable",
+		Short: "Puts the bucket in write-only mode",
+		Long: `Generates the bucket's key pair and asks for the passphrase that will protect its private key.
+Write-only mode cannot be turned off again. Usage:
+
+tless write-only enable
+
+If you want a machine to be able to read backups without being asked for the passphrase, add it
+to that machine's config file as 'private_key_passphrase' in the [backups] section.
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			writeOnlyEnableMain()
+		},
+	}
+
+	writeOnlyStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Shows whether the bucket is in write-only mode",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if sealKeys != nil {
+				fmt.Println("The bucket is in write-only mode")
+			} else {
+				fmt.Println("The bucket is not in write-only mode")
+			}
+		},
+	}
+)
+
+func init() {
+	writeOnlyCmd.AddCommand(writeOnlyEnableCmd)
+	writeOnlyCmd.AddCommand(writeOnlyStatusCmd)
+	rootCmd.AddCommand(writeOnlyCmd)
+}
+
+func writeOnlyEnableMain() {
+	vlog := util.NewVLog(nil, func() bool { return cfgVerbose })
+
+	var passphrase string
+	fmt.Println("Enter the new private key passphrase (not your master password): ")
+	fmt.Scanln(&passphrase)
+	var passphraseAgain string
+	fmt.Println("Enter it again: ")
+	fmt.Scanln(&passphraseAgain)
+	if passphrase != passphraseAgain {
+		log.Fatalln("error: passphrases do not match")
+	}
+	if passphrase == cfgMasterPassword {
+		log.Fatalln("error: the private key passphrase must be different from the master password")
+	}
+
+	ctx := context.Background()
+	objst := objstore.NewObjStore(ctx, cfgEndpoint, cfgAccessKeyId, cfgSecretAccessKey, cfgTrustSelfSignedCerts)
+	if err := objst.EnableWriteOnlyMode(ctx, cfgBucket, passphrase, vlog); err != nil {
+		log.Fatalf("error: could not enable write-only mode: %v", err)
+	}
+	fmt.Println("The bucket is now in write-only mode. Keep the private key passphrase somewhere safe:")
+	fmt.Println("without it, backups made from now on cannot be restored.")
+}
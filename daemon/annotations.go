@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+)
+
+// Callback for rpc.DaemonCtlServer.TagSnapshot requests
+func (s *server) TagSnapshot(ctx context.Context, in *pb.TagSnapshotRequest) (*pb.SnapshotAnnotationsResponse, error) {
+	log.Printf(">> GOT COMMAND: TagSnapshot (%s: +%v -%v)", in.SnapshotRawName, in.AddTags, in.RemoveTags)
+	defer log.Println(">> COMPLETED COMMAND: TagSnapshot")
+
+	return updateSnapshotAnnotations(ctx, "TagSnapshot", in.SnapshotRawName, func(a *snapshots.SnapshotAnnotations) error {
+		return a.UpdateTags(in.AddTags, in.RemoveTags)
+	}), nil
+}
+
+// Callback for rpc.DaemonCtlServer.PinSnapshot requests
+func (s *server) PinSnapshot(ctx context.Context, in *pb.PinSnapshotRequest) (*pb.SnapshotAnnotationsResponse, error) {
+	log.Printf(">> GOT COMMAND: PinSnapshot (%s: %v)", in.SnapshotRawName, in.Pinned)
+	defer log.Println(">> COMPLETED COMMAND: PinSnapshot")
+
+	return updateSnapshotAnnotations(ctx, "PinSnapshot", in.SnapshotRawName, func(a *snapshots.SnapshotAnnotations) error {
+		a.Pinned = in.Pinned
+		return nil
+	}), nil
+}
+
+func updateSnapshotAnnotations(ctx context.Context, rpcName string, snapshotRawName string, updateFunc func(a *snapshots.SnapshotAnnotations) error) *pb.SnapshotAnnotationsResponse {
+	errResponse := func(msg string) *pb.SnapshotAnnotationsResponse {
+		log.Println(msg)
+		return &pb.SnapshotAnnotationsResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+		}
+	}
+
+	backupName, snapshotName, err := util.SplitSnapshotName(snapshotRawName)
+	if err != nil {
+		return errResponse(fmt.Sprintf("error: %s: invalid snapshot name '%s'", rpcName, snapshotRawName))
+	}
+
+	// Make sure the global config we need is initialized
+	gGlobalsLock.Lock()
+	isGlobalConfigReady := gCfg != nil && gEncKey != nil
+	gGlobalsLock.Unlock()
+	if !isGlobalConfigReady {
+		return errResponse("global config not yet initialized")
+	}
+
+	gGlobalsLock.Lock()
+	endpoint := gCfg.Endpoint
+	accessKey := gCfg.AccessKeyId
+	secretKey := gCfg.SecretAccessKey
+	bucket := gCfg.Bucket
+	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
+	encKey := make([]byte, len(gEncKey))
+	copy(encKey, gEncKey)
+	gGlobalsLock.Unlock()
+	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
+
+	a, err := snapshots.UpdateSnapshotAnnotations(ctx, objst, bucket, encKey, backupName, snapshotName, updateFunc)
+	if err != nil {
+		return errResponse(fmt.Sprintf("error: %s: %v", rpcName, err))
+	}
+
+	return &pb.SnapshotAnnotationsResponse{
+		DidSucceed: true,
+		ErrMsg:     "",
+		Tags:       a.Tags,
+		IsPinned:   a.Pinned,
+	}
+}
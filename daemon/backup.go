@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/snapshots"
@@ -103,8 +104,13 @@ func Backup(vlog *util.VLog, completion func()) {
 	// Make sure we have the latest bucket metadata in case user just wiped the bucket.
 	gGlobalsLock.Lock()
 	masterPassword := gCfg.MasterPassword
+	privateKeyPassphrase := gCfg.PrivateKeyPassphrase
 	gGlobalsLock.Unlock()
 	salt, _, encKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, masterPassword, vlog)
+	var sealKeys *cryptography.SealKeys
+	if err == nil {
+		sealKeys, err = objst.GetSealKeys(ctx, bucket, privateKeyPassphrase)
+	}
 	if err != nil || len(salt) == 0 {
 		msg := fmt.Sprintf("error: could not read or initialize bucket metadata: %v", err)
 		log.Println(msg)
@@ -121,14 +127,20 @@ func Backup(vlog *util.VLog, completion func()) {
 	gCfg.Salt = salt
 	gEncKey = encKey
 	gHmacKey = hmacKey
+	gSealKeys = sealKeys
 	gGlobalsLock.Unlock()
 
-	// Get a copy of the encryption key
+	// Get a copy of the encryption and hmac keys
 	encKey = make([]byte, 32)
+	hmacKey = make([]byte, 32)
 	gGlobalsLock.Lock()
 	copy(encKey, gEncKey)
+	copy(hmacKey, gHmacKey)
 	gGlobalsLock.Unlock()
 
+	// Make sure no snapshots have vanished and the bucket has not been rolled back
+	checkManifest(ctx, objst, bucket, encKey, hmacKey, true)
+
 	// Now start backing up
 	stats := backup.NewBackupStats()
 	gGlobalsLock.Lock()
@@ -178,22 +190,71 @@ func Backup(vlog *util.VLog, completion func()) {
 
 		// Set up backup cancelation closure capturing locks from here
 		checkAndHandleBackupCancelationFunc := func(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string, backupDirPath string, snapshotName string) bool {
-			return checkAndHandleCancelation(ctx, key, objst, bucket, &gDbLock, gDb, &gGlobalsLock, backupDirPath, snapshotName)
+			return checkAndHandleCancelation(ctx, key, hmacKey, sealKeys, objst, bucket, &gDbLock, gDb, &gGlobalsLock, backupDirPath, snapshotName)
+		}
+
+		// Run the pre_backup hook, skipping this dir if it fails
+		util.LockIf(&gGlobalsLock)
+		hooks := gCfg.Hooks.HooksForBackup(backupDirName).AsUser(gRunAs)
+		util.UnlockIf(&gGlobalsLock)
+		hookCtx, hookDone := cancelableHookContext(ctx)
+		hookEvents, err := backup.RunPreBackupHook(hookCtx, hooks, backupDirPath, vlog)
+		hookCanceled := hookCtx.Err() != nil
+		hookDone()
+		if err != nil && hookCanceled {
+			// Canceled while the hook was running
+			util.LockIf(&gGlobalsLock)
+			gCancelRequested = false
+			util.UnlockIf(&gGlobalsLock)
+			backupEndedInCancelation = true
+			break
+		} else if err != nil {
+			backupEndedInError = true
+			gGlobalsLock.Lock()
+			gStatus.reportedEvents = append(gStatus.reportedEvents, hookEvents...)
+			gGlobalsLock.Unlock()
+			continue
 		}
 
 		// Traverse the FS for changed files and do the journaled backup
 		util.LockIf(&gGlobalsLock)
 		resourceUtilization := gCfg.ResourceUtilization
+		padding, _ := cryptography.ParsePadding(gCfg.Padding)
 		util.UnlockIf(&gGlobalsLock)
-		backupReportedEvents, breakFromLoop, continueLoop, fatalError := backup.DoJournaledBackup(ctx, encKey, objst, bucket, &gDbLock, gDb, backupDirPath, excludes, vlog, checkAndHandleTraversalCancelation, checkAndHandleBackupCancelationFunc, setBackupInitialProgressFunc, updateBackupProgressFunc, stats, resourceUtilization)
+		dirStats := backup.NewBackupStats()
+		backupReportedEvents, breakFromLoop, continueLoop, fatalError := backup.DoJournaledBackup(ctx, encKey, hmacKey, sealKeys, objst, bucket, &gDbLock, gDb, backupDirPath, excludes, false, vlog, checkAndHandleTraversalCancelation, checkAndHandleBackupCancelationFunc, setBackupInitialProgressFunc, updateBackupProgressFunc, dirStats, resourceUtilization, padding)
+		stats.Add(dirStats)
+		errorCount := int64(0)
 		for _, e := range backupReportedEvents {
 			if e.Kind == util.ERR_OP_NOT_PERMITTED {
 				backupEndedInError = true
+				errorCount += 1
 			}
 			gGlobalsLock.Lock()
 			gStatus.reportedEvents = append(gStatus.reportedEvents, e)
 			gGlobalsLock.Unlock()
 		}
+		if fatalError {
+			errorCount += 1
+		}
+
+		// Run the post_backup hook and then on_success or on_failure
+		result := backup.HookResultSuccess
+		if errorCount+dirStats.Errors() > 0 {
+			result = backup.HookResultFailure
+		} else if breakFromLoop {
+			result = backup.HookResultCanceled
+		}
+		hookCtx, hookDone = cancelableHookContext(ctx)
+		hookEvents = backup.RunPostBackupHooks(hookCtx, hooks, backupDirPath, dirStats, errorCount, result, vlog)
+		hookDone()
+		if len(hookEvents) > 0 {
+			backupEndedInError = true
+			gGlobalsLock.Lock()
+			gStatus.reportedEvents = append(gStatus.reportedEvents, hookEvents...)
+			gGlobalsLock.Unlock()
+		}
+
 		if fatalError {
 			backupEndedInError = true
 			goto done
@@ -248,6 +309,9 @@ func Backup(vlog *util.VLog, completion func()) {
 	}
 
 done:
+	// Remember the manifest version our snapshots brought the bucket to
+	checkManifest(ctx, objst, bucket, encKey, hmacKey, false)
+
 	// On finished, log the new total space usage
 	persistUsage(true, true, vlog)
 
@@ -260,6 +324,31 @@ done:
 	gGlobalsLock.Unlock()
 }
 
+// Verifies the bucket's snapshot manifest and records its version, reporting any sign of snapshots
+// having been deleted or the bucket rolled back behind our back if report is set
+func checkManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, encKey []byte, hmacKey []byte, report bool) {
+	problems, err := snapshots.CheckManifest(ctx, objst, bucket, encKey, hmacKey, &gDbLock, gDb)
+	if err != nil {
+		log.Println("error: checkManifest: ", err)
+		return
+	}
+	if !report {
+		return
+	}
+	for _, problem := range problems {
+		log.Println("warning: snapshot manifest: ", problem)
+		gGlobalsLock.Lock()
+		gStatus.reportedEvents = append(gStatus.reportedEvents, util.ReportedEvent{
+			Kind:     util.ERR_MANIFEST_MISMATCH,
+			Path:     "",
+			IsDir:    false,
+			Datetime: time.Now().Unix(),
+			Msg:      problem,
+		})
+		gGlobalsLock.Unlock()
+	}
+}
+
 func replayBackupJournal() {
 	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
 
@@ -283,10 +372,13 @@ func replayBackupJournal() {
 		return
 	}
 
-	// Get a copy of the encryption encKey
+	// Get a copy of the encryption encKey and hmacKey
 	encKey := make([]byte, 32)
+	hmacKey := make([]byte, 32)
 	gGlobalsLock.Lock()
 	copy(encKey, gEncKey)
+	copy(hmacKey, gHmacKey)
+	sealKeys := gSealKeys
 	gGlobalsLock.Unlock()
 
 	// Setup replay initial progress closure capturing locks from here
@@ -311,14 +403,15 @@ func replayBackupJournal() {
 
 	// Set up cancelation closure capturing locks from here
 	checkAndHandleReplayCancelationFunc := func(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string, backupDirPath string, snapshotName string) bool {
-		return checkAndHandleCancelation(ctx, key, objst, bucket, &gDbLock, gDb, &gGlobalsLock, backupDirPath, snapshotName)
+		return checkAndHandleCancelation(ctx, key, hmacKey, sealKeys, objst, bucket, &gDbLock, gDb, &gGlobalsLock, backupDirPath, snapshotName)
 	}
 
 	// Replay the journal
 	gGlobalsLock.Lock()
 	resourceUtilization := gCfg.ResourceUtilization
+	padding, _ := cryptography.ParsePadding(gCfg.Padding)
 	gGlobalsLock.Unlock()
-	re := backup.ReplayBackupJournal(ctx, encKey, objst, bucket, &gDbLock, gDb, vlog, setReplayInitialProgressFunc, checkAndHandleReplayCancelationFunc, updateBackupProgressFunc, resourceUtilization)
+	re := backup.ReplayBackupJournal(ctx, encKey, hmacKey, sealKeys, objst, bucket, &gDbLock, gDb, vlog, setReplayInitialProgressFunc, checkAndHandleReplayCancelationFunc, updateBackupProgressFunc, resourceUtilization, padding)
 	gGlobalsLock.Lock()
 	gStatus.reportedEvents = append(gStatus.reportedEvents, re)
 	gGlobalsLock.Unlock()
@@ -333,12 +426,12 @@ func replayBackupJournal() {
 	gGlobalsLock.Unlock()
 }
 
-func checkAndHandleCancelation(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, globalsLock *sync.Mutex, backupDirPath string, snapshotName string) bool {
+func checkAndHandleCancelation(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, globalsLock *sync.Mutex, backupDirPath string, snapshotName string) bool {
 	util.LockIf(globalsLock)
 	isCancelRequested := gCancelRequested
 	util.UnlockIf(globalsLock)
 	if isCancelRequested {
-		cancelBackup(ctx, key, dbLock, db, globalsLock, backupDirPath, snapshotName, objst, bucket)
+		cancelBackup(ctx, key, hmacKey, sealKeys, dbLock, db, globalsLock, backupDirPath, snapshotName, objst, bucket)
 		util.LockIf(globalsLock)
 		gCancelRequested = false
 		util.UnlockIf(globalsLock)
@@ -347,7 +440,7 @@ func checkAndHandleCancelation(ctx context.Context, key []byte, objst *objstore.
 	return false
 }
 
-func cancelBackup(ctx context.Context, key []byte, dbLock *sync.Mutex, db *database.DB, globalsLock *sync.Mutex, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string) {
+func cancelBackup(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, dbLock *sync.Mutex, db *database.DB, globalsLock *sync.Mutex, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string) {
 	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
 
 	vlog.Printf("CANCEL: Starting unwind")
@@ -365,13 +458,13 @@ func cancelBackup(ctx context.Context, key []byte, dbLock *sync.Mutex, db *datab
 		BackupDirName: filepath.Base(backupDirPath),
 		SnapshotName:  snapshotName,
 	}
-	err := snapshots.DeleteSnapshots(ctx, key, []snapshots.SnapshotForDeletion{ssDel}, objst, bucket, vlog, nil, nil)
+	err := snapshots.DeleteSnapshots(ctx, key, hmacKey, sealKeys, []snapshots.SnapshotForDeletion{ssDel}, objst, bucket, false, vlog, nil, nil)
 	if err != nil {
 		// This is ok and just means snapshot index file wasn't writetn to cloud yet
 		log.Printf("warning: cancelBackup: could not delete partially created snapshot's index (probably doesn't exist yet): %v", err)
 
 		// Garbage collect any orphaned chunks that were written while creating unused snapshot index file
-		if err = snapshots.GCChunks(ctx, objst, bucket, key, vlog, nil, nil); err != nil {
+		if err = snapshots.GCChunks(ctx, objst, bucket, key, sealKeys, vlog, nil, nil); err != nil {
 			log.Println("error: handleReplay: could not garbage collect chunks: ", err)
 		}
 	}
@@ -399,12 +492,33 @@ func cancelBackup(ctx context.Context, key []byte, dbLock *sync.Mutex, db *datab
 	// (When we return, status will be set back to Idle)
 }
 
+// Returns a context for running a backup hook that CancelBackup cancels, so that canceling the
+// backup also stops the hook. Call the returned func once the hook has finished.
+func cancelableHookContext(ctx context.Context) (context.Context, func()) {
+	hookCtx, cancel := context.WithCancel(ctx)
+	gGlobalsLock.Lock()
+	if gCancelRequested {
+		cancel()
+	}
+	gCancelHook = cancel
+	gGlobalsLock.Unlock()
+	return hookCtx, func() {
+		gGlobalsLock.Lock()
+		gCancelHook = nil
+		gGlobalsLock.Unlock()
+		cancel()
+	}
+}
+
 func (s *server) CancelBackup(ctx context.Context, in *pb.CancelRequest) (*pb.CancelResponse, error) {
 	log.Println(">> GOT COMMAND: CancelBackup")
 
 	gGlobalsLock.Lock()
 	gCancelRequested = true
 	gStatus.msg = "Canceling..."
+	if gCancelHook != nil {
+		gCancelHook()
+	}
 	gGlobalsLock.Unlock()
 
 	return &pb.CancelResponse{
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelBackupStopsHook(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	t.Cleanup(func() {
+		gGlobalsLock.Lock()
+		gCancelRequested = false
+		gGlobalsLock.Unlock()
+	})
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = (&server{}).CancelBackup(context.Background(), &pb.CancelRequest{})
+	}()
+	hookCtx, hookDone := cancelableHookContext(context.Background())
+	start := time.Now()
+	err := util.Hooks{PreBackup: "sleep 10"}.Run(hookCtx, "pre_backup", nil, vlog)
+	hookDone()
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// A hook started after the cancel request doesn't run at all
+	hookCtx, hookDone = cancelableHookContext(context.Background())
+	assert.Error(t, hookCtx.Err())
+	hookDone()
+}
@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+)
+
+func (s *server) Check(in *pb.CheckRequest, srv pb.DaemonCtl_CheckServer) error {
+	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
+
+	sendPartialFunc := func(didSucceed bool, percentDone float64, errMsg string) {
+		resp := pb.CheckResponse{
+			DidSucceed:  didSucceed,
+			PercentDone: percentDone,
+			ErrMsg:      errMsg,
+		}
+		if err := srv.Send(&resp); err != nil {
+			log.Println("error: server.Send failed: ", err)
+		}
+	}
+
+	log.Println(">> GOT COMMAND: Check")
+	defer log.Println(">> COMPLETED COMMAND: Check")
+
+	if in.ReadDataPercent < 0 || in.ReadDataPercent > 100 {
+		sendPartialFunc(false, float64(0), fmt.Sprintf("invalid read data percentage %.2f", in.ReadDataPercent))
+		return nil
+	}
+
+	gGlobalsLock.Lock()
+	isIdle := gStatus.state == Idle
+	gGlobalsLock.Unlock()
+	if !isIdle {
+		log.Println("CHECK> Not in Idle state, cannot check right now")
+		sendPartialFunc(false, float64(0), "not in Idle state")
+		return nil
+	}
+
+	gGlobalsLock.Lock()
+	gStatus.state = Checking
+	gStatus.msg = "Checking cloud data"
+	gStatus.percentage = 0.0
+	gGlobalsLock.Unlock()
+
+	// Sets status back to Idle when routine is done
+	defer func() {
+		lastBackupTimeFormatted := getLastBackupTimeFormatted(&gDbLock)
+		gGlobalsLock.Lock()
+		gStatus.state = Idle
+		gStatus.msg = "Last backup: " + lastBackupTimeFormatted
+		gStatus.percentage = -1.0
+		gGlobalsLock.Unlock()
+	}()
+
+	ctx := context.Background()
+	gGlobalsLock.Lock()
+	endpoint := gCfg.Endpoint
+	accessKey := gCfg.AccessKeyId
+	secretKey := gCfg.SecretAccessKey
+	bucket := gCfg.Bucket
+	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
+	encKey := make([]byte, len(gEncKey))
+	copy(encKey, gEncKey)
+	sealKeys := gSealKeys
+	hmacKey := make([]byte, len(gHmacKey))
+	copy(hmacKey, gHmacKey)
+	gGlobalsLock.Unlock()
+	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
+
+	progressFunc := func(percentDone float64) {
+		gGlobalsLock.Lock()
+		gStatus.percentage = float32(percentDone)
+		gGlobalsLock.Unlock()
+
+		sendPartialFunc(true, percentDone, "")
+	}
+	result, err := backup.CheckRepository(ctx, encKey, hmacKey, sealKeys, objst, bucket, in.ReadDataPercent, vlog, progressFunc)
+	if err != nil {
+		msg := fmt.Sprintf("error: Check: CheckRepository failed: %v", err)
+		log.Println(msg)
+		sendPartialFunc(false, float64(0), msg)
+		return nil
+	}
+	vlog.Printf("CHECK> Found %d problems and %d orphan chunks", len(result.Problems), len(result.OrphanChunks))
+
+	// Persist the bandwidth used downloading chunks
+	persistUsage(false, true, vlog)
+
+	toPbProblems := func(problems []backup.CheckProblem) []*pb.CheckProblem {
+		pbProblems := make([]*pb.CheckProblem, 0, len(problems))
+		for _, p := range problems {
+			pbProblems = append(pbProblems, &pb.CheckProblem{
+				Kind:      pb.CheckProblem_CheckProblemKind(p.Kind),
+				Snapshot:  p.Snapshot,
+				RelPath:   p.RelPath,
+				ChunkName: p.ChunkName,
+				Msg:       p.Msg,
+			})
+		}
+		return pbProblems
+	}
+	resp := pb.CheckResponse{
+		DidSucceed:       true,
+		PercentDone:      float64(100),
+		ErrMsg:           "",
+		IsFinal:          true,
+		SnapshotsChecked: int64(result.SnapshotsChecked),
+		ChunksReferenced: int64(result.ChunksReferenced),
+		ChunksInBucket:   int64(result.ChunksInBucket),
+		ChunksRead:       int64(result.ChunksRead),
+		BytesRead:        result.BytesRead,
+		Problems:         toPbProblems(result.Problems),
+		OrphanChunks:     toPbProblems(result.OrphanChunks),
+	}
+	if err := srv.Send(&resp); err != nil {
+		log.Println("error: server.Send failed: ", err)
+	}
+
+	return nil
+}
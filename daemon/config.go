@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,8 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/util"
 	pb "github.com/fsctl/tless/rpc"
@@ -23,12 +26,11 @@ var (
 	gUserHomeDir string
 	gEncKey      []byte
 	gHmacKey     []byte
+	gSealKeys    *cryptography.SealKeys // non-nil if the bucket is in write-only mode
+	gRunAs       *util.RunAsUser        // user to run commands from the config as, if the daemon runs as root
 )
 
 func initConfig(globalsLock *sync.Mutex) error {
-	// hmacKey is presently unused but may be in the future
-	_ = gHmacKey
-
 	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
 
 	globalsLock.Lock()
@@ -65,12 +67,84 @@ func initConfig(globalsLock *sync.Mutex) error {
 		Bucket:               viper.GetString("objectstore.bucket"),
 		TrustSelfSignedCerts: viper.GetBool("objectstore.trust_self_signed_certs"),
 		MasterPassword:       viper.GetString("backups.master_password"),
+		PrivateKeyPassphrase: viper.GetString("backups.private_key_passphrase"),
 		Dirs:                 viper.GetStringSlice("backups.dirs"),
 		ExcludePaths:         viper.GetStringSlice("backups.excludes"),
+		Padding:              viper.GetString("backups.padding"),
 		VerboseDaemon:        viper.GetBool("daemon.verbose"),
 		CachesPath:           viper.GetString("system.caches_path"),
 		MaxChunkCacheMb:      viper.GetInt64("system.max_chunk_cache_mb"),
 		ResourceUtilization:  viper.GetString("system.system_resource_utilization"),
+
+		SecretAccessKeyCommand: viper.GetString("objectstore.access_secret_command"),
+		SecretAccessKeyFile:    viper.GetString("objectstore.access_secret_file"),
+		MasterPasswordCommand:  viper.GetString("backups.master_password_command"),
+		MasterPasswordFile:     viper.GetString("backups.master_password_file"),
+	}
+	if err := viper.UnmarshalKey("retention", &gCfg.Retention); err != nil {
+		log.Printf("error: could not read [retention] section of config: %v", err)
+	}
+	hooksErr := viper.UnmarshalKey("hooks", &gCfg.Hooks)
+	if gCfg.Bucket == "" {
+		gCfg.Bucket = objstore.BucketFromEndpoint(gCfg.Endpoint)
+	}
+	padding := gCfg.Padding
+	hooks := gCfg.Hooks
+	secretAccessKeySources := util.AccessSecretSources(gCfg.SecretAccessKeyCommand, gCfg.SecretAccessKeyFile)
+	masterPasswordSources := util.MasterPasswordSources(gCfg.MasterPasswordCommand, gCfg.MasterPasswordFile)
+	globalsLock.Unlock()
+	if _, err := cryptography.ParsePadding(padding); err != nil {
+		e := fmt.Errorf("error: invalid padding '%s' in config: %v", padding, err)
+		vlog.Println(e.Error())
+		return e
+	}
+	// The config file belongs to the console user, so the commands and secret files it names must
+	// not be run and read as root
+	runAs, err := util.RunAsUserIfRoot(username)
+	if err != nil {
+		e := fmt.Errorf("error: cannot run commands from the config as user '%s': %v", username, err)
+		vlog.Println(e.Error())
+		return e
+	}
+	globalsLock.Lock()
+	gRunAs = runAs
+	globalsLock.Unlock()
+	secretAccessKeySources.RunAs = runAs
+	masterPasswordSources.RunAs = runAs
+	if hooksErr != nil {
+		e := fmt.Errorf("error: could not read [hooks] section of config: %v", hooksErr)
+		vlog.Println(e.Error())
+		return e
+	}
+	if err := hooks.Validate(); err != nil {
+		e := fmt.Errorf("error: invalid hooks in config: %v", err)
+		vlog.Println(e.Error())
+		return e
+	}
+
+	// Read secrets kept outside the config file
+	resolvedSecretAccessKey, secretAccessKeySource, err := secretAccessKeySources.Resolve()
+	if err != nil {
+		e := fmt.Errorf("error: could not read access secret: %v", err)
+		vlog.Println(e.Error())
+		return e
+	}
+	resolvedMasterPassword, masterPasswordSource, err := masterPasswordSources.Resolve()
+	if err != nil {
+		e := fmt.Errorf("error: could not read master password: %v", err)
+		vlog.Println(e.Error())
+		return e
+	}
+	globalsLock.Lock()
+	if secretAccessKeySource != "" {
+		vlog.Printf("Using access secret from %s", secretAccessKeySource)
+		gCfg.SecretAccessKey = resolvedSecretAccessKey
+		gCfg.SecretAccessKeySource = secretAccessKeySource
+	}
+	if masterPasswordSource != "" {
+		vlog.Printf("Using master password from %s", masterPasswordSource)
+		gCfg.MasterPassword = resolvedMasterPassword
+		gCfg.MasterPasswordSource = masterPasswordSource
 	}
 	globalsLock.Unlock()
 
@@ -92,6 +166,7 @@ func initConfig(globalsLock *sync.Mutex) error {
 	// Grab the master password
 	globalsLock.Lock()
 	masterPassword := gCfg.MasterPassword
+	privateKeyPassphrase := gCfg.PrivateKeyPassphrase
 	globalsLock.Unlock()
 
 	// Download (or create) the metadata
@@ -118,13 +193,50 @@ func initConfig(globalsLock *sync.Mutex) error {
 		return err
 	}
 
+	// Refuse to use the bucket until an unfinished key rotation is completed with RotateKey
+	isRotatingKey, err := objst.IsKeyRotationInProgress(ctx, bucket)
+	if err != nil {
+		vlog.Println(err.Error())
+		return err
+	}
+	if isRotatingKey {
+		globalsLock.Lock()
+		gCfg.Salt = salt
+		gEncKey = nil
+		gHmacKey = nil
+		gSealKeys = nil
+		globalsLock.Unlock()
+		vlog.Println(objstore.ErrKeyRotationInProgress.Error())
+		return objstore.ErrKeyRotationInProgress
+	}
+
+	// In write-only mode, the private key is only unlocked if its passphrase is in the config file
+	sealKeys, err := objst.GetSealKeys(ctx, bucket, privateKeyPassphrase)
+	if err != nil {
+		vlog.Println(err.Error())
+		return err
+	}
+
 	// Store keys in global
 	globalsLock.Lock()
 	gCfg.Salt = salt
 	gEncKey = encKey
 	gHmacKey = hmacKey
+	gSealKeys = sealKeys
 	globalsLock.Unlock()
 
+	// Now that we have the keys, the sensitive parts of the local database can be read and written
+	gDbLock.Lock()
+	if gDb != nil {
+		err = gDb.UnlockState(hmacKey, vlog)
+	}
+	gDbLock.Unlock()
+	if errors.Is(err, database.ErrStateKeyMismatch) {
+		return fmt.Errorf("error: cannot unlock local state: %v (was the bucket changed? if so, run 'tless backup --reset-local-state' to discard it)", err)
+	} else if err != nil {
+		return fmt.Errorf("error: cannot unlock local state: %v", err)
+	}
+
 	return nil
 }
 
@@ -183,15 +295,24 @@ func (s *server) ReadDaemonConfig(ctx context.Context, in *pb.ReadConfigRequest)
 	} else {
 		log.Println("Returning all config file settings")
 		gGlobalsLock.Lock()
+		// Secrets read from outside the config file are never sent to clients
+		secretKey := gCfg.SecretAccessKey
+		if gCfg.SecretAccessKeySource != "" {
+			secretKey = ""
+		}
+		masterPassword := gCfg.MasterPassword
+		if gCfg.MasterPasswordSource != "" {
+			masterPassword = ""
+		}
 		resp := &pb.ReadConfigResponse{
 			IsValid:              true,
 			ErrMsg:               "",
 			Endpoint:             gCfg.Endpoint,
 			AccessKey:            gCfg.AccessKeyId,
-			SecretKey:            gCfg.SecretAccessKey,
+			SecretKey:            secretKey,
 			BucketName:           gCfg.Bucket,
 			TrustSelfSignedCerts: gCfg.TrustSelfSignedCerts,
-			MasterPassword:       gCfg.MasterPassword,
+			MasterPassword:       masterPassword,
 			Salt:                 gCfg.Salt,
 			Dirs:                 gCfg.Dirs,
 			Excludes:             gCfg.ExcludePaths,
@@ -243,6 +364,17 @@ func (s *server) WriteToDaemonConfig(ctx context.Context, in *pb.WriteConfigRequ
 	}
 
 	gGlobalsLock.Lock()
+	configToWrite.Retention = gCfg.Retention // not editable over RPC, so keep what's in the file
+	configToWrite.Hooks = gCfg.Hooks
+	configToWrite.PrivateKeyPassphrase = gCfg.PrivateKeyPassphrase
+	configToWrite.Padding = gCfg.Padding
+	// Secrets read from outside the config file keep coming from there, and are not written to it
+	configToWrite.SecretAccessKeyCommand = gCfg.SecretAccessKeyCommand
+	configToWrite.SecretAccessKeyFile = gCfg.SecretAccessKeyFile
+	configToWrite.SecretAccessKeySource = gCfg.SecretAccessKeySource
+	configToWrite.MasterPasswordCommand = gCfg.MasterPasswordCommand
+	configToWrite.MasterPasswordFile = gCfg.MasterPasswordFile
+	configToWrite.MasterPasswordSource = gCfg.MasterPasswordSource
 	username := gUsername
 	userHomeDir := gUserHomeDir
 	gGlobalsLock.Unlock()
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+)
+
+// Callback for rpc.DaemonCtlServer.Diff requests
+func (s *server) Diff(in *pb.DiffRequest, srv pb.DaemonCtl_DiffServer) error {
+	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
+
+	log.Printf(">> GOT COMMAND: Diff (for '%s'/'%s' to '%s')", in.BackupName, in.SnapshotNameA, in.SnapshotNameB)
+	defer log.Println(">> COMPLETED COMMAND: Diff")
+
+	sendErrFunc := func(msg string) {
+		log.Println(msg)
+		resp := pb.DiffResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+			IsFinal:    true,
+		}
+		if err := srv.Send(&resp); err != nil {
+			log.Println("error: server.Send failed: ", err)
+		}
+	}
+
+	// Make sure arguments are non-blank as expected
+	if in.BackupName == "" || in.SnapshotNameA == "" {
+		sendErrFunc(fmt.Sprintf("error: Diff: received blank argument(s): in.BackupName='%s', in.SnapshotNameA='%s'", in.BackupName, in.SnapshotNameA))
+		return nil
+	}
+
+	// Make sure the global config we need is initialized
+	gGlobalsLock.Lock()
+	isGlobalConfigReady := gCfg != nil && gEncKey != nil
+	gGlobalsLock.Unlock()
+	if !isGlobalConfigReady {
+		sendErrFunc("global config not yet initialized")
+		return nil
+	}
+
+	ctx := context.Background()
+	gGlobalsLock.Lock()
+	endpoint := gCfg.Endpoint
+	accessKey := gCfg.AccessKeyId
+	secretKey := gCfg.SecretAccessKey
+	bucket := gCfg.Bucket
+	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
+	cachesPath := gCfg.CachesPath
+	maxChunkCacheMb := gCfg.MaxChunkCacheMb
+	dirs := gCfg.Dirs
+	excludes := gCfg.ExcludePaths
+	encKey := make([]byte, len(gEncKey))
+	copy(encKey, gEncKey)
+	sealKeys := gSealKeys
+	hmacKey := make([]byte, len(gHmacKey))
+	copy(hmacKey, gHmacKey)
+	username := gUsername
+	gGlobalsLock.Unlock()
+	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
+
+	uid, gid, err := util.GetUidGid(username)
+	if err != nil {
+		log.Printf("error: cannot get user'%s's UID/GID: %v", username, err)
+	}
+	cc := backup.NewChunkCache(objst, encKey, sealKeys, vlog, uid, gid, cachesPath, maxChunkCacheMb)
+
+	snapshotA, err := snapshots.GetSnapshot(ctx, objst, bucket, encKey, sealKeys, in.BackupName, in.SnapshotNameA)
+	if err != nil {
+		sendErrFunc(fmt.Sprintf("error: Diff: could not get snapshot '%s/%s': %v", in.BackupName, in.SnapshotNameA, err))
+		return nil
+	}
+
+	// Comparing is the first 90% of the work and sending the results the last 10%
+	progressFunc := func(percentDone float64) {
+		resp := pb.DiffResponse{
+			DidSucceed:  true,
+			PercentDone: percentDone * 0.9,
+		}
+		if err := srv.Send(&resp); err != nil {
+			log.Println("error: server.Send failed: ", err)
+		}
+	}
+
+	var entries []backup.DiffEntry
+	if in.SnapshotNameB != "" {
+		snapshotB, err := snapshots.GetSnapshot(ctx, objst, bucket, encKey, sealKeys, in.BackupName, in.SnapshotNameB)
+		if err != nil {
+			sendErrFunc(fmt.Sprintf("error: Diff: could not get snapshot '%s/%s': %v", in.BackupName, in.SnapshotNameB, err))
+			return nil
+		}
+		entries, err = backup.DiffSnapshots(ctx, cc, bucket, snapshotA, snapshotB, progressFunc)
+		if err != nil {
+			sendErrFunc(fmt.Sprintf("error: Diff: DiffSnapshots failed: %v", err))
+			return nil
+		}
+	} else {
+		backupDirPath := ""
+		for _, dir := range dirs {
+			if filepath.Base(util.StripTrailingSlashes(dir)) == in.BackupName {
+				backupDirPath = dir
+			}
+		}
+		if backupDirPath == "" {
+			sendErrFunc(fmt.Sprintf("error: Diff: no directory named '%s' is configured for backup", in.BackupName))
+			return nil
+		}
+		entries, err = backup.DiffSnapshotAgainstLive(ctx, cc, hmacKey, bucket, snapshotA, backupDirPath, excludes, vlog, progressFunc)
+		if err != nil {
+			sendErrFunc(fmt.Sprintf("error: Diff: DiffSnapshotAgainstLive failed: %v", err))
+			return nil
+		}
+	}
+	vlog.Printf("DIFF> Found %d differences", len(entries))
+
+	// Persist the bandwidth used downloading metadata
+	persistUsage(false, true, vlog)
+
+	// Send entries back one chunk at a time
+	pbPartialResponse := &pb.DiffResponse{
+		DidSucceed: true,
+		Entries:    make([]*pb.DiffEntry, 0),
+	}
+	for i, entry := range entries {
+		pbPartialResponse.Entries = append(pbPartialResponse.Entries, &pb.DiffEntry{
+			Kind:    pb.DiffEntry_DiffKind(entry.Kind),
+			RelPath: entry.RelPath,
+			Changes: entry.Changes,
+		})
+
+		if len(pbPartialResponse.Entries) >= SendPartialResponseEveryNRelPaths {
+			pbPartialResponse.PercentDone = float64(90) + float64(10)*float64(i+1)/float64(len(entries))
+			if err := srv.Send(pbPartialResponse); err != nil {
+				log.Println("error: server.Send failed: ", err)
+			}
+			pbPartialResponse.Entries = make([]*pb.DiffEntry, 0)
+		}
+	}
+
+	// Send the final chunk, even if empty, so the client knows we're done
+	pbPartialResponse.PercentDone = float64(100)
+	pbPartialResponse.IsFinal = true
+	if err := srv.Send(pbPartialResponse); err != nil {
+		log.Println("error: server.Send failed: ", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+)
+
+// Returns an ObjStore for the configured bucket plus the bucket name, keys and master password,
+// or ok=false if the global config is not yet initialized
+func getKeySlotGlobals(ctx context.Context) (objst *objstore.ObjStore, bucket string, encKey []byte, hmacKey []byte, masterPassword string, ok bool) {
+	gGlobalsLock.Lock()
+	defer gGlobalsLock.Unlock()
+	if gCfg == nil || gEncKey == nil {
+		return nil, "", nil, nil, "", false
+	}
+
+	objst = objstore.NewObjStore(ctx, gCfg.Endpoint, gCfg.AccessKeyId, gCfg.SecretAccessKey, gCfg.TrustSelfSignedCerts)
+	encKey = make([]byte, len(gEncKey))
+	copy(encKey, gEncKey)
+	hmacKey = make([]byte, len(gHmacKey))
+	copy(hmacKey, gHmacKey)
+	return objst, gCfg.Bucket, encKey, hmacKey, gCfg.MasterPassword, true
+}
+
+// Callback for rpc.DaemonCtlServer.ListKeySlots requests
+func (s *server) ListKeySlots(ctx context.Context, in *pb.ListKeySlotsRequest) (*pb.ListKeySlotsResponse, error) {
+	log.Println(">> GOT COMMAND: ListKeySlots")
+	defer log.Println(">> COMPLETED COMMAND: ListKeySlots")
+
+	objst, bucket, encKey, _, _, ok := getKeySlotGlobals(ctx)
+	if !ok {
+		log.Println("error: ListKeySlots: global config not yet initialized")
+		return &pb.ListKeySlotsResponse{
+			DidSucceed: false,
+			ErrMsg:     "global config not yet initialized",
+		}, nil
+	}
+
+	infos, err := objst.ListKeySlots(ctx, bucket, encKey)
+	if err != nil {
+		msg := fmt.Sprintf("error: ListKeySlots: %v", err)
+		log.Println(msg)
+		return &pb.ListKeySlotsResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+		}, nil
+	}
+
+	pbKeySlots := make([]*pb.KeySlotInfo, 0, len(infos))
+	for _, info := range infos {
+		pbKeySlots = append(pbKeySlots, &pb.KeySlotInfo{
+			Name:          info.Name,
+			IsRecoveryKey: info.IsRecoveryKey,
+			CreatedUnix:   info.CreatedUnix,
+		})
+	}
+	return &pb.ListKeySlotsResponse{
+		DidSucceed: true,
+		ErrMsg:     "",
+		KeySlots:   pbKeySlots,
+	}, nil
+}
+
+// Callback for rpc.DaemonCtlServer.AddKeySlot requests
+func (s *server) AddKeySlot(ctx context.Context, in *pb.AddKeySlotRequest) (*pb.AddKeySlotResponse, error) {
+	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
+
+	log.Printf(">> GOT COMMAND: AddKeySlot (%s)", in.Name)
+	defer log.Println(">> COMPLETED COMMAND: AddKeySlot")
+
+	objst, bucket, _, hmacKey, masterPassword, ok := getKeySlotGlobals(ctx)
+	if !ok {
+		log.Println("error: AddKeySlot: global config not yet initialized")
+		return &pb.AddKeySlotResponse{
+			DidSucceed: false,
+			ErrMsg:     "global config not yet initialized",
+		}, nil
+	}
+
+	passphrase := in.Passphrase
+	recoveryKey := ""
+	if in.IsRecoveryKey {
+		recoveryKey = util.GenerateRecoveryKey()
+		passphrase = recoveryKey
+	}
+	kek, err := objst.GetKeyEncryptionKey(ctx, bucket, masterPassword, vlog)
+	if err != nil {
+		msg := fmt.Sprintf("error: AddKeySlot: %v", err)
+		log.Println(msg)
+		return &pb.AddKeySlotResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+		}, nil
+	}
+	if err := objst.AddKeySlot(ctx, bucket, kek, hmacKey, in.Name, passphrase, in.IsRecoveryKey, vlog); err != nil {
+		msg := fmt.Sprintf("error: AddKeySlot: %v", err)
+		log.Println(msg)
+		return &pb.AddKeySlotResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+		}, nil
+	}
+
+	return &pb.AddKeySlotResponse{
+		DidSucceed:  true,
+		ErrMsg:      "",
+		RecoveryKey: recoveryKey,
+	}, nil
+}
+
+// Callback for rpc.DaemonCtlServer.RevokeKeySlot requests
+func (s *server) RevokeKeySlot(ctx context.Context, in *pb.RevokeKeySlotRequest) (*pb.RevokeKeySlotResponse, error) {
+	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
+
+	log.Printf(">> GOT COMMAND: RevokeKeySlot (%s)", in.Name)
+	defer log.Println(">> COMPLETED COMMAND: RevokeKeySlot")
+
+	objst, bucket, _, _, masterPassword, ok := getKeySlotGlobals(ctx)
+	if !ok {
+		log.Println("error: RevokeKeySlot: global config not yet initialized")
+		return &pb.RevokeKeySlotResponse{
+			DidSucceed: false,
+			ErrMsg:     "global config not yet initialized",
+		}, nil
+	}
+
+	if err := objst.RevokeKeySlot(ctx, bucket, masterPassword, in.Name, vlog); err != nil {
+		msg := fmt.Sprintf("error: RevokeKeySlot: %v", err)
+		log.Println(msg)
+		return &pb.RevokeKeySlotResponse{
+			DidSucceed: false,
+			ErrMsg:     msg,
+		}, nil
+	}
+
+	return &pb.RevokeKeySlotResponse{
+		DidSucceed: true,
+		ErrMsg:     "",
+	}, nil
+}
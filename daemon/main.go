@@ -47,6 +47,7 @@ var (
 var (
 	gGlobalsLock     sync.Mutex
 	gCancelRequested bool
+	gCancelHook      context.CancelFunc // non-nil while a backup hook is running
 )
 
 // Protected by gDbLock
@@ -275,17 +276,17 @@ func persistUsage(doSpaceUsage bool, doBandwidthUsage bool, vlog *util.VLog) {
 		copy(encKey, gEncKey)
 		gGlobalsLock.Unlock()
 
-		cloudSizeUsageBytes, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, bucket, encKey, vlog)
+		cloudSizeUsageBytes, paddingUsageBytes, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, bucket, encKey, &gDbLock, gDb, vlog)
 		if err != nil {
 			log.Println("error: persistUsage: ComputeTotalCloudSpaceUsage failed: ", err)
 		} else {
 			util.LockIf(&gDbLock)
-			err = gDb.AddSpaceUsageReport(time.Now().Unix(), cloudSizeUsageBytes)
+			err = gDb.AddSpaceUsageReport(time.Now().Unix(), cloudSizeUsageBytes, paddingUsageBytes)
 			util.UnlockIf(&gDbLock)
 			if err != nil {
 				log.Println("error: persistUsage: AddSpaceUsageReport failed: ", err)
 			} else {
-				vlog.Printf("USAGE> persisted cloud space usage of %s", util.FormatBytesAsString(cloudSizeUsageBytes))
+				vlog.Printf("USAGE> persisted cloud space usage of %s (%s of padding)", util.FormatBytesAsString(cloudSizeUsageBytes), util.FormatBytesAsString(paddingUsageBytes))
 			}
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/util"
 	pb "github.com/fsctl/tless/rpc"
@@ -68,8 +69,18 @@ func (s *server) ChangePassword(ctx context.Context, in *pb.ChangePasswordReques
 		}, nil
 	}
 
-	// Change the password
-	if err = objst.ChangePassword(ctx, bucket, encKey, hmacKey, newPassword, vlog); err != nil {
+	// Change the password, upgrading the key derivation parameters if requested
+	var kdfParams *cryptography.KdfParams
+	if in.KdfTime != 0 {
+		if in.KdfThreads > 255 {
+			return &pb.ChangePasswordResponse{
+				DidSucceed: false,
+				ErrMsg:     cryptography.ErrorBadKdfParams.Error(),
+			}, nil
+		}
+		kdfParams = &cryptography.KdfParams{Time: in.KdfTime, MemoryKiB: in.KdfMemoryKiB, Threads: uint8(in.KdfThreads)}
+	}
+	if err = objst.ChangePassword(ctx, bucket, oldPassword, newPassword, kdfParams, vlog); err != nil {
 		log.Println("error: ChangePassword: objst.ChangePassword failed: ", err)
 		return &pb.ChangePasswordResponse{
 			DidSucceed: false,
@@ -101,8 +112,19 @@ func (s *server) ChangePassword(ctx context.Context, in *pb.ChangePasswordReques
 	gHmacKey = hmacKey
 	gGlobalsLock.Unlock()
 
+	// A master password read from outside the config file has to be changed there by the user
+	gGlobalsLock.Lock()
+	masterPasswordSource := gCfg.MasterPasswordSource
+	if masterPasswordSource != "" {
+		gCfg.MasterPassword = newPassword
+	}
+	gGlobalsLock.Unlock()
+	if masterPasswordSource != "" && in.UpdateConfigFile {
+		log.Printf("Not updating config file: the master password comes from %s, which must be updated to the new password", masterPasswordSource)
+	}
+
 	// Update config file if requested
-	if in.UpdateConfigFile {
+	if in.UpdateConfigFile && masterPasswordSource == "" {
 		vlog.Println("Overwriting old config file settings")
 
 		configToWrite := &util.CfgSettings{
@@ -112,12 +134,20 @@ func (s *server) ChangePassword(ctx context.Context, in *pb.ChangePasswordReques
 			Bucket:               gCfg.Bucket,
 			TrustSelfSignedCerts: gCfg.TrustSelfSignedCerts,
 			MasterPassword:       newPassword,
+			PrivateKeyPassphrase: gCfg.PrivateKeyPassphrase,
 			Dirs:                 gCfg.Dirs,
 			ExcludePaths:         gCfg.ExcludePaths,
+			Padding:              gCfg.Padding,
 			VerboseDaemon:        gCfg.VerboseDaemon,
 			CachesPath:           gCfg.CachesPath,
 			MaxChunkCacheMb:      gCfg.MaxChunkCacheMb,
 			ResourceUtilization:  gCfg.ResourceUtilization,
+			Retention:            gCfg.Retention,
+			Hooks:                gCfg.Hooks,
+
+			SecretAccessKeyCommand: gCfg.SecretAccessKeyCommand,
+			SecretAccessKeyFile:    gCfg.SecretAccessKeyFile,
+			SecretAccessKeySource:  gCfg.SecretAccessKeySource,
 		}
 
 		gGlobalsLock.Lock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/fsctl/tless/pkg/objstore"
@@ -40,6 +41,7 @@ func PruneSnapshots() error {
 
 	ctx := context.Background()
 	encKey := make([]byte, 32)
+	hmacKey := make([]byte, 32)
 	gGlobalsLock.Lock()
 	endpoint := gCfg.Endpoint
 	accessKey := gCfg.AccessKeyId
@@ -47,7 +49,17 @@ func PruneSnapshots() error {
 	bucket := gCfg.Bucket
 	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
 	copy(encKey, gEncKey)
+	copy(hmacKey, gHmacKey)
+	sealKeys := gSealKeys
+	retention := gCfg.Retention
 	gGlobalsLock.Unlock()
+
+	// Pruning has to read the indexes, which needs the private key in write-only mode
+	if sealKeys != nil && !sealKeys.CanOpen() {
+		log.Println("AUTOPRUNE> bucket is in write-only mode and no private key passphrase is configured, not pruning")
+		return nil
+	}
+
 	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
 
 	mSnapshots, err := snapshots.GetAllSnapshotInfos(ctx, encKey, objst, bucket)
@@ -59,7 +71,11 @@ func PruneSnapshots() error {
 	cntDeletedSnapshots := 0
 	for backupName := range mSnapshots {
 		// Mark what is to be kept
-		keeps := snapshots.GetPruneKeepsList(mSnapshots[backupName])
+		keeps, reasons, err := snapshots.GetRetentionKeepsList(mSnapshots[backupName], retention.PolicyForBackup(backupName))
+		if err != nil {
+			log.Printf("AUTOPRUNE> error: invalid retention policy for '%s', not pruning it: %v\n", backupName, err)
+			continue
+		}
 
 		for _, ss := range mSnapshots[backupName] {
 			keepCurr := false
@@ -76,13 +92,13 @@ func PruneSnapshots() error {
 					BackupDirName: backupName,
 					SnapshotName:  ss.Name,
 				}
-				if err = snapshots.DeleteSnapshots(ctx, encKey, []snapshots.SnapshotForDeletion{ssDel}, objst, bucket, vlog, nil, nil); err != nil {
+				if err = snapshots.DeleteSnapshots(ctx, encKey, hmacKey, sealKeys, []snapshots.SnapshotForDeletion{ssDel}, objst, bucket, false, vlog, nil, nil); err != nil {
 					log.Printf("AUTOPRUNE> error: could not delete snapshot '%s': %v\n", ss.RawSnapshotName, err)
 				} else {
 					cntDeletedSnapshots += 1
 				}
 			} else {
-				log.Printf("AUTOPRUNE> Keeping snapshot '%s'\n", ss.RawSnapshotName)
+				log.Printf("AUTOPRUNE> Keeping snapshot '%s' (%s)\n", ss.RawSnapshotName, strings.Join(reasons[ss.TimestampUnix], "; "))
 			}
 		}
 	}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"io"
 	"log"
-	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/fsctl/tless/pkg/backup"
 	"github.com/fsctl/tless/pkg/cryptography"
@@ -111,6 +111,7 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 	// open connection to cloud server
 	ctx := context.Background()
 	encKey := make([]byte, 32)
+	hmacKey := make([]byte, 32)
 	gGlobalsLock.Lock()
 	endpoint := gCfg.Endpoint
 	accessKey := gCfg.AccessKeyId
@@ -118,6 +119,8 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 	bucket := gCfg.Bucket
 	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
 	copy(encKey, gEncKey)
+	sealKeys := gSealKeys
+	copy(hmacKey, gHmacKey)
 	gGlobalsLock.Unlock()
 	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
 	if ok, err := objst.IsReachable(ctx, bucket, vlog); !ok {
@@ -163,7 +166,7 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 
 	// Get the snapshot index
 	encSsIndexObjName := encBackupName + "/@" + encSnapshotName
-	ssIndexJson, err := snapshots.GetSnapshotIndexFile(ctx, objst, bucket, encKey, encSsIndexObjName)
+	ssIndexJson, err := snapshots.GetSnapshotIndexFile(ctx, objst, bucket, encKey, sealKeys, encSsIndexObjName)
 	if err != nil {
 		log.Printf("error: cannot get snapshot index file for '%s/%s': %v", backupName, snapshotName, err)
 		done()
@@ -176,6 +179,34 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 		return
 	}
 
+	// Run the pre_restore hook, not restoring anything if it fails
+	gGlobalsLock.Lock()
+	hooks := gCfg.Hooks.HooksForBackup(backupName).AsUser(gRunAs)
+	gGlobalsLock.Unlock()
+	reportHookFailure := func(err error) {
+		log.Printf("error: %v", err)
+		gGlobalsLock.Lock()
+		gStatus.reportedEvents = append(gStatus.reportedEvents, util.ReportedEvent{
+			Kind:     util.ERR_HOOK_FAILED,
+			Path:     restorePath,
+			IsDir:    true,
+			Datetime: time.Now().Unix(),
+			Msg:      err.Error(),
+		})
+		gGlobalsLock.Unlock()
+	}
+	if err := backup.RunPreRestoreHook(ctx, hooks, backupName, snapshotName, restorePath, vlog); err != nil {
+		reportHookFailure(err)
+		done()
+		return
+	}
+	errorCount := int64(0)
+	runPostRestoreHook := func() {
+		if err := backup.RunPostRestoreHook(ctx, hooks, backupName, snapshotName, restorePath, errorCount, vlog); err != nil {
+			reportHookFailure(err)
+		}
+	}
+
 	// Filter the rel paths we want to restore
 	//vlog.Printf("RESTORE: selectedRelPaths='%v'", selectedRelPaths)
 	mRelPathsObjsMap := backup.FilterRelPaths(snapshotObj, selectedRelPaths, nil)
@@ -197,7 +228,7 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 	cachesPath := gCfg.CachesPath
 	maxChunkCacheMb := gCfg.MaxChunkCacheMb
 	gGlobalsLock.Unlock()
-	cc := backup.NewChunkCache(objst, encKey, vlog, uid, gid, cachesPath, maxChunkCacheMb)
+	cc := backup.NewChunkCache(objst, encKey, sealKeys, vlog, uid, gid, cachesPath, maxChunkCacheMb)
 
 	// For locality of reference reasons, we'll get the best cache hit rate if we restore in lexiconigraphical
 	// order of rel paths.
@@ -209,6 +240,7 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 
 	// loop over all the relpaths and restore each
 	dirChmodQueue := make([]backup.DirChmodQueueItem, 0) // all directory mode bits are set at end
+	hardLinks := make(map[string]string)
 	for _, relPath := range relPathKeys {
 		// Check if cancel signal has been received
 		util.LockIf(&gGlobalsLock)
@@ -219,15 +251,17 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 			gCancelRequested = false
 			util.UnlockIf(&gGlobalsLock)
 			vlog.Println("RESTORING: Canceled restore")
+			runPostRestoreHook()
 			done()
 			return
 		}
 
 		vlog.Printf("RESTORING: '%s' from %s/%s", relPath, backupName, snapshotName)
 
-		err = backup.RestoreDirEntry(ctx, encKey, restorePath, mRelPathsObjsMap[relPath], backupName, snapshotName, relPath, objst, bucket, vlog, &dirChmodQueue, uid, gid, cc)
+		err = backup.RestoreDirEntry(ctx, encKey, hmacKey, restorePath, mRelPathsObjsMap[relPath], backupName, snapshotName, relPath, objst, bucket, vlog, &dirChmodQueue, uid, gid, nil, hardLinks, cc)
 		if err != nil {
 			log.Printf("error: could not restore a dir entry '%s'", relPath)
+			errorCount += 1
 		}
 
 		// Update the percentage done
@@ -241,16 +275,12 @@ func Restore(snapshotRawName string, restorePath string, selectedRelPaths []stri
 	}
 
 	// Do all the queued up directory chmods
-	for _, dirChmodQueueItem := range dirChmodQueue {
-		err := os.Chmod(dirChmodQueueItem.AbsPath, dirChmodQueueItem.FinalMode)
-		if err != nil {
-			log.Printf("error: could not chmod dir '%s' to final %#o\n", dirChmodQueueItem.AbsPath, dirChmodQueueItem.FinalMode)
-		}
-	}
+	backup.ApplyDirChmodQueue(dirChmodQueue)
 
 	// Print the cache hit rate to vlog for diagnostics
 	cc.PrintCacheStatistics()
 
+	runPostRestoreHook()
 	done()
 }
 
@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fsctl/tless/pkg/backup"
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	pb "github.com/fsctl/tless/rpc"
+)
+
+func (s *server) RotateKey(in *pb.RotateKeyRequest, srv pb.DaemonCtl_RotateKeyServer) error {
+	vlog := util.NewVLog(&gGlobalsLock, func() bool { return gCfg == nil || gCfg.VerboseDaemon })
+
+	sendPartialFunc := func(didSucceed bool, percentDone float64, errMsg string, isFinal bool) {
+		resp := pb.RotateKeyResponse{
+			DidSucceed:  didSucceed,
+			PercentDone: percentDone,
+			ErrMsg:      errMsg,
+			IsFinal:     isFinal,
+		}
+		if err := srv.Send(&resp); err != nil {
+			log.Println("error: server.Send failed: ", err)
+		}
+	}
+
+	log.Println(">> GOT COMMAND: RotateKey")
+	defer log.Println(">> COMPLETED COMMAND: RotateKey")
+
+	// Make sure the global config and db we need are initialized (the keys need not be, since
+	// initConfig refuses to load them while a rotation is unfinished)
+	gDbLock.Lock()
+	isDbReady := gDb != nil
+	gDbLock.Unlock()
+	gGlobalsLock.Lock()
+	isGlobalConfigReady := gCfg != nil
+	isIdle := gStatus.state == Idle
+	gGlobalsLock.Unlock()
+	if !isGlobalConfigReady || !isDbReady {
+		log.Println("error: RotateKey: global config not yet initialized")
+		sendPartialFunc(false, float64(0), "global config not yet initialized", true)
+		return nil
+	}
+	if !isIdle {
+		log.Println("ROTATEKEY> Not in Idle state, cannot rotate key right now")
+		sendPartialFunc(false, float64(0), "not in Idle state", true)
+		return nil
+	}
+
+	gGlobalsLock.Lock()
+	gStatus.state = RotatingKey
+	gStatus.msg = "Re-encrypting cloud data"
+	gStatus.percentage = 0.0
+	gGlobalsLock.Unlock()
+
+	// Sets status back to Idle when routine is done
+	defer func() {
+		lastBackupTimeFormatted := getLastBackupTimeFormatted(&gDbLock)
+		gGlobalsLock.Lock()
+		gStatus.state = Idle
+		gStatus.msg = "Last backup: " + lastBackupTimeFormatted
+		gStatus.percentage = -1.0
+		gGlobalsLock.Unlock()
+	}()
+
+	ctx := context.Background()
+	gGlobalsLock.Lock()
+	endpoint := gCfg.Endpoint
+	accessKey := gCfg.AccessKeyId
+	secretKey := gCfg.SecretAccessKey
+	bucket := gCfg.Bucket
+	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
+	masterPassword := gCfg.MasterPassword
+	privateKeyPassphrase := gCfg.PrivateKeyPassphrase
+	padding, _ := cryptography.ParsePadding(gCfg.Padding)
+	gGlobalsLock.Unlock()
+	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
+
+	// In write-only mode, the private key is only unlocked if its passphrase is in the config file
+	sealKeys, err := objst.GetSealKeys(ctx, bucket, privateKeyPassphrase)
+	if err != nil {
+		msg := fmt.Sprintf("error: RotateKey: GetSealKeys failed: %v", err)
+		log.Println(msg)
+		sendPartialFunc(false, float64(0), msg, true)
+		return nil
+	}
+	oldKey, newKey, keyGeneration, hmacKey, err := objst.BeginKeyRotation(ctx, bucket, masterPassword, sealKeys, vlog)
+	if err != nil {
+		msg := fmt.Sprintf("error: RotateKey: BeginKeyRotation failed: %v", err)
+		log.Println(msg)
+		sendPartialFunc(false, float64(0), msg, true)
+		return nil
+	}
+
+	// Nothing else may use the bucket until the rotation is finished
+	gGlobalsLock.Lock()
+	gEncKey = nil
+	gHmacKey = nil
+	gGlobalsLock.Unlock()
+
+	progressFunc := func(percentDone float64) {
+		gGlobalsLock.Lock()
+		gStatus.percentage = float32(percentDone)
+		gGlobalsLock.Unlock()
+
+		sendPartialFunc(true, percentDone, "", false)
+	}
+	if err = backup.RotateKey(ctx, objst, bucket, oldKey, newKey, hmacKey, sealKeys, padding, keyGeneration, &gDbLock, gDb, vlog, progressFunc); err != nil {
+		msg := fmt.Sprintf("error: RotateKey: rotation failed (call RotateKey again to resume): %v", err)
+		log.Println(msg)
+		sendPartialFunc(false, float64(0), msg, true)
+		return nil
+	}
+	vlog.Printf("ROTATEKEY> Bucket is now encrypted with key generation %d", keyGeneration)
+
+	// Load the new keys
+	if err = initConfig(&gGlobalsLock); err != nil {
+		msg := fmt.Sprintf("error: RotateKey: could not reload keys: %v", err)
+		log.Println(msg)
+		sendPartialFunc(false, float64(100), msg, true)
+		return nil
+	}
+
+	// Persist the bandwidth used re-encrypting
+	persistUsage(false, true, vlog)
+
+	sendPartialFunc(true, float64(100), "", true)
+	return nil
+}
@@ -60,17 +60,33 @@ func (s *server) ReadAllSnapshotsMetadata(context.Context, *pb.ReadAllSnapshotsM
 		}, nil
 	}
 
+	mAnnotations, err := snapshots.GetAllSnapshotAnnotations(ctxBkg, objst, bucket, encKey)
+	if err != nil {
+		msg := fmt.Sprintf("error: ReadAllSnapshotsMetadata: %v", err)
+		log.Println(msg)
+		return &pb.ReadAllSnapshotsMetadataResponse{
+			DidSucceed:       false,
+			ErrMsg:           msg,
+			SnapshotMetadata: nil,
+		}, nil
+	}
+
 	pbSnapshotMetadatas := make([]*pb.SnapshotMetadata, 0)
 	for backupName, ssInfos := range mSnapshots {
 		vlog.Printf("SNAPSHOT_METADATA> '%s'", backupName)
 		for _, ssInfo := range ssInfos {
 			vlog.Printf("SNAPSHOT_METADATA>     '%s' (%d, %s)", ssInfo.Name, ssInfo.TimestampUnix, ssInfo.RawSnapshotName)
-			pbSnapshotMetadatas = append(pbSnapshotMetadatas, &pb.SnapshotMetadata{
+			pbSnapshotMetadata := &pb.SnapshotMetadata{
 				BackupName:        backupName,
 				SnapshotName:      ssInfo.Name,
 				SnapshotTimestamp: ssInfo.TimestampUnix,
 				SnapshotRawName:   ssInfo.RawSnapshotName,
-			})
+				IsPinned:          ssInfo.Pinned,
+			}
+			if a, ok := mAnnotations[ssInfo.RawSnapshotName]; ok {
+				pbSnapshotMetadata.Tags = a.Tags
+			}
+			pbSnapshotMetadatas = append(pbSnapshotMetadatas, pbSnapshotMetadata)
 		}
 	}
 
@@ -128,6 +144,7 @@ func (s *server) ReadSnapshotPaths(in *pb.ReadSnapshotPathsRequest, srv pb.Daemo
 	bucket := gCfg.Bucket
 	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
 	encKey := gEncKey
+	sealKeys := gSealKeys
 	gGlobalsLock.Unlock()
 	objst := objstore.NewObjStore(ctxBkg, endpoint, accessKey, secretKey, trustSelfSignedCerts)
 
@@ -164,7 +181,7 @@ func (s *server) ReadSnapshotPaths(in *pb.ReadSnapshotPathsRequest, srv pb.Daemo
 	//vlog.Printf("SNAPSHOT_PATHS> encObjName = '%s'", encObjName)
 
 	// Download the snapshot file and unmarshall it
-	plaintextIndexFileBuf, err := snapshots.GetSnapshotIndexFile(ctxBkg, objst, bucket, encKey, encObjName)
+	plaintextIndexFileBuf, err := snapshots.GetSnapshotIndexFile(ctxBkg, objst, bucket, encKey, sealKeys, encObjName)
 	if err != nil {
 		msg := fmt.Sprintf("error: ReadSnapshotPaths: could not retrieve snapshot index file (%s): %v\n", in.SnapshotName, err)
 		log.Println(msg)
@@ -291,6 +308,8 @@ func (s *server) DeleteSnapshots(in *pb.DeleteSnapshotsRequest, srv pb.DaemonCtl
 	bucket := gCfg.Bucket
 	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
 	encKey := gEncKey
+	hmacKey := gHmacKey
+	sealKeys := gSealKeys
 	gGlobalsLock.Unlock()
 	objst := objstore.NewObjStore(ctxBkg, endpoint, accessKey, secretKey, trustSelfSignedCerts)
 
@@ -340,7 +359,7 @@ func (s *server) DeleteSnapshots(in *pb.DeleteSnapshotsRequest, srv pb.DaemonCtl
 		}
 	}
 
-	groupedObjects, err := snapshots.GetGroupedSnapshots(ctxBkg, objst, encKey, bucket, vlog, setInitialGGS1Progress, updateGGS1Progress)
+	groupedObjects, err := snapshots.GetGroupedSnapshots(ctxBkg, objst, encKey, sealKeys, bucket, vlog, setInitialGGS1Progress, updateGGS1Progress)
 	if err != nil {
 		log.Printf("Could not get grouped snapshots: %v", err)
 		resp := pb.DeleteSnapshotsResponse{
@@ -402,7 +421,7 @@ func (s *server) DeleteSnapshots(in *pb.DeleteSnapshotsRequest, srv pb.DaemonCtl
 		}
 	}
 
-	err = snapshots.DeleteSnapshots(ctxBkg, encKey, ssDelItems, objst, bucket, vlog, setInitialGGS2Progress, updateGGS2Progress)
+	err = snapshots.DeleteSnapshots(ctxBkg, encKey, hmacKey, sealKeys, ssDelItems, objst, bucket, in.Force, vlog, setInitialGGS2Progress, updateGGS2Progress)
 	if err != nil {
 		resp := pb.DeleteSnapshotsResponse{
 			DidSucceed:  false,
@@ -434,6 +453,7 @@ func (s *server) GetSnapshotSpaceUsage(in *pb.GetSnapshotSpaceUsageRequest, srv
 	bucket := gCfg.Bucket
 	trustSelfSignedCerts := gCfg.TrustSelfSignedCerts
 	copy(encKey, gEncKey)
+	sealKeys := gSealKeys
 	gGlobalsLock.Unlock()
 	objst := objstore.NewObjStore(ctx, endpoint, accessKey, secretKey, trustSelfSignedCerts)
 
@@ -529,7 +549,7 @@ func (s *server) GetSnapshotSpaceUsage(in *pb.GetSnapshotSpaceUsageRequest, srv
 				continue
 			}
 
-			plaintextIndexFileBuf, err := snapshots.GetSnapshotIndexFile(ctx, objst, bucket, encKey, encObjName)
+			plaintextIndexFileBuf, err := snapshots.GetSnapshotIndexFile(ctx, objst, bucket, encKey, sealKeys, encObjName)
 			if err != nil {
 				msg := fmt.Sprintf("error: GetSnapshotSpaceUsage: could not retrieve snapshot index file (%s) - skipping: %v\n", ssName, err)
 				log.Println(msg)
@@ -18,6 +18,8 @@ const (
 	BackingUp
 	Restoring
 	CleaningUp
+	Checking
+	RotatingKey
 )
 
 type Status struct {
@@ -43,7 +45,8 @@ func (s *server) Status(ctx context.Context, in *pb.DaemonStatusRequest) (*pb.Da
 
 	// If daemon has restarted we need to tell the client we need a new Hello to boot us up
 	gGlobalsLock.Lock()
-	isNeedingHello := gUsername == "" || gUserHomeDir == "" || gCfg == nil || gEncKey == nil
+	// (Keys are unset while a key rotation is in progress)
+	isNeedingHello := gUsername == "" || gUserHomeDir == "" || gCfg == nil || (gEncKey == nil && gStatus.state != RotatingKey)
 	gGlobalsLock.Unlock()
 	gDbLock.Lock()
 	isNeedingHello = isNeedingHello || gDb == nil
@@ -114,6 +117,22 @@ func (s *server) Status(ctx context.Context, in *pb.DaemonStatusRequest) (*pb.Da
 					Datetime: e.Datetime,
 					Msg:      e.Msg,
 				})
+			case util.ERR_MANIFEST_MISMATCH:
+				pbReportedEvents = append(pbReportedEvents, &pb.ReportedEvent{
+					Kind:     pb.ReportedEvent_ErrManifestMismatch,
+					Path:     e.Path,
+					IsDir:    e.IsDir,
+					Datetime: e.Datetime,
+					Msg:      e.Msg,
+				})
+			case util.ERR_HOOK_FAILED:
+				pbReportedEvents = append(pbReportedEvents, &pb.ReportedEvent{
+					Kind:     pb.ReportedEvent_ErrHookFailed,
+					Path:     e.Path,
+					IsDir:    e.IsDir,
+					Datetime: e.Datetime,
+					Msg:      e.Msg,
+				})
 			}
 		}
 		gStatus.reportedEvents = make([]util.ReportedEvent, 0)
@@ -147,6 +166,18 @@ func (s *server) Status(ctx context.Context, in *pb.DaemonStatusRequest) (*pb.Da
 			Msg:            gStatus.msg,
 			Percentage:     gStatus.percentage,
 			ReportedEvents: nil}, nil
+	} else if gStatus.state == Checking {
+		return &pb.DaemonStatusResponse{
+			Status:         pb.DaemonStatusResponse_CHECKING,
+			Msg:            gStatus.msg,
+			Percentage:     gStatus.percentage,
+			ReportedEvents: nil}, nil
+	} else if gStatus.state == RotatingKey {
+		return &pb.DaemonStatusResponse{
+			Status:         pb.DaemonStatusResponse_ROTATING_KEY,
+			Msg:            gStatus.msg,
+			Percentage:     gStatus.percentage,
+			ReportedEvents: nil}, nil
 	} else {
 		// We need a default return
 		return &pb.DaemonStatusResponse{
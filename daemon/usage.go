@@ -49,8 +49,9 @@ func (s *server) GetUsageHistory(context.Context, *pb.GetUsageHistoryRequest) (*
 	retPeakSpace := make([]*pb.DailyUsage, 0, 3*31)
 	for _, peakDailySpaceUsage := range peakDailySpaceUsages {
 		retPeakSpace = append(retPeakSpace, &pb.DailyUsage{
-			DayYmd:    peakDailySpaceUsage.DateYMD,
-			ByteCount: peakDailySpaceUsage.ByteCnt,
+			DayYmd:           peakDailySpaceUsage.DateYMD,
+			ByteCount:        peakDailySpaceUsage.ByteCnt,
+			PaddingByteCount: peakDailySpaceUsage.PaddingByteCnt,
 		})
 	}
 
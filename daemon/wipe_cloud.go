@@ -95,5 +95,13 @@ func (s *server) WipeCloud(in *pb.WipeCloudRequest, srv pb.DaemonCtl_WipeCloudSe
 		sendPartialFunc(true, float64(percentDone), "")
 	}
 
+	// The manifest is gone too, which is not a sign of tampering
+	gDbLock.Lock()
+	err = gDb.SetLastSeenManifestVersion(bucket, 0)
+	gDbLock.Unlock()
+	if err != nil {
+		log.Printf("error: WipeCloud: could not reset manifest version: %v", err)
+	}
+
 	return nil
 }
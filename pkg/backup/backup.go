@@ -12,6 +12,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/database"
@@ -22,25 +23,43 @@ import (
 
 var (
 	ErrSocket    = fmt.Errorf("the file is a socket")
-	ErrDevice    = fmt.Errorf("the file is a device")
 	ErrIrregular = fmt.Errorf("the file is an irregular filesystem entry")
-	ErrFifo      = fmt.Errorf("the file is a named pipe (FIFO)")
 )
 
 const (
-	ChunkSize int64 = 134217728 // 128mb
+	ChunkSize          int64 = 134217728 // 128mb
+	LargeFileThreshold int64 = 8388608   // 8mb; files this size or larger are content-defined chunked
 )
 
+// Serialized with gob, which skips fields it doesn't know and leaves missing ones zero, so fields
+// can be added as long as Version is bumped (see posix_metadata.go)
 type dirEntMetadata struct {
 	IsDir         bool
-	MTime         int64
+	MTime         int64 // seconds
 	XAttrs        string
 	Mode          uint32
 	IsSymlink     bool
 	SymlinkOrigin string
+
+	// Version 1
+	Version    int
+	Uid        uint32
+	Gid        uint32
+	UserName   string
+	GroupName  string
+	MTimeNs    int64  // nanoseconds since the epoch
+	ATimeNs    int64  // nanoseconds since the epoch
+	CTimeNs    int64  // nanoseconds since the epoch; recorded but cannot be restored
+	HardLinkId string // device and inode of a file with more than one hard link, else empty
+
+	// Version 2
+	Rdev      uint64         // device number of a device node
+	IsSparse  bool           // only the data regions in SparseMap are stored
+	Size      int64          // length of a sparse file, including holes
+	SparseMap []sparseRegion // data regions of a sparse file
 }
 
-func Backup(ctx context.Context, key []byte, rootDirName string, relPath string, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, cp *chunkPacker, bjt *database.BackupJournalTask) (chunkExtents []snapshots.ChunkExtent, pendingInChunkPacker bool, err error) {
+func Backup(ctx context.Context, key []byte, hmacKey []byte, rootDirName string, relPath string, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, cp *chunkPacker, kc *knownChunks, bjt *database.BackupJournalTask) (chunkExtents []snapshots.ChunkExtent, pendingInChunkPacker bool, err error) {
 	chunkExtents = make([]snapshots.ChunkExtent, 0)
 	pendingInChunkPacker = false
 
@@ -55,48 +74,66 @@ func Backup(ctx context.Context, key []byte, rootDirName string, relPath string,
 	}
 
 	//
-	// filter out socket, FIFO and device files
+	// filter out sockets and irregular files; FIFOs and devices are stored as metadata only
 	//
-	if info.Mode()&fs.ModeDevice != 0 {
-		return nil, false, ErrDevice
-	}
 	if info.Mode()&fs.ModeSocket != 0 {
 		return nil, false, ErrSocket
 	}
 	if info.Mode()&fs.ModeIrregular != 0 {
 		return nil, false, ErrIrregular
 	}
-	if info.Mode()&fs.ModeNamedPipe != 0 {
-		return nil, false, ErrFifo
-	}
+	isSpecial := info.Mode()&(fs.ModeNamedPipe|fs.ModeDevice) != 0
 
 	//
 	// get the metadata on dirent
 	//
-
-	// get symlink origin if it's a symlink
-	symlinkOrigin, err := getSymlinkOriginIfSymlink(absPath)
+	metadata, err := readDirEntMetadata(absPath, info)
 	if err != nil {
-		log.Printf("error: Backup: could not get symlink info on '%s'\n", absPath)
 		return nil, false, err
 	}
-	var isSymlink bool = false
-	if symlinkOrigin != "" {
-		isSymlink = true
-	}
-	// get the xattrs if any
-	xattrs, err := serializeXAttrsToHex(absPath)
-	if err != nil {
-		// fs may validly not support xattrs, so if serialization fails just set xattrs to blank
-		xattrs = ""
+	isSymlink := metadata.IsSymlink
+
+	// Of several hard links to the same file, only the first one this run comes across is read; the
+	// others get its extents, either right away or once it has been backed up
+	hardLinkKey := metadata.hardLinkKey()
+	if hardLinkKey != "" {
+		linkExtents, isLeader, isPending := cp.hardLinks.join(hardLinkKey, relPath, bjt)
+		if isPending {
+			vlog.Printf("Backed up %s (pending on an earlier hard link)\n", relPath)
+			return chunkExtents, true, nil
+		} else if !isLeader {
+			vlog.Printf("Backed up %s (chunkExtents of an earlier hard link: %v)\n", relPath, linkExtents)
+			return linkExtents, false, nil
+		}
+		defer func() {
+			if err != nil {
+				cp.finishHardLinks(hardLinkKey, nil)
+			} else if !pendingInChunkPacker {
+				cp.finishHardLinks(hardLinkKey, chunkExtents)
+			}
+		}()
 	}
-	metadata := dirEntMetadata{
-		IsDir:         info.IsDir(),
-		MTime:         info.ModTime().Unix(),
-		XAttrs:        xattrs,
-		Mode:          uint32(info.Mode()),
-		SymlinkOrigin: symlinkOrigin,
-		IsSymlink:     isSymlink,
+
+	// only the data regions of sparse files are backed up
+	var f *os.File
+	dataLen := info.Size()
+	if info.Mode().IsRegular() {
+		if f, err = os.Open(absPath); err != nil {
+			log.Printf("error: could not open '%s': %v", absPath, err)
+			return nil, false, err
+		}
+		defer f.Close()
+		sparseMap, err := findSparseRegions(f, info)
+		if err != nil {
+			// Not every filesystem can find holes, in which case the file is read densely
+			sparseMap = nil
+		}
+		if sparseMap != nil {
+			metadata.IsSparse = true
+			metadata.Size = info.Size()
+			metadata.SparseMap = sparseMap
+			dataLen = sparseDataLen(sparseMap)
+		}
 	}
 
 	//
@@ -108,28 +145,27 @@ func Backup(ctx context.Context, key []byte, rootDirName string, relPath string,
 		return nil, false, err
 	}
 
-	// If dir or small file (<ChunkSize bytes), process as single chunk.
-	// If large file (>ChunkSize bytes), apply chunk processing logic.
-	size := info.Size() + int64(len(buf))
-	if info.IsDir() || (size < ChunkSize) || isSymlink {
-		// Contents smaller than ChunkSize; if file just read entire file into
+	// If dir or small file (<LargeFileThreshold bytes), pack it into a shared chunk.
+	// If large file (>=LargeFileThreshold bytes), apply content-defined chunking logic.
+	size := dataLen + int64(len(buf))
+	if info.IsDir() || (size < LargeFileThreshold) || isSymlink || isSpecial {
+		// Contents smaller than LargeFileThreshold; if file just read entire file into
 		// rest of buffer after metadata
-		if !info.IsDir() && !isSymlink {
-			buf, err = cryptography.AppendEntireFileToBuffer(absPath, buf)
+		if metadata.IsSparse {
+			contents, err := io.ReadAll(newSparseReader(f, metadata.SparseMap))
 			if err != nil {
-				log.Printf("error: Backup: AppendEntireFileToBuffer failed: %v\n", err)
+				log.Printf("error: Backup: could not read sparse file '%s': %v\n", absPath, err)
 				return nil, false, err
 			}
-		}
-		didSucceed := cp.AddDirEntry(relPath, buf, bjt)
-		if !didSucceed {
-			cp.Complete()
-			didSucceedNow := cp.AddDirEntry(relPath, buf, bjt)
-			if !didSucceedNow {
-				log.Printf("error: Backup: failed to add dir entry to chunk packer even after clearing it (%s)", relPath)
+			buf = append(buf, contents...)
+		} else if !info.IsDir() && !isSymlink && !isSpecial {
+			buf, err = cryptography.AppendEntireFileToBuffer(absPath, buf)
+			if err != nil {
+				log.Printf("error: Backup: AppendEntireFileToBuffer failed: %v\n", err)
 				return nil, false, err
 			}
 		}
+		cp.AddDirEntry(relPath, buf, bjt, hardLinkKey)
 		pendingInChunkPacker = true
 
 		vlog.Printf("Backed up %s (pending in chunkPacker)\n", relPath)
@@ -137,77 +173,207 @@ func Backup(ctx context.Context, key []byte, rootDirName string, relPath string,
 		return chunkExtents, pendingInChunkPacker, nil
 
 	} else {
-		// File is larger than ChunkSize
+		// File is at least LargeFileThreshold bytes: upload metadata header as its own chunk and
+		// split contents into content-defined chunks named by their HMAC, skipping any chunk that
+		// already exists in the cloud.
 
-		// Generate initial nonce randomly; subsequent chunks will increment this value
-		nonce := make([]byte, 12)
-		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		// The header changes whenever mtime does, so keep it out of the first content chunk
+		chunkName, err := uploadChunkIfNew(ctx, key, hmacKey, cp.sealKeys, cp.padding, buf, true, objst, bucket, kc, cp.dbLock, cp.db, cp.stats)
+		if err != nil {
+			log.Printf("error: Backup: failed while backing up header for '%s': %v\n", relPath, err)
+			return nil, false, err
+		}
+		chunkExtents = append(chunkExtents, snapshots.ChunkExtent{
+			ChunkName: chunkName,
+			Offset:    0,
+			Len:       int64(len(buf)),
+		})
+
+		// Don't waste time trying to compress files that are already compressed
+		fileHeader := make([]byte, 16)
+		n, _ := io.ReadFull(f, fileHeader)
+		tryCompression := !cryptography.IsAlreadyCompressedFormat(fileHeader[:n])
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			log.Printf("error: could not seek in '%s': %v", absPath, err)
 			return nil, false, err
 		}
 
-		// Open the file for reading
-		f, err := os.Open(absPath)
+		var r io.Reader = f
+		if metadata.IsSparse {
+			r = newSparseReader(f, metadata.SparseMap)
+		}
+		contentExtents, err := uploadContentChunks(ctx, key, hmacKey, cp.sealKeys, cp.padding, r, tryCompression, objst, bucket, kc, cp.dbLock, cp.db, cp.stats)
 		if err != nil {
-			log.Printf("error: could not open '%s': %v", absPath, err)
+			log.Printf("error: Backup: failed while backing up '%s': %v\n", absPath, err)
 			return nil, false, err
 		}
-		defer f.Close()
+		chunkExtents = append(chunkExtents, contentExtents...)
 
-		// Loop until last partial chunk is processed
-		i := 0
-		for {
-			// Read next ChunkSize bytes
-			readBuf := make([]byte, ChunkSize)
-			bytesRead, err := f.Read(readBuf)
-			if errors.Is(err, io.EOF) && bytesRead == 0 {
-				break
-			}
-			if err != nil {
-				log.Printf("error: could not read from '%s': %v", absPath, err)
-				return nil, false, err
-			}
-			if bytesRead < len(readBuf) {
-				readBuf = readBuf[0:bytesRead]
-			}
+		vlog.Printf("Backed up %s (chunkExtents: %v)\n", relPath, chunkExtents)
 
-			// On first iterationn only: prepend buf (containing header) to readBuf
-			if i == 0 {
-				readBuf = append(buf, readBuf...)
-			}
+		return chunkExtents, pendingInChunkPacker, nil
+	}
+}
 
-			// Encrypt readBuf with key and current nonce
-			ciphertextReadBuf, err := cryptography.EncryptBufferWithNonce(key, readBuf, nonce)
-			if err != nil {
-				log.Printf("error: could not encrypt buffer: %v", err)
-				return nil, false, err
-			}
+// Returns the metadata of the dir entry at absPath, which info describes. Sparse file fields are
+// left unset.
+func readDirEntMetadata(absPath string, info fs.FileInfo) (dirEntMetadata, error) {
+	// get symlink origin if it's a symlink
+	symlinkOrigin, err := getSymlinkOriginIfSymlink(absPath)
+	if err != nil {
+		log.Printf("error: readDirEntMetadata: could not get symlink info on '%s'\n", absPath)
+		return dirEntMetadata{}, err
+	}
+	var isSymlink bool = false
+	if symlinkOrigin != "" {
+		isSymlink = true
+	}
+	// get the xattrs if any
+	xattrs, err := serializeXAttrsToHex(absPath)
+	if err != nil {
+		// fs may validly not support xattrs, so if serialization fails just set xattrs to blank
+		xattrs = ""
+	}
+	metadata := dirEntMetadata{
+		IsDir:         info.IsDir(),
+		MTime:         info.ModTime().Unix(),
+		XAttrs:        xattrs,
+		Mode:          uint32(info.Mode()),
+		SymlinkOrigin: symlinkOrigin,
+		IsSymlink:     isSymlink,
+	}
+	setPosixMetadata(&metadata, info)
+	return metadata, nil
+}
 
-			// Generate chunk name
-			chunkName := generateRandomChunkName()
+// Backs up the large file at relPath, which was moved from a path whose extents in the previous
+// snapshot were prevExtents, by uploading a new metadata header for it and reusing the old content
+// extents. The old header can't be reused since the move may have come with a new owner, xattrs
+// or ctime, or it may be a copy matched by its contents. Returns ok false, having uploaded
+// nothing, if the file has to be backed up in full instead: small files share their extent with
+// their header, and the content extents of sparse files depend on where their holes are.
+func backupMovedFile(ctx context.Context, key []byte, hmacKey []byte, relPath string, backupDirPath string, prevExtents []snapshots.ChunkExtent, objst *objstore.ObjStore, bucket string, vlog *util.VLog, cp *chunkPacker, kc *knownChunks) (chunkExtents []snapshots.ChunkExtent, ok bool, err error) {
+	if len(prevExtents) < 2 {
+		return nil, false, nil
+	}
+	absPath := filepath.Join(util.StripTrailingSlashes(backupDirPath), relPath)
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		log.Printf("error: backupMovedFile: could not stat '%s'\n", absPath)
+		return nil, false, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, false, nil
+	}
 
-			// Save the current chunk extent to return
-			chunkExtents = append(chunkExtents, snapshots.ChunkExtent{
-				ChunkName: chunkName,
-				Offset:    0,
-				Len:       int64(len(readBuf)),
-			})
+	// Old contents stored densely in full, and no holes now
+	var contentLen int64 = 0
+	for _, ce := range prevExtents[1:] {
+		contentLen += ce.Len
+	}
+	if contentLen != info.Size() {
+		return nil, false, nil
+	}
+	f, err := os.Open(absPath)
+	if err != nil {
+		log.Printf("error: could not open '%s': %v", absPath, err)
+		return nil, false, err
+	}
+	sparseMap, _ := findSparseRegions(f, info)
+	f.Close()
+	if sparseMap != nil {
+		return nil, false, nil
+	}
 
-			// Upload encrypted readBuf
-			objName := "chunks/" + chunkName
-			err = objst.UploadObjFromBuffer(ctx, bucket, objName, ciphertextReadBuf, objstore.ComputeETag(ciphertextReadBuf))
-			if err != nil {
-				log.Printf("error: Backup: failed while backing up file: %v\n", err)
-				return nil, false, err
-			}
+	metadata, err := readDirEntMetadata(absPath, info)
+	if err != nil {
+		return nil, false, err
+	}
+	buf, err := serializeMetadataStruct(metadata)
+	if err != nil {
+		log.Printf("error: backupMovedFile: serializeMetadata failed: %v\n", err)
+		return nil, false, err
+	}
+	chunkName, err := uploadChunkIfNew(ctx, key, hmacKey, cp.sealKeys, cp.padding, buf, true, objst, bucket, kc, cp.dbLock, cp.db, cp.stats)
+	if err != nil {
+		log.Printf("error: backupMovedFile: failed while backing up header for '%s': %v\n", relPath, err)
+		return nil, false, err
+	}
+	chunkExtents = append([]snapshots.ChunkExtent{{ChunkName: chunkName, Offset: 0, Len: int64(len(buf))}}, prevExtents[1:]...)
 
-			// For next iteration:  increment nonce
-			i += 1
-			nonce = incrementNonce(nonce)
+	vlog.Printf("Backed up %s (new header, reused content chunkExtents: %v)\n", relPath, chunkExtents)
+
+	return chunkExtents, true, nil
+}
+
+// Splits everything read from r into content-defined chunks and uploads the ones not already in
+// the cloud, returning their extents in order
+func uploadContentChunks(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, r io.Reader, tryCompression bool, objst *objstore.ObjStore, bucket string, kc *knownChunks, dbLock *sync.Mutex, db *database.DB, stats *BackupStats) ([]snapshots.ChunkExtent, error) {
+	chunkExtents := make([]snapshots.ChunkExtent, 0)
+	chunker := newCdcChunker(r, hmacKey)
+	for {
+		plaintextChunk, err := chunker.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read: %v", err)
 		}
 
-		vlog.Printf("Backed up %s (chunkExtents: %v)\n", relPath, chunkExtents)
+		chunkName, err := uploadChunkIfNew(ctx, key, hmacKey, sealKeys, padding, plaintextChunk, tryCompression, objst, bucket, kc, dbLock, db, stats)
+		if err != nil {
+			return nil, err
+		}
+		chunkExtents = append(chunkExtents, snapshots.ChunkExtent{
+			ChunkName: chunkName,
+			Offset:    0,
+			Len:       int64(len(plaintextChunk)),
+		})
+	}
+	return chunkExtents, nil
+}
 
-		return chunkExtents, pendingInChunkPacker, nil
+// Names plaintext by its HMAC and uploads it compressed (if tryCompression), padded and encrypted, unless
+// a chunk with that name is already in the cloud. The padding is recorded in db if it isn't nil.
+func uploadChunkIfNew(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, plaintext []byte, tryCompression bool, objst *objstore.ObjStore, bucket string, kc *knownChunks, dbLock *sync.Mutex, db *database.DB, stats *BackupStats) (chunkName string, err error) {
+	chunkName = cryptography.ComputeChunkName(hmacKey, plaintext)
+	if kc.has(chunkName) {
+		if stats != nil {
+			stats.AddDedupBytes(int64(len(plaintext)))
+		}
+		return chunkName, nil
+	}
+
+	ciphertext, paddingLen, err := cryptography.EncryptPaddedDataBuffer(key, sealKeys, plaintext, tryCompression, padding)
+	if err != nil {
+		log.Printf("error: uploadChunkIfNew: could not encrypt buffer: %v", err)
+		return "", err
+	}
+	objName := "chunks/" + chunkName
+	err = objst.UploadObjFromBuffer(ctx, bucket, objName, ciphertext, objstore.ComputeETag(ciphertext))
+	if err != nil {
+		log.Printf("error: uploadChunkIfNew: failed while uploading '%s': %v", chunkName, err)
+		return "", err
+	}
+	kc.add(chunkName)
+	if stats != nil {
+		stats.AddPaddingBytes(paddingLen)
+	}
+	recordPadding(db, dbLock, objName, paddingLen)
+
+	return chunkName, nil
+}
+
+// Remembers how much padding objName was uploaded with, for space usage reports
+func recordPadding(db *database.DB, dbLock *sync.Mutex, objName string, paddingLen int64) {
+	if db == nil || paddingLen == 0 {
+		return
+	}
+	util.LockIf(dbLock)
+	err := db.AddObjectPadding(objName, paddingLen)
+	util.UnlockIf(dbLock)
+	if err != nil {
+		log.Printf("error: recordPadding: could not record padding of '%s': %v", objName, err)
 	}
 }
 
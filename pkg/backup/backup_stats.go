@@ -12,13 +12,19 @@ import (
 type BackupStats struct {
 	cntFiles      int64
 	cntBytes      int64
+	cntDedupBytes int64
+	cntPadBytes   int64
+	cntErrors     int64
 	startTimeUnix int64
+	snapshotName  string
 }
 
 func NewBackupStats() *BackupStats {
 	return &BackupStats{
 		cntFiles:      0,
 		cntBytes:      0,
+		cntDedupBytes: 0,
+		cntPadBytes:   0,
 		startTimeUnix: time.Now().Unix(),
 	}
 }
@@ -31,6 +37,51 @@ func (bs *BackupStats) AddBytes(n int64) {
 	atomic.AddInt64(&bs.cntBytes, n)
 }
 
+// Bytes of chunks that were already in the cloud and so were not uploaded again
+func (bs *BackupStats) AddDedupBytes(n int64) {
+	atomic.AddInt64(&bs.cntDedupBytes, n)
+}
+
+// Bytes of padding uploaded to hide the sizes of chunks
+func (bs *BackupStats) AddPaddingBytes(n int64) {
+	atomic.AddInt64(&bs.cntPadBytes, n)
+}
+
+// Files that could not be backed up
+func (bs *BackupStats) AddError() {
+	atomic.AddInt64(&bs.cntErrors, 1)
+}
+
+// Adds the counts of other, such as one backup dir's stats to those of a whole run
+func (bs *BackupStats) Add(other *BackupStats) {
+	atomic.AddInt64(&bs.cntFiles, atomic.LoadInt64(&other.cntFiles))
+	atomic.AddInt64(&bs.cntBytes, atomic.LoadInt64(&other.cntBytes))
+	atomic.AddInt64(&bs.cntDedupBytes, atomic.LoadInt64(&other.cntDedupBytes))
+	atomic.AddInt64(&bs.cntPadBytes, atomic.LoadInt64(&other.cntPadBytes))
+	atomic.AddInt64(&bs.cntErrors, atomic.LoadInt64(&other.cntErrors))
+}
+
+func (bs *BackupStats) Files() int64 {
+	return atomic.LoadInt64(&bs.cntFiles)
+}
+
+func (bs *BackupStats) Bytes() int64 {
+	return atomic.LoadInt64(&bs.cntBytes)
+}
+
+func (bs *BackupStats) DedupBytes() int64 {
+	return atomic.LoadInt64(&bs.cntDedupBytes)
+}
+
+func (bs *BackupStats) Errors() int64 {
+	return atomic.LoadInt64(&bs.cntErrors)
+}
+
+// The name of the snapshot the backup wrote, or "" if there was nothing to back up
+func (bs *BackupStats) SnapshotName() string {
+	return bs.snapshotName
+}
+
 func (bs *BackupStats) AddBytesFromChunkExtents(chunkExtents []snapshots.ChunkExtent) {
 	for _, chunkExtent := range chunkExtents {
 		bs.AddBytes(chunkExtent.Len)
@@ -43,5 +94,12 @@ func (bs *BackupStats) FinalReport() string {
 	humanReadableBytes := util.FormatBytesAsString(atomic.LoadInt64(&bs.cntBytes))
 	humanFilesCount := util.FormatNumberAsString(atomic.LoadInt64(&bs.cntFiles))
 	humanDataRate := util.FormatDataRateAsString(atomic.LoadInt64(&bs.cntBytes), durationSeconds)
-	return fmt.Sprintf("%s files (%s) in %s ~= %s", humanFilesCount, humanReadableBytes, humanReadableDuration, humanDataRate)
+	report := fmt.Sprintf("%s files (%s) in %s ~= %s", humanFilesCount, humanReadableBytes, humanReadableDuration, humanDataRate)
+	if cntDedupBytes := atomic.LoadInt64(&bs.cntDedupBytes); cntDedupBytes > 0 {
+		report += fmt.Sprintf(" (%s deduplicated)", util.FormatBytesAsString(cntDedupBytes))
+	}
+	if cntPadBytes := atomic.LoadInt64(&bs.cntPadBytes); cntPadBytes > 0 {
+		report += fmt.Sprintf(" (%s of padding)", util.FormatBytesAsString(cntPadBytes))
+	}
+	return report
 }
@@ -1,8 +1,14 @@
 package backup
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +43,44 @@ func TestIsNonceOneMoreThanPrev(t *testing.T) {
 	bIsOneMore = isNonceOneMoreThanPrev(doubleIncrementedNonce, nonce)
 	assert.Equal(t, false, bIsOneMore)
 }
+
+func TestBackupMovedFile(t *testing.T) {
+	s := newDiffTestStore(t)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	vlog := util.NewVLog(nil, func() bool { return false })
+	kc, err := newKnownChunks(s.ctx, s.objst, s.bucket, vlog)
+	assert.NoError(t, err)
+	cp := newChunkPacker(s.ctx, s.objst, s.bucket, nil, nil, s.key, nil, cryptography.PaddingNone, vlog, nil, nil, nil)
+
+	backupDirPath := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(backupDirPath, "large"), bytes.Repeat([]byte("large file"), int(LargeFileThreshold)/10+1), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(backupDirPath, "small"), []byte("small file"), 0600))
+	prevExtents, _, err := Backup(s.ctx, s.key, hmacKey, filepath.Base(backupDirPath), "large", backupDirPath, "2022-01-01_00.00.00", s.objst, s.bucket, vlog, cp, kc, nil)
+	assert.NoError(t, err)
+
+	// A moved large file keeps its content extents but gets a header describing it as it is now
+	assert.NoError(t, os.Rename(filepath.Join(backupDirPath, "large"), filepath.Join(backupDirPath, "moved")))
+	assert.NoError(t, os.Chmod(filepath.Join(backupDirPath, "moved"), 0640))
+	chunkExtents, ok, err := backupMovedFile(s.ctx, s.key, hmacKey, "moved", backupDirPath, prevExtents, s.objst, s.bucket, vlog, cp, kc)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, prevExtents[1:], chunkExtents[1:])
+	assert.NotEqual(t, prevExtents[0].ChunkName, chunkExtents[0].ChunkName)
+	header, _, err := s.cc.FetchChunkExtent(s.ctx, s.bucket, chunkExtents[0])
+	assert.NoError(t, err)
+	metadata, _, err := deserializeMetadataStruct(header)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0640), metadata.Mode)
+
+	// Old extents that don't hold the whole file as it is now can't be reused
+	shortExtents := append([]snapshots.ChunkExtent{}, prevExtents...)
+	shortExtents[len(shortExtents)-1].Len -= 1
+	_, ok, err = backupMovedFile(s.ctx, s.key, hmacKey, "moved", backupDirPath, shortExtents, s.objst, s.bucket, vlog, cp, kc)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Nor can those of a small file, which share an extent with its header
+	_, ok, err = backupMovedFile(s.ctx, s.key, hmacKey, "small", backupDirPath, prevExtents[:1], s.objst, s.bucket, vlog, cp, kc)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Content-defined chunking (FastCDC-style gear hash with normalized chunking).  Chunk boundaries
+// depend only on the bytes near them, so inserting or deleting bytes in a large file only changes
+// the chunks around the edit and every other chunk deduplicates against the previous snapshot.
+const (
+	CdcMinChunkSize int = 1048576  // 1mb
+	CdcAvgChunkSize int = 4194304  // 4mb
+	CdcMaxChunkSize int = 16777216 // 16mb
+)
+
+const (
+	// Avg chunk size is 2^22, so we test the top 22+2 bits of the hash before the avg size
+	// and the top 22-2 bits after it
+	cdcMaskSmall uint64 = 0xffffff0000000000
+	cdcMaskLarge uint64 = 0xfffff00000000000
+)
+
+type cdcChunker struct {
+	r    *bufio.Reader
+	gear [256]uint64
+}
+
+// The gear table is derived from hmacKey so that chunk boundaries (and thus chunk sizes) can't be
+// used to fingerprint known files by someone who doesn't have the key.
+func newCdcChunker(r io.Reader, hmacKey []byte) *cdcChunker {
+	c := &cdcChunker{
+		r: bufio.NewReaderSize(r, CdcMaxChunkSize),
+	}
+	for i := 0; i < 256; i++ {
+		mac := hmac.New(sha256.New, hmacKey)
+		mac.Write([]byte("tless-cdc-gear"))
+		mac.Write([]byte{byte(i)})
+		c.gear[i] = binary.LittleEndian.Uint64(mac.Sum(nil))
+	}
+	return c
+}
+
+// Next returns the next chunk of the underlying reader, or io.EOF once it has been exhausted.
+// The returned slice is owned by the caller.
+func (c *cdcChunker) Next() ([]byte, error) {
+	data, err := c.r.Peek(CdcMaxChunkSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, io.EOF
+	}
+
+	cutPoint := c.findCutPoint(data)
+	chunk := make([]byte, cutPoint)
+	copy(chunk, data[:cutPoint])
+	if _, err := c.r.Discard(cutPoint); err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+func (c *cdcChunker) findCutPoint(data []byte) int {
+	n := len(data)
+	if n <= CdcMinChunkSize {
+		return n
+	}
+	normalSize := CdcAvgChunkSize
+	if n < normalSize {
+		normalSize = n
+	}
+
+	var fp uint64 = 0
+	i := CdcMinChunkSize
+	for ; i < normalSize; i++ {
+		fp = (fp << 1) + c.gear[data[i]]
+		if fp&cdcMaskSmall == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + c.gear[data[i]]
+		if fp&cdcMaskLarge == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
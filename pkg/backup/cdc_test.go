@@ -0,0 +1,51 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chunkAll(t *testing.T, data []byte, hmacKey []byte) [][]byte {
+	chunks := make([][]byte, 0)
+	chunker := newCdcChunker(bytes.NewReader(data), hmacKey)
+	for {
+		chunk, err := chunker.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestCdcChunker(t *testing.T) {
+	hmacKey := make([]byte, 32)
+	data := make([]byte, 40*1024*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	// chunks reassemble to the original data and respect the size bounds
+	chunks := chunkAll(t, data, hmacKey)
+	assert.Greater(t, len(chunks), 2)
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), CdcMaxChunkSize)
+		if i < len(chunks)-1 {
+			assert.GreaterOrEqual(t, len(chunk), CdcMinChunkSize)
+		}
+	}
+
+	// inserting a byte at the front only changes the chunk(s) near the start
+	shiftedData := append([]byte{0x42}, data...)
+	shiftedChunks := chunkAll(t, shiftedData, hmacKey)
+	assert.Equal(t, chunks[len(chunks)-1], shiftedChunks[len(shiftedChunks)-1])
+	assert.Equal(t, chunks[1:], shiftedChunks[1:])
+
+	// empty input gives no chunks
+	assert.Equal(t, 0, len(chunkAll(t, []byte{}, hmacKey)))
+}
@@ -0,0 +1,389 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Every valid ciphertext has at least a 12-byte nonce and a 16-byte GCM tag
+const minCiphertextLen = 12 + 16
+
+type CheckProblemKind int
+
+const (
+	CheckMissingChunk CheckProblemKind = iota
+	CheckTruncatedChunk
+	CheckOrphanChunk
+	CheckBadIndexFile
+	CheckBadChunkData
+	CheckNonceOutOfOrder
+	CheckManifestMismatch
+)
+
+func (k CheckProblemKind) String() string {
+	switch k {
+	case CheckMissingChunk:
+		return "missing chunk"
+	case CheckTruncatedChunk:
+		return "truncated chunk"
+	case CheckOrphanChunk:
+		return "orphan chunk"
+	case CheckBadIndexFile:
+		return "bad index file"
+	case CheckBadChunkData:
+		return "bad chunk data"
+	case CheckNonceOutOfOrder:
+		return "nonce out of order"
+	case CheckManifestMismatch:
+		return "manifest mismatch"
+	default:
+		return "unknown problem"
+	}
+}
+
+type CheckProblem struct {
+	Kind      CheckProblemKind
+	Snapshot  string // backupName/snapshotName, if the problem is in a snapshot
+	RelPath   string
+	ChunkName string
+	Msg       string
+}
+
+func (p CheckProblem) String() string {
+	var parts []string
+	if p.Snapshot != "" {
+		parts = append(parts, fmt.Sprintf("snapshot '%s'", p.Snapshot))
+	}
+	if p.RelPath != "" {
+		parts = append(parts, fmt.Sprintf("path '%s'", p.RelPath))
+	}
+	if p.ChunkName != "" {
+		parts = append(parts, fmt.Sprintf("chunk '%s'", p.ChunkName))
+	}
+	parts = append(parts, p.Msg)
+	return p.Kind.String() + ": " + strings.Join(parts, ", ")
+}
+
+type CheckResult struct {
+	SnapshotsChecked int
+	ChunksReferenced int
+	ChunksInBucket   int
+	ChunksRead       int
+	BytesRead        int64
+	Problems         []CheckProblem
+
+	// Chunks no snapshot references. They are not problems, since they are removed the next time a
+	// snapshot is deleted.
+	OrphanChunks []CheckProblem
+}
+
+type CheckProgressFunc func(percentDone float64)
+
+// A reference from a snapshot's dir entry to one of its chunk extents
+type chunkExtentRef struct {
+	snapshot string
+	crp      *snapshots.CloudRelPath
+	idx      int
+}
+
+// What we learned about an extent by downloading and decrypting it
+type checkedExtent struct {
+	nonce       []byte
+	nameMatches bool // chunk is named by the HMAC of this extent's plaintext
+}
+
+// Verifies that every chunk extent referenced by every snapshot index exists in the bucket and is
+// large enough, and reports index files that cannot be read and (separately) chunks no snapshot
+// references. If readDataPercent > 0, that percentage of the referenced chunks is also downloaded
+// and decrypted (authenticating it), and the extents read are checked for chunk substitution or
+// reordering the same way RestoreDirEntry does.
+func CheckRepository(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, objst *objstore.ObjStore, bucket string, readDataPercent float64, vlog *util.VLog, progressFunc CheckProgressFunc) (*CheckResult, error) {
+	result := &CheckResult{Problems: make([]CheckProblem, 0), OrphanChunks: make([]CheckProblem, 0)}
+	reportProgress := func(percentDone float64) {
+		if progressFunc != nil {
+			progressFunc(percentDone)
+		}
+	}
+	indexPhasePercent := 100.0
+	if readDataPercent > 0 {
+		indexPhasePercent = 20.0
+	}
+
+	//
+	// Read every snapshot index
+	//
+	updateGGSProgress := func(finished int64, total int64) {
+		if total > 0 {
+			reportProgress(indexPhasePercent * float64(finished) / float64(total))
+		}
+	}
+	groupedObjects, badIndexFiles, err := snapshots.GetGroupedSnapshotsSkippingBadIndexes(ctx, objst, key, sealKeys, bucket, vlog, nil, updateGGSProgress)
+	if err != nil {
+		log.Printf("error: CheckRepository: could not get grouped snapshots: %v", err)
+		return nil, err
+	}
+	for _, badIndexFile := range badIndexFiles {
+		result.Problems = append(result.Problems, CheckProblem{
+			Kind:     CheckBadIndexFile,
+			Snapshot: badIndexFile.Name,
+			Msg:      fmt.Sprintf("object '%s' could not be read: %v", badIndexFile.EncObjName, badIndexFile.Err),
+		})
+	}
+
+	// Make sure the snapshot manifest is authentic and matches the indexes
+	_, manifestProblems, err := snapshots.VerifyManifest(ctx, objst, bucket, key, hmacKey, 0, true)
+	if err != nil {
+		log.Printf("error: CheckRepository: could not verify snapshot manifest: %v", err)
+		return nil, err
+	}
+	for _, manifestProblem := range manifestProblems {
+		result.Problems = append(result.Problems, CheckProblem{
+			Kind: CheckManifestMismatch,
+			Msg:  manifestProblem,
+		})
+	}
+
+	// Collect every reference to every chunk, in a stable order
+	refsByChunk := make(map[string][]chunkExtentRef)
+	multiExtentRefs := make([]chunkExtentRef, 0)
+	for _, backupName := range sortedKeys(groupedObjects) {
+		backupDir := groupedObjects[backupName]
+		for _, snapshotName := range sortedKeys(backupDir.Snapshots) {
+			snapshot := backupDir.Snapshots[snapshotName]
+			snapshotFullName := backupName + "/" + snapshotName
+			result.SnapshotsChecked += 1
+			for _, relPath := range sortedKeys(snapshot.RelPaths) {
+				crp := snapshot.RelPaths[relPath]
+				if len(crp.ChunkExtents) == 0 {
+					result.Problems = append(result.Problems, CheckProblem{
+						Kind:     CheckBadIndexFile,
+						Snapshot: snapshotFullName,
+						RelPath:  relPath,
+						Msg:      "entry has no chunk extents",
+					})
+					continue
+				}
+				for i, chunkExtent := range crp.ChunkExtents {
+					ref := chunkExtentRef{snapshot: snapshotFullName, crp: &crp, idx: i}
+					refsByChunk[chunkExtent.ChunkName] = append(refsByChunk[chunkExtent.ChunkName], ref)
+					if i > 0 {
+						multiExtentRefs = append(multiExtentRefs, ref)
+					}
+				}
+			}
+		}
+	}
+	result.ChunksReferenced = len(refsByChunk)
+
+	//
+	// Check that referenced chunks exist and are large enough, and look for orphans
+	//
+	mCloudChunks, err := objst.GetObjList(ctx, bucket, "chunks/", false, vlog)
+	if err != nil {
+		log.Printf("error: CheckRepository: could not get list of chunks: %v", err)
+		return nil, err
+	}
+	result.ChunksInBucket = len(mCloudChunks)
+
+	presentChunkNames := make([]string, 0, len(refsByChunk))
+	for _, chunkName := range sortedKeys(refsByChunk) {
+		size, ok := mCloudChunks["chunks/"+chunkName]
+		for _, ref := range refsByChunk[chunkName] {
+			chunkExtent := ref.crp.ChunkExtents[ref.idx]
+			problem := CheckProblem{Snapshot: ref.snapshot, RelPath: ref.crp.RelPath, ChunkName: chunkName}
+			if !ok {
+				problem.Kind = CheckMissingChunk
+				problem.Msg = "chunk not found in bucket"
+			} else if chunkExtent.EncLen > 0 && chunkExtent.Offset+chunkExtent.EncLen > size {
+				problem.Kind = CheckTruncatedChunk
+				problem.Msg = fmt.Sprintf("extent ends at byte %d but chunk is only %d bytes", chunkExtent.Offset+chunkExtent.EncLen, size)
+			} else if chunkExtent.EncLen == 0 && size < minCiphertextLen {
+				problem.Kind = CheckTruncatedChunk
+				problem.Msg = fmt.Sprintf("chunk is only %d bytes", size)
+			} else {
+				continue
+			}
+			result.Problems = append(result.Problems, problem)
+		}
+		if ok {
+			presentChunkNames = append(presentChunkNames, chunkName)
+		}
+	}
+
+	for _, objName := range sortedKeys(mCloudChunks) {
+		chunkName := strings.TrimPrefix(objName, "chunks/")
+		if _, ok := refsByChunk[chunkName]; !ok {
+			result.OrphanChunks = append(result.OrphanChunks, CheckProblem{
+				Kind:      CheckOrphanChunk,
+				ChunkName: chunkName,
+				Msg:       fmt.Sprintf("%s not referenced by any snapshot", util.FormatBytesAsString(mCloudChunks[objName])),
+			})
+		}
+	}
+
+	if readDataPercent <= 0 {
+		reportProgress(100.0)
+		return result, nil
+	}
+
+	//
+	// Download and authenticate a random sample of chunks
+	//
+	numToRead := int(math.Ceil(float64(len(presentChunkNames)) * math.Min(readDataPercent, 100.0) / 100.0))
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rnd.Shuffle(len(presentChunkNames), func(i, j int) {
+		presentChunkNames[i], presentChunkNames[j] = presentChunkNames[j], presentChunkNames[i]
+	})
+	sampledChunkNames := presentChunkNames[:numToRead]
+	sort.Strings(sampledChunkNames)
+
+	checkedExtents := make(map[snapshots.ChunkExtent]checkedExtent)
+	for i, chunkName := range sampledChunkNames {
+		vlog.Printf("CheckRepository: reading chunk '%s'", chunkName)
+		ciphertextBuf, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/"+chunkName)
+		if err != nil {
+			result.Problems = append(result.Problems, CheckProblem{
+				Kind:      CheckBadChunkData,
+				ChunkName: chunkName,
+				Msg:       fmt.Sprintf("could not download chunk: %v", err),
+			})
+			continue
+		}
+		result.ChunksRead += 1
+		result.BytesRead += int64(len(ciphertextBuf))
+
+		checkChunkData(key, hmacKey, sealKeys, chunkName, ciphertextBuf, refsByChunk[chunkName], checkedExtents, result)
+
+		reportProgress(indexPhasePercent + (100.0-indexPhasePercent)*float64(i+1)/float64(len(sampledChunkNames)))
+	}
+
+	//
+	// For every extent read that is not named by its own HMAC (chunks written before content-defined
+	// chunking), check that its nonce is one more than the previous extent's
+	//
+	checkedPairs := make(map[[2]snapshots.ChunkExtent]bool)
+	for _, ref := range multiExtentRefs {
+		prevExtent := ref.crp.ChunkExtents[ref.idx-1]
+		curExtent := ref.crp.ChunkExtents[ref.idx]
+		cur, ok := checkedExtents[curExtent]
+		if !ok || cur.nameMatches || checkedPairs[[2]snapshots.ChunkExtent{prevExtent, curExtent}] {
+			continue
+		}
+		checkedPairs[[2]snapshots.ChunkExtent{prevExtent, curExtent}] = true
+
+		var prevNonce []byte
+		if prev, ok := checkedExtents[prevExtent]; ok {
+			prevNonce = prev.nonce
+		} else {
+			// Only the nonce (the first 12 bytes of the extent's ciphertext) is needed
+			var nonceOffset int64 = 0
+			if prevExtent.EncLen > 0 {
+				nonceOffset = prevExtent.Offset
+			}
+			prevNonce, err = objst.DownloadObjRangeToBuffer(ctx, bucket, "chunks/"+prevExtent.ChunkName, nonceOffset, 12)
+			if err != nil || len(prevNonce) != 12 {
+				log.Printf("error: CheckRepository: could not read nonce of chunk '%s': %v", prevExtent.ChunkName, err)
+				continue
+			}
+		}
+		if !isNonceOneMoreThanPrev(cur.nonce, prevNonce) {
+			result.Problems = append(result.Problems, CheckProblem{
+				Kind:      CheckNonceOutOfOrder,
+				Snapshot:  ref.snapshot,
+				RelPath:   ref.crp.RelPath,
+				ChunkName: curExtent.ChunkName,
+				Msg:       fmt.Sprintf("extent %d is neither named by its HMAC nor follows extent %d's nonce; data may have been tampered with (chunk substitution or reordering)", ref.idx, ref.idx-1),
+			})
+		}
+	}
+
+	reportProgress(100.0)
+	return result, nil
+}
+
+// Decrypts every extent of a downloaded chunk that is referenced by refs, recording problems in
+// result and what was learned about each extent in checkedExtents
+func checkChunkData(key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, chunkName string, ciphertextBuf []byte, refs []chunkExtentRef, checkedExtents map[snapshots.ChunkExtent]checkedExtent, result *CheckResult) {
+	var wholePlaintext, wholeNonce []byte = nil, nil
+	var wholeErr error = nil
+
+	for _, ref := range refs {
+		chunkExtent := ref.crp.ChunkExtents[ref.idx]
+		if _, ok := checkedExtents[chunkExtent]; ok {
+			continue
+		}
+		problem := CheckProblem{Snapshot: ref.snapshot, RelPath: ref.crp.RelPath, ChunkName: chunkName}
+
+		var extent, nonce []byte
+		if chunkExtent.EncLen > 0 {
+			if chunkExtent.Offset+chunkExtent.EncLen > int64(len(ciphertextBuf)) {
+				// already reported as truncated
+				continue
+			}
+			var err error
+			extent, nonce, err = decryptChunkData(key, sealKeys, ciphertextBuf[chunkExtent.Offset:chunkExtent.Offset+chunkExtent.EncLen], chunkExtent.Aad())
+			if err != nil {
+				problem.Kind = CheckBadChunkData
+				problem.Msg = fmt.Sprintf("extent could not be decrypted: %v", err)
+				result.Problems = append(result.Problems, problem)
+				continue
+			}
+			if int64(len(extent)) != chunkExtent.Len {
+				problem.Kind = CheckBadChunkData
+				problem.Msg = fmt.Sprintf("extent decrypted to %d bytes, expected %d", len(extent), chunkExtent.Len)
+				result.Problems = append(result.Problems, problem)
+				continue
+			}
+		} else {
+			if wholePlaintext == nil && wholeErr == nil {
+				wholePlaintext, wholeNonce, wholeErr = decryptChunkData(key, sealKeys, ciphertextBuf, nil)
+			}
+			if wholeErr != nil {
+				problem.Kind = CheckBadChunkData
+				problem.Msg = fmt.Sprintf("chunk could not be decrypted: %v", wholeErr)
+				result.Problems = append(result.Problems, problem)
+				continue
+			}
+			if chunkExtent.Offset+chunkExtent.Len > int64(len(wholePlaintext)) {
+				problem.Kind = CheckTruncatedChunk
+				problem.Msg = fmt.Sprintf("extent ends at byte %d but chunk decrypted to only %d bytes", chunkExtent.Offset+chunkExtent.Len, len(wholePlaintext))
+				result.Problems = append(result.Problems, problem)
+				continue
+			}
+			extent = wholePlaintext[chunkExtent.Offset : chunkExtent.Offset+chunkExtent.Len]
+			nonce = wholeNonce
+		}
+
+		checkedExtents[chunkExtent] = checkedExtent{
+			nonce:       nonce,
+			nameMatches: cryptography.ComputeChunkName(hmacKey, extent) == chunkName,
+		}
+	}
+}
+
+func decryptChunkData(key []byte, sealKeys *cryptography.SealKeys, ciphertext []byte, aad []byte) (plaintext []byte, nonce []byte, err error) {
+	if len(ciphertext) < minCiphertextLen {
+		return nil, nil, fmt.Errorf("ciphertext is only %d bytes", len(ciphertext))
+	}
+	return cryptography.DecryptDataBufferWithAad(key, sealKeys, ciphertext, aad)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRepository(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	upload := func(objName string, buf []byte) {
+		assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, objName, buf, objstore.ComputeETag(buf)))
+	}
+	encryptWithNonce := func(plaintext []byte, lastNonceByte byte) []byte {
+		nonce := make([]byte, 12)
+		nonce[11] = lastNonceByte
+		ciphertext, err := cryptography.EncryptBufferWithNonce(key, plaintext, nonce)
+		assert.NoError(t, err)
+		return ciphertext
+	}
+
+	// A content-addressed chunk
+	goodPlaintext := []byte("good chunk contents")
+	goodName := cryptography.ComputeChunkName(hmacKey, goodPlaintext)
+	goodCiphertext, err := cryptography.EncryptBuffer(key, goodPlaintext)
+	assert.NoError(t, err)
+	upload("chunks/"+goodName, goodCiphertext)
+
+	// A packed chunk with one intact and one corrupted entry
+	item1, err := cryptography.EncryptBuffer(key, []byte("item1"))
+	assert.NoError(t, err)
+	item2, err := cryptography.EncryptBuffer(key, []byte("item2"))
+	assert.NoError(t, err)
+	item2[len(item2)-1] ^= 0xff
+	upload("chunks/packed", append(append([]byte{}, item1...), item2...))
+
+	// Legacy chunks with sequential nonces, and one from somewhere else
+	upload("chunks/legacy1", encryptWithNonce([]byte("legacy1"), 1))
+	upload("chunks/legacy2", encryptWithNonce([]byte("legacy2"), 2))
+	upload("chunks/legacy5", encryptWithNonce([]byte("legacy5"), 5))
+
+	upload("chunks/orphan", goodCiphertext)
+
+	ce := func(name string, offset int64, len int64, encLen int64) snapshots.ChunkExtent {
+		return snapshots.ChunkExtent{ChunkName: name, Offset: offset, Len: len, EncLen: encLen}
+	}
+	relPaths := map[string]snapshots.CloudRelPath{
+		"good":       {RelPath: "good", ChunkExtents: []snapshots.ChunkExtent{ce(goodName, 0, int64(len(goodPlaintext)), 0)}},
+		"item1":      {RelPath: "item1", ChunkExtents: []snapshots.ChunkExtent{ce("packed", 0, 5, int64(len(item1)))}},
+		"item2":      {RelPath: "item2", ChunkExtents: []snapshots.ChunkExtent{ce("packed", int64(len(item1)), 5, int64(len(item2)))}},
+		"truncated":  {RelPath: "truncated", ChunkExtents: []snapshots.ChunkExtent{ce("packed", int64(len(item1)), 5, 1000)}},
+		"missing":    {RelPath: "missing", ChunkExtents: []snapshots.ChunkExtent{ce("missing", 0, 10, 0)}},
+		"inorder":    {RelPath: "inorder", ChunkExtents: []snapshots.ChunkExtent{ce("legacy1", 0, 7, 0), ce("legacy2", 0, 7, 0)}},
+		"outoforder": {RelPath: "outoforder", ChunkExtents: []snapshots.ChunkExtent{ce("legacy1", 0, 7, 0), ce("legacy5", 0, 7, 0)}},
+		"cdc":        {RelPath: "cdc", ChunkExtents: []snapshots.ChunkExtent{ce("legacy5", 0, 7, 0), ce(goodName, 0, int64(len(goodPlaintext)), 0)}},
+	}
+	encBackupName, err := cryptography.EncryptFilename(key, "backup")
+	assert.NoError(t, err)
+	encSnapshotName, err := cryptography.EncryptFilename(key, "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	snapshotObj := &snapshots.Snapshot{DecryptedName: "2022-01-01_01.01.01", RelPaths: relPaths}
+	_, err = snapshots.SerializeAndWriteSnapshotObj(snapshotObj, key, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+	assert.NoError(t, err)
+
+	// An index file that does not decrypt
+	encBadSnapshotName, err := cryptography.EncryptFilename(key, "2022-01-02_01.01.01")
+	assert.NoError(t, err)
+	upload(encBackupName+"/@"+encBadSnapshotName, []byte("not an index file"))
+
+	countProblems := func(result *CheckResult) map[CheckProblemKind][]string {
+		m := make(map[CheckProblemKind][]string)
+		for _, problem := range result.Problems {
+			m[problem.Kind] = append(m[problem.Kind], problem.RelPath+problem.ChunkName)
+		}
+		return m
+	}
+
+	// Without reading data
+	result, err := CheckRepository(ctx, key, hmacKey, nil, objst, bucket, 0, vlog, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.SnapshotsChecked)
+	assert.Equal(t, 6, result.ChunksReferenced)
+	assert.Equal(t, 6, result.ChunksInBucket)
+	assert.Equal(t, 0, result.ChunksRead)
+	problems := countProblems(result)
+	assert.Equal(t, []string{"missingmissing"}, problems[CheckMissingChunk])
+	assert.Equal(t, []string{"truncatedpacked"}, problems[CheckTruncatedChunk])
+	assert.Equal(t, 0, len(problems[CheckOrphanChunk]))
+	assert.Equal(t, 1, len(result.OrphanChunks))
+	assert.Equal(t, "orphan", result.OrphanChunks[0].ChunkName)
+	assert.Equal(t, 1, len(problems[CheckBadIndexFile]))
+	assert.Equal(t, 0, len(problems[CheckBadChunkData]))
+	assert.Equal(t, 0, len(problems[CheckNonceOutOfOrder]))
+
+	// Reading all data also authenticates it and checks nonce ordering
+	var lastPercentDone float64
+	result, err = CheckRepository(ctx, key, hmacKey, nil, objst, bucket, 100, vlog, func(percentDone float64) { lastPercentDone = percentDone })
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.ChunksRead)
+	assert.Equal(t, float64(100), lastPercentDone)
+	problems = countProblems(result)
+	assert.Equal(t, []string{"item2packed"}, problems[CheckBadChunkData])
+	assert.Equal(t, []string{"outoforderlegacy5"}, problems[CheckNonceOutOfOrder])
+	assert.Equal(t, 1, len(problems[CheckMissingChunk]))
+	assert.Equal(t, 1, len(problems[CheckTruncatedChunk]))
+}
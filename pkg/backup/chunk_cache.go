@@ -12,6 +12,7 @@ import (
 
 	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
 	"github.com/fsctl/tless/pkg/util"
 )
 
@@ -38,16 +39,19 @@ type CacheStatistics struct {
 	evictedChunks            []string
 	redownloadedChunks       []string
 	redownloadedChunksBytes  int64
+	totalRangeDownloads      int
+	totalRangeDownloadsBytes int64
 }
 
 type ChunkCache struct {
-	objst  *objstore.ObjStore
-	key    []byte
-	vlog   *util.VLog
-	uid    int
-	gid    int
-	chunks map[string]CachedChunk
-	stats  *CacheStatistics
+	objst    *objstore.ObjStore
+	key      []byte
+	sealKeys *cryptography.SealKeys
+	vlog     *util.VLog
+	uid      int
+	gid      int
+	chunks   map[string]CachedChunk
+	stats    *CacheStatistics
 }
 
 func removeCachedChunk(objName string) {
@@ -57,7 +61,7 @@ func removeCachedChunk(objName string) {
 	}
 }
 
-func NewChunkCache(objst *objstore.ObjStore, key []byte, vlog *util.VLog, uid int, gid int, cachesDirPath string, maxChunkCacheSizeMb int64) *ChunkCache {
+func NewChunkCache(objst *objstore.ObjStore, key []byte, sealKeys *cryptography.SealKeys, vlog *util.VLog, uid int, gid int, cachesDirPath string, maxChunkCacheSizeMb int64) *ChunkCache {
 	if maxChunkCacheSizeMb > 0 {
 		MaxCacheSizeOnDisk = maxChunkCacheSizeMb * 1024 * 1024
 	}
@@ -65,12 +69,13 @@ func NewChunkCache(objst *objstore.ObjStore, key []byte, vlog *util.VLog, uid in
 
 	// Construct cache obj
 	cc := &ChunkCache{
-		objst:  objst,
-		key:    key,
-		vlog:   vlog,
-		uid:    uid,
-		gid:    gid,
-		chunks: make(map[string]CachedChunk, 0),
+		objst:    objst,
+		key:      key,
+		sealKeys: sealKeys,
+		vlog:     vlog,
+		uid:      uid,
+		gid:      gid,
+		chunks:   make(map[string]CachedChunk, 0),
 		stats: &CacheStatistics{
 			hits:                     0,
 			total:                    0,
@@ -80,6 +85,8 @@ func NewChunkCache(objst *objstore.ObjStore, key []byte, vlog *util.VLog, uid in
 			evictedChunks:            make([]string, 0),
 			redownloadedChunks:       make([]string, 0),
 			redownloadedChunksBytes:  0,
+			totalRangeDownloads:      0,
+			totalRangeDownloadsBytes: 0,
 		},
 	}
 
@@ -121,9 +128,9 @@ func NewChunkCache(objst *objstore.ObjStore, key []byte, vlog *util.VLog, uid in
 				log.Printf("error: NewChunkCache: WalkDirFunc: failed to read file cache of obj '%s': %v", objName, err)
 				return err
 			}
-			plaintextBuf, nonce, err := cryptography.DecryptBufferReturningNonce(key, ciphertextChunkBuf)
+			plaintextBuf, nonce, err := cryptography.DecryptDataBuffer(key, sealKeys, ciphertextChunkBuf)
 			if err != nil {
-				vlog.Printf("NewChunkCache: WalkDirFunc: DecryptBufferReturningNonce failed on chunk '%s' (purging): %v\n", objName, err)
+				vlog.Printf("NewChunkCache: WalkDirFunc: DecryptDataBuffer failed on chunk '%s' (purging): %v\n", objName, err)
 				// remove this chunk
 				removeCachedChunk(objName)
 			} else {
@@ -185,6 +192,38 @@ func (cc *ChunkCache) FetchExtentIntoBuffer(ctx context.Context, bucket string,
 	return extent, nonce, nil
 }
 
+// Returns the plaintext of chunkExtent. Extents that were encrypted on their own within a packed
+// chunk are fetched with a ranged download of just their ciphertext and are not cached; all others
+// are extracted from the cached (or newly downloaded and cached) plaintext of the entire chunk.
+func (cc *ChunkCache) FetchChunkExtent(ctx context.Context, bucket string, chunkExtent snapshots.ChunkExtent) (extent []byte, nonce []byte, err error) {
+	objectName := "chunks/" + chunkExtent.ChunkName
+	if chunkExtent.EncLen == 0 {
+		return cc.FetchExtentIntoBuffer(ctx, bucket, objectName, chunkExtent.Offset, chunkExtent.Len)
+	}
+
+	cc.vlog.Printf("FetchChunkExtent: downloading %d bytes at offset %d of '%s'", chunkExtent.EncLen, chunkExtent.Offset, chunkExtent.ChunkName)
+	ciphertextBuf, err := cc.objst.DownloadObjRangeToBuffer(ctx, bucket, objectName, chunkExtent.Offset, chunkExtent.EncLen)
+	if err != nil {
+		log.Printf("error: FetchChunkExtent: failed to retrieve range of object '%s': %v", objectName, err)
+		return nil, nil, err
+	}
+	cc.stats.totalRangeDownloads += 1
+	cc.stats.totalRangeDownloadsBytes += int64(len(ciphertextBuf))
+
+	extent, nonce, err = cryptography.DecryptDataBufferWithAad(cc.key, cc.sealKeys, ciphertextBuf, chunkExtent.Aad())
+	if err != nil {
+		log.Printf("error: FetchChunkExtent: could not decrypt extent of '%s': %v", objectName, err)
+		return nil, nil, err
+	}
+	if int64(len(extent)) != chunkExtent.Len {
+		msg := fmt.Sprintf("error: FetchChunkExtent: extent of '%s' decrypted to %d bytes, expected %d", objectName, len(extent), chunkExtent.Len)
+		log.Println(msg)
+		return nil, nil, fmt.Errorf(msg)
+	}
+
+	return extent, nonce, nil
+}
+
 func readEntireFile(objName string) ([]byte, error) {
 	path := filepath.Join(CacheDirectory, objName)
 	f, err := os.Open(path)
@@ -258,9 +297,9 @@ func (cc *ChunkCache) saveObjToCache(objName string, ciphertextBuf []byte) {
 	cc.stats.totalChunkDownloadsBytes += int64(len(ciphertextBuf))
 
 	// Decrypt the ciphertext buffer and save its plaintext in memory
-	plaintextBuf, nonce, err := cryptography.DecryptBufferReturningNonce(cc.key, ciphertextBuf)
+	plaintextBuf, nonce, err := cryptography.DecryptDataBuffer(cc.key, cc.sealKeys, ciphertextBuf)
 	if err != nil {
-		log.Printf("error: saveObjToCache: DecryptBufferReturningNonce failed: %v\n", err)
+		log.Printf("error: saveObjToCache: DecryptDataBuffer failed: %v\n", err)
 		return
 	}
 
@@ -322,6 +361,7 @@ func (cc *ChunkCache) PrintCacheStatistics() {
 		percentageHitRate := float64(100) * (float64(cc.stats.hits) / float64(cc.stats.total))
 		cc.vlog.Printf("ChunkCache> cache hit rate %d / %d (%02f%%)", cc.stats.hits, cc.stats.total, percentageHitRate)
 		cc.vlog.Printf("ChunkCache> downloaded %d chunks (%s)", cc.stats.totalChunkDownloads, util.FormatBytesAsString(cc.stats.totalChunkDownloadsBytes))
+		cc.vlog.Printf("ChunkCache> downloaded %d extents of packed chunks (%s)", cc.stats.totalRangeDownloads, util.FormatBytesAsString(cc.stats.totalRangeDownloadsBytes))
 		cc.vlog.Printf("ChunkCache> total memory usage %s", util.FormatBytesAsString(cc.stats.totalMemoryUseBytes))
 		cc.vlog.Printf("ChunkCache> evicted %d chunks with %d (%.02f%%) redownloaded later (%s redownloaded)", len(cc.stats.evictedChunks), len(cc.stats.redownloadedChunks), float64(len(cc.stats.redownloadedChunks))/float64(len(cc.stats.evictedChunks)), util.FormatBytesAsString(cc.stats.redownloadedChunksBytes))
 	}
@@ -15,70 +15,131 @@ import (
 type runWhileUploadingFuncType func(runWhileUploadingFinished chan bool, goodTime bool, forcePersist bool)
 
 type chunkPackerItem struct {
-	relPath string
-	Offset  int
-	Len     int
-	bjt     *database.BackupJournalTask
+	relPath     string
+	Offset      int
+	Len         int
+	bjt         *database.BackupJournalTask
+	hardLinkKey string // set if other hard links to this file are waiting for its extents
 }
 
+// A full chunk taken out of the packer, waiting to be uploaded and have its items finalized
+type packedChunk struct {
+	items             []chunkPackerItem
+	plaintextChunkBuf []byte
+}
+
+// Packs small dir entries into shared chunks. Safe for use by multiple upload workers: a full
+// chunk is handed off to the worker that filled it while the others keep packing a new one.
 type chunkPacker struct {
+	lock                  sync.Mutex
 	items                 []chunkPackerItem
 	plaintextChunkBuf     []byte
-	posInPlaintextChunk   int
 	db                    *database.DB
 	dbLock                *sync.Mutex
 	ctx                   context.Context
 	objst                 *objstore.ObjStore
 	bucket                string
 	key                   []byte
+	sealKeys              *cryptography.SealKeys
+	padding               cryptography.Padding
 	vlog                  *util.VLog
 	runWhileUploadingFunc runWhileUploadingFuncType
-	totalCntJournal       *int64
-	finishedCountJournal  *int64
+	jc                    *journalCounts
 	stats                 *BackupStats
+	hardLinks             *hardLinks
 }
 
-func (cp *chunkPacker) AddDirEntry(relPath string, buf []byte, bjt *database.BackupJournalTask) (succeeded bool) {
-	//cp.vlog.Printf("chunkPacker: AddDirEntry: asked to add %d byte buffer", len(buf))
-
-	// If adding this buffer would exceed chunk's capacity, return false immediately
-	if int64(len(buf))+int64(cp.posInPlaintextChunk) > ChunkSize {
-		//cp.vlog.Printf("chunkPacker: AddDirEntry: declining (%d+%d kb = %d kb would exceed ChunkSize = %d kb)", int64(len(buf))/1024, int64(cp.posInPlaintextChunk)/1024, (int64(len(buf))+int64(cp.posInPlaintextChunk))/1024, ChunkSize/1024)
-		return false
+// Adds buf to the chunk being packed. If buf would exceed the chunk's capacity, the chunk is
+// first uploaded and its items finalized on the calling goroutine. hardLinkKey is the key of the
+// entry's hard link group if it leads one, else empty.
+func (cp *chunkPacker) AddDirEntry(relPath string, buf []byte, bjt *database.BackupJournalTask, hardLinkKey string) {
+	cp.lock.Lock()
+	var fullChunk *packedChunk = nil
+	if int64(len(buf))+int64(len(cp.plaintextChunkBuf)) > ChunkSize {
+		fullChunk = cp.takePackedChunk()
 	}
-
-	// Otherwise, add the chunk
-	//cp.vlog.Printf("chunkPacker: AddDirEntry: adding dir entry")
 	newItem := chunkPackerItem{
-		relPath: relPath,
-		Offset:  cp.posInPlaintextChunk,
-		Len:     len(buf),
-		bjt:     bjt,
+		relPath:     relPath,
+		Offset:      len(cp.plaintextChunkBuf),
+		Len:         len(buf),
+		bjt:         bjt,
+		hardLinkKey: hardLinkKey,
 	}
 	cp.plaintextChunkBuf = append(cp.plaintextChunkBuf, buf...)
-	cp.posInPlaintextChunk += len(buf)
 	cp.items = append(cp.items, newItem)
+	cp.lock.Unlock()
+
 	if cp.stats != nil {
 		cp.stats.AddBytes(int64(len(buf)))
 	}
-	return true
+
+	if fullChunk != nil {
+		cp.uploadAndFinalize(fullChunk)
+	}
+}
+
+// Uploads whatever is in the chunk being packed and finalizes its items
+func (cp *chunkPacker) Complete() {
+	cp.lock.Lock()
+	pc := cp.takePackedChunk()
+	cp.lock.Unlock()
+
+	cp.uploadAndFinalize(pc)
 }
 
-func (cp *chunkPacker) Complete() (isJournalComplete bool) {
-	isJournalComplete = false
+// Must be called with cp.lock held
+func (cp *chunkPacker) takePackedChunk() *packedChunk {
+	pc := &packedChunk{
+		items:             cp.items,
+		plaintextChunkBuf: cp.plaintextChunkBuf,
+	}
 
+	// Reset struct to initial state so it can be reused for next chunk
+	cp.items = make([]chunkPackerItem, 0)
+	cp.plaintextChunkBuf = make([]byte, 0)
+
+	return pc
+}
+
+// Packed chunks are the concatenation of each item's separately encrypted plaintext, so that a
+// single item can be restored with a ranged download of just its ciphertext
+func (cp *chunkPacker) uploadAndFinalize(pc *packedChunk) {
 	//
 	// Commit the chunk to the cloud if there's anything in it
 	//
 	chunkName := generateRandomChunkName()
-	//cp.vlog.Printf("chunkPacker: Complete: completing the current chunk as %s", chunkName)
-	if len(cp.plaintextChunkBuf) > 0 {
-		//cp.vlog.Printf("chunkPacker: Complete: found %d byte plaintextChunkBuf with posInChunkBuf = %d", len(cp.plaintextChunkBuf), cp.posInPlaintextChunk)
+	chunkExtents := make([]snapshots.ChunkExtent, len(pc.items))
+	if len(pc.plaintextChunkBuf) > 0 {
+		// Encrypt each item, padded so that the ranged download of a single item doesn't reveal
+		// its size
+		ciphertextChunkBuf := make([]byte, 0, len(pc.plaintextChunkBuf))
+		var paddingLen int64 = 0
+		for i, item := range pc.items {
+			// Bind each entry to where it goes, so that entries can't be moved around undetected
+			aad := cryptography.PackedEntryAad(chunkName, int64(len(ciphertextChunkBuf)))
+			ciphertextItemBuf, itemPaddingLen, err := cryptography.EncryptPaddedDataBufferWithAad(cp.key, cp.sealKeys, pc.plaintextChunkBuf[item.Offset:item.Offset+item.Len], true, cp.padding, aad)
+			if err != nil {
+				log.Printf("error: chunkPacker.uploadAndFinalize: EncryptPaddedDataBufferWithAad failed: %v\n", err)
+				cp.failItems(pc)
+				return
+			}
+			chunkExtents[i] = snapshots.ChunkExtent{
+				ChunkName:    chunkName,
+				Offset:       int64(len(ciphertextChunkBuf)),
+				Len:          int64(item.Len),
+				EncLen:       int64(len(ciphertextItemBuf)),
+				BoundToChunk: true,
+			}
+			ciphertextChunkBuf = append(ciphertextChunkBuf, ciphertextItemBuf...)
+			paddingLen += itemPaddingLen
+		}
 
-		// Encrypt the chunk
-		ciphertextChunkBuf, err := cryptography.EncryptBuffer(cp.key, cp.plaintextChunkBuf)
+		// Hide the total size of the entries by padding the end of the chunk, which no extent covers
+		ciphertextChunkBuf, tailPaddingLen, err := cryptography.AppendRandomPadding(ciphertextChunkBuf, cp.padding)
+		paddingLen += tailPaddingLen
 		if err != nil {
-			log.Fatalf("error: chunkPacker.Complete: EncryptBuffer failed: %v\n", err)
+			log.Printf("error: chunkPacker.uploadAndFinalize: AppendRandomPadding failed: %v\n", err)
+			cp.failItems(pc)
 			return
 		}
 
@@ -92,66 +153,73 @@ func (cp *chunkPacker) Complete() (isJournalComplete bool) {
 
 		// Upload chunk (and run unrelated parallel func during upload)
 		objName := "chunks/" + chunkName
-		cp.vlog.Printf("chunkPacker: Complete: writing object '%s' to cloud (%s)", objName, util.FormatBytesAsString(int64(len(ciphertextChunkBuf))))
+		cp.vlog.Printf("chunkPacker: uploadAndFinalize: writing object '%s' to cloud (%s)", objName, util.FormatBytesAsString(int64(len(ciphertextChunkBuf))))
 		err = cp.objst.UploadObjFromBuffer(cp.ctx, cp.bucket, objName, ciphertextChunkBuf, objstore.ComputeETag(ciphertextChunkBuf))
-		if err != nil {
-			log.Printf("error: chunkPacker.Complete: failed while uploading '%s': %v\n", chunkName, err)
-			return
-		}
 
 		// Wait for runWhileUploadingFunc to finish
 		cp.vlog.Println("RUN WHILE UPLOAD> Waiting for 'runWhileUploadingFunc' to finish...")
 		<-runWhileUploadingFinished
 		cp.vlog.Println("RUN WHILE UPLOAD> Has finished: 'runWhileUploadingFunc'")
+
+		if err != nil {
+			log.Printf("error: chunkPacker.uploadAndFinalize: failed while uploading '%s': %v\n", chunkName, err)
+			cp.failItems(pc)
+			return
+		}
+		if cp.stats != nil {
+			cp.stats.AddPaddingBytes(paddingLen)
+		}
+		recordPadding(cp.db, cp.dbLock, objName, paddingLen)
 	}
 
 	//
 	// Finalize each item
 	//
-	for _, item := range cp.items {
-		// sanity check
-		if isJournalComplete {
-			log.Println("error: chunkPacker.Complete: something's wrong; journal should never be exhausted before end of last item in this loop")
-		}
+	for i, item := range pc.items {
 		crp := &snapshots.CloudRelPath{
-			RelPath: item.relPath,
-			ChunkExtents: []snapshots.ChunkExtent{
-				{
-					ChunkName: chunkName,
-					Offset:    int64(item.Offset),
-					Len:       int64(item.Len),
-				},
-			},
+			RelPath:      item.relPath,
+			ChunkExtents: []snapshots.ChunkExtent{chunkExtents[i]},
 		}
-		cp.vlog.Printf("chunkPacker: Complete: finalizing '%s' with offset=%d, len=%d", crp.RelPath, crp.ChunkExtents[0].Offset, crp.ChunkExtents[0].Len)
+		cp.vlog.Printf("chunkPacker: uploadAndFinalize: finalizing '%s' with offset=%d, len=%d, enclen=%d", crp.RelPath, crp.ChunkExtents[0].Offset, crp.ChunkExtents[0].Len, crp.ChunkExtents[0].EncLen)
 		updateLastBackupTime(cp.db, cp.dbLock, item.bjt.DirEntId)
-		isJournalComplete = completeTask(cp.db, cp.dbLock, item.bjt, crp, cp.totalCntJournal, cp.finishedCountJournal)
+		completeTask(cp.db, cp.dbLock, item.bjt, crp, cp.jc)
+		if item.hardLinkKey != "" {
+			cp.finishHardLinks(item.hardLinkKey, crp.ChunkExtents)
+		}
 	}
+}
 
-	// Reset struct to initial state so it can be reused for next chunk
-	cp.items = make([]chunkPackerItem, 0)
-	cp.plaintextChunkBuf = make([]byte, 0)
-	cp.posInPlaintextChunk = 0
-
-	//cp.vlog.Printf("chunkPacker: Complete: return isJournalComplete=%s", isJournalComplete)
-	return isJournalComplete
+// Finishes the tasks of a chunk that could not be encrypted or uploaded without index entries, and with their
+// last backup times reset so that the next backup picks these dir entries up again
+func (cp *chunkPacker) failItems(pc *packedChunk) {
+	for _, item := range pc.items {
+		resetLastBackupTime(cp.db, cp.dbLock, item.bjt.DirEntId)
+		if cp.stats != nil {
+			cp.stats.AddError()
+		}
+		completeTask(cp.db, cp.dbLock, item.bjt, nil, cp.jc)
+		if item.hardLinkKey != "" {
+			cp.finishHardLinks(item.hardLinkKey, nil)
+		}
+	}
 }
 
-func newChunkPacker(ctx context.Context, objst *objstore.ObjStore, bucket string, db *database.DB, dbLock *sync.Mutex, key []byte, vlog *util.VLog, runWhileUploadingFunc runWhileUploadingFuncType, totalCntJournal *int64, finishedCountJournal *int64, stats *BackupStats) *chunkPacker {
+func newChunkPacker(ctx context.Context, objst *objstore.ObjStore, bucket string, db *database.DB, dbLock *sync.Mutex, key []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, vlog *util.VLog, runWhileUploadingFunc runWhileUploadingFuncType, jc *journalCounts, stats *BackupStats) *chunkPacker {
 	return &chunkPacker{
 		items:                 make([]chunkPackerItem, 0),
 		plaintextChunkBuf:     make([]byte, 0),
-		posInPlaintextChunk:   0,
 		db:                    db,
 		dbLock:                dbLock,
 		ctx:                   ctx,
 		objst:                 objst,
 		bucket:                bucket,
 		key:                   key,
+		sealKeys:              sealKeys,
+		padding:               padding,
 		vlog:                  vlog,
 		runWhileUploadingFunc: runWhileUploadingFunc,
-		totalCntJournal:       totalCntJournal,
-		finishedCountJournal:  finishedCountJournal,
+		jc:                    jc,
 		stats:                 stats,
+		hardLinks:             newHardLinks(),
 	}
 }
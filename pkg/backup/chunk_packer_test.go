@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkPackerConcurrentWorkers(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	db, err := database.NewDB(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.CreateTablesIfNotExist())
+
+	const numTasks = 200
+	insertBJTxn, err := db.NewInsertBackupJournalStmt("/dir/subdir")
+	assert.NoError(t, err)
+	for i := 0; i < numTasks; i++ {
+		assert.NoError(t, insertBJTxn.InsertBackupJournalRow(int64(i+1), database.Unstarted, database.Updated))
+	}
+	insertBJTxn.Close()
+
+	var dbLock sync.Mutex
+	jc := &journalCounts{total: numTasks}
+	cp := newChunkPacker(ctx, objst, bucket, db, &dbLock, key, nil, cryptography.PaddingNone, vlog, nil, jc, nil)
+
+	// Several workers add entries while chunks are completed underneath them
+	tasks := make(chan *database.BackupJournalTask)
+	var wg sync.WaitGroup
+	for i := 0; i < MaxBackupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bjt := range tasks {
+				cp.AddDirEntry(fmt.Sprintf("file%d", bjt.DirEntId), []byte(fmt.Sprintf("<contents of file%d>", bjt.DirEntId)), bjt, "")
+				if bjt.DirEntId%50 == 0 {
+					cp.Complete()
+				}
+			}
+		}()
+	}
+	for i := 0; i < numTasks; i++ {
+		dbLock.Lock()
+		bjt, err := db.ClaimNextBackupJournalTask()
+		dbLock.Unlock()
+		assert.NoError(t, err)
+		tasks <- bjt
+	}
+	close(tasks)
+	wg.Wait()
+	cp.Complete()
+
+	// Every task is finished exactly once
+	finished, total := jc.get()
+	assert.Equal(t, int64(numTasks), finished)
+	assert.Equal(t, int64(numTasks), total)
+	finishedDb, totalDb, err := db.GetBackupJournalCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(numTasks), finishedDb)
+	assert.Equal(t, int64(numTasks), totalDb)
+
+	// Every file's contents are recoverable with a ranged download of just its extent
+	indexEntries, err := db.GetAllBackupJournalRowIndexEntries()
+	assert.NoError(t, err)
+	assert.Equal(t, numTasks, len(indexEntries))
+	prevCacheDirectory := CacheDirectory
+	t.Cleanup(func() { CacheDirectory = prevCacheDirectory })
+	cc := NewChunkCache(objst, key, nil, vlog, -1, -1, t.TempDir(), -1)
+	mChunkNames := make(map[string]bool)
+	for _, indexEntry := range indexEntries {
+		crp := snapshots.NewCloudRelPathFromJson(indexEntry)
+		assert.Equal(t, 1, len(crp.ChunkExtents))
+		assert.Greater(t, crp.ChunkExtents[0].EncLen, int64(0))
+		mChunkNames[crp.ChunkExtents[0].ChunkName] = true
+
+		plaintext, _, err := cc.FetchChunkExtent(ctx, bucket, crp.ChunkExtents[0])
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("<contents of %s>", crp.RelPath), string(plaintext))
+	}
+	assert.Greater(t, len(mChunkNames), 1)
+	assert.Equal(t, numTasks, cc.stats.totalRangeDownloads)
+
+	// An entry copied into another chunk does not decrypt there
+	crp := snapshots.NewCloudRelPathFromJson(indexEntries[0])
+	assert.True(t, crp.ChunkExtents[0].BoundToChunk)
+	chunkBuf, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/"+crp.ChunkExtents[0].ChunkName)
+	assert.NoError(t, err)
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, "chunks/copy", chunkBuf, objstore.ComputeETag(chunkBuf)))
+	movedExtent := crp.ChunkExtents[0]
+	movedExtent.ChunkName = "copy"
+	_, _, err = cc.FetchChunkExtent(ctx, bucket, movedExtent)
+	assert.Error(t, err)
+}
+
+func TestChunkPackerPadding(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	db, err := database.NewDB(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	const numTasks = 10
+	insertBJTxn, err := db.NewInsertBackupJournalStmt("/dir/subdir")
+	assert.NoError(t, err)
+	for i := 0; i < numTasks; i++ {
+		assert.NoError(t, insertBJTxn.InsertBackupJournalRow(int64(i+1), database.Unstarted, database.Updated))
+	}
+	insertBJTxn.Close()
+
+	var dbLock sync.Mutex
+	jc := &journalCounts{total: numTasks}
+	stats := NewBackupStats()
+	cp := newChunkPacker(ctx, objst, bucket, db, &dbLock, key, nil, cryptography.PaddingPadme, vlog, nil, jc, stats)
+	for i := 0; i < numTasks; i++ {
+		bjt, err := db.ClaimNextBackupJournalTask()
+		assert.NoError(t, err)
+		contents := make([]byte, 1000*i+7)
+		rand.New(rand.NewSource(int64(i))).Read(contents)
+		cp.AddDirEntry(fmt.Sprintf("file%d", bjt.DirEntId), contents, bjt, "")
+	}
+	cp.Complete()
+
+	// Each extent is padded, so that downloading it alone doesn't reveal the file's size, and the
+	// chunk is padded past its last extent. Every extent is still readable.
+	indexEntries, err := db.GetAllBackupJournalRowIndexEntries()
+	assert.NoError(t, err)
+	assert.Equal(t, numTasks, len(indexEntries))
+	prevCacheDirectory := CacheDirectory
+	t.Cleanup(func() { CacheDirectory = prevCacheDirectory })
+	cc := NewChunkCache(objst, key, nil, vlog, -1, -1, t.TempDir(), -1)
+	const gcmOverhead = 12 + 16 // nonce and tag
+	var encLenSum int64 = 0
+	var entryPaddingSum int64 = 0
+	for _, indexEntry := range indexEntries {
+		crp := snapshots.NewCloudRelPathFromJson(indexEntry)
+		ce := crp.ChunkExtents[0]
+		encLenSum += ce.EncLen
+		compressedLen := ce.EncLen - gcmOverhead
+		assert.Equal(t, cryptography.PaddedLen(compressedLen, cryptography.PaddingPadme), compressedLen)
+		assert.Greater(t, compressedLen, ce.Len+1) // format byte, contents and padding
+		entryPaddingSum += compressedLen - (ce.Len + 1)
+		plaintext, _, err := cc.FetchChunkExtent(ctx, bucket, ce)
+		assert.NoError(t, err)
+		assert.Equal(t, ce.Len, int64(len(plaintext)))
+	}
+	mCloudChunks, err := objst.GetObjList(ctx, bucket, "chunks/", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mCloudChunks))
+	for _, size := range mCloudChunks {
+		assert.Equal(t, cryptography.PaddedLen(encLenSum, cryptography.PaddingPadme), size)
+		assert.Greater(t, size, encLenSum)
+		assert.Equal(t, size-encLenSum+entryPaddingSum, stats.cntPadBytes)
+
+		// and the padding, which can't be seen in the bucket, shows in its space usage
+		total, padding, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, bucket, key, &dbLock, db, vlog)
+		assert.NoError(t, err)
+		assert.Equal(t, size, total)
+		assert.Equal(t, size-encLenSum+entryPaddingSum, padding)
+	}
+
+	// Padding of deleted objects is forgotten
+	for objName := range mCloudChunks {
+		assert.NoError(t, objst.DeleteObj(ctx, bucket, objName))
+	}
+	_, padding, err := snapshots.ComputeTotalCloudSpaceUsage(ctx, objst, bucket, key, &dbLock, db, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), padding)
+	objPadding, err := db.GetObjectPadding()
+	assert.NoError(t, err)
+	assert.Empty(t, objPadding)
+}
+
+func TestNumBackupWorkers(t *testing.T) {
+	assert.Equal(t, 1, numBackupWorkers("low"))
+	assert.GreaterOrEqual(t, numBackupWorkers("high"), 2)
+	assert.LessOrEqual(t, numBackupWorkers("high"), MaxBackupWorkers)
+}
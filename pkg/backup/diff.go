@@ -0,0 +1,387 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/fstraverse"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+type DiffKind int
+
+const (
+	DiffAdded DiffKind = iota
+	DiffRemoved
+	DiffModified
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+func (k DiffKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// What changed about a modified dir entry
+const (
+	DiffChangeType    = "type"
+	DiffChangeContent = "content"
+	DiffChangeSize    = "size"
+	DiffChangeMTime   = "mtime"
+	DiffChangeMode    = "mode"
+	DiffChangeXAttrs  = "xattrs"
+	DiffChangeSymlink = "symlink"
+)
+
+type DiffEntry struct {
+	Kind    DiffKind `json:"kind"`
+	RelPath string   `json:"relPath"`
+
+	// Only set for DiffModified. Empty if the stored metadata could not be read to find out.
+	Changes []string `json:"changes,omitempty"`
+}
+
+type DiffProgressFunc func(percentDone float64)
+
+// A dir entry's metadata header and the part of its contents that is stored after the header
+// in its first extent
+type storedDirEnt struct {
+	metadata         *dirEntMetadata
+	firstExtentConts []byte
+	size             int64
+	contentsInHeader bool // the whole file is in the first extent (dirs, symlinks, small files)
+	contentExtents   []snapshots.ChunkExtent
+}
+
+func fetchStoredDirEnt(ctx context.Context, cc *ChunkCache, bucket string, crp snapshots.CloudRelPath) (*storedDirEnt, error) {
+	if len(crp.ChunkExtents) == 0 {
+		log.Printf("error: fetchStoredDirEnt: crp.ChunkExtents has no elements on '%s'", crp.RelPath)
+		return nil, errors.New("no chunk extents")
+	}
+	plaintextBuf, _, err := cc.FetchChunkExtent(ctx, bucket, crp.ChunkExtents[0])
+	if err != nil {
+		log.Printf("error: fetchStoredDirEnt: failed to retrieve first extent of '%s': %v", crp.RelPath, err)
+		return nil, err
+	}
+	metadataPtr, fileContents, err := deserializeMetadataStruct(plaintextBuf)
+	if err != nil {
+		log.Printf("error: fetchStoredDirEnt: deserializeMetadataStruct failed on '%s': %v", crp.RelPath, err)
+		return nil, err
+	}
+
+	sde := &storedDirEnt{
+		metadata:         metadataPtr,
+		firstExtentConts: fileContents,
+		size:             int64(len(fileContents)),
+		contentsInHeader: len(crp.ChunkExtents) == 1,
+		contentExtents:   crp.ChunkExtents[1:],
+	}
+	for _, chunkExtent := range sde.contentExtents {
+		sde.size += chunkExtent.Len
+	}
+	if metadataPtr.IsSparse {
+		sde.size = metadataPtr.Size
+	}
+	return sde, nil
+}
+
+func dirEntTypeOf(isDir bool, isSymlink bool) string {
+	if isSymlink {
+		return "symlink"
+	} else if isDir {
+		return "dir"
+	}
+	return "file"
+}
+
+func chunkExtentsEqual(a []snapshots.ChunkExtent, b []snapshots.ChunkExtent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Compares the metadata common to both kinds of diff. mtimes are compared to the nanosecond if
+// both sides have nanoseconds (newMTimeHasNs, and old's metadata version), else to the second.
+func diffMetadata(old *dirEntMetadata, newIsDir bool, newIsSymlink bool, newMTime time.Time, newMTimeHasNs bool, newMode uint32, newXAttrs string, newSymlinkOrigin string) []string {
+	changes := make([]string, 0)
+	if dirEntTypeOf(old.IsDir, old.IsSymlink) != dirEntTypeOf(newIsDir, newIsSymlink) {
+		return append(changes, DiffChangeType)
+	}
+	if old.IsSymlink && old.SymlinkOrigin != newSymlinkOrigin {
+		changes = append(changes, DiffChangeSymlink)
+	}
+	if old.Version >= 1 && newMTimeHasNs {
+		if !old.mTime().Equal(newMTime) {
+			changes = append(changes, DiffChangeMTime)
+		}
+	} else if old.MTime != newMTime.Unix() {
+		changes = append(changes, DiffChangeMTime)
+	}
+	if old.Mode != newMode {
+		changes = append(changes, DiffChangeMode)
+	}
+	if old.XAttrs != newXAttrs {
+		changes = append(changes, DiffChangeXAttrs)
+	}
+	return changes
+}
+
+// Returns the paths added, removed and modified going from snapshotA to snapshotB, sorted by
+// path. Paths whose chunk extents are identical in both are unchanged and cost nothing; the
+// metadata of all others is downloaded to find out what changed.
+func DiffSnapshots(ctx context.Context, cc *ChunkCache, bucket string, snapshotA *snapshots.Snapshot, snapshotB *snapshots.Snapshot, progressFunc DiffProgressFunc) ([]DiffEntry, error) {
+	entries := make([]DiffEntry, 0)
+	candidates := make([]string, 0)
+
+	for relPath := range snapshotA.RelPaths {
+		if _, ok := snapshotB.RelPaths[relPath]; !ok {
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, RelPath: relPath})
+		}
+	}
+	for relPath, crpB := range snapshotB.RelPaths {
+		crpA, ok := snapshotA.RelPaths[relPath]
+		if !ok {
+			entries = append(entries, DiffEntry{Kind: DiffAdded, RelPath: relPath})
+		} else if !chunkExtentsEqual(crpA.ChunkExtents, crpB.ChunkExtents) {
+			candidates = append(candidates, relPath)
+		}
+	}
+	sort.Strings(candidates)
+
+	for i, relPath := range candidates {
+		entry := DiffEntry{Kind: DiffModified, RelPath: relPath}
+
+		sdeA, errA := fetchStoredDirEnt(ctx, cc, bucket, snapshotA.RelPaths[relPath])
+		sdeB, errB := fetchStoredDirEnt(ctx, cc, bucket, snapshotB.RelPaths[relPath])
+		if errA != nil || errB != nil {
+			// Report it as modified without saying how
+			entries = append(entries, entry)
+		} else {
+			entry.Changes = diffStoredDirEnts(sdeA, sdeB)
+
+			// Extents can differ without anything else changing, eg if a file was touched and reverted
+			if len(entry.Changes) > 0 {
+				entries = append(entries, entry)
+			}
+		}
+
+		if progressFunc != nil {
+			progressFunc(float64(i+1) / float64(len(candidates)) * 100)
+		}
+	}
+
+	sortDiffEntries(entries)
+	return entries, nil
+}
+
+// Returns the paths added, removed and modified going from snapshot to the live dir tree at
+// backupDirPath, sorted by path. Only entries whose mtime or ctime is no older than the snapshot
+// (to the second, since snapshot times are whole seconds) are examined: their stored metadata is
+// downloaded and their contents are compared against the local files. Since ctime can't be set
+// back, this catches files restored or edited with old mtimes, just as a backup would.
+func DiffSnapshotAgainstLive(ctx context.Context, cc *ChunkCache, hmacKey []byte, bucket string, snapshot *snapshots.Snapshot, backupDirPath string, excludes []string, vlog *util.VLog, progressFunc DiffProgressFunc) ([]DiffEntry, error) {
+	liveInfos, _, err := fstraverse.TraverseDryRun(backupDirPath, excludes, vlog)
+	if err != nil {
+		log.Printf("error: DiffSnapshotAgainstLive: TraverseDryRun failed on '%s': %v", backupDirPath, err)
+		return nil, err
+	}
+
+	// If the snapshot time is unknown, examine every entry
+	snapshotTime := snapshot.Datetime
+	if snapshotTime.IsZero() {
+		if t, err := time.Parse("2006-01-02_15.04.05", snapshot.DecryptedName); err == nil {
+			snapshotTime = t
+		}
+	}
+
+	entries := make([]DiffEntry, 0)
+	candidates := make([]string, 0)
+
+	for relPath := range snapshot.RelPaths {
+		if _, ok := liveInfos[relPath]; !ok {
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, RelPath: relPath})
+		}
+	}
+	for relPath, info := range liveInfos {
+		if _, ok := snapshot.RelPaths[relPath]; !ok {
+			entries = append(entries, DiffEntry{Kind: DiffAdded, RelPath: relPath})
+		} else if snapshotTime.IsZero() || isChangedSince(info, snapshotTime) {
+			candidates = append(candidates, relPath)
+		}
+	}
+	sort.Strings(candidates)
+
+	for i, relPath := range candidates {
+		entry := DiffEntry{Kind: DiffModified, RelPath: relPath}
+
+		sde, err := fetchStoredDirEnt(ctx, cc, bucket, snapshot.RelPaths[relPath])
+		if err == nil {
+			entry.Changes, err = diffLiveDirEnt(sde, filepath.Join(backupDirPath, relPath), liveInfos[relPath], hmacKey)
+		}
+		if err != nil {
+			// Report it as modified without saying how
+			entry.Changes = nil
+			entries = append(entries, entry)
+		} else if len(entry.Changes) > 0 {
+			entries = append(entries, entry)
+		}
+
+		if progressFunc != nil {
+			progressFunc(float64(i+1) / float64(len(candidates)) * 100)
+		}
+	}
+
+	sortDiffEntries(entries)
+	return entries, nil
+}
+
+// Whether info's mtime or ctime is in or after t's second. Any change to an entry's contents,
+// metadata or name updates its ctime, so an entry for which this is false is as it was at t.
+func isChangedSince(info fs.FileInfo, t time.Time) bool {
+	if info.ModTime().Unix() >= t.Unix() {
+		return true
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+	_, cTimeNs := util.StatATimeCTimeNs(st)
+	return time.Unix(0, cTimeNs).Unix() >= t.Unix()
+}
+
+func diffStoredDirEnts(sdeA *storedDirEnt, sdeB *storedDirEnt) []string {
+	mB := sdeB.metadata
+	changes := diffMetadata(sdeA.metadata, mB.IsDir, mB.IsSymlink, mB.mTime(), mB.Version >= 1, mB.Mode, mB.XAttrs, mB.SymlinkOrigin)
+	if len(changes) > 0 && changes[0] == DiffChangeType {
+		return changes
+	}
+	if sdeA.size != sdeB.size {
+		changes = append(changes, DiffChangeSize)
+	}
+	if !bytes.Equal(sdeA.firstExtentConts, sdeB.firstExtentConts) || !chunkExtentsEqual(sdeA.contentExtents, sdeB.contentExtents) ||
+		!sparseRegionsEqual(sdeA.metadata.SparseMap, mB.SparseMap) {
+		changes = append(changes, DiffChangeContent)
+	}
+	return changes
+}
+
+func diffLiveDirEnt(sde *storedDirEnt, absPath string, info fs.FileInfo, hmacKey []byte) ([]string, error) {
+	isSymlink := info.Mode()&fs.ModeSymlink != 0
+	symlinkOrigin := ""
+	if isSymlink {
+		symlinkOrigin, _ = os.Readlink(absPath)
+	}
+	xattrs, err := serializeXAttrsToHex(absPath)
+	if err != nil {
+		xattrs = ""
+	}
+
+	changes := diffMetadata(sde.metadata, info.IsDir(), isSymlink, info.ModTime(), true, uint32(info.Mode()), xattrs, symlinkOrigin)
+	if len(changes) > 0 && changes[0] == DiffChangeType {
+		return changes, nil
+	}
+	if info.IsDir() || isSymlink || info.Mode()&(fs.ModeNamedPipe|fs.ModeDevice) != 0 {
+		// FIFOs and devices have no contents, and opening them could block
+		return changes, nil
+	}
+
+	if sde.size != info.Size() {
+		return append(changes, DiffChangeSize, DiffChangeContent), nil
+	}
+	isSame, err := isLiveFileContentSame(sde, absPath, hmacKey)
+	if err != nil {
+		log.Printf("error: diffLiveDirEnt: could not compare contents of '%s': %v", absPath, err)
+		return nil, err
+	}
+	if !isSame {
+		changes = append(changes, DiffChangeContent)
+	}
+	return changes, nil
+}
+
+// Compares a local file against its stored contents without downloading anything beyond the
+// header: small files are in the header's extent and large files' chunks are named by the HMAC
+// of their contents, so rechunking the local file reproduces the same names if it's unchanged.
+func isLiveFileContentSame(sde *storedDirEnt, absPath string, hmacKey []byte) (bool, error) {
+	f, err := os.Open(absPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	// Sparse files are stored as their data regions only, so the holes have to be in the same places
+	var r io.Reader = f
+	if sde.metadata.IsSparse {
+		info, err := f.Stat()
+		if err != nil {
+			return false, err
+		}
+		liveRegions, err := findSparseRegions(f, info)
+		if err != nil {
+			return false, err
+		}
+		if liveRegions == nil || !sparseRegionsEqual(liveRegions, sde.metadata.SparseMap) {
+			return false, nil
+		}
+		r = newSparseReader(f, liveRegions)
+	}
+
+	if sde.contentsInHeader {
+		liveContents, err := io.ReadAll(r)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(liveContents, sde.firstExtentConts), nil
+	}
+
+	chunker := newCdcChunker(r, hmacKey)
+	for _, chunkExtent := range sde.contentExtents {
+		plaintextChunk, err := chunker.Next()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if int64(len(plaintextChunk)) != chunkExtent.Len || cryptography.ComputeChunkName(hmacKey, plaintextChunk) != chunkExtent.ChunkName {
+			return false, nil
+		}
+	}
+	if _, err := chunker.Next(); !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return true, nil
+}
+
+func sortDiffEntries(entries []DiffEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].RelPath < entries[j].RelPath
+	})
+}
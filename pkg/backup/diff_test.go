@@ -0,0 +1,190 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type diffTestStore struct {
+	t      *testing.T
+	ctx    context.Context
+	key    []byte
+	bucket string
+	objst  *objstore.ObjStore
+	cc     *ChunkCache
+}
+
+func newDiffTestStore(t *testing.T) *diffTestStore {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	prevCacheDirectory := CacheDirectory
+	t.Cleanup(func() { CacheDirectory = prevCacheDirectory })
+	cc := NewChunkCache(objst, key, nil, vlog, -1, -1, t.TempDir(), -1)
+
+	return &diffTestStore{t: t, ctx: ctx, key: key, bucket: bucket, objst: objst, cc: cc}
+}
+
+func (s *diffTestStore) uploadChunk(chunkName string, plaintext []byte) snapshots.ChunkExtent {
+	ciphertext, err := cryptography.EncryptBuffer(s.key, plaintext)
+	assert.NoError(s.t, err)
+	assert.NoError(s.t, s.objst.UploadObjFromBuffer(s.ctx, s.bucket, "chunks/"+chunkName, ciphertext, objstore.ComputeETag(ciphertext)))
+	return snapshots.ChunkExtent{ChunkName: chunkName, Offset: 0, Len: int64(len(plaintext))}
+}
+
+// Uploads a dir entry with its contents in the same extent as its header
+func (s *diffTestStore) uploadSmallDirEnt(chunkName string, relPath string, metadata dirEntMetadata, contents string) snapshots.CloudRelPath {
+	header, err := serializeMetadataStruct(metadata)
+	assert.NoError(s.t, err)
+	ce := s.uploadChunk(chunkName, append(header, []byte(contents)...))
+	return snapshots.CloudRelPath{RelPath: relPath, ChunkExtents: []snapshots.ChunkExtent{ce}}
+}
+
+func diffEntriesToMap(entries []DiffEntry) map[string]DiffEntry {
+	m := make(map[string]DiffEntry)
+	for _, entry := range entries {
+		m[entry.RelPath] = entry
+	}
+	return m
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	s := newDiffTestStore(t)
+
+	md := dirEntMetadata{MTime: 1600000000, Mode: 0644}
+	mdTouched := dirEntMetadata{MTime: 1600000001, Mode: 0644}
+
+	same := s.uploadSmallDirEnt("same", "same", md, "same")
+	snapshotA := &snapshots.Snapshot{DecryptedName: "2022-01-01_01.01.01", RelPaths: map[string]snapshots.CloudRelPath{
+		"same":     same,
+		"removed":  s.uploadSmallDirEnt("removed", "removed", md, "removed"),
+		"touched":  s.uploadSmallDirEnt("touched-a", "touched", md, "abc"),
+		"edited":   s.uploadSmallDirEnt("edited-a", "edited", md, "abc"),
+		"reverted": s.uploadSmallDirEnt("reverted-a", "reverted", md, "abc"),
+	}}
+	snapshotB := &snapshots.Snapshot{DecryptedName: "2022-01-02_01.01.01", RelPaths: map[string]snapshots.CloudRelPath{
+		"same":     same,
+		"added":    s.uploadSmallDirEnt("added", "added", md, "added"),
+		"touched":  s.uploadSmallDirEnt("touched-b", "touched", mdTouched, "abc"),
+		"edited":   s.uploadSmallDirEnt("edited-b", "edited", md, "abcd"),
+		"reverted": s.uploadSmallDirEnt("reverted-b", "reverted", md, "abc"),
+	}}
+
+	entries, err := DiffSnapshots(s.ctx, s.cc, s.bucket, snapshotA, snapshotB, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []DiffEntry{
+		{Kind: DiffAdded, RelPath: "added"},
+		{Kind: DiffModified, RelPath: "edited", Changes: []string{DiffChangeSize, DiffChangeContent}},
+		{Kind: DiffRemoved, RelPath: "removed"},
+		{Kind: DiffModified, RelPath: "touched", Changes: []string{DiffChangeMTime}},
+	}, entries)
+
+	// Reversed, additions and removals swap
+	entries, err = DiffSnapshots(s.ctx, s.cc, s.bucket, snapshotB, snapshotA, nil)
+	assert.NoError(t, err)
+	m := diffEntriesToMap(entries)
+	assert.Equal(t, DiffRemoved, m["added"].Kind)
+	assert.Equal(t, DiffAdded, m["removed"].Kind)
+}
+
+func TestDiffSnapshotAgainstLive(t *testing.T) {
+	s := newDiffTestStore(t)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	snapshotTime := time.Unix(1600000000, 0)
+	before := snapshotTime.Add(-time.Hour)
+	after := snapshotTime.Add(time.Hour)
+
+	dir := t.TempDir()
+	writeFile := func(name string, contents string, mtime time.Time) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+		assert.NoError(t, os.Chmod(path, 0644))
+		assert.NoError(t, os.Chtimes(path, mtime, mtime))
+		return path
+	}
+	metadataOf := func(path string, mtime time.Time) dirEntMetadata {
+		info, err := os.Lstat(path)
+		assert.NoError(t, err)
+		xattrs, _ := serializeXAttrsToHex(path)
+		return dirEntMetadata{MTime: mtime.Unix(), Mode: uint32(info.Mode()), XAttrs: xattrs}
+	}
+
+	relPaths := make(map[string]snapshots.CloudRelPath)
+
+	path := writeFile("unchanged", "unchanged", before)
+	relPaths["unchanged"] = s.uploadSmallDirEnt("unchanged", "unchanged", metadataOf(path, before), "unchanged")
+
+	// Examined even though its mtime is older than the snapshot, since its ctime isn't
+	path = writeFile("stale", "stale", before)
+	relPaths["stale"] = s.uploadSmallDirEnt("stale", "stale", metadataOf(path, before), "stored")
+
+	path = writeFile("edited", "edited!", after)
+	relPaths["edited"] = s.uploadSmallDirEnt("edited", "edited", metadataOf(path, before), "edited")
+
+	path = writeFile("same-size", "abcd", after)
+	relPaths["same-size"] = s.uploadSmallDirEnt("same-size", "same-size", metadataOf(path, before), "wxyz")
+
+	// A large file is compared by rechunking it rather than by downloading it
+	largeContents := "large file contents"
+	path = writeFile("large", largeContents, after)
+	header, err := serializeMetadataStruct(metadataOf(path, before))
+	assert.NoError(t, err)
+	relPaths["large"] = snapshots.CloudRelPath{RelPath: "large", ChunkExtents: []snapshots.ChunkExtent{
+		s.uploadChunk("large-header", header),
+		{ChunkName: cryptography.ComputeChunkName(hmacKey, []byte(largeContents)), Offset: 0, Len: int64(len(largeContents))},
+	}}
+
+	// Stored metadata with nanosecond mtimes is compared to the nanosecond
+	touchedMTime := after.Add(1234 * time.Nanosecond)
+	path = writeFile("touched", "touched", touchedMTime)
+	touchedMetadata := metadataOf(path, after)
+	touchedMetadata.Version, touchedMetadata.MTimeNs = metadataVersion, after.UnixNano()
+	relPaths["touched"] = s.uploadSmallDirEnt("touched", "touched", touchedMetadata, "touched")
+
+	// Changes in the same second as the snapshot are examined too
+	sameSecond := snapshotTime.Add(500 * time.Millisecond)
+	path = writeFile("same-second", "same-second", sameSecond)
+	relPaths["same-second"] = s.uploadSmallDirEnt("same-second", "same-second", metadataOf(path, sameSecond), "same-secont")
+
+	writeFile("added", "added", after)
+	relPaths["removed"] = s.uploadSmallDirEnt("removed", "removed", dirEntMetadata{MTime: before.Unix(), Mode: 0644}, "removed")
+
+	snapshot := &snapshots.Snapshot{DecryptedName: "2020-09-13_12.26.40", Datetime: snapshotTime, RelPaths: relPaths}
+	var lastPercentDone float64
+	entries, err := DiffSnapshotAgainstLive(s.ctx, s.cc, hmacKey, s.bucket, snapshot, dir, nil, vlog, func(percentDone float64) { lastPercentDone = percentDone })
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), lastPercentDone)
+	assert.Equal(t, []DiffEntry{
+		{Kind: DiffAdded, RelPath: "added"},
+		{Kind: DiffModified, RelPath: "edited", Changes: []string{DiffChangeMTime, DiffChangeSize, DiffChangeContent}},
+		{Kind: DiffModified, RelPath: "large", Changes: []string{DiffChangeMTime}},
+		{Kind: DiffRemoved, RelPath: "removed"},
+		{Kind: DiffModified, RelPath: "same-second", Changes: []string{DiffChangeContent}},
+		{Kind: DiffModified, RelPath: "same-size", Changes: []string{DiffChangeMTime, DiffChangeContent}},
+		{Kind: DiffModified, RelPath: "stale", Changes: []string{DiffChangeSize, DiffChangeContent}},
+		{Kind: DiffModified, RelPath: "touched", Changes: []string{DiffChangeMTime}},
+	}, entries)
+
+	// Entries last changed before the snapshot, by ctime as well as mtime, aren't examined
+	info, err := os.Lstat(filepath.Join(dir, "stale"))
+	assert.NoError(t, err)
+	assert.False(t, isChangedSince(info, time.Now().Add(time.Hour)))
+	assert.True(t, isChangedSince(info, time.Now().Add(-time.Hour)))
+}
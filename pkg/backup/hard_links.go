@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"sync"
+
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/snapshots"
+)
+
+// The hard links to one file found during a backup run. The first link backed up (the leader) is
+// read and stored as usual; the others reuse its extents. Links that turn up while the leader is
+// still being backed up wait in followers to be finished along with it.
+type hardLinkGroup struct {
+	done      bool
+	extents   []snapshots.ChunkExtent
+	followers []chunkPackerItem
+}
+
+// Groups of hard links by dirEntMetadata.hardLinkKey. Safe for use by multiple upload workers.
+type hardLinks struct {
+	lock   sync.Mutex
+	groups map[string]*hardLinkGroup
+}
+
+func newHardLinks() *hardLinks {
+	return &hardLinks{groups: make(map[string]*hardLinkGroup)}
+}
+
+// Adds the link at relPath to the group for key. Returns isLeader if it is the first, in which case
+// the caller backs it up and then calls chunkPacker.finishHardLinks. Otherwise, if the leader has
+// been backed up, returns its extents; if not, returns isPending, and the task is finished along
+// with the leader's.
+func (hl *hardLinks) join(key string, relPath string, bjt *database.BackupJournalTask) (extents []snapshots.ChunkExtent, isLeader bool, isPending bool) {
+	hl.lock.Lock()
+	defer hl.lock.Unlock()
+
+	g, ok := hl.groups[key]
+	if !ok {
+		hl.groups[key] = &hardLinkGroup{followers: make([]chunkPackerItem, 0)}
+		return nil, true, false
+	}
+	if g.done {
+		return g.extents, false, false
+	}
+	g.followers = append(g.followers, chunkPackerItem{relPath: relPath, bjt: bjt})
+	return nil, false, true
+}
+
+// Records how the leader of key's group was backed up and returns the links that were waiting for
+// it. If the leader failed (extents is nil), the group is forgotten so that a later link leads anew.
+func (hl *hardLinks) finish(key string, extents []snapshots.ChunkExtent) []chunkPackerItem {
+	hl.lock.Lock()
+	defer hl.lock.Unlock()
+
+	g, ok := hl.groups[key]
+	if !ok {
+		return nil
+	}
+	followers := g.followers
+	g.followers = make([]chunkPackerItem, 0)
+	if extents == nil {
+		delete(hl.groups, key)
+	} else {
+		g.done = true
+		g.extents = extents
+	}
+	return followers
+}
+
+// Finishes the tasks of the hard links waiting for the leader of key's group with its extents, or if
+// extents is nil, as failed like the leader
+func (cp *chunkPacker) finishHardLinks(key string, extents []snapshots.ChunkExtent) {
+	for _, item := range cp.hardLinks.finish(key, extents) {
+		if extents == nil {
+			resetLastBackupTime(cp.db, cp.dbLock, item.bjt.DirEntId)
+			if cp.stats != nil {
+				cp.stats.AddError()
+			}
+			completeTask(cp.db, cp.dbLock, item.bjt, nil, cp.jc)
+			continue
+		}
+
+		cp.vlog.Printf("Reusing chunks of an earlier hard link for '%s'", item.relPath)
+		if cp.stats != nil {
+			cp.stats.AddBytesFromChunkExtents(extents)
+		}
+		updateLastBackupTime(cp.db, cp.dbLock, item.bjt.DirEntId)
+		completeTask(cp.db, cp.dbLock, item.bjt, &snapshots.CloudRelPath{RelPath: item.relPath, ChunkExtents: extents}, cp.jc)
+	}
+}
@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupHardLinks(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	kc, err := newKnownChunks(ctx, objst, bucket, vlog)
+	assert.NoError(t, err)
+
+	db, err := database.NewDB(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	// Three links each to a small file, which is packed, and a large one, which is not
+	backupDirPath := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(backupDirPath, "small0"), []byte("small file"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(backupDirPath, "large0"), bytes.Repeat([]byte("large file"), int(LargeFileThreshold)/10+1), 0600))
+	for i := 1; i < 3; i++ {
+		assert.NoError(t, os.Link(filepath.Join(backupDirPath, "small0"), filepath.Join(backupDirPath, fmt.Sprintf("small%d", i))))
+		assert.NoError(t, os.Link(filepath.Join(backupDirPath, "large0"), filepath.Join(backupDirPath, fmt.Sprintf("large%d", i))))
+	}
+	relPaths := []string{"small0", "small1", "large0", "large1", "small2", "large2"}
+
+	insertBJTxn, err := db.NewInsertBackupJournalStmt(backupDirPath)
+	assert.NoError(t, err)
+	for i := range relPaths {
+		assert.NoError(t, insertBJTxn.InsertBackupJournalRow(int64(i+1), database.Unstarted, database.Updated))
+	}
+	insertBJTxn.Close()
+
+	var dbLock sync.Mutex
+	jc := &journalCounts{total: int64(len(relPaths))}
+	cp := newChunkPacker(ctx, objst, bucket, db, &dbLock, key, nil, cryptography.PaddingNone, vlog, nil, jc, nil)
+	for i, relPath := range relPaths {
+		if i == 4 {
+			// Later links to a file already backed up are finished right away
+			cp.Complete()
+		}
+		bjt, err := db.ClaimNextBackupJournalTask()
+		assert.NoError(t, err)
+		chunkExtents, pendingInChunkPacker, err := Backup(ctx, key, hmacKey, filepath.Base(backupDirPath), relPath, backupDirPath, "2022-01-01_00.00.00", objst, bucket, vlog, cp, kc, bjt)
+		assert.NoError(t, err)
+		if !pendingInChunkPacker {
+			completeTask(db, &dbLock, bjt, &snapshots.CloudRelPath{RelPath: relPath, ChunkExtents: chunkExtents}, jc)
+		}
+	}
+	cp.Complete()
+
+	// Every task is finished, and the links to a file share its extents
+	finished, _ := jc.get()
+	assert.Equal(t, int64(len(relPaths)), finished)
+	indexEntries, err := db.GetAllBackupJournalRowIndexEntries()
+	assert.NoError(t, err)
+	assert.Equal(t, len(relPaths), len(indexEntries))
+	mExtents := make(map[string][]snapshots.ChunkExtent)
+	for _, indexEntry := range indexEntries {
+		crp := snapshots.NewCloudRelPathFromJson(indexEntry)
+		assert.NotEmpty(t, crp.ChunkExtents)
+		mExtents[crp.RelPath] = crp.ChunkExtents
+	}
+	for i := 1; i < 3; i++ {
+		assert.Equal(t, mExtents["small0"], mExtents[fmt.Sprintf("small%d", i)])
+		assert.Equal(t, mExtents["large0"], mExtents[fmt.Sprintf("large%d", i)])
+	}
+	assert.NotEqual(t, mExtents["small0"], mExtents["large0"])
+}
@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// How a backup dir's backup ended, as passed to its hooks in TLESS_RESULT
+const (
+	HookResultSuccess  = "success"
+	HookResultFailure  = "failure"
+	HookResultCanceled = "canceled"
+)
+
+// Runs the pre_backup hook of backupDirPath. If it fails the dir must not be backed up: its
+// on_failure hook is run, and the returned events describe what went wrong.
+func RunPreBackupHook(ctx context.Context, hooks util.Hooks, backupDirPath string, vlog *util.VLog) ([]util.ReportedEvent, error) {
+	env := backupHookEnv(backupDirPath, nil, 0, "")
+	err := hooks.Run(ctx, "pre_backup", env, vlog)
+	if err == nil {
+		return nil, nil
+	}
+	log.Printf("error: skipping backup of '%s': %v", backupDirPath, err)
+	events := []util.ReportedEvent{hookFailedEvent(backupDirPath, err)}
+	env = backupHookEnv(backupDirPath, nil, 1, HookResultFailure)
+	events = append(events, runHooks(ctx, hooks, []string{"on_failure"}, env, backupDirPath, vlog)...)
+	return events, err
+}
+
+// Runs the post_backup hook of backupDirPath, followed by on_success or on_failure depending on
+// result, and returns events for any of them that failed. errorCount is how many errors there
+// were besides those already counted in stats.
+func RunPostBackupHooks(ctx context.Context, hooks util.Hooks, backupDirPath string, stats *BackupStats, errorCount int64, result string, vlog *util.VLog) []util.ReportedEvent {
+	hookNames := []string{"post_backup"}
+	if result == HookResultSuccess {
+		hookNames = append(hookNames, "on_success")
+	} else if result == HookResultFailure {
+		hookNames = append(hookNames, "on_failure")
+	}
+	env := backupHookEnv(backupDirPath, stats, errorCount, result)
+	return runHooks(ctx, hooks, hookNames, env, backupDirPath, vlog)
+}
+
+// Runs the pre_restore hook for a restore of backupName/snapshotName into restorePath. If it fails
+// the restore must not go ahead.
+func RunPreRestoreHook(ctx context.Context, hooks util.Hooks, backupName string, snapshotName string, restorePath string, vlog *util.VLog) error {
+	env := restoreHookEnv(backupName, snapshotName, restorePath, 0, "")
+	return hooks.Run(ctx, "pre_restore", env, vlog)
+}
+
+// Runs the post_restore hook for a restore of backupName/snapshotName into restorePath that had
+// errorCount errors
+func RunPostRestoreHook(ctx context.Context, hooks util.Hooks, backupName string, snapshotName string, restorePath string, errorCount int64, vlog *util.VLog) error {
+	result := HookResultSuccess
+	if errorCount > 0 {
+		result = HookResultFailure
+	}
+	env := restoreHookEnv(backupName, snapshotName, restorePath, errorCount, result)
+	return hooks.Run(ctx, "post_restore", env, vlog)
+}
+
+func runHooks(ctx context.Context, hooks util.Hooks, hookNames []string, env map[string]string, path string, vlog *util.VLog) []util.ReportedEvent {
+	events := make([]util.ReportedEvent, 0)
+	for _, hookName := range hookNames {
+		if err := hooks.Run(ctx, hookName, env, vlog); err != nil {
+			log.Printf("error: backup of '%s': %v", path, err)
+			events = append(events, hookFailedEvent(path, err))
+		}
+	}
+	return events
+}
+
+func hookFailedEvent(path string, err error) util.ReportedEvent {
+	return util.ReportedEvent{
+		Kind:     util.ERR_HOOK_FAILED,
+		Path:     path,
+		IsDir:    true,
+		Datetime: time.Now().Unix(),
+		Msg:      err.Error(),
+	}
+}
+
+func backupHookEnv(backupDirPath string, stats *BackupStats, errorCount int64, result string) map[string]string {
+	env := map[string]string{
+		"TLESS_BACKUP_DIR":  backupDirPath,
+		"TLESS_BACKUP_NAME": filepath.Base(backupDirPath),
+	}
+	if stats != nil {
+		env["TLESS_SNAPSHOT"] = stats.SnapshotName()
+		env["TLESS_FILES"] = strconv.FormatInt(stats.Files(), 10)
+		env["TLESS_BYTES"] = strconv.FormatInt(stats.Bytes(), 10)
+		env["TLESS_DEDUP_BYTES"] = strconv.FormatInt(stats.DedupBytes(), 10)
+		env["TLESS_DURATION_SECONDS"] = strconv.FormatInt(time.Now().Unix()-stats.startTimeUnix, 10)
+		errorCount += stats.Errors()
+	}
+	if result != "" {
+		env["TLESS_ERRORS"] = strconv.FormatInt(errorCount, 10)
+		env["TLESS_RESULT"] = result
+	}
+	return env
+}
+
+func restoreHookEnv(backupName string, snapshotName string, restorePath string, errorCount int64, result string) map[string]string {
+	env := map[string]string{
+		"TLESS_BACKUP_NAME":  backupName,
+		"TLESS_SNAPSHOT":     snapshotName,
+		"TLESS_RESTORE_PATH": restorePath,
+	}
+	if result != "" {
+		env["TLESS_ERRORS"] = strconv.FormatInt(errorCount, 10)
+		env["TLESS_RESULT"] = result
+	}
+	return env
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupHooks(t *testing.T) {
+	ctx := context.Background()
+	vlog := util.NewVLog(nil, func() bool { return false })
+	tmp := t.TempDir()
+	logFile := filepath.Join(tmp, "hooks.log")
+	logHook := "echo \"$TLESS_HOOK $TLESS_BACKUP_NAME $TLESS_SNAPSHOT $TLESS_FILES $TLESS_ERRORS $TLESS_RESULT\" >> " + logFile
+	hooks := util.Hooks{PreBackup: logHook, PostBackup: logHook, OnSuccess: logHook, OnFailure: logHook}
+	readLog := func() string {
+		buf, err := os.ReadFile(logFile)
+		assert.NoError(t, err)
+		os.Remove(logFile)
+		return string(buf)
+	}
+
+	// A successful backup runs pre_backup, post_backup and on_success
+	events, err := RunPreBackupHook(ctx, hooks, "/home/me/Documents", vlog)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	stats := NewBackupStats()
+	stats.snapshotName = "2022-01-01_00.00.00"
+	stats.AddFile()
+	stats.AddFile()
+	events = RunPostBackupHooks(ctx, hooks, "/home/me/Documents", stats, 0, HookResultSuccess, vlog)
+	assert.Empty(t, events)
+	assert.Equal(t, "pre_backup Documents    \npost_backup Documents 2022-01-01_00.00.00 2 0 success\non_success Documents 2022-01-01_00.00.00 2 0 success\n", readLog())
+
+	// Errors during the backup run on_failure instead
+	stats.AddError()
+	events = RunPostBackupHooks(ctx, hooks, "/home/me/Documents", stats, 1, HookResultFailure, vlog)
+	assert.Empty(t, events)
+	assert.Equal(t, "post_backup Documents 2022-01-01_00.00.00 2 2 failure\non_failure Documents 2022-01-01_00.00.00 2 2 failure\n", readLog())
+
+	// A failing pre_backup is reported, and runs only on_failure
+	hooks.PreBackup = "exit 1"
+	events, err = RunPreBackupHook(ctx, hooks, "/home/me/Documents", vlog)
+	assert.Error(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, util.ERR_HOOK_FAILED, events[0].Kind)
+	assert.Equal(t, "/home/me/Documents", events[0].Path)
+	assert.Equal(t, "on_failure Documents   1 failure\n", readLog())
+
+	// Restore hooks see where the restore goes
+	hooks = util.Hooks{PostRestore: "echo \"$TLESS_SNAPSHOT $TLESS_RESTORE_PATH $TLESS_RESULT\" > " + logFile}
+	assert.NoError(t, RunPreRestoreHook(ctx, hooks, "Documents", "2022-01-01_00.00.00", "/tmp/restored", vlog))
+	assert.NoError(t, RunPostRestoreHook(ctx, hooks, "Documents", "2022-01-01_00.00.00", "/tmp/restored", 0, vlog))
+	assert.Equal(t, "2022-01-01_00.00.00 /tmp/restored success\n", readLog())
+}
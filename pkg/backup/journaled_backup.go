@@ -6,10 +6,12 @@ import (
 	"errors"
 	"log"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/fstraverse"
 	"github.com/fsctl/tless/pkg/objstore"
@@ -23,6 +25,11 @@ type UpdateProgressFuncType func(finished int64, total int64, vlog *util.VLog)
 type SetReplayInitialProgressFuncType func(finished int64, total int64, backupDirName string, vlog *util.VLog)
 type SetBackupInitialProgressFuncType func(finished int64, total int64, backupDirName string, vlog *util.VLog)
 
+const (
+	// Upper bound on upload workers; each may hold a full chunk (ChunkSize) in memory
+	MaxBackupWorkers = 4
+)
+
 //
 // MemDB Functions (see note in DoJournaledBackup)
 //
@@ -46,7 +53,11 @@ func initMemDb(dbLock *sync.Mutex, db *database.DB) (*database.DB, int64) {
 // 3) If minimum time since last persist exceeded AND it's a "good time" to parallelize with
 // upload operation (ie, goodTime is true)
 func makePersistMemDbToFile(db *database.DB, dbMem *database.DB, dbLock *sync.Mutex, memDbLastPersistedToFileUnixtime int64, vlog *util.VLog) func(runWhileUploadingFinished chan bool, goodTime bool, forcePersist bool) {
+	// Upload workers may call this concurrently
+	var persistLock sync.Mutex
 	return func(runWhileUploadingFinished chan bool, goodTime bool, forcePersist bool) {
+		persistLock.Lock()
+		defer persistLock.Unlock()
 		defer func() {
 			if runWhileUploadingFinished != nil {
 				runWhileUploadingFinished <- true
@@ -66,7 +77,7 @@ func makePersistMemDbToFile(db *database.DB, dbMem *database.DB, dbLock *sync.Mu
 	}
 }
 
-func DoJournaledBackup(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, backupDirPath string, excludes []string, vlog *util.VLog, checkAndHandleTraversalCancelation fstraverse.CheckAndHandleTraversalCancelationFuncType, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, setBackupInitialProgressFunc SetBackupInitialProgressFuncType, updateBackupProgressFunc UpdateProgressFuncType, stats *BackupStats, resourceUtilization string) (backupReportedEvents []util.ReportedEvent, breakFromLoop bool, continueLoop bool, fatalError bool) {
+func DoJournaledBackup(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, backupDirPath string, excludes []string, verifyContent bool, vlog *util.VLog, checkAndHandleTraversalCancelation fstraverse.CheckAndHandleTraversalCancelationFuncType, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, setBackupInitialProgressFunc SetBackupInitialProgressFuncType, updateBackupProgressFunc UpdateProgressFuncType, stats *BackupStats, resourceUtilization string, padding cryptography.Padding) (backupReportedEvents []util.ReportedEvent, breakFromLoop bool, continueLoop bool, fatalError bool) {
 	// Return values
 	breakFromLoop = false
 	continueLoop = false
@@ -98,7 +109,7 @@ func DoJournaledBackup(ctx context.Context, key []byte, objst *objstore.ObjStore
 		return
 	}
 	var backupIdsQueue fstraverse.BackupIdsQueue
-	backupReportedEvents, err = fstraverse.Traverse(backupDirPath, prevPaths, dbMem, dbLock, &backupIdsQueue, excludes, checkAndHandleTraversalCancelation, vlog)
+	backupReportedEvents, err = fstraverse.Traverse(backupDirPath, prevPaths, dbMem, dbLock, &backupIdsQueue, excludes, verifyContent, checkAndHandleTraversalCancelation, vlog)
 	if errors.Is(err, fstraverse.ErrTraversalCanceled) {
 		breakFromLoop = true // signals cancelation to caller
 		return
@@ -115,7 +126,11 @@ func DoJournaledBackup(ctx context.Context, key []byte, objst *objstore.ObjStore
 	}
 	for _, backupQueueItem := range backupIdsQueue.Items {
 		util.LockIf(dbLock)
-		err = insertBJTxn.InsertBackupJournalRow(int64(backupQueueItem.Id), database.Unstarted, backupQueueItem.ChangeType)
+		if backupQueueItem.ChangeType == database.Moved {
+			err = insertBJTxn.InsertMovedBackupJournalRow(int64(backupQueueItem.Id), database.Unstarted, backupQueueItem.MovedFrom)
+		} else {
+			err = insertBJTxn.InsertBackupJournalRow(int64(backupQueueItem.Id), database.Unstarted, backupQueueItem.ChangeType)
+		}
 		util.UnlockIf(dbLock)
 		if err != nil {
 			log.Printf("error: DoJournaledBackup: could not insert backup item into journal txn: %v", err)
@@ -185,6 +200,9 @@ func DoJournaledBackup(ctx context.Context, key []byte, objst *objstore.ObjStore
 		return
 	}
 	snapshotName := time.Unix(snapshotUnixtime, 0).UTC().Format("2006-01-02_15.04.05")
+	if stats != nil {
+		stats.snapshotName = snapshotName
+	}
 
 	// Set the initial progress bar
 	if setBackupInitialProgressFunc != nil {
@@ -197,14 +215,19 @@ func DoJournaledBackup(ctx context.Context, key []byte, objst *objstore.ObjStore
 		setBackupInitialProgressFunc(finished, total, backupDirName, vlog)
 	}
 
-	breakFromLoop = PlayBackupJournal(ctx, key, dbLock, dbMem, backupDirPath, snapshotName, objst, bucket, vlog, checkAndHandleCancelationFunc, updateBackupProgressFunc, persistMemDbToFile, stats, resourceUtilization)
+	breakFromLoop = PlayBackupJournal(ctx, key, hmacKey, sealKeys, dbLock, dbMem, backupDirPath, snapshotName, objst, bucket, vlog, checkAndHandleCancelationFunc, updateBackupProgressFunc, persistMemDbToFile, stats, resourceUtilization, padding)
 	return
 }
 
-func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *database.DB, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, updateProgressFunc UpdateProgressFuncType, persistMemDbToFile runWhileUploadingFuncType, stats *BackupStats, resourceUtilization string) (breakFromLoop bool) {
+func PlayBackupJournal(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, dbLock *sync.Mutex, db *database.DB, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, updateProgressFunc UpdateProgressFuncType, persistMemDbToFile runWhileUploadingFuncType, stats *BackupStats, resourceUtilization string, padding cryptography.Padding) (breakFromLoop bool) {
 	// By default, don't signal we want to break out of caller's loop over backups
 	breakFromLoop = false
 
+	// The upload workers share db, so its accesses must be serialized even if caller has no lock
+	if dbLock == nil {
+		dbLock = &sync.Mutex{}
+	}
+
 	util.LockIf(dbLock)
 	finishedCountJournal, totalCntJournal, err := db.GetBackupJournalCounts()
 	util.UnlockIf(dbLock)
@@ -212,30 +235,55 @@ func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *
 		log.Println("error: PlayBackupJournal: db.GetBackupJournalCounts failed: ", err)
 		return
 	}
+	jc := &journalCounts{
+		total:    totalCntJournal,
+		finished: finishedCountJournal,
+	}
 
-	progressUpdateClosure := func(totalCntJournal int64, finishedCountJournal int64) {
+	progressUpdateClosure := func() {
 		// Update the percentage based on where we are now
 		if updateProgressFunc != nil {
-			updateProgressFunc(finishedCountJournal, totalCntJournal, vlog)
+			finished, total := jc.get()
+			updateProgressFunc(finished, total, vlog)
 		}
 	}
 
-	// Get the previous snapshot so we know the chunk extents for all the unchanged files
-	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, key, bucket, vlog, nil, nil)
+	// Get the previous snapshot so we know the chunk extents for all the unchanged files. In
+	// write-only mode we cannot read it, so unchanged files reuse the index entries kept in db
+	// from the last backup instead (see KeepBackupJournalIndexEntries).
+	var prevSnapshot *snapshots.Snapshot = nil
+	isWriteOnly := sealKeys != nil && !sealKeys.CanOpen()
+	if !isWriteOnly {
+		groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, key, sealKeys, bucket, vlog, nil, nil)
+		if err != nil {
+			log.Printf("Could not get grouped snapshots: %v", err)
+			return true
+		}
+		prevSnapshot = groupedObjects[filepath.Base(backupDirPath)].GetMostRecentSnapshot()
+	}
+
+	// Get the chunks already in the cloud so deduplicated chunks aren't uploaded again
+	kc, err := newKnownChunks(ctx, objst, bucket, vlog)
 	if err != nil {
-		log.Printf("Could not get grouped snapshots: %v", err)
+		log.Printf("Could not get list of existing chunks: %v", err)
 		return true
 	}
-	prevSnapshot := groupedObjects[filepath.Base(backupDirPath)].GetMostRecentSnapshot()
 
 	// closure used inside loop to eliminate duplicated code
 	writeIndexFileAndWipeJournal := func() {
 		vlog.Printf("Finished the journal (re-)play")
-		progressUpdateClosure(totalCntJournal, finishedCountJournal)
+		progressUpdateClosure()
 
-		err = snapshots.WriteIndexFile(ctx, dbLock, db, objst, bucket, key, filepath.Base(backupDirPath), snapshotName)
+		err = snapshots.WriteIndexFile(ctx, dbLock, db, objst, bucket, key, hmacKey, sealKeys, padding, filepath.Base(backupDirPath), snapshotName)
 		if err != nil {
 			log.Println("error: PlayBackupJournal: writeIndexFileAndWipeJournal: couldn't write index file: ", err)
+		} else if isWriteOnly {
+			util.LockIf(dbLock)
+			err = db.KeepBackupJournalIndexEntries()
+			util.UnlockIf(dbLock)
+			if err != nil {
+				log.Println("error: PlayBackupJournal: writeIndexFileAndWipeJournal: couldn't keep index entries: ", err)
+			}
 		}
 		vlog.Printf("Deleting all journal rows")
 		util.LockIf(dbLock)
@@ -253,13 +301,36 @@ func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *
 		vlog.Printf("Done with journal")
 	}
 
-	cp := newChunkPacker(ctx, objst, bucket, db, dbLock, key, vlog, persistMemDbToFile, &totalCntJournal, &finishedCountJournal, stats)
+	// Workers read, encrypt and upload claimed tasks concurrently; canceling workerCtx aborts
+	// their in-flight uploads
+	workerCtx, cancelWorkers := context.WithCancel(ctx)
+	defer cancelWorkers()
+
+	cp := newChunkPacker(workerCtx, objst, bucket, db, dbLock, key, sealKeys, padding, vlog, persistMemDbToFile, jc, stats)
 
 	// Force persist once before the backup starts
 	if persistMemDbToFile != nil {
 		persistMemDbToFile(nil, true, true)
 	}
 
+	numWorkers := numBackupWorkers(resourceUtilization)
+	vlog.Printf("PlayBackupJournal: starting %d upload workers", numWorkers)
+	tasks := make(chan *database.BackupJournalTask)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for bjt := range tasks {
+				playBackupJournalTask(workerCtx, key, hmacKey, dbLock, db, backupDirPath, snapshotName, objst, bucket, vlog, cp, kc, prevSnapshot, bjt, jc, stats)
+			}
+		}()
+	}
+	stopWorkers := func() {
+		close(tasks)
+		wg.Wait()
+	}
+
 	n := 0
 	for {
 
@@ -272,6 +343,8 @@ func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *
 		if checkAndHandleCancelationFunc != nil {
 			isCanceled := checkAndHandleCancelationFunc(ctx, key, objst, bucket, backupDirPath, snapshotName)
 			if isCanceled {
+				cancelWorkers()
+				stopWorkers()
 				return true
 			}
 		}
@@ -287,11 +360,14 @@ func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *
 		util.UnlockIf(dbLock)
 		if err != nil {
 			if errors.Is(err, database.ErrNoWork) {
-				vlog.Println("PlayBackupJournal: no work found in journal... done")
+				vlog.Println("PlayBackupJournal: no work found in journal... waiting for workers to finish")
 
-				// Commit the pending chunk packer if it has anything in it
-				isJournalComplete := cp.Complete()
-				if !isJournalComplete {
+				// Let the in-flight tasks finish, then commit the pending chunk packer if it has
+				// anything in it
+				stopWorkers()
+				cp.Complete()
+
+				if finished, total := jc.get(); finished < total {
 					log.Println("error: PlayBackupJournal: something's wrong, journal should be complete at this point")
 				}
 
@@ -300,96 +376,186 @@ func PlayBackupJournal(ctx context.Context, key []byte, dbLock *sync.Mutex, db *
 				return
 			} else {
 				log.Println("error: PlayBackupJournal: db.ClaimNextBackupJournalTask: ", err)
+				stopWorkers()
 				return
 			}
 		}
 
-		util.LockIf(dbLock)
-		rootDirName, relPath, err := db.GetDirEntPaths(int(bjt.DirEntId))
-		util.UnlockIf(dbLock)
-		if err != nil {
-			log.Printf("error: PlayBackupJournal: db.GetDirEntPaths: could not get dirent id '%d'\n", bjt.DirEntId)
-		}
+		// Blocks until a worker is free, which bounds the number of tasks in flight
+		tasks <- bjt
 
-		// For JSON serialization into journal
-		crp := &snapshots.CloudRelPath{
-			RelPath: relPath,
+		n += 1
+		if _, total := jc.get(); (total <= 1000) || (n%1000 == 0) {
+			progressUpdateClosure()
 		}
+	}
+}
 
-		if stats != nil {
-			stats.AddFile()
-		}
+// Backs up a single journal task claimed by PlayBackupJournal. Runs on an upload worker.
+func playBackupJournalTask(ctx context.Context, key []byte, hmacKey []byte, dbLock *sync.Mutex, db *database.DB, backupDirPath string, snapshotName string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, cp *chunkPacker, kc *knownChunks, prevSnapshot *snapshots.Snapshot, bjt *database.BackupJournalTask, jc *journalCounts, stats *BackupStats) {
+	util.LockIf(dbLock)
+	rootDirName, relPath, err := db.GetDirEntPaths(int(bjt.DirEntId))
+	util.UnlockIf(dbLock)
+	if err != nil {
+		log.Printf("error: playBackupJournalTask: db.GetDirEntPaths: could not get dirent id '%d'\n", bjt.DirEntId)
+	}
+
+	// For JSON serialization into journal
+	crp := &snapshots.CloudRelPath{
+		RelPath: relPath,
+	}
 
-		finishTaskImmediately := true
-		if bjt.ChangeType == database.Updated {
-			//vlog.Printf("Backing up '%s/%s'", rootDirName, relPath)
-			chunkExtents, pendingInChunkPacker, err := Backup(ctx, key, rootDirName, relPath, backupDirPath, snapshotName, objst, bucket, vlog, cp, bjt)
+	if stats != nil {
+		stats.AddFile()
+	}
+
+	// A moved file reuses the content extents it had at its old path under a new metadata header.
+	// If the old path isn't in the previous snapshot, or the file is too small or sparse for its
+	// contents to be reused apart from its header, it is backed up like any updated entry.
+	changeType := bjt.ChangeType
+	if changeType == database.Moved {
+		changeType = database.Updated
+		if prevCrp, ok := prevSnapshotRelPath(prevSnapshot, bjt.MovedFrom); ok {
+			chunkExtents, ok, err := backupMovedFile(ctx, key, hmacKey, relPath, backupDirPath, prevCrp.ChunkExtents, objst, bucket, vlog, cp, kc)
 			if err != nil {
-				log.Printf("error: PlayBackupJournal (Updated): backup.Backup: %v", err)
-				completeTask(db, dbLock, bjt, nil, &totalCntJournal, &finishedCountJournal)
+				log.Printf("error: playBackupJournalTask (Moved): could not reuse chunks of '%s/%s' for '%s/%s': %v", rootDirName, bjt.MovedFrom, rootDirName, relPath, err)
+			} else if ok {
+				vlog.Printf("Reusing chunks of '%s/%s' for '%s/%s'", rootDirName, bjt.MovedFrom, rootDirName, relPath)
+				changeType = database.Moved
+				crp.ChunkExtents = chunkExtents
+				if stats != nil {
+					stats.AddBytesFromChunkExtents(chunkExtents)
+				}
+			}
+		}
+	}
+
+	finishTaskImmediately := true
+	if changeType == database.Moved {
+		// Extents were set above
+	} else if changeType == database.Updated {
+		//vlog.Printf("Backing up '%s/%s'", rootDirName, relPath)
+		chunkExtents, pendingInChunkPacker, err := Backup(ctx, key, hmacKey, rootDirName, relPath, backupDirPath, snapshotName, objst, bucket, vlog, cp, kc, bjt)
+		if err != nil {
+			log.Printf("error: playBackupJournalTask (Updated): backup.Backup: %v", err)
+			resetLastBackupTime(db, dbLock, bjt.DirEntId)
+			if stats != nil {
+				stats.AddError()
+			}
+			completeTask(db, dbLock, bjt, nil, jc)
+			finishTaskImmediately = false
+		} else {
+			if pendingInChunkPacker {
 				finishTaskImmediately = false
 			} else {
-				if pendingInChunkPacker {
-					finishTaskImmediately = false
-				} else {
-					crp.ChunkExtents = chunkExtents
-					if stats != nil {
-						stats.AddBytesFromChunkExtents(chunkExtents)
-					}
+				crp.ChunkExtents = chunkExtents
+				if stats != nil {
+					stats.AddBytesFromChunkExtents(chunkExtents)
 				}
 			}
-		} else if bjt.ChangeType == database.Unchanged {
-			if prevSnapshot != nil {
-				// Just use the same extents as prev snapshot had
-				chunkExtents := prevSnapshot.RelPaths[relPath].ChunkExtents
-				crp.ChunkExtents = chunkExtents
+		}
+	} else if changeType == database.Unchanged {
+		keptCrp, isKept := keptIndexEntry(db, dbLock, kc, prevSnapshot, bjt.DirEntId)
+		if prevSnapshot != nil {
+			// Just use the same extents as prev snapshot had
+			chunkExtents := prevSnapshot.RelPaths[relPath].ChunkExtents
+			crp.ChunkExtents = chunkExtents
+
+			if stats != nil {
+				stats.AddBytesFromChunkExtents(chunkExtents)
+			}
+		} else if isKept {
+			// Use the extents the entry had in the last snapshot, as kept in db
+			crp.ChunkExtents = keptCrp.ChunkExtents
 
+			if stats != nil {
+				stats.AddBytesFromChunkExtents(keptCrp.ChunkExtents)
+			}
+		} else {
+			if cp.sealKeys == nil || cp.sealKeys.CanOpen() {
+				log.Printf("warning: found an unchanged file but have no previous snapshot; treating it as updated: '%s/%s'", rootDirName, relPath)
+			}
+			chunkExtents, pendingInChunkPacker, err := Backup(ctx, key, hmacKey, rootDirName, relPath, backupDirPath, snapshotName, objst, bucket, vlog, cp, kc, bjt)
+			if err != nil {
+				log.Printf("error: playBackupJournalTask (Unchanged): backup.Backup: %v", err)
+				resetLastBackupTime(db, dbLock, bjt.DirEntId)
 				if stats != nil {
-					stats.AddBytesFromChunkExtents(chunkExtents)
+					stats.AddError()
 				}
+				completeTask(db, dbLock, bjt, nil, jc)
+				finishTaskImmediately = false
 			} else {
-				log.Printf("warning: found an unchanged file but have no previous snapshot; treating it as updated: '%s/%s'", rootDirName, relPath)
-				chunkExtents, pendingInChunkPacker, err := Backup(ctx, key, rootDirName, relPath, backupDirPath, snapshotName, objst, bucket, vlog, cp, bjt)
-				if err != nil {
-					log.Printf("error: PlayBackupJournal (Unchanged): backup.Backup: %v", err)
-					completeTask(db, dbLock, bjt, nil, &totalCntJournal, &finishedCountJournal)
+				if stats != nil {
+					stats.AddBytesFromChunkExtents(chunkExtents)
+				}
+
+				if pendingInChunkPacker {
 					finishTaskImmediately = false
 				} else {
-					if stats != nil {
-						stats.AddBytesFromChunkExtents(chunkExtents)
-					}
-
-					if pendingInChunkPacker {
-						finishTaskImmediately = false
-					} else {
-						crp.ChunkExtents = chunkExtents
-					}
+					crp.ChunkExtents = chunkExtents
 				}
 			}
-		} else if bjt.ChangeType == database.Deleted {
-			// Remove from dirents table
-			if err = purgeFromDb(db, dbLock, filepath.Base(backupDirPath), relPath); err != nil {
-				log.Printf("error: PlayBackupJournal (Deleted): failed to purge from dirents '%s': %v", relPath, err)
-			}
-			crp = nil
-		} else {
-			log.Printf("error: PlayBackupJournal: unrecognized journal type '%v' on '%s'", bjt.ChangeType, relPath)
 		}
-
-		if finishTaskImmediately {
-			updateLastBackupTime(db, dbLock, bjt.DirEntId)
-			isJournalComplete := completeTask(db, dbLock, bjt, crp, &totalCntJournal, &finishedCountJournal)
-			if isJournalComplete {
-				writeIndexFileAndWipeJournal()
-				return
-			}
+	} else if changeType == database.Deleted {
+		// Remove from dirents table
+		if err = purgeFromDb(db, dbLock, filepath.Base(backupDirPath), relPath); err != nil {
+			log.Printf("error: playBackupJournalTask (Deleted): failed to purge from dirents '%s': %v", relPath, err)
 		}
+		crp = nil
+	} else {
+		log.Printf("error: playBackupJournalTask: unrecognized journal type '%v' on '%s'", bjt.ChangeType, relPath)
+	}
 
-		n += 1
-		if (totalCntJournal <= 1000) || (n%1000 == 0) {
-			progressUpdateClosure(totalCntJournal, finishedCountJournal)
+	if finishTaskImmediately {
+		updateLastBackupTime(db, dbLock, bjt.DirEntId)
+		completeTask(db, dbLock, bjt, crp, jc)
+	}
+}
+
+// Returns the index entry kept in db for a dir entry, if there is no previous snapshot to read it
+// from and all its chunks are still in the cloud (its snapshot may have been deleted since)
+func keptIndexEntry(db *database.DB, dbLock *sync.Mutex, kc *knownChunks, prevSnapshot *snapshots.Snapshot, dirEntId int64) (snapshots.CloudRelPath, bool) {
+	if prevSnapshot != nil {
+		return snapshots.CloudRelPath{}, false
+	}
+	util.LockIf(dbLock)
+	indexEntry, err := db.GetDirEntIndexEntry(int(dirEntId))
+	util.UnlockIf(dbLock)
+	if err != nil || len(indexEntry) == 0 {
+		return snapshots.CloudRelPath{}, false
+	}
+	crp := snapshots.NewCloudRelPathFromJson(indexEntry)
+	if crp == nil || len(crp.ChunkExtents) == 0 {
+		return snapshots.CloudRelPath{}, false
+	}
+	for _, chunkExtent := range crp.ChunkExtents {
+		if !kc.has(chunkExtent.ChunkName) {
+			return snapshots.CloudRelPath{}, false
 		}
 	}
+	return *crp, true
+}
+
+func prevSnapshotRelPath(prevSnapshot *snapshots.Snapshot, relPath string) (snapshots.CloudRelPath, bool) {
+	if prevSnapshot == nil || relPath == "" {
+		return snapshots.CloudRelPath{}, false
+	}
+	crp, ok := prevSnapshot.RelPaths[relPath]
+	return crp, ok && len(crp.ChunkExtents) > 0
+}
+
+// Returns how many journal tasks are backed up concurrently
+func numBackupWorkers(resourceUtilization string) int {
+	if resourceUtilization == "low" {
+		return 1
+	}
+	n := runtime.NumCPU()
+	if n < 2 {
+		n = 2
+	} else if n > MaxBackupWorkers {
+		n = MaxBackupWorkers
+	}
+	return n
 }
 
 func updateLastBackupTime(db *database.DB, dbLock *sync.Mutex, dirEntId int64) {
@@ -401,7 +567,31 @@ func updateLastBackupTime(db *database.DB, dbLock *sync.Mutex, dirEntId int64) {
 	}
 }
 
-func completeTask(db *database.DB, dbLock *sync.Mutex, bjt *database.BackupJournalTask, crp *snapshots.CloudRelPath, totalCntJournal *int64, finishedCountJournal *int64) (isJournalComplete bool) {
+// Makes sure a dir entry that failed to back up is tried again next time, even though its
+// fingerprint was already recorded
+func resetLastBackupTime(db *database.DB, dbLock *sync.Mutex, dirEntId int64) {
+	util.LockIf(dbLock)
+	err := db.ResetLastBackedUpTime(int(dirEntId))
+	util.UnlockIf(dbLock)
+	if err != nil {
+		log.Printf("error: resetLastBackupTime: db.ResetLastBackedUpTime: %v", err)
+	}
+}
+
+// Progress through the journal, shared by PlayBackupJournal and its upload workers
+type journalCounts struct {
+	lock     sync.Mutex
+	total    int64
+	finished int64
+}
+
+func (jc *journalCounts) get() (finished int64, total int64) {
+	jc.lock.Lock()
+	defer jc.lock.Unlock()
+	return jc.finished, jc.total
+}
+
+func completeTask(db *database.DB, dbLock *sync.Mutex, bjt *database.BackupJournalTask, crp *snapshots.CloudRelPath, jc *journalCounts) {
 	var err error
 	util.LockIf(dbLock)
 	if crp == nil {
@@ -413,22 +603,10 @@ func completeTask(db *database.DB, dbLock *sync.Mutex, bjt *database.BackupJourn
 	if err != nil {
 		log.Printf("error: completeTask: db.CompleteBackupJournalTask: %v", err)
 	}
-	*finishedCountJournal += 1
-
-	// Double check with values from db before concluding that isJournalComplete
-	isJournalComplete = false
-	if *finishedCountJournal >= *totalCntJournal {
-		util.LockIf(dbLock)
-		*finishedCountJournal, *totalCntJournal, err = db.GetBackupJournalCounts()
-		util.UnlockIf(dbLock)
-		if err != nil {
-			log.Printf("error: completeTask: db.GetBackupJournalCounts failed: %v", err)
-		}
-
-		isJournalComplete = (*finishedCountJournal >= *totalCntJournal)
-	}
 
-	return
+	jc.lock.Lock()
+	jc.finished += 1
+	jc.lock.Unlock()
 }
 
 func purgeFromDb(db *database.DB, dbLock *sync.Mutex, backupDirName string, deletedPath string) error {
@@ -444,7 +622,7 @@ func purgeFromDb(db *database.DB, dbLock *sync.Mutex, backupDirName string, dele
 	return nil
 }
 
-func ReplayBackupJournal(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, vlog *util.VLog, setReplayInitialProgressFunc SetReplayInitialProgressFuncType, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, updateProgressFunc UpdateProgressFuncType, resourceUtilization string) util.ReportedEvent {
+func ReplayBackupJournal(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, objst *objstore.ObjStore, bucket string, dbLock *sync.Mutex, db *database.DB, vlog *util.VLog, setReplayInitialProgressFunc SetReplayInitialProgressFuncType, checkAndHandleCancelationFunc CheckAndHandleCancelationFuncType, updateProgressFunc UpdateProgressFuncType, resourceUtilization string, padding cryptography.Padding) util.ReportedEvent {
 	// MemDB - see note at top of DoJournaledBackup
 	dbMem, memDbLastPersistedToFileUnixtime := initMemDb(dbLock, db)
 	persistMemDbToFile := makePersistMemDbToFile(db, dbMem, dbLock, memDbLastPersistedToFileUnixtime, vlog)
@@ -484,7 +662,7 @@ func ReplayBackupJournal(ctx context.Context, key []byte, objst *objstore.ObjSto
 		setReplayInitialProgressFunc(finished, total, backupDirName, vlog)
 	}
 
-	breakFromLoop := PlayBackupJournal(ctx, key, dbLock, dbMem, backupDirPath, snapshotName, objst, bucket, vlog, checkAndHandleCancelationFunc, updateProgressFunc, persistMemDbToFile, nil, resourceUtilization)
+	breakFromLoop := PlayBackupJournal(ctx, key, hmacKey, sealKeys, dbLock, dbMem, backupDirPath, snapshotName, objst, bucket, vlog, checkAndHandleCancelationFunc, updateProgressFunc, persistMemDbToFile, nil, resourceUtilization, padding)
 
 	vlog.Println("Journal replay finished")
 
@@ -0,0 +1,45 @@
+package backup
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Set of chunk names already present in the bucket, used to skip uploading deduplicated chunks
+type knownChunks struct {
+	lock  sync.Mutex
+	names map[string]bool
+}
+
+func newKnownChunks(ctx context.Context, objst *objstore.ObjStore, bucket string, vlog *util.VLog) (*knownChunks, error) {
+	mCloudChunks, err := objst.GetObjList(ctx, bucket, "chunks/", false, vlog)
+	if err != nil {
+		log.Printf("error: newKnownChunks: could not list chunks in cloud: %v", err)
+		return nil, err
+	}
+
+	kc := &knownChunks{
+		names: make(map[string]bool, len(mCloudChunks)),
+	}
+	for objName := range mCloudChunks {
+		kc.names[strings.TrimPrefix(objName, "chunks/")] = true
+	}
+	return kc, nil
+}
+
+func (kc *knownChunks) has(chunkName string) bool {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	return kc.names[chunkName]
+}
+
+func (kc *knownChunks) add(chunkName string) {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	kc.names[chunkName] = true
+}
@@ -0,0 +1,330 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsctl/tless/pkg/snapshots"
+	gofs "github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// Snapshot contents never change, so the kernel may cache entries and attrs for a long time
+const mountCacheTimeout = 10 * time.Minute
+
+// A read-only FUSE filesystem over every snapshot in the bucket
+type snapshotsFs struct {
+	cc     *ChunkCache
+	ccLock sync.Mutex // ChunkCache is not safe for concurrent use
+	bucket string
+	uid    uint32
+	gid    uint32
+}
+
+func (sfs *snapshotsFs) fetchChunkExtent(ctx context.Context, chunkExtent snapshots.ChunkExtent) ([]byte, error) {
+	sfs.ccLock.Lock()
+	defer sfs.ccLock.Unlock()
+
+	extent, _, err := sfs.cc.FetchChunkExtent(ctx, sfs.bucket, chunkExtent)
+	return extent, err
+}
+
+// A backup dir, snapshot dir or dir entry. Dir entries' metadata is only fetched from the cloud
+// once they are looked up.
+type snapshotsFsNode struct {
+	gofs.Inode
+
+	sfs  *snapshotsFs
+	tree *snapshots.FsTreeNode
+
+	loadLock  sync.Mutex
+	loaded    bool
+	metadata  *dirEntMetadata // nil for synthesized dirs
+	headerLen int64           // length of metadata header at start of first extent
+	dataLen   int64           // length of file contents as stored (only the data regions if sparse)
+	size      int64           // length of file contents
+}
+
+var _ = (gofs.NodeLookuper)((*snapshotsFsNode)(nil))
+var _ = (gofs.NodeReaddirer)((*snapshotsFsNode)(nil))
+var _ = (gofs.NodeGetattrer)((*snapshotsFsNode)(nil))
+var _ = (gofs.NodeOpener)((*snapshotsFsNode)(nil))
+var _ = (gofs.NodeReadlinker)((*snapshotsFsNode)(nil))
+
+// Fetches the metadata header of the dir entry, if this node has one and it is not loaded yet
+func (n *snapshotsFsNode) load(ctx context.Context) error {
+	n.loadLock.Lock()
+	defer n.loadLock.Unlock()
+
+	if n.loaded || n.tree.Crp == nil {
+		return nil
+	}
+
+	crp := n.tree.Crp
+	if len(crp.ChunkExtents) == 0 {
+		msg := fmt.Sprintf("error: snapshotsFsNode.load: crp.ChunkExtents has no elements on '%s'", crp.RelPath)
+		log.Println(msg)
+		return fmt.Errorf(msg)
+	}
+	plaintextBuf, err := n.sfs.fetchChunkExtent(ctx, crp.ChunkExtents[0])
+	if err != nil {
+		log.Printf("error: snapshotsFsNode.load: failed to retrieve first extent of '%s': %v", crp.RelPath, err)
+		return err
+	}
+	metadataPtr, fileContents, err := deserializeMetadataStruct(plaintextBuf)
+	if err != nil {
+		log.Printf("error: snapshotsFsNode.load: deserializeMetadataStruct failed on '%s': %v", crp.RelPath, err)
+		return err
+	}
+
+	n.metadata = metadataPtr
+	n.headerLen = int64(len(plaintextBuf) - len(fileContents))
+	n.dataLen = -n.headerLen
+	for _, chunkExtent := range crp.ChunkExtents {
+		n.dataLen += chunkExtent.Len
+	}
+	n.size = n.dataLen
+	if metadataPtr.IsSparse {
+		n.size = metadataPtr.Size
+	}
+	n.loaded = true
+
+	return nil
+}
+
+// Mode bits with all write permissions removed. Must be called after load.
+func (n *snapshotsFsNode) mode() uint32 {
+	if n.metadata == nil {
+		return fuse.S_IFDIR | 0555
+	}
+	perm := uint32(fs.FileMode(n.metadata.Mode).Perm()) &^ 0222
+	if n.metadata.IsSymlink {
+		return fuse.S_IFLNK | 0777
+	} else if n.metadata.IsDir {
+		return fuse.S_IFDIR | perm
+	}
+	mode := fs.FileMode(n.metadata.Mode)
+	if mode&fs.ModeNamedPipe != 0 {
+		return syscall.S_IFIFO | perm
+	} else if mode&fs.ModeCharDevice != 0 {
+		return syscall.S_IFCHR | perm
+	} else if mode&fs.ModeDevice != 0 {
+		return syscall.S_IFBLK | perm
+	} else {
+		return fuse.S_IFREG | perm
+	}
+}
+
+// Must be called after load
+func (n *snapshotsFsNode) fillAttr(out *fuse.Attr) {
+	out.Mode = n.mode()
+	out.Nlink = 1
+	out.Owner = fuse.Owner{Uid: n.sfs.uid, Gid: n.sfs.gid}
+
+	mtime := n.tree.Datetime
+	if n.metadata != nil {
+		mtime = n.metadata.mTime()
+		if n.metadata.IsSymlink {
+			out.Size = uint64(len(n.metadata.SymlinkOrigin))
+		} else if !n.metadata.IsDir {
+			out.Size = uint64(n.size)
+		}
+		out.Rdev = uint32(n.metadata.Rdev)
+	}
+	out.SetTimes(nil, &mtime, &mtime)
+	out.Blocks = (out.Size + 511) / 512
+	if n.metadata != nil && n.metadata.IsSparse {
+		out.Blocks = (uint64(n.dataLen) + 511) / 512
+	}
+}
+
+func (n *snapshotsFsNode) Getattr(ctx context.Context, f gofs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	if err := n.load(ctx); err != nil {
+		return syscall.EIO
+	}
+	n.fillAttr(&out.Attr)
+	return 0
+}
+
+func (n *snapshotsFsNode) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*gofs.Inode, syscall.Errno) {
+	childTree, ok := n.tree.Children[name]
+	if !ok {
+		return nil, syscall.ENOENT
+	}
+	if existing := n.GetChild(name); existing != nil {
+		existing.Operations().(*snapshotsFsNode).fillAttr(&out.Attr)
+		return existing, 0
+	}
+
+	child := &snapshotsFsNode{sfs: n.sfs, tree: childTree}
+	if err := child.load(ctx); err != nil {
+		return nil, syscall.EIO
+	}
+	child.fillAttr(&out.Attr)
+	return n.NewInode(ctx, child, gofs.StableAttr{Mode: child.mode() & syscall.S_IFMT}), 0
+}
+
+// Lists children without fetching their metadata; entries whose type is not known yet are
+// reported as unknown and get looked up on demand.
+func (n *snapshotsFsNode) Readdir(ctx context.Context) (gofs.DirStream, syscall.Errno) {
+	childNames := n.tree.SortedChildNames()
+	entries := make([]fuse.DirEntry, 0, len(childNames))
+	for _, name := range childNames {
+		childTree := n.tree.Children[name]
+		var mode uint32 = 0
+		if existing := n.GetChild(name); existing != nil {
+			mode = existing.Mode()
+		} else if childTree.Crp == nil || len(childTree.Children) > 0 {
+			mode = fuse.S_IFDIR
+		}
+		entries = append(entries, fuse.DirEntry{Name: name, Mode: mode})
+	}
+	return gofs.NewListDirStream(entries), 0
+}
+
+func (n *snapshotsFsNode) Open(ctx context.Context, flags uint32) (gofs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR|syscall.O_TRUNC|syscall.O_APPEND) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+	if err := n.load(ctx); err != nil {
+		return nil, 0, syscall.EIO
+	}
+	if n.metadata == nil || n.metadata.IsDir {
+		return nil, 0, syscall.EISDIR
+	}
+	return &snapshotsFsFileHandle{node: n, extentIdx: -1}, fuse.FOPEN_KEEP_CACHE, 0
+}
+
+func (n *snapshotsFsNode) Readlink(ctx context.Context) ([]byte, syscall.Errno) {
+	if err := n.load(ctx); err != nil {
+		return nil, syscall.EIO
+	}
+	if n.metadata == nil || !n.metadata.IsSymlink {
+		return nil, syscall.EINVAL
+	}
+	return []byte(n.metadata.SymlinkOrigin), 0
+}
+
+// An open file. Holds on to the most recently read extent, since the kernel reads files in
+// pieces much smaller than an extent.
+type snapshotsFsFileHandle struct {
+	node      *snapshotsFsNode
+	lock      sync.Mutex
+	extentIdx int
+	extentBuf []byte
+}
+
+var _ = (gofs.FileReader)((*snapshotsFsFileHandle)(nil))
+
+func (fh *snapshotsFsFileHandle) fetchExtent(ctx context.Context, idx int) ([]byte, error) {
+	if fh.extentIdx != idx {
+		extent, err := fh.node.sfs.fetchChunkExtent(ctx, fh.node.tree.Crp.ChunkExtents[idx])
+		if err != nil {
+			return nil, err
+		}
+		fh.extentIdx = idx
+		fh.extentBuf = extent
+	}
+	return fh.extentBuf, nil
+}
+
+func (fh *snapshotsFsFileHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	fh.lock.Lock()
+	defer fh.lock.Unlock()
+
+	n := fh.node
+	if off >= n.size {
+		return fuse.ReadResultData(nil), 0
+	}
+
+	var buf []byte
+	var err error
+	if n.metadata.IsSparse {
+		buf, err = readSparse(dest, off, n.size, n.metadata.SparseMap, func(from int64, to int64) ([]byte, error) {
+			return fh.readStored(ctx, nil, from, to)
+		})
+	} else {
+		end := off + int64(len(dest))
+		if end > n.size {
+			end = n.size
+		}
+		buf, err = fh.readStored(ctx, dest[:0], off, end)
+	}
+	if err != nil {
+		return nil, syscall.EIO
+	}
+
+	return fuse.ReadResultData(buf), 0
+}
+
+// Appends [from, to) of the file contents as stored to buf
+func (fh *snapshotsFsFileHandle) readStored(ctx context.Context, buf []byte, from int64, to int64) ([]byte, error) {
+	n := fh.node
+
+	// Offsets within the concatenation of all extents, which starts with the metadata header
+	start := from + n.headerLen
+	end := to + n.headerLen
+
+	var extentStart int64 = 0
+	for i, chunkExtent := range n.tree.Crp.ChunkExtents {
+		extentEnd := extentStart + chunkExtent.Len
+		if extentEnd > start && extentStart < end {
+			extent, err := fh.fetchExtent(ctx, i)
+			if err != nil {
+				log.Printf("error: snapshotsFsFileHandle.readStored: failed to retrieve extent %d of '%s': %v", i, n.tree.Crp.RelPath, err)
+				return nil, err
+			}
+			from, to := start, end
+			if from < extentStart {
+				from = extentStart
+			}
+			if to > extentEnd {
+				to = extentEnd
+			}
+			buf = append(buf, extent[from-extentStart:to-extentStart]...)
+		}
+		if extentEnd >= end {
+			break
+		}
+		extentStart = extentEnd
+	}
+	return buf, nil
+}
+
+// Mounts every snapshot in groupedObjects read-only at mountPoint, laid out as
+// backupName/snapshotName/relPath. Entries' chunks are fetched through cc only when they are
+// looked up or read. Call Wait() on the returned server to serve until it is unmounted.
+func MountSnapshots(mountPoint string, groupedObjects map[string]snapshots.BackupDir, bucket string, cc *ChunkCache, debug bool) (*fuse.Server, error) {
+	sfs := &snapshotsFs{
+		cc:     cc,
+		bucket: bucket,
+		uid:    uint32(os.Getuid()),
+		gid:    uint32(os.Getgid()),
+	}
+	root := &snapshotsFsNode{sfs: sfs, tree: snapshots.NewFsTree(groupedObjects)}
+
+	timeout := mountCacheTimeout
+	server, err := gofs.Mount(mountPoint, root, &gofs.Options{
+		MountOptions: fuse.MountOptions{
+			FsName:             "tless",
+			Name:               "tless",
+			Options:            []string{"ro"},
+			DisableReadDirPlus: true,
+			Debug:              debug,
+		},
+		EntryTimeout: &timeout,
+		AttrTimeout:  &timeout,
+	})
+	if err != nil {
+		log.Printf("error: MountSnapshots: could not mount at '%s': %v", mountPoint, err)
+		return nil, err
+	}
+
+	return server, nil
+}
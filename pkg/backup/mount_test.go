@@ -0,0 +1,105 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotsFsRead(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	prevCacheDirectory := CacheDirectory
+	defer func() { CacheDirectory = prevCacheDirectory }()
+	cc := NewChunkCache(objst, key, nil, vlog, -1, -1, t.TempDir(), -1)
+
+	uploadChunk := func(chunkName string, plaintext []byte) {
+		ciphertext, err := cryptography.EncryptBuffer(key, plaintext)
+		assert.NoError(t, err)
+		assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, "chunks/"+chunkName, ciphertext, objstore.ComputeETag(ciphertext)))
+	}
+
+	// A small file encrypted on its own within a packed chunk
+	header, err := serializeMetadataStruct(dirEntMetadata{MTime: 1600000000, Mode: 0644})
+	assert.NoError(t, err)
+	smallPlaintext := append(append([]byte{}, header...), []byte("small file contents")...)
+	smallCiphertext, err := cryptography.EncryptBuffer(key, smallPlaintext)
+	assert.NoError(t, err)
+	packedChunk := append([]byte("some other entry"), smallCiphertext...)
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, "chunks/packed", packedChunk, objstore.ComputeETag(packedChunk)))
+	smallCrp := &snapshots.CloudRelPath{
+		RelPath:      "small",
+		ChunkExtents: []snapshots.ChunkExtent{{ChunkName: "packed", Offset: 16, Len: int64(len(smallPlaintext)), EncLen: int64(len(smallCiphertext))}},
+	}
+
+	// A large file with its header in its own chunk and contents split across two more
+	header, err = serializeMetadataStruct(dirEntMetadata{MTime: 1600000000, Mode: 0755})
+	assert.NoError(t, err)
+	content1 := bytes.Repeat([]byte("0123456789"), 100)
+	content2 := bytes.Repeat([]byte("abcdefghij"), 50)
+	uploadChunk("header", header)
+	uploadChunk("content1", content1)
+	uploadChunk("content2", content2)
+	largeCrp := &snapshots.CloudRelPath{
+		RelPath: "large",
+		ChunkExtents: []snapshots.ChunkExtent{
+			{ChunkName: "header", Offset: 0, Len: int64(len(header))},
+			{ChunkName: "content1", Offset: 0, Len: int64(len(content1))},
+			{ChunkName: "content2", Offset: 0, Len: int64(len(content2))},
+		},
+	}
+
+	sfs := &snapshotsFs{cc: cc, bucket: bucket}
+	readAt := func(crp *snapshots.CloudRelPath, off int64, length int) []byte {
+		n := &snapshotsFsNode{sfs: sfs, tree: &snapshots.FsTreeNode{Name: crp.RelPath, Crp: crp}}
+		fh, _, errno := n.Open(ctx, 0)
+		assert.Equal(t, 0, int(errno))
+		rr, errno := fh.(*snapshotsFsFileHandle).Read(ctx, make([]byte, length), off)
+		assert.Equal(t, 0, int(errno))
+		buf, status := rr.Bytes(nil)
+		assert.Equal(t, fuse.OK, status)
+		return buf
+	}
+
+	assert.Equal(t, []byte("small file contents"), readAt(smallCrp, 0, 4096))
+	assert.Equal(t, []byte("file"), readAt(smallCrp, 6, 4))
+	assert.Equal(t, 0, len(readAt(smallCrp, 100, 10)))
+
+	allContents := append(append([]byte{}, content1...), content2...)
+	assert.Equal(t, allContents, readAt(largeCrp, 0, 4096))
+	assert.Equal(t, allContents[995:1005], readAt(largeCrp, 995, 10))
+	assert.Equal(t, allContents[1400:], readAt(largeCrp, 1400, 200))
+
+	// A sparse file whose data regions are stored in the header's extent
+	sparseMap := []sparseRegion{{Offset: 5, Len: 4}, {Offset: 12, Len: 3}}
+	header, err = serializeMetadataStruct(dirEntMetadata{MTime: 1600000000, Mode: 0644, IsSparse: true, Size: 20, SparseMap: sparseMap})
+	assert.NoError(t, err)
+	uploadChunk("sparse", append(header, []byte("datamor")...))
+	sparseCrp := &snapshots.CloudRelPath{
+		RelPath:      "sparse",
+		ChunkExtents: []snapshots.ChunkExtent{{ChunkName: "sparse", Offset: 0, Len: int64(len(header) + 7)}},
+	}
+	assert.Equal(t, []byte("\x00\x00\x00\x00\x00data\x00\x00\x00mor\x00\x00\x00\x00\x00"), readAt(sparseCrp, 0, 4096))
+	assert.Equal(t, []byte("a\x00\x00\x00mo"), readAt(sparseCrp, 8, 6))
+
+	// Attrs come from the metadata header and never allow writing
+	n := &snapshotsFsNode{sfs: sfs, tree: &snapshots.FsTreeNode{Name: "large", Crp: largeCrp}}
+	var out fuse.AttrOut
+	assert.Equal(t, 0, int(n.Getattr(ctx, nil, &out)))
+	assert.Equal(t, uint64(len(allContents)), out.Size)
+	assert.Equal(t, uint32(fuse.S_IFREG|0555), out.Mode)
+	assert.Equal(t, uint64(1600000000), out.Mtime)
+}
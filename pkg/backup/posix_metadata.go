@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsctl/tless/pkg/util"
+
+	"golang.org/x/sys/unix"
+)
+
+// Version of the metadata written by this version of the program. Version 0 (before the Version
+// field existed) only recorded whole-second mtimes, and no ownership, atime, ctime or hard links.
+// Version 2 added device numbers and sparse files.
+const metadataVersion = 2
+
+// Caches of user and group names by id, since every backed up file would otherwise look them up
+var (
+	ownerNamesLock sync.Mutex
+	userNames      = make(map[uint32]string)
+	groupNames     = make(map[uint32]string)
+)
+
+// Fills in the ownership, nanosecond timestamps and hard link identity of info
+func setPosixMetadata(metadata *dirEntMetadata, info fs.FileInfo) {
+	metadata.Version = metadataVersion
+	metadata.MTimeNs = info.ModTime().UnixNano()
+	metadata.ATimeNs = metadata.MTimeNs
+	metadata.CTimeNs = metadata.MTimeNs
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	metadata.Uid = st.Uid
+	metadata.Gid = st.Gid
+	metadata.UserName, metadata.GroupName = lookupOwnerNames(st.Uid, st.Gid)
+	metadata.ATimeNs, metadata.CTimeNs = util.StatATimeCTimeNs(st)
+	if info.Mode()&fs.ModeDevice != 0 {
+		metadata.Rdev = uint64(st.Rdev)
+	}
+	if !info.IsDir() && uint64(st.Nlink) > 1 {
+		metadata.HardLinkId = fmt.Sprintf("%x:%x", uint64(st.Dev), uint64(st.Ino))
+	}
+}
+
+func lookupOwnerNames(uid uint32, gid uint32) (userName string, groupName string) {
+	ownerNamesLock.Lock()
+	defer ownerNamesLock.Unlock()
+
+	userName, ok := userNames[uid]
+	if !ok {
+		if u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10)); err == nil {
+			userName = u.Username
+		}
+		userNames[uid] = userName
+	}
+	groupName, ok = groupNames[gid]
+	if !ok {
+		if g, err := user.LookupGroupId(strconv.FormatUint(uint64(gid), 10)); err == nil {
+			groupName = g.Name
+		}
+		groupNames[gid] = groupName
+	}
+	return userName, groupName
+}
+
+// Returns the mtime, with nanoseconds if the metadata has them
+func (metadata *dirEntMetadata) mTime() time.Time {
+	if metadata.Version >= 1 {
+		return time.Unix(0, metadata.MTimeNs)
+	}
+	return time.Unix(metadata.MTime, 0)
+}
+
+// Returns the atime, or the mtime if the metadata predates atimes
+func (metadata *dirEntMetadata) aTime() time.Time {
+	if metadata.Version >= 1 {
+		return time.Unix(0, metadata.ATimeNs)
+	}
+	return metadata.mTime()
+}
+
+// Identifies the other hard links to the same file within a snapshot. Snapshots can combine
+// entries from several backups, and an inode number can be reused by an unrelated file in the
+// meantime, so the mtime has to match as well.
+func (metadata *dirEntMetadata) hardLinkKey() string {
+	if metadata.HardLinkId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s@%d", metadata.HardLinkId, metadata.MTimeNs)
+}
+
+// OwnerMap decides who owns restored files. By default the user and group recorded in the backup
+// are looked up by name, falling back to their numeric ids if there is no such name on this
+// machine. Ids in Uids and Gids are mapped first, for restoring onto a machine whose ids differ.
+type OwnerMap struct {
+	Uids         map[uint32]uint32
+	Gids         map[uint32]uint32
+	NumericOwner bool // ignore recorded names and use numeric ids only
+}
+
+// Parses uid and gid mappings of the form "FROM:TO", such as "501:1000"
+func ParseOwnerMap(uidMappings []string, gidMappings []string, numericOwner bool) (*OwnerMap, error) {
+	parse := func(mappings []string) (map[uint32]uint32, error) {
+		m := make(map[uint32]uint32)
+		for _, mapping := range mappings {
+			from, to, found := strings.Cut(mapping, ":")
+			fromId, err1 := strconv.ParseUint(from, 10, 32)
+			toId, err2 := strconv.ParseUint(to, 10, 32)
+			if !found || err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("malformed id mapping '%s' (expected FROM:TO, such as 501:1000)", mapping)
+			}
+			m[uint32(fromId)] = uint32(toId)
+		}
+		return m, nil
+	}
+
+	uids, err := parse(uidMappings)
+	if err != nil {
+		return nil, err
+	}
+	gids, err := parse(gidMappings)
+	if err != nil {
+		return nil, err
+	}
+	return &OwnerMap{Uids: uids, Gids: gids, NumericOwner: numericOwner}, nil
+}
+
+// Returns the uid and gid a restored entry should have, or -1s if the metadata has no owner
+func (om *OwnerMap) resolve(metadata *dirEntMetadata) (uid int, gid int) {
+	if om == nil || metadata.Version < 1 {
+		return -1, -1
+	}
+
+	uid, gid = int(metadata.Uid), int(metadata.Gid)
+	if mapped, ok := om.Uids[metadata.Uid]; ok {
+		uid = int(mapped)
+	} else if !om.NumericOwner && metadata.UserName != "" {
+		if u, err := user.Lookup(metadata.UserName); err == nil {
+			if id, err := strconv.Atoi(u.Uid); err == nil {
+				uid = id
+			}
+		}
+	}
+	if mapped, ok := om.Gids[metadata.Gid]; ok {
+		gid = int(mapped)
+	} else if !om.NumericOwner && metadata.GroupName != "" {
+		if g, err := user.LookupGroup(metadata.GroupName); err == nil {
+			if id, err := strconv.Atoi(g.Gid); err == nil {
+				gid = id
+			}
+		}
+	}
+	return uid, gid
+}
+
+// Sets the owner and timestamps of a restored entry without following symlinks. If uid and gid
+// are not -1, they override the owner recorded in the metadata.
+func restoreOwnerAndTimes(path string, metadata *dirEntMetadata, owners *OwnerMap, uid int, gid int) error {
+	if uid == -1 || gid == -1 {
+		uid, gid = owners.resolve(metadata)
+	}
+	if uid != -1 && gid != -1 {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			log.Printf("error: restoreOwnerAndTimes: could not chown '%s' to '%d/%d': %v", path, uid, gid, err)
+			return err
+		}
+	}
+
+	times := []unix.Timespec{
+		unix.NsecToTimespec(metadata.aTime().UnixNano()),
+		unix.NsecToTimespec(metadata.mTime().UnixNano()),
+	}
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, path, times, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		log.Printf("error: restoreOwnerAndTimes: could not set times on '%s': %v", path, err)
+		return err
+	}
+	return nil
+}
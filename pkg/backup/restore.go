@@ -4,24 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/fsctl/tless/pkg/cryptography"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/snapshots"
 	"github.com/fsctl/tless/pkg/util"
+
+	"golang.org/x/sys/unix"
 )
 
 type DirChmodQueueItem struct {
 	AbsPath   string
 	FinalMode fs.FileMode
+	Uid       int // -1 to leave the owner alone
+	Gid       int
+	ATime     time.Time
+	MTime     time.Time
 }
 
-func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string, crp snapshots.CloudRelPath, rootDirName string, snapshotName string, relPath string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, dirChmodQueue *[]DirChmodQueueItem, uid int, gid int, cc *ChunkCache) error {
+// Restores one entry of a snapshot. If uid and gid are -1, entries are owned as owners decides,
+// or by whoever is running the program if owners is nil. Hard links are recreated between files
+// restored with the same hardLinks map.
+func RestoreDirEntry(ctx context.Context, key []byte, hmacKey []byte, restoreIntoDirPath string, crp snapshots.CloudRelPath, rootDirName string, snapshotName string, relPath string, objst *objstore.ObjStore, bucket string, vlog *util.VLog, dirChmodQueue *[]DirChmodQueueItem, uid int, gid int, owners *OwnerMap, hardLinks map[string]string, cc *ChunkCache) error {
 	// Strip any trailing slashes on destination path
 	restoreIntoDirPath = util.StripTrailingSlashes(restoreIntoDirPath)
 
@@ -32,9 +44,7 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 		return fmt.Errorf(msg)
 	}
 	objName := "chunks/" + crp.ChunkExtents[0].ChunkName
-	offset := crp.ChunkExtents[0].Offset
-	len := crp.ChunkExtents[0].Len
-	plaintextBuf, prevNonce, err := cc.FetchExtentIntoBuffer(ctx, bucket, objName, offset, len)
+	plaintextBuf, prevNonce, err := cc.FetchChunkExtent(ctx, bucket, crp.ChunkExtents[0])
 	if err != nil {
 		log.Fatalf("error: RestoreDirEntry: failed to retrieve obj '%s': %v", objName, err)
 	}
@@ -62,6 +72,8 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 		linkNameAbsPath := filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, dir, linkName)
 		if err := os.Symlink(metadataPtr.SymlinkOrigin, linkNameAbsPath); err != nil {
 			log.Printf("error: could not create symlink '%s': %v\n", linkNameAbsPath, err)
+		} else if err := restoreOwnerAndTimes(linkNameAbsPath, metadataPtr, owners, uid, gid); err != nil {
+			log.Printf("error: could not set owner and times on symlink '%s': %v\n", linkNameAbsPath, err)
 		}
 
 		// We don't worry about xattrs on symlink entries
@@ -74,11 +86,45 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 			log.Printf("error: could not create dir '%s': %v\n", dirFullPath, err)
 			return err
 		}
-		*dirChmodQueue = append(*dirChmodQueue, DirChmodQueueItem{AbsPath: dirFullPath, FinalMode: fs.FileMode(metadataPtr.Mode)})
+		dirUid, dirGid := uid, gid
+		if dirUid == -1 || dirGid == -1 {
+			dirUid, dirGid = owners.resolve(metadataPtr)
+		}
+		*dirChmodQueue = append(*dirChmodQueue, DirChmodQueueItem{
+			AbsPath:   dirFullPath,
+			FinalMode: fs.FileMode(metadataPtr.Mode),
+			Uid:       dirUid,
+			Gid:       dirGid,
+			ATime:     metadataPtr.aTime(),
+			MTime:     metadataPtr.mTime(),
+		})
 		if err = deserializeAndSetXAttrs(filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, relPath), metadataPtr.XAttrs); err != nil {
 			log.Printf("error: could not set xattrs on dir '%s': %v\n", dirFullPath, err)
 			return err
 		}
+	} else if fs.FileMode(metadataPtr.Mode)&(fs.ModeNamedPipe|fs.ModeDevice) != 0 {
+		// FIFOs and devices have no contents, only metadata
+		dir, name := filepath.Split(relPath)
+		err = createFullPath(restoreIntoDirPath, rootDirName, snapshotName, dir, 0755, uid, gid)
+		if err != nil {
+			log.Printf("error: could not create dir '%s': %v\n",
+				filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, dir), err)
+			return err
+		}
+		nodeAbsPath := filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, dir, name)
+		if created, err := mknodSpecialFile(nodeAbsPath, metadataPtr); err != nil {
+			return err
+		} else if !created {
+			return nil
+		}
+		if err = deserializeAndSetXAttrs(nodeAbsPath, metadataPtr.XAttrs); err != nil {
+			log.Printf("error: could not set xattrs on '%s': %v\n", nodeAbsPath, err)
+			return err
+		}
+		if err := restoreOwnerAndTimes(nodeAbsPath, metadataPtr, owners, uid, gid); err != nil {
+			log.Printf("error: could not set owner and times on '%s': %v\n", nodeAbsPath, err)
+			return err
+		}
 	} else {
 		// create the directory containing this file in case it does not exist yet
 		// (We can create dirs initially as 0755 b/c they'll get fixed later when we process
@@ -91,8 +137,20 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 			return err
 		}
 
-		// Create the file and write first + all subsequent chunks to it
+		// Another hard link to a file we already restored is linked to it instead of written again
 		filenameAbsPath := filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, dir, filename)
+		hardLinkKey := metadataPtr.hardLinkKey()
+		if linkTarget, ok := hardLinks[hardLinkKey]; ok && hardLinkKey != "" {
+			_ = os.Remove(filenameAbsPath)
+			if err := os.Link(linkTarget, filenameAbsPath); err == nil {
+				vlog.Printf("Restored %s (hard link to %s)\n", filenameAbsPath, linkTarget)
+				return nil
+			} else {
+				log.Printf("warning: could not hard link '%s' to '%s' (restoring a copy instead): %v", filenameAbsPath, linkTarget, err)
+			}
+		}
+
+		// Create the file and write first + all subsequent chunks to it
 		file, err := os.Create(filenameAbsPath)
 		if err != nil {
 			log.Fatalf("error: RestoreDirEntry: %v", err)
@@ -106,7 +164,13 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 			return errors.New("could not chmod file")
 		}
 
-		_, err = file.Write(fileContents)
+		// Sparse files only have their data regions stored, which are written back around the holes
+		var w io.Writer = file
+		if metadataPtr.IsSparse {
+			w = newSparseWriter(file, metadataPtr.SparseMap)
+		}
+
+		_, err = w.Write(fileContents)
 		if err != nil {
 			log.Fatalf("RestoreDirEntry: Write() failed: %v", err)
 			return err
@@ -116,21 +180,23 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 		for _, chunkExtent := range crp.ChunkExtents[1:] {
 			// download the chunk
 			objName := "chunks/" + chunkExtent.ChunkName
-			offset := chunkExtent.Offset
-			len := chunkExtent.Len
-			plaintextBuf, nonce, err := cc.FetchExtentIntoBuffer(ctx, bucket, objName, offset, len)
+			plaintextBuf, nonce, err := cc.FetchChunkExtent(ctx, bucket, chunkExtent)
 			if err != nil {
 				log.Fatalf("error: RestoreDirEntry: failed to retrieve obj '%s': %v", objName, err)
 			}
 
-			// check that the nonce is one larger than prev nonce
-			isNonceOneMore := isNonceOneMoreThanPrev(nonce, prevNonce)
-			if !isNonceOneMore {
-				log.Println("error: RestoreDirEntry: nonce ordering expectation violated, data may have been tampered with (chunk reordering)")
+			// content-defined chunks are named by their plaintext's HMAC, so check that; older large
+			// files were split into chunks with sequential nonces, so check that the nonce is one
+			// larger than prev nonce
+			if cryptography.ComputeChunkName(hmacKey, plaintextBuf) != chunkExtent.ChunkName {
+				isNonceOneMore := isNonceOneMoreThanPrev(nonce, prevNonce)
+				if !isNonceOneMore {
+					log.Println("error: RestoreDirEntry: chunk name and nonce ordering expectations violated, data may have been tampered with (chunk substitution or reordering)")
+				}
 			}
 
 			// append plaintext to file
-			_, err = file.Write(plaintextBuf)
+			_, err = w.Write(plaintextBuf)
 			if err != nil {
 				log.Fatalf("error: RestoreDirEntry: Write() failed: %v", err)
 				return err
@@ -140,14 +206,29 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 			prevNonce = nonce
 		}
 
+		if metadataPtr.IsSparse {
+			// extends the file over any trailing hole
+			if err := file.Truncate(metadataPtr.Size); err != nil {
+				log.Printf("error: could not truncate sparse file '%s' to %d: %v", filenameAbsPath, metadataPtr.Size, err)
+				return err
+			}
+		}
+
 		if err = deserializeAndSetXAttrs(filenameAbsPath, metadataPtr.XAttrs); err != nil {
 			log.Printf("error: could not set xattrs on file '%s': %v\n", filenameAbsPath, err)
 			return err
 		}
-		if err := os.Chown(filenameAbsPath, uid, gid); err != nil {
-			log.Printf("error: could not chown file '%s' to '%d/%d': %v", filenameAbsPath, uid, gid, err)
+		if err := file.Close(); err != nil {
+			log.Printf("error: could not close file '%s': %v", filenameAbsPath, err)
+			return err
+		}
+		if err := restoreOwnerAndTimes(filenameAbsPath, metadataPtr, owners, uid, gid); err != nil {
+			log.Printf("error: could not set owner and times on file '%s': %v\n", filenameAbsPath, err)
 			return err
 		}
+		if hardLinkKey != "" && hardLinks != nil {
+			hardLinks[hardLinkKey] = filenameAbsPath
+		}
 	}
 
 	vlog.Printf("Restored %s\n", filepath.Join(restoreIntoDirPath, rootDirName, snapshotName, relPath))
@@ -155,6 +236,57 @@ func RestoreDirEntry(ctx context.Context, key []byte, restoreIntoDirPath string,
 	return nil
 }
 
+// Creates the FIFO or device node described by metadata at path. Only root can create devices, so
+// for anyone else they are skipped with a warning and created is false.
+func mknodSpecialFile(path string, metadata *dirEntMetadata) (created bool, err error) {
+	mode := fs.FileMode(metadata.Mode)
+	var nodeType uint32
+	switch {
+	case mode&fs.ModeNamedPipe != 0:
+		nodeType = unix.S_IFIFO
+	case mode&fs.ModeCharDevice != 0:
+		nodeType = unix.S_IFCHR
+	default:
+		nodeType = unix.S_IFBLK
+	}
+	if nodeType != unix.S_IFIFO && os.Geteuid() != 0 {
+		log.Printf("warning: skipping device '%s' (only root can create device nodes)", path)
+		return false, nil
+	}
+
+	_ = os.Remove(path)
+	if err := unix.Mknod(path, nodeType|uint32(mode.Perm()), int(metadata.Rdev)); err != nil {
+		log.Printf("error: could not create '%s': %v", path, err)
+		return false, err
+	}
+	// mknod applies the umask
+	if err := os.Chmod(path, mode.Perm()); err != nil {
+		log.Printf("error: could not chmod '%s' to %#o: %v", path, mode.Perm(), err)
+		return false, err
+	}
+	return true, nil
+}
+
+// Sets the final mode, owner and times of the directories queued by RestoreDirEntry. Call this
+// after everything else is restored, since creating entries in a directory changes its mtime.
+func ApplyDirChmodQueue(dirChmodQueue []DirChmodQueueItem) {
+	// Deepest directories first, in case a parent's final mode doesn't let us into it
+	for i := len(dirChmodQueue) - 1; i >= 0; i-- {
+		item := dirChmodQueue[i]
+		if err := os.Chmod(item.AbsPath, item.FinalMode); err != nil {
+			log.Printf("error: could not chmod dir '%s' to final %#o\n", item.AbsPath, item.FinalMode)
+		}
+		if item.Uid != -1 && item.Gid != -1 {
+			if err := os.Lchown(item.AbsPath, item.Uid, item.Gid); err != nil {
+				log.Printf("error: could not chown dir '%s' to '%d/%d': %v", item.AbsPath, item.Uid, item.Gid, err)
+			}
+		}
+		if err := os.Chtimes(item.AbsPath, item.ATime, item.MTime); err != nil {
+			log.Printf("error: could not set times on dir '%s': %v", item.AbsPath, err)
+		}
+	}
+}
+
 // If uid and gid are -1, we don't try to set an owner/group and just let it default to whoever is running the program
 func createFullPath(basePath string, backupRootDirName string, snapshotName string, relPath string, mode os.FileMode, uid int, gid int) error {
 	joinedDirs := filepath.Join(basePath, backupRootDirName, snapshotName, relPath)
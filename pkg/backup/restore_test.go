@@ -0,0 +1,175 @@
+package backup
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestRestorePosixMetadata(t *testing.T) {
+	s := newDiffTestStore(t)
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	// A directory holding a file with a second hard link and a symlink, all with sub-second times
+	srcDir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(srcDir, "dir"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(srcDir, "dir", "a"), []byte("contents"), 0640))
+	assert.NoError(t, os.Link(filepath.Join(srcDir, "dir", "a"), filepath.Join(srcDir, "dir", "b")))
+	assert.NoError(t, os.Symlink("a", filepath.Join(srcDir, "dir", "link")))
+	fileMTime := time.Unix(1600000000, 123456789)
+	dirMTime := time.Unix(1500000000, 987654321)
+	assert.NoError(t, os.Chtimes(filepath.Join(srcDir, "dir", "a"), fileMTime, fileMTime))
+	assert.NoError(t, os.Chtimes(filepath.Join(srcDir, "dir"), dirMTime, dirMTime))
+
+	relPaths := make(map[string]snapshots.CloudRelPath)
+	for _, relPath := range []string{"dir", "dir/a", "dir/b", "dir/link"} {
+		info, err := os.Lstat(filepath.Join(srcDir, relPath))
+		assert.NoError(t, err)
+		symlinkOrigin, err := getSymlinkOriginIfSymlink(filepath.Join(srcDir, relPath))
+		assert.NoError(t, err)
+		metadata := dirEntMetadata{
+			IsDir:         info.IsDir(),
+			MTime:         info.ModTime().Unix(),
+			Mode:          uint32(info.Mode()),
+			IsSymlink:     symlinkOrigin != "",
+			SymlinkOrigin: symlinkOrigin,
+		}
+		setPosixMetadata(&metadata, info)
+		contents := ""
+		if info.Mode().IsRegular() {
+			contents = "contents"
+		}
+		relPaths[relPath] = s.uploadSmallDirEnt("chunk-"+filepath.Base(relPath), relPath, metadata, contents)
+	}
+
+	// Restore everything
+	restoreDir := t.TempDir()
+	sortedRelPaths := make([]string, 0, len(relPaths))
+	for relPath := range relPaths {
+		sortedRelPaths = append(sortedRelPaths, relPath)
+	}
+	sort.Strings(sortedRelPaths)
+	dirChmodQueue := make([]DirChmodQueueItem, 0)
+	hardLinks := make(map[string]string)
+	for _, relPath := range sortedRelPaths {
+		err := RestoreDirEntry(s.ctx, s.key, nil, restoreDir, relPaths[relPath], "root", "snap", relPath, s.objst, s.bucket, vlog, &dirChmodQueue, -1, -1, nil, hardLinks, s.cc)
+		assert.NoError(t, err)
+	}
+	ApplyDirChmodQueue(dirChmodQueue)
+
+	// Times are restored to the nanosecond, and the hard link is a hard link again
+	restored := filepath.Join(restoreDir, "root", "snap", "dir")
+	infoA, err := os.Stat(filepath.Join(restored, "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, fileMTime.UnixNano(), infoA.ModTime().UnixNano())
+	assert.Equal(t, fs.FileMode(0640), infoA.Mode().Perm())
+	infoB, err := os.Stat(filepath.Join(restored, "b"))
+	assert.NoError(t, err)
+	assert.True(t, os.SameFile(infoA, infoB))
+	infoDir, err := os.Stat(restored)
+	assert.NoError(t, err)
+	assert.Equal(t, dirMTime.UnixNano(), infoDir.ModTime().UnixNano())
+	assert.Equal(t, fs.FileMode(0750), infoDir.Mode().Perm())
+	origin, err := os.Readlink(filepath.Join(restored, "link"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", origin)
+}
+
+func TestRestoreSparseAndSpecialFiles(t *testing.T) {
+	s := newDiffTestStore(t)
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	// A 3 MiB file with 4 KiB of data after a 1 MiB hole, and a FIFO
+	srcDir := t.TempDir()
+	sparsePath := filepath.Join(srcDir, "sparse")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 256)
+	f, err := os.Create(sparsePath)
+	assert.NoError(t, err)
+	_, err = f.WriteAt(data, 1<<20)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Truncate(3<<20))
+	assert.NoError(t, f.Close())
+	assert.NoError(t, unix.Mkfifo(filepath.Join(srcDir, "fifo"), 0600))
+	assert.NoError(t, os.Chmod(filepath.Join(srcDir, "fifo"), 0620))
+
+	f, err = os.Open(sparsePath)
+	assert.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	assert.NoError(t, err)
+	regions, err := findSparseRegions(f, info)
+	assert.NoError(t, err)
+	if regions == nil {
+		t.Skip("filesystem does not report holes")
+	}
+	assert.Equal(t, []sparseRegion{{Offset: 1 << 20, Len: int64(len(data))}}, regions)
+	storedData, err := io.ReadAll(newSparseReader(f, regions))
+	assert.NoError(t, err)
+	assert.Equal(t, data, storedData)
+
+	relPaths := make(map[string]snapshots.CloudRelPath)
+	sparseMetadata := dirEntMetadata{Mode: uint32(info.Mode()), IsSparse: true, Size: info.Size(), SparseMap: regions}
+	setPosixMetadata(&sparseMetadata, info)
+	relPaths["sparse"] = s.uploadSmallDirEnt("chunk-sparse", "sparse", sparseMetadata, string(storedData))
+	fifoInfo, err := os.Lstat(filepath.Join(srcDir, "fifo"))
+	assert.NoError(t, err)
+	fifoMetadata := dirEntMetadata{Mode: uint32(fifoInfo.Mode())}
+	setPosixMetadata(&fifoMetadata, fifoInfo)
+	relPaths["fifo"] = s.uploadSmallDirEnt("chunk-fifo", "fifo", fifoMetadata, "")
+
+	restoreDir := t.TempDir()
+	dirChmodQueue := make([]DirChmodQueueItem, 0)
+	for relPath, crp := range relPaths {
+		err := RestoreDirEntry(s.ctx, s.key, nil, restoreDir, crp, "root", "snap", relPath, s.objst, s.bucket, vlog, &dirChmodQueue, -1, -1, nil, nil, s.cc)
+		assert.NoError(t, err)
+	}
+
+	// The restored file has the same contents and is still sparse
+	restored := filepath.Join(restoreDir, "root", "snap")
+	contents, err := os.ReadFile(filepath.Join(restored, "sparse"))
+	assert.NoError(t, err)
+	expected := make([]byte, 3<<20)
+	copy(expected[1<<20:], data)
+	assert.True(t, bytes.Equal(expected, contents))
+	restoredInfo, err := os.Stat(filepath.Join(restored, "sparse"))
+	assert.NoError(t, err)
+	assert.Less(t, restoredInfo.Sys().(*syscall.Stat_t).Blocks*512, int64(1<<20))
+
+	restoredFifo, err := os.Lstat(filepath.Join(restored, "fifo"))
+	assert.NoError(t, err)
+	assert.True(t, restoredFifo.Mode()&fs.ModeNamedPipe != 0)
+	assert.Equal(t, fs.FileMode(0620), restoredFifo.Mode().Perm())
+}
+
+func TestOwnerMap(t *testing.T) {
+	_, err := ParseOwnerMap([]string{"501"}, nil, false)
+	assert.Error(t, err)
+
+	owners, err := ParseOwnerMap([]string{"501:1000"}, []string{"20:1001"}, true)
+	assert.NoError(t, err)
+	uid, gid := owners.resolve(&dirEntMetadata{Version: 1, Uid: 501, Gid: 20, UserName: "root", GroupName: "root"})
+	assert.Equal(t, 1000, uid)
+	assert.Equal(t, 1001, gid)
+	uid, gid = owners.resolve(&dirEntMetadata{Version: 1, Uid: 502, Gid: 21, UserName: "root", GroupName: "root"})
+	assert.Equal(t, 502, uid)
+	assert.Equal(t, 21, gid)
+
+	// Metadata from before ownership was recorded leaves the owner alone
+	uid, gid = owners.resolve(&dirEntMetadata{Uid: 0, Gid: 0})
+	assert.Equal(t, -1, uid)
+	assert.Equal(t, -1, gid)
+	var noOwners *OwnerMap
+	uid, _ = noOwners.resolve(&dirEntMetadata{Version: 1, Uid: 501})
+	assert.Equal(t, -1, uid)
+}
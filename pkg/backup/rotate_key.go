@@ -0,0 +1,344 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+type RotateKeyProgressFunc func(percentDone float64)
+
+// Share of the progress bar taken up by re-encrypting names and index files
+const rotateKeyNamesPhasePercent = 10.0
+
+// A separately encrypted entry within a packed chunk
+type packedSegment struct {
+	offset int64
+	encLen int64
+	aad    []byte
+}
+
+// Re-encrypts everything in the bucket that is encrypted under oldKey so that it is encrypted under
+// newKey instead, then makes newKey the bucket's current key. The caller must first have called
+// objst.BeginKeyRotation to obtain newKey and its keyGeneration.
+//
+// Backup and snapshot names and index files are moved to their new names first; that pass can
+// simply be repeated after an interruption. Chunks keep their names (they are derived from the HMAC
+// key, which is not rotated; see the rotate-key command's help for what that leaves exposed) and are
+// resealed in place, tracked by a journal in db so that an interrupted rotation picks up where it
+// left off.
+//
+// In write-only mode sealKeys must have its private key unlocked, since the sealed index files have
+// to be read to be renamed. Sealed data is not encrypted under oldKey and is left as it is.
+func RotateKey(ctx context.Context, objst *objstore.ObjStore, bucket string, oldKey []byte, newKey []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, keyGeneration int, dbLock *sync.Mutex, db *database.DB, vlog *util.VLog, progressFunc RotateKeyProgressFunc) error {
+	reportProgress := func(percentDone float64) {
+		if progressFunc != nil {
+			progressFunc(percentDone)
+		}
+	}
+
+	// Index files are added to the manifest as they are moved, so a bucket that predates manifests
+	// needs one first
+	problems, err := snapshots.CheckManifest(ctx, objst, bucket, oldKey, hmacKey, dbLock, db)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		log.Printf("warning: RotateKey: %s", problem)
+	}
+
+	//
+	// Move backup dirs, index files and annotations to names encrypted under the new key
+	//
+	topLevelObjs, err := objst.GetObjListTopLevel(ctx, bucket, []string{"metadata", "chunks"})
+	if err != nil {
+		log.Println("error: RotateKey: objst.GetObjListTopLevel: ", err)
+		return err
+	}
+	for i, encBackupName := range topLevelObjs {
+		if err = rotateBackupDir(ctx, objst, bucket, oldKey, newKey, hmacKey, sealKeys, padding, encBackupName, dbLock, db, vlog); err != nil {
+			return err
+		}
+		reportProgress(rotateKeyNamesPhasePercent * float64(i+1) / float64(len(topLevelObjs)))
+	}
+
+	// The indexes all have new names, so the manifest has to list them afresh
+	util.LockIf(dbLock)
+	lastSeenVersion, err := db.GetLastSeenManifestVersion(bucket)
+	util.UnlockIf(dbLock)
+	if err != nil {
+		return err
+	}
+	if err = snapshots.RebuildManifest(ctx, objst, bucket, hmacKey, lastSeenVersion); err != nil {
+		log.Println("error: RotateKey: could not rebuild the snapshot manifest: ", err)
+		return err
+	}
+
+	//
+	// Find where the separately encrypted entries of every packed chunk are
+	//
+	groupedObjects, err := snapshots.GetGroupedSnapshots(ctx, objst, newKey, sealKeys, bucket, vlog, nil, nil)
+	if err != nil {
+		log.Println("error: RotateKey: could not read snapshot indexes: ", err)
+		return err
+	}
+	segmentsByChunk := make(map[string][]packedSegment)
+	for _, backupDir := range groupedObjects {
+		for _, snapshot := range backupDir.Snapshots {
+			for _, crp := range snapshot.RelPaths {
+				for _, chunkExtent := range crp.ChunkExtents {
+					if _, ok := segmentsByChunk[chunkExtent.ChunkName]; !ok {
+						segmentsByChunk[chunkExtent.ChunkName] = make([]packedSegment, 0)
+					}
+					if chunkExtent.EncLen > 0 {
+						segmentsByChunk[chunkExtent.ChunkName] = append(segmentsByChunk[chunkExtent.ChunkName], packedSegment{offset: chunkExtent.Offset, encLen: chunkExtent.EncLen, aad: chunkExtent.Aad()})
+					}
+				}
+			}
+		}
+	}
+
+	//
+	// Reseal every chunk, journaling our progress
+	//
+	util.LockIf(dbLock)
+	hasJournal, err := db.HasRotateKeyJournal(keyGeneration)
+	util.UnlockIf(dbLock)
+	if err != nil {
+		return err
+	}
+	if hasJournal {
+		vlog.Println("Resuming re-encryption of chunks")
+		util.LockIf(dbLock)
+		err = db.ResetAllInProgressRotateKeyJournalTasks()
+		util.UnlockIf(dbLock)
+		if err != nil {
+			return err
+		}
+	} else {
+		mCloudChunks, err := objst.GetObjList(ctx, bucket, "chunks/", false, vlog)
+		if err != nil {
+			log.Println("error: RotateKey: could not get list of chunks: ", err)
+			return err
+		}
+		chunkNames := make([]string, 0, len(mCloudChunks))
+		for objName := range mCloudChunks {
+			chunkNames = append(chunkNames, strings.TrimPrefix(objName, "chunks/"))
+		}
+		sort.Strings(chunkNames)
+		util.LockIf(dbLock)
+		err = db.InsertRotateKeyJournalTasks(keyGeneration, chunkNames)
+		util.UnlockIf(dbLock)
+		if err != nil {
+			return err
+		}
+	}
+
+	util.LockIf(dbLock)
+	finishedCount, totalCount, err := db.GetRotateKeyJournalCounts()
+	util.UnlockIf(dbLock)
+	if err != nil {
+		return err
+	}
+	for {
+		util.LockIf(dbLock)
+		task, err := db.ClaimNextRotateKeyJournalTask()
+		util.UnlockIf(dbLock)
+		if errors.Is(err, database.ErrNoWork) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		segments, isReferenced := segmentsByChunk[task.ChunkName]
+		if err = reencryptChunk(ctx, objst, bucket, oldKey, newKey, sealKeys, task.ChunkName, segments); err != nil {
+			if !errors.Is(err, errCannotReencrypt) {
+				return err
+			}
+			// Leave it be: it was already unreadable, and 'tless check' will report it
+			if isReferenced {
+				log.Printf("warning: RotateKey: chunk '%s' could not be re-encrypted: %v", task.ChunkName, err)
+			} else {
+				vlog.Printf("Skipping unreferenced chunk '%s' that could not be re-encrypted: %v", task.ChunkName, err)
+			}
+		}
+
+		util.LockIf(dbLock)
+		err = db.CompleteRotateKeyJournalTask(task)
+		util.UnlockIf(dbLock)
+		if err != nil {
+			return err
+		}
+		finishedCount += 1
+		reportProgress(rotateKeyNamesPhasePercent + (100.0-rotateKeyNamesPhasePercent)*float64(finishedCount)/float64(totalCount))
+	}
+
+	//
+	// Everything is under the new key now, so make it the current one
+	//
+	if err = objst.FinishKeyRotation(ctx, bucket, vlog); err != nil {
+		log.Println("error: RotateKey: objst.FinishKeyRotation: ", err)
+		return err
+	}
+	util.LockIf(dbLock)
+	err = db.WipeRotateKeyJournal()
+	util.UnlockIf(dbLock)
+	if err != nil {
+		return err
+	}
+	reportProgress(100.0)
+
+	return nil
+}
+
+// Moves the index files and annotations of backup dir encBackupName to names encrypted under newKey.
+// Does nothing if the dir's name is already encrypted under newKey.
+func rotateBackupDir(ctx context.Context, objst *objstore.ObjStore, bucket string, oldKey []byte, newKey []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, encBackupName string, dbLock *sync.Mutex, db *database.DB, vlog *util.VLog) error {
+	backupName, err := cryptography.DecryptFilename(oldKey, encBackupName)
+	if err != nil {
+		if _, err2 := cryptography.DecryptFilename(newKey, encBackupName); err2 == nil {
+			return nil
+		}
+		log.Printf("error: rotateBackupDir: could not decrypt backup dir name (%s): %v", encBackupName, err)
+		return err
+	}
+	newEncBackupName, err := cryptography.EncryptFilename(newKey, backupName)
+	if err != nil {
+		log.Printf("error: rotateBackupDir: could not encrypt backup dir name (%s): %v", backupName, err)
+		return err
+	}
+	vlog.Printf("Re-encrypting index files of backup '%s'", backupName)
+
+	mObjs, err := objst.GetObjList(ctx, bucket, encBackupName+"/", false, nil)
+	if err != nil {
+		log.Printf("error: rotateBackupDir: could not list objects of '%s': %v", backupName, err)
+		return err
+	}
+	for objName := range mObjs {
+		rest := strings.TrimPrefix(objName, encBackupName+"/")
+		if !strings.HasPrefix(rest, "@") && !strings.HasPrefix(rest, "!") {
+			log.Printf("warning: rotateBackupDir: leaving unrecognized object '%s' alone", objName)
+			continue
+		}
+		prefix, encSnapshotName := rest[:1], rest[1:]
+		snapshotName, err := cryptography.DecryptFilename(oldKey, encSnapshotName)
+		if err != nil {
+			log.Printf("error: rotateBackupDir: could not decrypt snapshot name (%s): %v", encSnapshotName, err)
+			return err
+		}
+		newEncSnapshotName, err := cryptography.EncryptFilename(newKey, snapshotName)
+		if err != nil {
+			log.Printf("error: rotateBackupDir: could not encrypt snapshot name (%s): %v", snapshotName, err)
+			return err
+		}
+
+		encBuf, err := objst.DownloadObjToBuffer(ctx, bucket, objName)
+		if err != nil {
+			log.Printf("error: rotateBackupDir: could not download '%s': %v", objName, err)
+			return err
+		}
+		if prefix == "@" {
+			buf, _, err := cryptography.DecryptDataBuffer(oldKey, sealKeys, encBuf)
+			if err != nil {
+				log.Printf("error: rotateBackupDir: could not decrypt index file of '%s/%s': %v", backupName, snapshotName, err)
+				return err
+			}
+			snapshotObj, err := snapshots.UnmarshalSnapshotObj(buf)
+			if err != nil {
+				return err
+			}
+			snapshotObj.EncryptedName = newEncSnapshotName
+			paddingLen, err := snapshots.SerializeAndWriteSnapshotObj(snapshotObj, newKey, hmacKey, sealKeys, padding, newEncBackupName, newEncSnapshotName, objst, ctx, bucket)
+			if err != nil {
+				return err
+			}
+			recordPadding(db, dbLock, newEncBackupName+"/@"+newEncSnapshotName, paddingLen)
+		} else {
+			newEncBuf, err := cryptography.ReencryptBuffer(oldKey, newKey, encBuf, nil)
+			if err != nil {
+				log.Printf("error: rotateBackupDir: could not re-encrypt annotations of '%s/%s': %v", backupName, snapshotName, err)
+				return err
+			}
+			newObjName := newEncBackupName + "/" + prefix + newEncSnapshotName
+			if err = objst.UploadObjFromBuffer(ctx, bucket, newObjName, newEncBuf, objstore.ComputeETag(newEncBuf)); err != nil {
+				log.Printf("error: rotateBackupDir: could not upload '%s': %v", newObjName, err)
+				return err
+			}
+		}
+
+		if err = objst.DeleteObj(ctx, bucket, objName); err != nil {
+			log.Printf("error: rotateBackupDir: could not delete '%s': %v", objName, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returned by reencryptChunk when a chunk's contents do not decrypt under either key
+var errCannotReencrypt = errors.New("ciphertext does not authenticate under the old or the new key")
+
+// Reseals chunkName under newKey in place. If segments is empty the chunk object is a single
+// ciphertext; otherwise each segment is a separately encrypted entry of a packed chunk.
+func reencryptChunk(ctx context.Context, objst *objstore.ObjStore, bucket string, oldKey []byte, newKey []byte, sealKeys *cryptography.SealKeys, chunkName string, segments []packedSegment) error {
+	encBuf, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/"+chunkName)
+	if err != nil {
+		log.Printf("error: reencryptChunk: could not download chunk '%s': %v", chunkName, err)
+		return err
+	}
+
+	var newEncBuf []byte
+	var errSegments error
+	if len(segments) == 0 {
+		if newEncBuf, err = reencryptData(oldKey, newKey, sealKeys, encBuf, nil); err != nil {
+			return fmt.Errorf("%w: %v", errCannotReencrypt, err)
+		}
+	} else {
+		// Reseal whatever entries we can, even if some are damaged
+		newEncBuf = append([]byte{}, encBuf...)
+		for _, seg := range segments {
+			if seg.offset < 0 || seg.offset+seg.encLen > int64(len(encBuf)) {
+				errSegments = fmt.Errorf("%w: entry at offset %d runs past the end of the chunk", errCannotReencrypt, seg.offset)
+				continue
+			}
+			newSeg, err := reencryptData(oldKey, newKey, sealKeys, encBuf[seg.offset:seg.offset+seg.encLen], seg.aad)
+			if err != nil {
+				errSegments = fmt.Errorf("%w: entry at offset %d: %v", errCannotReencrypt, seg.offset, err)
+				continue
+			}
+			copy(newEncBuf[seg.offset:], newSeg)
+		}
+	}
+
+	if bytes.Equal(newEncBuf, encBuf) {
+		return errSegments
+	}
+	if err = objst.UploadObjFromBuffer(ctx, bucket, "chunks/"+chunkName, newEncBuf, objstore.ComputeETag(newEncBuf)); err != nil {
+		log.Printf("error: reencryptChunk: could not upload chunk '%s': %v", chunkName, err)
+		return err
+	}
+	return errSegments
+}
+
+// Re-encrypts one ciphertext, encrypted with additional data aad if not nil, under newKey, or
+// returns it unchanged if it is sealed to the write-only public key, which rotation leaves alone.
+func reencryptData(oldKey []byte, newKey []byte, sealKeys *cryptography.SealKeys, ciphertext []byte, aad []byte) ([]byte, error) {
+	newCiphertext, err := cryptography.ReencryptBuffer(oldKey, newKey, ciphertext, aad)
+	if err != nil && cryptography.IsSealedBuffer(ciphertext) && sealKeys.CanOpen() {
+		if _, _, err2 := cryptography.OpenSealedBuffer(sealKeys.PrivateKey, ciphertext, aad); err2 == nil {
+			return ciphertext, nil
+		}
+	}
+	return newCiphertext, err
+}
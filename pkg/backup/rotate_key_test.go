@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateKey(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-bucket"
+	password := "correct horse battery staple"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	_, _, oldKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, password, vlog)
+	assert.NoError(t, err)
+
+	upload := func(objName string, buf []byte) {
+		assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, objName, buf, objstore.ComputeETag(buf)))
+	}
+
+	// A whole-object chunk, a packed chunk and an orphan
+	wholePlaintext := []byte("whole chunk contents")
+	wholeName := cryptography.ComputeChunkName(hmacKey, wholePlaintext)
+	wholeCiphertext, err := cryptography.EncryptBuffer(oldKey, wholePlaintext)
+	assert.NoError(t, err)
+	upload("chunks/"+wholeName, wholeCiphertext)
+	item1, err := cryptography.EncryptBuffer(oldKey, []byte("item1"))
+	assert.NoError(t, err)
+	item2, err := cryptography.EncryptBuffer(oldKey, []byte("item2"))
+	assert.NoError(t, err)
+	upload("chunks/packed", append(append([]byte{}, item1...), item2...))
+	upload("chunks/orphan", wholeCiphertext)
+
+	ce := func(name string, offset int64, len int64, encLen int64) snapshots.ChunkExtent {
+		return snapshots.ChunkExtent{ChunkName: name, Offset: offset, Len: len, EncLen: encLen}
+	}
+	relPaths := map[string]snapshots.CloudRelPath{
+		"whole": {RelPath: "whole", ChunkExtents: []snapshots.ChunkExtent{ce(wholeName, 0, int64(len(wholePlaintext)), 0)}},
+		"item1": {RelPath: "item1", ChunkExtents: []snapshots.ChunkExtent{ce("packed", 0, 5, int64(len(item1)))}},
+		"item2": {RelPath: "item2", ChunkExtents: []snapshots.ChunkExtent{ce("packed", int64(len(item1)), 5, int64(len(item2)))}},
+	}
+	encBackupName, err := cryptography.EncryptFilename(oldKey, "backup")
+	assert.NoError(t, err)
+	encSnapshotName, err := cryptography.EncryptFilename(oldKey, "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	snapshotObj := &snapshots.Snapshot{EncryptedName: encSnapshotName, DecryptedName: "2022-01-01_01.01.01", RelPaths: relPaths}
+	_, err = snapshots.SerializeAndWriteSnapshotObj(snapshotObj, oldKey, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+	assert.NoError(t, err)
+	assert.NoError(t, snapshots.WriteSnapshotAnnotations(ctx, objst, bucket, oldKey, "backup", "2022-01-01_01.01.01", &snapshots.SnapshotAnnotations{Pinned: true}))
+
+	// Rotate
+	db, err := database.NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	encKey, newKey, keyGeneration, rotationHmacKey, err := objst.BeginKeyRotation(ctx, bucket, password, nil, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, oldKey, encKey)
+	assert.Equal(t, hmacKey, rotationHmacKey)
+	assert.Equal(t, 1, keyGeneration)
+	inProgress, err := objst.IsKeyRotationInProgress(ctx, bucket)
+	assert.NoError(t, err)
+	assert.True(t, inProgress)
+
+	var lastPercent float64
+	assert.NoError(t, RotateKey(ctx, objst, bucket, oldKey, newKey, hmacKey, nil, cryptography.PaddingNone, keyGeneration, nil, db, vlog, func(percentDone float64) { lastPercent = percentDone }))
+	assert.Equal(t, 100.0, lastPercent)
+
+	// The bucket now uses the new key everywhere
+	inProgress, err = objst.IsKeyRotationInProgress(ctx, bucket)
+	assert.NoError(t, err)
+	assert.False(t, inProgress)
+	_, _, encKey, hmacKey2, err := objst.GetOrCreateBucketMetadata(ctx, bucket, password, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, newKey, encKey)
+	assert.Equal(t, hmacKey, hmacKey2)
+
+	snapshot, err := snapshots.GetSnapshot(ctx, objst, bucket, newKey, nil, "backup", "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	assert.Equal(t, relPaths, snapshot.RelPaths)
+	a, err := snapshots.GetSnapshotAnnotations(ctx, objst, bucket, newKey, "backup", "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	assert.True(t, a.Pinned)
+	mObjs, err := objst.GetObjList(ctx, bucket, encBackupName+"/", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(mObjs))
+
+	// The manifest lists the renamed index
+	_, problems, err := snapshots.VerifyManifest(ctx, objst, bucket, newKey, hmacKey, 0, true)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	for _, chunkName := range []string{wholeName, "orphan"} {
+		buf, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/"+chunkName)
+		assert.NoError(t, err)
+		plaintext, err := cryptography.DecryptBuffer(newKey, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, wholePlaintext, plaintext)
+	}
+	packed, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/packed")
+	assert.NoError(t, err)
+	plaintext, err := cryptography.DecryptBuffer(newKey, packed[len(item1):])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("item2"), plaintext)
+
+	_, total, err := db.GetRotateKeyJournalCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestRotateKeyWriteOnly(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-bucket"
+	password := "correct horse battery staple"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	_, _, oldKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, password, vlog)
+	assert.NoError(t, err)
+
+	// A chunk from before write-only mode was enabled, and one sealed after
+	oldPlaintext := []byte("old chunk contents")
+	oldCiphertext, err := cryptography.EncryptBuffer(oldKey, oldPlaintext)
+	assert.NoError(t, err)
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, "chunks/old", oldCiphertext, objstore.ComputeETag(oldCiphertext)))
+	assert.NoError(t, objst.EnableWriteOnlyMode(ctx, bucket, "private passphrase", vlog))
+	sealKeys, err := objst.GetSealKeys(ctx, bucket, "private passphrase")
+	assert.NoError(t, err)
+	sealedCiphertext, err := cryptography.SealBuffer(sealKeys.PublicKey, []byte("sealed chunk contents"), false)
+	assert.NoError(t, err)
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, "chunks/sealed", sealedCiphertext, objstore.ComputeETag(sealedCiphertext)))
+
+	relPaths := map[string]snapshots.CloudRelPath{
+		"old":    {RelPath: "old", ChunkExtents: []snapshots.ChunkExtent{{ChunkName: "old", Offset: 0, Len: int64(len(oldPlaintext))}}},
+		"sealed": {RelPath: "sealed", ChunkExtents: []snapshots.ChunkExtent{{ChunkName: "sealed", Offset: 0, Len: 21}}},
+	}
+	encBackupName, err := cryptography.EncryptFilename(oldKey, "backup")
+	assert.NoError(t, err)
+	encSnapshotName, err := cryptography.EncryptFilename(oldKey, "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	snapshotObj := &snapshots.Snapshot{EncryptedName: encSnapshotName, DecryptedName: "2022-01-01_01.01.01", RelPaths: relPaths}
+	_, err = snapshots.SerializeAndWriteSnapshotObj(snapshotObj, oldKey, hmacKey, sealKeys, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+	assert.NoError(t, err)
+
+	// Without the private key the rotation does not even start
+	_, _, _, _, err = objst.BeginKeyRotation(ctx, bucket, password, &cryptography.SealKeys{PublicKey: sealKeys.PublicKey}, vlog)
+	assert.ErrorIs(t, err, objstore.ErrWriteOnlyMode)
+	inProgress, err := objst.IsKeyRotationInProgress(ctx, bucket)
+	assert.NoError(t, err)
+	assert.False(t, inProgress)
+
+	db, err := database.NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+	_, newKey, keyGeneration, _, err := objst.BeginKeyRotation(ctx, bucket, password, sealKeys, vlog)
+	assert.NoError(t, err)
+	assert.NoError(t, RotateKey(ctx, objst, bucket, oldKey, newKey, hmacKey, sealKeys, cryptography.PaddingNone, keyGeneration, nil, db, vlog, nil))
+
+	// The sealed index was renamed and is still sealed, the old chunk is under the new key and the
+	// sealed chunk is untouched
+	snapshot, err := snapshots.GetSnapshot(ctx, objst, bucket, newKey, sealKeys, "backup", "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	assert.Equal(t, relPaths, snapshot.RelPaths)
+	_, err = snapshots.GetSnapshot(ctx, objst, bucket, newKey, &cryptography.SealKeys{PublicKey: sealKeys.PublicKey}, "backup", "2022-01-01_01.01.01")
+	assert.Error(t, err)
+	buf, err := objst.DownloadObjToBuffer(ctx, bucket, "chunks/old")
+	assert.NoError(t, err)
+	plaintext, err := cryptography.DecryptBuffer(newKey, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, oldPlaintext, plaintext)
+	buf, err = objst.DownloadObjToBuffer(ctx, bucket, "chunks/sealed")
+	assert.NoError(t, err)
+	assert.Equal(t, sealedCiphertext, buf)
+}
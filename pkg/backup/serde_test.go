@@ -1,7 +1,11 @@
 package backup
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,3 +32,35 @@ func TestSerde(t *testing.T) {
 	assert.Equal(t, metadata.XAttrs, metadataPtr.XAttrs)
 	assert.Equal(t, []byte{0x01, 0x02, 0x03}, fileContents)
 }
+
+func TestSerdeLegacyMetadata(t *testing.T) {
+	// Metadata written before the Version field existed still decodes, with its times in seconds
+	type legacyDirEntMetadata struct {
+		IsDir         bool
+		MTime         int64
+		XAttrs        string
+		Mode          uint32
+		IsSymlink     bool
+		SymlinkOrigin string
+	}
+	var gobBuf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&gobBuf).Encode(legacyDirEntMetadata{MTime: 1653231330, Mode: 0644}))
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(gobBuf.Len()))
+	buf = append(buf, gobBuf.Bytes()...)
+
+	metadataPtr, _, err := deserializeMetadataStruct(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, metadataPtr.Version)
+	assert.Equal(t, time.Unix(1653231330, 0), metadataPtr.mTime())
+	assert.Equal(t, "", metadataPtr.hardLinkKey())
+
+	// New metadata round-trips with nanoseconds
+	metadata := dirEntMetadata{Version: metadataVersion, MTime: 1653231330, MTimeNs: 1653231330123456789, Uid: 501, UserName: "someone", HardLinkId: "1:2"}
+	buf, err = serializeMetadataStruct(metadata)
+	assert.NoError(t, err)
+	metadataPtr, _, err = deserializeMetadataStruct(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, *metadataPtr)
+	assert.Equal(t, int64(1653231330123456789), metadataPtr.mTime().UnixNano())
+}
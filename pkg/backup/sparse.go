@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// A run of data in a sparse file. Everything between regions is a hole, which reads as zeroes
+// but takes no space on disk. Only the data regions of a sparse file are backed up, one after
+// the other.
+type sparseRegion struct {
+	Offset int64
+	Len    int64
+}
+
+// Returns the data regions of f if it has holes, or nil if it doesn't (or the filesystem can't
+// tell us). A file that is all hole has an empty, non-nil slice of regions.
+func findSparseRegions(f *os.File, info fs.FileInfo) ([]sparseRegion, error) {
+	// A file that has as many blocks allocated as its size needs has no holes
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || !info.Mode().IsRegular() || int64(st.Blocks)*512 >= info.Size() {
+		return nil, nil
+	}
+
+	size := info.Size()
+	regions := make([]sparseRegion, 0)
+	var off int64 = 0
+	for off < size {
+		dataStart, err := f.Seek(off, unix.SEEK_DATA)
+		if errors.Is(err, syscall.ENXIO) {
+			// no more data before the end of the file
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		holeStart, err := f.Seek(dataStart, unix.SEEK_HOLE)
+		if err != nil {
+			return nil, err
+		}
+		if holeStart > size {
+			holeStart = size
+		}
+		regions = append(regions, sparseRegion{Offset: dataStart, Len: holeStart - dataStart})
+		off = holeStart
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if len(regions) == 1 && regions[0].Offset == 0 && regions[0].Len == size {
+		return nil, nil
+	}
+	return regions, nil
+}
+
+// Returns the total length of the data in regions
+func sparseDataLen(regions []sparseRegion) int64 {
+	var dataLen int64 = 0
+	for _, region := range regions {
+		dataLen += region.Len
+	}
+	return dataLen
+}
+
+func sparseRegionsEqual(a []sparseRegion, b []sparseRegion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a reader over the data regions of f, one after the other
+func newSparseReader(f io.ReaderAt, regions []sparseRegion) io.Reader {
+	readers := make([]io.Reader, 0, len(regions))
+	for _, region := range regions {
+		readers = append(readers, io.NewSectionReader(f, region.Offset, region.Len))
+	}
+	return io.MultiReader(readers...)
+}
+
+// Writes the data regions of a sparse file, as read by newSparseReader, back to their offsets in
+// f, leaving the holes in between unwritten
+type sparseWriter struct {
+	f         io.WriterAt
+	regions   []sparseRegion
+	regionIdx int
+	regionPos int64
+}
+
+func newSparseWriter(f io.WriterAt, regions []sparseRegion) *sparseWriter {
+	return &sparseWriter{f: f, regions: regions}
+}
+
+func (sw *sparseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if sw.regionIdx >= len(sw.regions) {
+			return written, errors.New("sparseWriter: more data than the sparse map has room for")
+		}
+		region := sw.regions[sw.regionIdx]
+		n := region.Len - sw.regionPos
+		if n > int64(len(p)) {
+			n = int64(len(p))
+		}
+		if _, err := sw.f.WriteAt(p[:n], region.Offset+sw.regionPos); err != nil {
+			return written, err
+		}
+		written += int(n)
+		p = p[n:]
+		sw.regionPos += n
+		if sw.regionPos == region.Len {
+			sw.regionIdx++
+			sw.regionPos = 0
+		}
+	}
+	return written, nil
+}
+
+// Reads the part [off, off+len(dest)) of a sparse file of length size whose data regions are
+// available through readData, which reads [from, to) of the data regions laid end to end
+func readSparse(dest []byte, off int64, size int64, regions []sparseRegion, readData func(from int64, to int64) ([]byte, error)) ([]byte, error) {
+	end := off + int64(len(dest))
+	if end > size {
+		end = size
+	}
+	if off >= end {
+		return dest[:0], nil
+	}
+	buf := dest[:end-off]
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	var dataOff int64 = 0
+	for _, region := range regions {
+		from, to := region.Offset, region.Offset+region.Len
+		if from < off {
+			from = off
+		}
+		if to > end {
+			to = end
+		}
+		if from < to {
+			data, err := readData(dataOff+from-region.Offset, dataOff+to-region.Offset)
+			if err != nil {
+				return nil, err
+			}
+			copy(buf[from-off:to-off], data)
+		}
+		dataOff += region.Len
+	}
+	return buf, nil
+}
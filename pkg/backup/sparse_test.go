@@ -0,0 +1,50 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseWriterAndReadSparse(t *testing.T) {
+	regions := []sparseRegion{{Offset: 2, Len: 3}, {Offset: 8, Len: 2}}
+	expected := []byte("\x00\x00abc\x00\x00\x00de\x00\x00")
+	data := []byte("abcde")
+
+	// Writing the data regions end to end puts each at its offset
+	f, err := os.Create(filepath.Join(t.TempDir(), "sparse"))
+	assert.NoError(t, err)
+	defer f.Close()
+	sw := newSparseWriter(f, regions)
+	for _, piece := range [][]byte{data[:1], data[1:4], data[4:]} {
+		n, err := sw.Write(piece)
+		assert.NoError(t, err)
+		assert.Equal(t, len(piece), n)
+	}
+	_, err = sw.Write([]byte("f"))
+	assert.Error(t, err)
+	assert.NoError(t, f.Truncate(int64(len(expected))))
+	contents, err := os.ReadFile(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, contents)
+
+	// Reading any part of the file fills in the holes with zeroes
+	readData := func(from int64, to int64) ([]byte, error) {
+		return data[from:to], nil
+	}
+	for off := 0; off <= len(expected); off++ {
+		for length := 0; length <= len(expected)-off+2; length++ {
+			dest := bytes.Repeat([]byte{0xff}, length)
+			buf, err := readSparse(dest, int64(off), int64(len(expected)), regions, readData)
+			assert.NoError(t, err)
+			end := off + length
+			if end > len(expected) {
+				end = len(expected)
+			}
+			assert.Equal(t, expected[off:end], buf)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Checks that relPath can name the file a stream is backed up as
+func ValidateStreamRelPath(relPath string) error {
+	if relPath == "" || strings.HasPrefix(relPath, "/") || path.Clean(relPath) != relPath || relPath == "." || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return fmt.Errorf("'%s' is not a clean relative path such as 'db/dump.sql'", relPath)
+	}
+	return nil
+}
+
+// Backs up everything read from r as a single file at relPath in a new snapshot of backupName,
+// without writing it to disk first. The file is chunked like any other large file and gets
+// synthetic metadata: owned by the current user, mode 0600, and timestamped when r ran out.
+// Padding is recorded in db unless it is nil. Returns the name of the new snapshot.
+func BackupStream(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, objst *objstore.ObjStore, bucket string, db *database.DB, backupName string, relPath string, r io.Reader, stats *BackupStats, vlog *util.VLog) (string, error) {
+	if err := ValidateStreamRelPath(relPath); err != nil {
+		return "", err
+	}
+	snapshotTime := time.Now().UTC()
+	snapshotName := snapshotTime.Format("2006-01-02_15.04.05")
+
+	kc, err := newKnownChunks(ctx, objst, bucket, vlog)
+	if err != nil {
+		return "", err
+	}
+
+	// Don't waste time trying to compress streams that are already compressed
+	br := bufio.NewReader(r)
+	streamHeader, _ := br.Peek(16)
+	tryCompression := !cryptography.IsAlreadyCompressedFormat(streamHeader)
+
+	contentExtents, err := uploadContentChunks(ctx, key, hmacKey, sealKeys, padding, br, tryCompression, objst, bucket, kc, nil, db, stats)
+	if err != nil {
+		log.Printf("error: BackupStream: failed while backing up '%s': %v", relPath, err)
+		return "", err
+	}
+
+	// The metadata header goes in its own chunk, as it does for large files
+	buf, err := serializeMetadataStruct(streamMetadata(time.Now()))
+	if err != nil {
+		log.Printf("error: BackupStream: serializeMetadata failed: %v", err)
+		return "", err
+	}
+	headerChunkName, err := uploadChunkIfNew(ctx, key, hmacKey, sealKeys, padding, buf, true, objst, bucket, kc, nil, db, stats)
+	if err != nil {
+		log.Printf("error: BackupStream: failed while backing up header for '%s': %v", relPath, err)
+		return "", err
+	}
+	chunkExtents := append([]snapshots.ChunkExtent{{ChunkName: headerChunkName, Offset: 0, Len: int64(len(buf))}}, contentExtents...)
+	vlog.Printf("Backed up %s (chunkExtents: %v)\n", relPath, chunkExtents)
+
+	// Write the index of a snapshot holding just this file
+	encBackupName, err := cryptography.EncryptFilename(key, backupName)
+	if err != nil {
+		log.Printf("error: BackupStream: could not encrypt backup name '%s': %v", backupName, err)
+		return "", err
+	}
+	encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+	if err != nil {
+		log.Printf("error: BackupStream: could not encrypt snapshot name '%s': %v", snapshotName, err)
+		return "", err
+	}
+	snapshotObj := snapshots.Snapshot{
+		EncryptedName: encSnapshotName,
+		DecryptedName: snapshotName,
+		Datetime:      snapshotTime,
+		RelPaths: map[string]snapshots.CloudRelPath{
+			relPath: {RelPath: relPath, ChunkExtents: chunkExtents},
+		},
+	}
+	indexPaddingLen, err := snapshots.SerializeAndWriteSnapshotObj(&snapshotObj, key, hmacKey, sealKeys, padding, encBackupName, encSnapshotName, objst, ctx, bucket)
+	if err != nil {
+		log.Printf("error: BackupStream: could not write snapshot index: %v", err)
+		return "", err
+	}
+	recordPadding(db, nil, encBackupName+"/@"+encSnapshotName, indexPaddingLen)
+
+	if stats != nil {
+		stats.AddFile()
+		stats.AddBytesFromChunkExtents(chunkExtents)
+		stats.snapshotName = snapshotName
+	}
+	return snapshotName, nil
+}
+
+// Metadata for a file that only ever existed as a stream
+func streamMetadata(t time.Time) dirEntMetadata {
+	metadata := dirEntMetadata{
+		MTime:   t.Unix(),
+		Mode:    0600,
+		Version: metadataVersion,
+		Uid:     uint32(os.Getuid()),
+		Gid:     uint32(os.Getgid()),
+		MTimeNs: t.UnixNano(),
+		ATimeNs: t.UnixNano(),
+		CTimeNs: t.UnixNano(),
+	}
+	metadata.UserName, metadata.GroupName = lookupOwnerNames(metadata.Uid, metadata.Gid)
+	return metadata
+}
@@ -0,0 +1,63 @@
+package backup
+
+import (
+	"bytes"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/snapshots"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupStream(t *testing.T) {
+	s := newDiffTestStore(t)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	for _, size := range []int{0, 1000, 3 * int(LargeFileThreshold) / 2} {
+		contents := make([]byte, size)
+		rand.New(rand.NewSource(int64(size))).Read(contents)
+
+		stats := NewBackupStats()
+		snapshotName, err := BackupStream(s.ctx, s.key, hmacKey, nil, cryptography.PaddingNone, s.objst, s.bucket, nil, "dumps", "db/dump.sql", bytes.NewReader(contents), stats, vlog)
+		assert.NoError(t, err)
+		assert.Equal(t, snapshotName, stats.SnapshotName())
+		assert.Equal(t, int64(1), stats.Files())
+
+		// The snapshot holds just the streamed file
+		encBackupName, err := cryptography.EncryptFilename(s.key, "dumps")
+		assert.NoError(t, err)
+		encSnapshotName, err := cryptography.EncryptFilename(s.key, snapshotName)
+		assert.NoError(t, err)
+		ssIndexJson, err := snapshots.GetSnapshotIndexFile(s.ctx, s.objst, s.bucket, s.key, nil, encBackupName+"/@"+encSnapshotName)
+		assert.NoError(t, err)
+		snapshotObj, err := snapshots.UnmarshalSnapshotObj(ssIndexJson)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(snapshotObj.RelPaths))
+		crp, ok := snapshotObj.RelPaths["db/dump.sql"]
+		assert.True(t, ok)
+
+		// and restores like any other file
+		restoreDir := t.TempDir()
+		dirChmodQueue := make([]DirChmodQueueItem, 0)
+		err = RestoreDirEntry(s.ctx, s.key, hmacKey, restoreDir, crp, "dumps", snapshotName, "db/dump.sql", s.objst, s.bucket, vlog, &dirChmodQueue, -1, -1, nil, nil, s.cc)
+		assert.NoError(t, err)
+		restoredPath := filepath.Join(restoreDir, "dumps", snapshotName, "db", "dump.sql")
+		restored, err := os.ReadFile(restoredPath)
+		assert.NoError(t, err)
+		assert.True(t, bytes.Equal(contents, restored), "size %d", size)
+		info, err := os.Stat(restoredPath)
+		assert.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0600), info.Mode().Perm())
+	}
+
+	for _, bad := range []string{"", "/abs", "../up", "a/../b", "a/", "."} {
+		_, err := BackupStream(s.ctx, s.key, hmacKey, nil, cryptography.PaddingNone, s.objst, s.bucket, nil, "dumps", bad, bytes.NewReader(nil), nil, vlog)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,125 @@
+package cryptography
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Every compressed buffer starts with one of these format bytes. Buffers written by earlier
+// versions are whole gzip streams, so they start with the first byte of the gzip magic number
+// instead and are still readable.
+const (
+	compressionNone   byte = 0x00
+	compressionZstd   byte = 0x01
+	compressionLegacy byte = 0x1f // gzip magic number is 0x1f 0x8b
+)
+
+var (
+	zstdEncoder           *zstd.Encoder
+	zstdDecoder           *zstd.Decoder
+	zstdInitOnce          sync.Once
+	ErrUnknownCompression = fmt.Errorf("unknown compression format")
+)
+
+func initZstd() {
+	var err error
+	zstdEncoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	if err != nil {
+		log.Fatalf("error: initZstd: cannot create zstd encoder: %v", err)
+	}
+	zstdDecoder, err = zstd.NewReader(nil)
+	if err != nil {
+		log.Fatalf("error: initZstd: cannot create zstd decoder: %v", err)
+	}
+}
+
+// Compresses buf with zstd and prepends the format byte. If tryCompression is false, or if
+// compression doesn't save at least 1/32 of the size (as with data that's already compressed),
+// buf is stored as-is.
+func compressBuffer(buf []byte, tryCompression bool) []byte {
+	if tryCompression {
+		zstdInitOnce.Do(initZstd)
+		compressed := zstdEncoder.EncodeAll(buf, []byte{compressionZstd})
+		if len(compressed) < len(buf)-len(buf)/32 {
+			return compressed
+		}
+	}
+
+	ret := make([]byte, 0, len(buf)+1)
+	ret = append(ret, compressionNone)
+	return append(ret, buf...)
+}
+
+func decompressBuffer(buf []byte) ([]byte, error) {
+	if len(buf) == 0 {
+		return nil, fmt.Errorf("error: decompressBuffer: buffer is empty")
+	}
+	if buf[0]&compressionPadded != 0 {
+		var err error
+		if buf, err = unpadBuffer(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	switch buf[0] {
+	case compressionNone:
+		return buf[1:], nil
+	case compressionZstd:
+		zstdInitOnce.Do(initZstd)
+		return zstdDecoder.DecodeAll(buf[1:], nil)
+	case compressionLegacy:
+		zr, err := gzip.NewReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+		if err := zr.Close(); err != nil {
+			return nil, err
+		}
+		return plaintext, nil
+	default:
+		return nil, ErrUnknownCompression
+	}
+}
+
+// Magic numbers of common file formats that are already compressed
+var compressedFormatMagics = [][]byte{
+	{0x1f, 0x8b},                                 // gzip
+	{0x28, 0xb5, 0x2f, 0xfd},                     // zstd
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},             // xz
+	{'B', 'Z', 'h'},                              // bzip2
+	{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c},           // 7z
+	{'P', 'K', 0x03, 0x04},                       // zip, jar, docx, xlsx, etc.
+	{'R', 'a', 'r', '!', 0x1a, 0x07},             // rar
+	{0xff, 0xd8, 0xff},                           // jpeg
+	{0x89, 'P', 'N', 'G'},                        // png
+	{'G', 'I', 'F', '8'},                         // gif
+	{'I', 'D', '3'},                              // mp3
+	{'O', 'g', 'g', 'S'},                         // ogg
+	{'f', 'L', 'a', 'C'},                         // flac
+	{0x1a, 0x45, 0xdf, 0xa3},                     // mkv, webm
+	{0x04, 0x22, 0x4d, 0x18},                     // lz4
+	{0x00, 0x00, 0x00, 0x18, 'f', 't', 'y', 'p'}, // mp4, mov, heic
+	{0x00, 0x00, 0x00, 0x1c, 'f', 't', 'y', 'p'},
+	{0x00, 0x00, 0x00, 0x20, 'f', 't', 'y', 'p'},
+}
+
+// Returns true if header (the first few bytes of a file) identifies a file format that is
+// already compressed, in which case compressing it again is a waste of time.
+func IsAlreadyCompressedFormat(header []byte) bool {
+	for _, magic := range compressedFormatMagics {
+		if bytes.HasPrefix(header, magic) {
+			return true
+		}
+	}
+	return false
+}
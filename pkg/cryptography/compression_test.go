@@ -0,0 +1,82 @@
+package cryptography
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressDecompressBuffer(t *testing.T) {
+	// compressible buffer is stored as zstd
+	compressible := bytes.Repeat([]byte("abcdefgh"), 4096)
+	compressed := compressBuffer(compressible, true)
+	assert.Equal(t, compressionZstd, compressed[0])
+	assert.Less(t, len(compressed), len(compressible))
+	decompressed, err := decompressBuffer(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, compressible, decompressed)
+
+	// incompressible buffer is stored as-is
+	incompressible := make([]byte, 32768)
+	rand.New(rand.NewSource(1)).Read(incompressible)
+	compressed = compressBuffer(incompressible, true)
+	assert.Equal(t, compressionNone, compressed[0])
+	decompressed, err = decompressBuffer(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, incompressible, decompressed)
+
+	// compression is skipped when asked
+	compressed = compressBuffer(compressible, false)
+	assert.Equal(t, compressionNone, compressed[0])
+	decompressed, err = decompressBuffer(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, compressible, decompressed)
+
+	// empty buffer round trips
+	decompressed, err = decompressBuffer(compressBuffer([]byte{}, true))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(decompressed))
+
+	// buffers written by older versions are gzip streams
+	decompressed, err = decompressBuffer([]byte(gz(string(compressible))))
+	assert.NoError(t, err)
+	assert.Equal(t, compressible, decompressed)
+
+	// unknown format byte
+	_, err = decompressBuffer([]byte{0x7f, 0x01, 0x02})
+	assert.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestDecryptLegacyGzipBuffer(t *testing.T) {
+	key := []byte{0x47, 0x0e, 0x0b, 0x8b, 0xee, 0x2c, 0x22, 0x07, 0x58, 0x00, 0xf3, 0x33, 0x42, 0xd9, 0x2e, 0x34, 0xf7, 0x1f, 0x20, 0xff, 0xb7, 0x98, 0xa2, 0x5c, 0x2c, 0x6a, 0xfc, 0x79, 0x36, 0x8f, 0x62, 0xba}
+	nonce := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+	buf := []byte{0x01, 0x02, 0x03}
+
+	// encrypt the way older versions did: gzip then AES-GCM
+	block, err := aes.NewCipher(key)
+	assert.NoError(t, err)
+	aesgcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	legacyCiphertextBuf := append(nonce, aesgcm.Seal(nil, nonce, []byte(gz(string(buf))), nil)...)
+
+	recoveredPlaintextBuf, err := DecryptBuffer(key, legacyCiphertextBuf)
+	assert.NoError(t, err)
+	assert.Equal(t, buf, recoveredPlaintextBuf)
+
+	recoveredPlaintextBuf, recoveredNonce, err := DecryptBufferReturningNonce(key, legacyCiphertextBuf)
+	assert.NoError(t, err)
+	assert.Equal(t, buf, recoveredPlaintextBuf)
+	assert.Equal(t, nonce, recoveredNonce)
+}
+
+func TestIsAlreadyCompressedFormat(t *testing.T) {
+	assert.True(t, IsAlreadyCompressedFormat([]byte{0x1f, 0x8b, 0x08, 0x00}))
+	assert.True(t, IsAlreadyCompressedFormat([]byte{0xff, 0xd8, 0xff, 0xe0, 0x00}))
+	assert.True(t, IsAlreadyCompressedFormat([]byte{0x00, 0x00, 0x00, 0x20, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm'}))
+	assert.False(t, IsAlreadyCompressedFormat([]byte("#!/bin/sh\n")))
+	assert.False(t, IsAlreadyCompressedFormat([]byte{}))
+}
@@ -13,7 +13,9 @@ import (
 
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 
 	siv "github.com/secure-io/siv-go"
 )
@@ -142,9 +144,20 @@ func AppendEntireFileToBuffer(path string, buf []byte) ([]byte, error) {
 }
 
 func EncryptBuffer(key []byte, plaintext []byte) ([]byte, error) {
-	// gzip the plaintext
-	plaintext = []byte(gz(string(plaintext)))
+	return encryptBuffer(key, plaintext, true)
+}
+
+// Like EncryptBuffer but doesn't attempt to compress plaintext, for data known to be already compressed
+func EncryptBufferWithoutCompression(key []byte, plaintext []byte) ([]byte, error) {
+	return encryptBuffer(key, plaintext, false)
+}
+
+func encryptBuffer(key []byte, plaintext []byte, tryCompression bool) ([]byte, error) {
+	return encryptCompressedBuffer(key, compressBuffer(plaintext, tryCompression), nil)
+}
 
+// Encrypts the output of compressBuffer (and padBuffer, if padded), authenticating aad with it
+func encryptCompressedBuffer(key []byte, plaintext []byte, aad []byte) ([]byte, error) {
 	// do AES-GCM encryption of plaintext buffer
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -161,7 +174,7 @@ func EncryptBuffer(key []byte, plaintext []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	ciphertext := aesgcm.Seal(nil, nonce, plaintext, nil)
+	ciphertext := aesgcm.Seal(nil, nonce, plaintext, aad)
 	noncePrefixedCipherText := append(nonce, ciphertext...)
 
 	return noncePrefixedCipherText, nil
@@ -193,15 +206,18 @@ func DecryptBuffer(key []byte, ciphertext []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	// gunzip the plaintext
-	plaintext = []byte(gunzip(string(plaintext)))
+	// decompress the plaintext
+	plaintext, err = decompressBuffer(plaintext)
+	if err != nil {
+		return nil, err
+	}
 
 	return plaintext, nil
 }
 
 func EncryptBufferWithNonce(key []byte, plaintext []byte, nonce []byte) ([]byte, error) {
-	// gzip the plaintext
-	plaintext = []byte(gz(string(plaintext)))
+	// compress the plaintext
+	plaintext = compressBuffer(plaintext, true)
 
 	// do AES-GCM encryption of plaintext buffer using nonce
 	block, err := aes.NewCipher(key)
@@ -221,6 +237,10 @@ func EncryptBufferWithNonce(key []byte, plaintext []byte, nonce []byte) ([]byte,
 }
 
 func DecryptBufferReturningNonce(key []byte, ciphertext []byte) (plaintext []byte, nonce []byte, err error) {
+	return decryptBufferReturningNonce(key, ciphertext, nil)
+}
+
+func decryptBufferReturningNonce(key []byte, ciphertext []byte, aad []byte) (plaintext []byte, nonce []byte, err error) {
 	// Extract the nonce (first 12 bytes of ciphertext)
 	nonce = ciphertext[0:12]
 	ciphertext = ciphertext[12:]
@@ -236,13 +256,66 @@ func DecryptBufferReturningNonce(key []byte, ciphertext []byte) (plaintext []byt
 		return nil, nil, err
 	}
 
-	plaintext, err = aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err = aesgcm.Open(nil, nonce, ciphertext, aad)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// gunzip the plaintext
-	plaintext = []byte(gunzip(string(plaintext)))
+	// decompress the plaintext
+	plaintext, err = decompressBuffer(plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	return plaintext, nonce, nil
 }
+
+// ReencryptBuffer re-encrypts ciphertext from EncryptBuffer (or any of its variants) under newKey for
+// key rotation. The nonce and the (possibly compressed) plaintext are kept as they are, so the
+// result is exactly as long as ciphertext and offsets into a buffer of several ciphertexts remain
+// valid. Reusing the nonce is safe because nonces only have to be unique per key. Ciphertext that
+// is already encrypted under newKey is returned unchanged, so this can safely be repeated. aad is
+// the additional data the ciphertext was encrypted with, if any.
+func ReencryptBuffer(oldKey []byte, newKey []byte, ciphertext []byte, aad []byte) ([]byte, error) {
+	if len(ciphertext) <= 12 {
+		return nil, fmt.Errorf("error: ReencryptBuffer: ciphertext too short to be valid")
+	}
+	nonce := ciphertext[0:12]
+
+	oldAesgcm, err := newAesGcm(oldKey)
+	if err != nil {
+		return nil, err
+	}
+	newAesgcm, err := newAesGcm(newKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := newAesgcm.Open(nil, nonce, ciphertext[12:], aad); err == nil {
+		return ciphertext, nil
+	}
+	plaintext, err := oldAesgcm.Open(nil, nonce, ciphertext[12:], aad)
+	if err != nil {
+		return nil, err
+	}
+
+	noncePrefixedCipherText := append([]byte{}, nonce...)
+	return newAesgcm.Seal(noncePrefixedCipherText, nonce, plaintext, aad), nil
+}
+
+func newAesGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ComputeChunkName returns the content-addressed name of a chunk of plaintext: its HMAC-SHA256
+// under hmacKey, base64 encoded. Identical plaintext yields identical names, which is what lets
+// us deduplicate chunks without revealing their contents to the cloud.
+func ComputeChunkName(hmacKey []byte, plaintext []byte) string {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(plaintext)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,7 @@
 package cryptography
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -104,3 +105,45 @@ func TestEncryptDecryptBufferWithNonceFunctions(t *testing.T) {
 	assert.Equal(t, buf, recoveredPlaintextBuf)
 	assert.Equal(t, nonce, recoveredNonce)
 }
+
+func TestComputeChunkName(t *testing.T) {
+	hmacKey := []byte{0x47, 0x0e, 0x0b, 0x8b, 0xee, 0x2c, 0x22, 0x07, 0x58, 0x00, 0xf3, 0x33, 0x42, 0xd9, 0x2e, 0x34, 0xf7, 0x1f, 0x20, 0xff, 0xb7, 0x98, 0xa2, 0x5c, 0x2c, 0x6a, 0xfc, 0x79, 0x36, 0x8f, 0x62, 0xba}
+	otherHmacKey := make([]byte, 32)
+
+	// same plaintext and key always gives the same name
+	name := ComputeChunkName(hmacKey, []byte("abc"))
+	assert.Equal(t, name, ComputeChunkName(hmacKey, []byte("abc")))
+	assert.Equal(t, 44, len(name))
+
+	// different plaintext or different key gives a different name
+	assert.NotEqual(t, name, ComputeChunkName(hmacKey, []byte("abd")))
+	assert.NotEqual(t, name, ComputeChunkName(otherHmacKey, []byte("abc")))
+}
+
+func TestReencryptBuffer(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{0x01}, 32)
+	newKey := bytes.Repeat([]byte{0x02}, 32)
+	plaintext := []byte("some plaintext that compresses compresses compresses compresses")
+
+	ciphertext, err := EncryptBuffer(oldKey, plaintext)
+	assert.NoError(t, err)
+
+	reencrypted, err := ReencryptBuffer(oldKey, newKey, ciphertext, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(ciphertext), len(reencrypted))
+	assert.Equal(t, ciphertext[:12], reencrypted[:12])
+
+	_, err = DecryptBuffer(oldKey, reencrypted)
+	assert.Error(t, err)
+	decrypted, err := DecryptBuffer(newKey, reencrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// Repeating it is a no-op
+	again, err := ReencryptBuffer(oldKey, newKey, reencrypted, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, reencrypted, again)
+
+	_, err = ReencryptBuffer(bytes.Repeat([]byte{0x03}, 32), newKey, ciphertext, nil)
+	assert.Error(t, err)
+}
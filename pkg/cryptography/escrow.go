@@ -0,0 +1,123 @@
+package cryptography
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const escrowSharePrefix = "tless-share:v1:"
+
+var (
+	// ErrEscrowCheckFailed is returned by RecoverKeysFromEscrowShares if the shares do not combine
+	// into the keys they were split from
+	ErrEscrowCheckFailed = errors.New("escrow: shares did not combine into valid keys (are they all from the same split?)")
+
+	escrowB32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// One share of a bucket's encKey and hmacKey. Threshold shares together rebuild both keys. Check
+// is a short MAC of the keys, identical in every share of a split, that tells us whether the
+// shares combined correctly.
+type EscrowShare struct {
+	Threshold int
+	X         byte
+	Check     string
+	Y         []byte
+}
+
+// Returns the share as a single line of printable text
+func (s *EscrowShare) String() string {
+	return fmt.Sprintf("%s%d:%d:%s:%s", escrowSharePrefix, s.Threshold, s.X, s.Check, escrowB32.EncodeToString(s.Y))
+}
+
+// Parses a share from text, which may contain other lines (such as the header written with it)
+func ParseEscrowShare(text string) (*EscrowShare, error) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, escrowSharePrefix) {
+			continue
+		}
+		fields := strings.Split(strings.TrimPrefix(line, escrowSharePrefix), ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("escrow: malformed share '%s'", line)
+		}
+		threshold, err := strconv.Atoi(fields[0])
+		if err != nil || threshold < 2 {
+			return nil, fmt.Errorf("escrow: malformed share threshold '%s'", fields[0])
+		}
+		x, err := strconv.Atoi(fields[1])
+		if err != nil || x < 1 || x > 255 {
+			return nil, fmt.Errorf("escrow: malformed share number '%s'", fields[1])
+		}
+		y, err := escrowB32.DecodeString(strings.ToUpper(fields[3]))
+		if err != nil {
+			return nil, fmt.Errorf("escrow: malformed share data: %v", err)
+		}
+		return &EscrowShare{Threshold: threshold, X: byte(x), Check: fields[2], Y: y}, nil
+	}
+	return nil, fmt.Errorf("escrow: no share found (shares begin with '%s')", escrowSharePrefix)
+}
+
+func computeEscrowCheck(encKey []byte, hmacKey []byte) string {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write([]byte("tless escrow check"))
+	mac.Write(encKey)
+	return hex.EncodeToString(mac.Sum(nil)[:4])
+}
+
+// Splits encKey and hmacKey into n shares, any threshold of which can rebuild them
+func SplitKeysIntoEscrowShares(encKey []byte, hmacKey []byte, n int, threshold int) ([]*EscrowShare, error) {
+	secret := append(append([]byte{}, encKey...), hmacKey...)
+	xs, ys, err := SplitSecret(secret, n, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	check := computeEscrowCheck(encKey, hmacKey)
+	shares := make([]*EscrowShare, n)
+	for i := range xs {
+		shares[i] = &EscrowShare{Threshold: threshold, X: xs[i], Check: check, Y: ys[i]}
+	}
+	return shares, nil
+}
+
+// Rebuilds the keys from at least Threshold shares of the same split
+func RecoverKeysFromEscrowShares(shares []*EscrowShare) (encKey []byte, hmacKey []byte, err error) {
+	if len(shares) == 0 {
+		return nil, nil, fmt.Errorf("escrow: no shares given")
+	}
+	threshold := shares[0].Threshold
+	xs := make([]byte, 0, len(shares))
+	ys := make([][]byte, 0, len(shares))
+	for _, s := range shares {
+		if s.Threshold != threshold || s.Check != shares[0].Check {
+			return nil, nil, fmt.Errorf("escrow: shares are from different splits")
+		}
+		xs = append(xs, s.X)
+		ys = append(ys, s.Y)
+	}
+	if len(shares) < threshold {
+		return nil, nil, fmt.Errorf("escrow: %d shares are needed but only %d were given", threshold, len(shares))
+	}
+
+	secret, err := CombineShares(xs, ys)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(secret) != 64 {
+		return nil, nil, ErrEscrowCheckFailed
+	}
+	encKey, hmacKey = secret[:32], secret[32:]
+	if computeEscrowCheck(encKey, hmacKey) != shares[0].Check {
+		return nil, nil, ErrEscrowCheckFailed
+	}
+	return encKey, hmacKey, nil
+}
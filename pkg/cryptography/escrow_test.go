@@ -0,0 +1,69 @@
+package cryptography
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAndCombineSecret(t *testing.T) {
+	secret := []byte("the quick brown fox jumps over the lazy dog")
+	xs, shares, err := SplitSecret(secret, 5, 3)
+	assert.NoError(t, err)
+
+	// Any 3 shares work, in any order
+	for _, pick := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		pickedXs := make([]byte, 0)
+		pickedShares := make([][]byte, 0)
+		for _, i := range pick {
+			pickedXs = append(pickedXs, xs[i])
+			pickedShares = append(pickedShares, shares[i])
+		}
+		combined, err := CombineShares(pickedXs, pickedShares)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, combined)
+	}
+
+	// Two are not enough
+	combined, err := CombineShares(xs[:2], shares[:2])
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, combined)
+
+	_, err = CombineShares([]byte{xs[0], xs[0]}, [][]byte{shares[0], shares[0]})
+	assert.ErrorIs(t, err, ErrShamirDuplicateShare)
+	_, _, err = SplitSecret(secret, 2, 3)
+	assert.Error(t, err)
+}
+
+func TestEscrowShares(t *testing.T) {
+	encKey := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	shares, err := SplitKeysIntoEscrowShares(encKey, hmacKey, 3, 2)
+	assert.NoError(t, err)
+
+	// Round trip through text, with a header line
+	parsed := make([]*EscrowShare, 0)
+	for _, s := range shares {
+		p, err := ParseEscrowShare("# share of my keys\n" + s.String() + "\n")
+		assert.NoError(t, err)
+		assert.Equal(t, s, p)
+		parsed = append(parsed, p)
+	}
+
+	gotEncKey, gotHmacKey, err := RecoverKeysFromEscrowShares([]*EscrowShare{parsed[2], parsed[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, encKey, gotEncKey)
+	assert.Equal(t, hmacKey, gotHmacKey)
+
+	_, _, err = RecoverKeysFromEscrowShares(parsed[:1])
+	assert.Error(t, err)
+
+	// A corrupted share is detected
+	parsed[1].Y[0] ^= 0x01
+	_, _, err = RecoverKeysFromEscrowShares(parsed[:2])
+	assert.ErrorIs(t, err, ErrEscrowCheckFailed)
+
+	_, err = ParseEscrowShare("not a share")
+	assert.Error(t, err)
+}
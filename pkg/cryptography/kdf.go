@@ -5,6 +5,7 @@ package cryptography
 
 import (
 	"errors"
+	"time"
 
 	"golang.org/x/crypto/argon2"
 )
@@ -12,14 +13,82 @@ import (
 var (
 	// ErrorBadSalt is returned by DeriveKey if salt is empty or too short
 	ErrorBadSalt = errors.New("salt was empty or too short")
+
+	// ErrorBadKdfParams is returned if Argon2id parameters are too weak to be used
+	ErrorBadKdfParams = errors.New("key derivation parameters are invalid or too weak")
+
+	// DefaultKdfParams are the Argon2id parameters used by buckets that do not record their own
+	DefaultKdfParams = KdfParams{Time: 10, MemoryKiB: 256 * 1024, Threads: 4}
 )
 
+// Bounds on KdfParams. The upper bounds keep a bucket's metadata from making every client that
+// opens it spend hours or more memory than a machine is likely to have deriving a key.
+const (
+	minKdfMemoryKiB = 64 * 1024
+	maxKdfMemoryKiB = 4 * 1024 * 1024
+	maxKdfTime      = 100
+)
+
+// KdfParams are the Argon2id time, memory and parallelism parameters
+type KdfParams struct {
+	Time      uint32
+	MemoryKiB uint32
+	Threads   uint8
+}
+
+// Validate returns ErrorBadKdfParams if the parameters are out of range
+func (p KdfParams) Validate() error {
+	if p.Time < 1 || p.Time > maxKdfTime || p.Threads < 1 || p.MemoryKiB < minKdfMemoryKiB || p.MemoryKiB > maxKdfMemoryKiB {
+		return ErrorBadKdfParams
+	}
+	return nil
+}
+
 // DeriveKey derives a 32-byte (256-bit) encryption key from the user's
-// master password and a non-secret but random salt string
+// master password and a non-secret but random salt string, using DefaultKdfParams
 func DeriveKey(salt string, masterPassword string) ([]byte, error) {
+	return DeriveKeyWithParams(salt, masterPassword, DefaultKdfParams)
+}
+
+// DeriveKeyWithParams is DeriveKey with explicit Argon2id parameters
+func DeriveKeyWithParams(salt string, masterPassword string, params KdfParams) ([]byte, error) {
 	if len(salt) < 8 {
 		return nil, ErrorBadSalt
 	}
-	key := argon2.IDKey([]byte(masterPassword), []byte(salt), 10, 256*1024, 4, 32)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(masterPassword), []byte(salt), params.Time, params.MemoryKiB, params.Threads, 32)
 	return key, nil
 }
+
+// BenchmarkKdfParams picks the number of Argon2id passes that makes one key derivation with the
+// given memory and threads take about target on this machine. Returns the parameters and how long
+// a derivation with them actually took.
+func BenchmarkKdfParams(target time.Duration, memoryKiB uint32, threads uint8) (KdfParams, time.Duration, error) {
+	params := KdfParams{Time: 1, MemoryKiB: memoryKiB, Threads: threads}
+	if err := params.Validate(); err != nil {
+		return KdfParams{}, 0, err
+	}
+	salt := "kdf-benchmark-salt"
+
+	// Time one pass, then scale up to the target
+	start := time.Now()
+	if _, err := DeriveKeyWithParams(salt, "password", params); err != nil {
+		return KdfParams{}, 0, err
+	}
+	onePass := time.Since(start)
+	if onePass < target && onePass > 0 {
+		passes := int64(target / onePass)
+		if passes > maxKdfTime {
+			passes = maxKdfTime
+		}
+		params.Time = uint32(passes)
+	}
+
+	start = time.Now()
+	if _, err := DeriveKeyWithParams(salt, "password", params); err != nil {
+		return KdfParams{}, 0, err
+	}
+	return params, time.Since(start), nil
+}
@@ -2,6 +2,7 @@ package cryptography
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,3 +14,36 @@ func TestDeriveKey(t *testing.T) {
 	expect := []byte{0xbd, 0x99, 0x25, 0x45, 0x6c, 0x49, 0xf3, 0x7f, 0x43, 0xd1, 0xc1, 0x5d, 0x80, 0x64, 0x4, 0x16, 0xca, 0x1a, 0xb6, 0x72, 0x6d, 0x3d, 0xd8, 0xb9, 0xe1, 0x8, 0x4e, 0x13, 0xd8, 0xa4, 0xe0, 0x18}
 	assert.Equal(t, expect, key)
 }
+
+func TestDeriveKeyWithParams(t *testing.T) {
+	salt := "saltSALTsaltSALTsaltSALTsaltSALT"
+	key, err := DeriveKeyWithParams(salt, "verysecretpassword", DefaultKdfParams)
+	assert.NoError(t, err)
+	defaultKey, err := DeriveKey(salt, "verysecretpassword")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultKey, key)
+
+	cheap := KdfParams{Time: 1, MemoryKiB: 64 * 1024, Threads: 1}
+	cheapKey, err := DeriveKeyWithParams(salt, "verysecretpassword", cheap)
+	assert.NoError(t, err)
+	assert.NotEqual(t, defaultKey, cheapKey)
+
+	_, err = DeriveKeyWithParams(salt, "verysecretpassword", KdfParams{Time: 1, MemoryKiB: 1024, Threads: 1})
+	assert.ErrorIs(t, err, ErrorBadKdfParams)
+	_, err = DeriveKeyWithParams(salt, "verysecretpassword", KdfParams{Time: 0, MemoryKiB: 64 * 1024, Threads: 1})
+	assert.ErrorIs(t, err, ErrorBadKdfParams)
+
+	// Parameters read from a bucket cannot demand unbounded time or memory
+	assert.ErrorIs(t, KdfParams{Time: maxKdfTime + 1, MemoryKiB: 64 * 1024, Threads: 1}.Validate(), ErrorBadKdfParams)
+	assert.ErrorIs(t, KdfParams{Time: 1, MemoryKiB: maxKdfMemoryKiB + 1, Threads: 1}.Validate(), ErrorBadKdfParams)
+	assert.NoError(t, KdfParams{Time: maxKdfTime, MemoryKiB: maxKdfMemoryKiB, Threads: 1}.Validate())
+}
+
+func TestBenchmarkKdfParams(t *testing.T) {
+	params, took, err := BenchmarkKdfParams(50*time.Millisecond, 64*1024, 1)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, params.Time, uint32(1))
+	assert.Equal(t, uint32(64*1024), params.MemoryKiB)
+	assert.Equal(t, uint8(1), params.Threads)
+	assert.Greater(t, took, time.Duration(0))
+}
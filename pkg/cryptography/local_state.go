@@ -0,0 +1,38 @@
+package cryptography
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	siv "github.com/secure-io/siv-go"
+)
+
+// Derives the key that encrypts the sensitive columns of the local sqlite database from the
+// bucket's HMAC key, which (unlike the encryption key) never changes when the key is rotated
+func DeriveLocalStateKey(hmacKey []byte) []byte {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write([]byte("tless local state key"))
+	return mac.Sum(nil)
+}
+
+// Encrypts plaintext with AES-GCM-SIV and a fixed nonce, so that equal plaintexts produce equal
+// ciphertexts and the result can be looked up by equality. Unlike EncryptFilename, the plaintext
+// isn't compressed, so the ciphertext doesn't depend on the compressor's output staying the same.
+func EncryptDeterministic(key []byte, plaintext []byte) ([]byte, error) {
+	aessiv, err := siv.NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aessiv.NonceSize())
+	return aessiv.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// Decrypts ciphertext from EncryptDeterministic
+func DecryptDeterministic(key []byte, ciphertext []byte) ([]byte, error) {
+	aessiv, err := siv.NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aessiv.NonceSize())
+	return aessiv.Open(nil, nonce, ciphertext, nil)
+}
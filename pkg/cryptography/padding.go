@@ -0,0 +1,105 @@
+package cryptography
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// Padding schemes that round the size of chunks and indexes up so that the cloud provider cannot
+// learn exact file sizes or file counts from the sizes of the objects it stores
+type Padding string
+
+const (
+	PaddingNone       Padding = "none"
+	PaddingPowerOfTwo Padding = "pow2"  // rounds up to the next power of two; at most 100% overhead
+	PaddingPadme      Padding = "padme" // Padmé (Nikitin et al., 2019); at most 12% overhead
+)
+
+// Set in the format byte of a compressed buffer whose end holds padding
+const compressionPadded byte = 0x80
+
+var (
+	ErrUnknownPadding = fmt.Errorf("unknown padding scheme (use 'none', 'padme' or 'pow2')")
+	ErrBadPadding     = fmt.Errorf("padding of buffer is malformed")
+)
+
+// Parses a padding scheme from the config file. Empty means PaddingNone.
+func ParsePadding(s string) (Padding, error) {
+	switch Padding(s) {
+	case "", PaddingNone:
+		return PaddingNone, nil
+	case PaddingPowerOfTwo, PaddingPadme:
+		return Padding(s), nil
+	default:
+		return PaddingNone, ErrUnknownPadding
+	}
+}
+
+// Returns the length that a buffer of n bytes is padded to under padding
+func PaddedLen(n int64, padding Padding) int64 {
+	if n < 2 {
+		return n
+	}
+	switch padding {
+	case PaddingPowerOfTwo:
+		return int64(1) << bits.Len64(uint64(n-1))
+	case PaddingPadme:
+		// Keep only the top log2(log2(n))+1 bits of n significant and round up the rest
+		e := bits.Len64(uint64(n)) - 1
+		s := bits.Len64(uint64(e))
+		mask := int64(1)<<(e-s) - 1
+		return (n + mask) &^ mask
+	default:
+		return n
+	}
+}
+
+// Pads a compressed buffer (which starts with its format byte) to its padded length by appending
+// zeroes followed by their 4-byte count, and flags the format byte as padded. Returns buf and 0
+// if padding is PaddingNone, otherwise the padded buffer and the number of bytes added.
+func padBuffer(buf []byte, padding Padding) ([]byte, int64) {
+	if padding == PaddingNone || padding == "" || len(buf) == 0 {
+		return buf, 0
+	}
+	paddedLen := PaddedLen(int64(len(buf))+4, padding)
+	zeroes := int(paddedLen) - len(buf) - 4
+
+	ret := make([]byte, 0, paddedLen)
+	ret = append(ret, buf[0]|compressionPadded)
+	ret = append(ret, buf[1:]...)
+	ret = append(ret, make([]byte, zeroes+4)...)
+	binary.BigEndian.PutUint32(ret[len(ret)-4:], uint32(zeroes))
+	return ret, paddedLen - int64(len(buf))
+}
+
+// Strips the padding added by padBuffer in place, returning the compressed buffer with its
+// original format byte
+func unpadBuffer(buf []byte) ([]byte, error) {
+	if len(buf) < 5 {
+		return nil, ErrBadPadding
+	}
+	zeroes := int64(binary.BigEndian.Uint32(buf[len(buf)-4:]))
+	if zeroes > int64(len(buf)-5) {
+		return nil, ErrBadPadding
+	}
+	buf[0] &^= compressionPadded
+	return buf[:int64(len(buf)-4)-zeroes], nil
+}
+
+// Pads a buffer of concatenated ciphertexts to its padded length with random bytes, which cannot
+// be told apart from ciphertext. Readers must locate each ciphertext by its offset and length.
+// Returns the padded buffer and the number of bytes added.
+func AppendRandomPadding(buf []byte, padding Padding) ([]byte, int64, error) {
+	paddingLen := PaddedLen(int64(len(buf)), padding) - int64(len(buf))
+	if paddingLen == 0 {
+		return buf, 0, nil
+	}
+	randBytes := make([]byte, paddingLen)
+	if _, err := io.ReadFull(rand.Reader, randBytes); err != nil {
+		return nil, 0, err
+	}
+	return append(buf, randBytes...), paddingLen, nil
+}
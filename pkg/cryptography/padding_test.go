@@ -0,0 +1,56 @@
+package cryptography
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaddedLen(t *testing.T) {
+	assert.Equal(t, int64(1000), PaddedLen(1000, PaddingNone))
+
+	assert.Equal(t, int64(1024), PaddedLen(1000, PaddingPowerOfTwo))
+	assert.Equal(t, int64(1024), PaddedLen(1024, PaddingPowerOfTwo))
+	assert.Equal(t, int64(2048), PaddedLen(1025, PaddingPowerOfTwo))
+
+	assert.Equal(t, int64(1024), PaddedLen(1000, PaddingPadme))
+	assert.Equal(t, int64(10240), PaddedLen(9999, PaddingPadme))
+	for _, n := range []int64{2, 3, 100, 4097, 123456, 134217727} {
+		padded := PaddedLen(n, PaddingPadme)
+		assert.GreaterOrEqual(t, padded, n)
+		assert.LessOrEqual(t, float64(padded), float64(n)*1.12)
+	}
+
+	_, err := ParsePadding("bogus")
+	assert.ErrorIs(t, err, ErrUnknownPadding)
+	padding, err := ParsePadding("")
+	assert.NoError(t, err)
+	assert.Equal(t, PaddingNone, padding)
+}
+
+func TestEncryptPaddedDataBuffer(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, 32)
+	sealKeys, err := GenerateSealKeys()
+	assert.NoError(t, err)
+
+	for _, sk := range []*SealKeys{nil, sealKeys} {
+		for _, plaintext := range [][]byte{[]byte("a"), bytes.Repeat([]byte("abcdefgh"), 1000)} {
+			unpadded, err := EncryptDataBuffer(key, sk, plaintext, false)
+			assert.NoError(t, err)
+
+			ciphertext, paddingLen, err := EncryptPaddedDataBuffer(key, sk, plaintext, false, PaddingPowerOfTwo)
+			assert.NoError(t, err)
+			assert.Greater(t, paddingLen, int64(0))
+			assert.Equal(t, len(unpadded)+int(paddingLen), len(ciphertext))
+
+			decrypted, _, err := DecryptDataBuffer(key, sk, ciphertext)
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+		}
+	}
+
+	// Malformed padding is rejected rather than misread
+	_, err = decompressBuffer([]byte{compressionNone | compressionPadded, 0x01, 0x00, 0x00, 0x00, 0x09})
+	assert.ErrorIs(t, err, ErrBadPadding)
+}
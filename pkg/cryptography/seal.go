@@ -0,0 +1,215 @@
+package cryptography
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// Sealed buffers are encrypted to an X25519 public key, so whoever writes them cannot read them
+// back without the private key. Each buffer gets its own random data key, which is encrypted with
+// a key agreed between a one-time (ephemeral) key pair and the public key:
+//
+//	magic | ephemeral public key | nonce + AES-GCM(data key) | EncryptBuffer(data key, plaintext)
+
+var (
+	// ErrPrivateKeyRequired is returned when decrypting a sealed buffer without the private key
+	ErrPrivateKeyRequired = errors.New("this data is sealed to the bucket's write-only public key; its private key is required to read it")
+
+	sealMagic = []byte("tls1")
+)
+
+const (
+	sealWrappedDataKeyLen = 12 + 32 + 16
+	sealHeaderLen         = 4 + 32 + sealWrappedDataKeyLen
+)
+
+// SealKeys is the X25519 key pair of a bucket in write-only mode. PrivateKey is nil unless it was
+// unlocked, in which case sealed buffers can be read as well as written.
+type SealKeys struct {
+	PublicKey  []byte
+	PrivateKey []byte
+}
+
+func (sk *SealKeys) CanOpen() bool {
+	return sk != nil && sk.PrivateKey != nil
+}
+
+// Generates a new X25519 key pair
+func GenerateSealKeys() (*SealKeys, error) {
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := io.ReadFull(rand.Reader, privateKey); err != nil {
+		return nil, err
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	return &SealKeys{PublicKey: publicKey, PrivateKey: privateKey}, nil
+}
+
+// Returns the key that encrypts a sealed buffer's data key
+func sealKek(sharedSecret []byte, ephemeralPublicKey []byte, publicKey []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("tless seal v1"))
+	h.Write(sharedSecret)
+	h.Write(ephemeralPublicKey)
+	h.Write(publicKey)
+	return h.Sum(nil)
+}
+
+// Encrypts plaintext so that only the holder of publicKey's private key can decrypt it
+func SealBuffer(publicKey []byte, plaintext []byte, tryCompression bool) ([]byte, error) {
+	return sealCompressedBuffer(publicKey, compressBuffer(plaintext, tryCompression), nil)
+}
+
+// Seals the output of compressBuffer (and padBuffer, if padded), authenticating aad with it
+func sealCompressedBuffer(publicKey []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	ephemeral, err := GenerateSealKeys()
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := curve25519.X25519(ephemeral.PrivateKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	kekAesgcm, err := newAesGcm(sealKek(sharedSecret, ephemeral.PublicKey, publicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	ciphertext, err := encryptCompressedBuffer(dataKey, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, sealHeaderLen+len(ciphertext))
+	sealed = append(sealed, sealMagic...)
+	sealed = append(sealed, ephemeral.PublicKey...)
+	sealed = append(sealed, nonce...)
+	sealed = kekAesgcm.Seal(sealed, nonce, dataKey, nil)
+	return append(sealed, ciphertext...), nil
+}
+
+// Returns true if ciphertext looks like it came from SealBuffer. (A buffer from EncryptBuffer
+// begins with a random nonce, so it can, rarely, look sealed too.)
+func IsSealedBuffer(ciphertext []byte) bool {
+	return len(ciphertext) > sealHeaderLen && bytes.Equal(ciphertext[:len(sealMagic)], sealMagic)
+}
+
+// Decrypts a buffer from SealBuffer, which was sealed with additional data aad if not nil. Like
+// DecryptBufferReturningNonce, also returns the nonce of the encrypted plaintext.
+func OpenSealedBuffer(privateKey []byte, ciphertext []byte, aad []byte) (plaintext []byte, nonce []byte, err error) {
+	if !IsSealedBuffer(ciphertext) {
+		return nil, nil, fmt.Errorf("error: OpenSealedBuffer: not a sealed buffer")
+	}
+	ephemeralPublicKey := ciphertext[4:36]
+	wrappedDataKey := ciphertext[36:sealHeaderLen]
+
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedSecret, err := curve25519.X25519(privateKey, ephemeralPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	kekAesgcm, err := newAesGcm(sealKek(sharedSecret, ephemeralPublicKey, publicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	dataKey, err := kekAesgcm.Open(nil, wrappedDataKey[:12], wrappedDataKey[12:], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decryptBufferReturningNonce(dataKey, ciphertext[sealHeaderLen:], aad)
+}
+
+// Encrypts chunk or index data: sealed to the public key if sealKeys is non-nil (the bucket is in
+// write-only mode), otherwise with key.
+func EncryptDataBuffer(key []byte, sealKeys *SealKeys, plaintext []byte, tryCompression bool) ([]byte, error) {
+	return EncryptDataBufferWithAad(key, sealKeys, plaintext, tryCompression, nil)
+}
+
+// Like EncryptDataBuffer, but also authenticates aad, which must then be passed to
+// DecryptDataBufferWithAad for the ciphertext to decrypt
+func EncryptDataBufferWithAad(key []byte, sealKeys *SealKeys, plaintext []byte, tryCompression bool, aad []byte) ([]byte, error) {
+	if sealKeys != nil {
+		return sealCompressedBuffer(sealKeys.PublicKey, compressBuffer(plaintext, tryCompression), aad)
+	}
+	return encryptCompressedBuffer(key, compressBuffer(plaintext, tryCompression), aad)
+}
+
+// The additional data that binds a separately encrypted entry of a packed chunk to the chunk and
+// offset it was written at, so that entries cannot be moved or swapped without failing to decrypt
+func PackedEntryAad(chunkName string, offset int64) []byte {
+	aad := []byte("tless packed entry v1\x00" + chunkName + "\x00")
+	offsetBuf := make([]byte, 8)
+	binary.BigEndian.PutUint64(offsetBuf, uint64(offset))
+	return append(aad, offsetBuf...)
+}
+
+// Like EncryptDataBuffer, but first pads the (possibly compressed) plaintext under padding. Also
+// returns the number of bytes of padding added.
+func EncryptPaddedDataBuffer(key []byte, sealKeys *SealKeys, plaintext []byte, tryCompression bool, padding Padding) (ciphertext []byte, paddingLen int64, err error) {
+	return EncryptPaddedDataBufferWithAad(key, sealKeys, plaintext, tryCompression, padding, nil)
+}
+
+// Like EncryptDataBufferWithAad, but first pads the (possibly compressed) plaintext under padding.
+// Also returns the number of bytes of padding added.
+func EncryptPaddedDataBufferWithAad(key []byte, sealKeys *SealKeys, plaintext []byte, tryCompression bool, padding Padding, aad []byte) (ciphertext []byte, paddingLen int64, err error) {
+	compressed, paddingLen := padBuffer(compressBuffer(plaintext, tryCompression), padding)
+	if sealKeys != nil {
+		ciphertext, err = sealCompressedBuffer(sealKeys.PublicKey, compressed, aad)
+	} else {
+		ciphertext, err = encryptCompressedBuffer(key, compressed, aad)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return ciphertext, paddingLen, nil
+}
+
+// Decrypts chunk or index data from EncryptDataBuffer, whichever way it was encrypted. Returns
+// ErrPrivateKeyRequired for sealed data if sealKeys cannot open it.
+func DecryptDataBuffer(key []byte, sealKeys *SealKeys, ciphertext []byte) (plaintext []byte, nonce []byte, err error) {
+	return DecryptDataBufferWithAad(key, sealKeys, ciphertext, nil)
+}
+
+// Decrypts data from EncryptDataBufferWithAad, which fails unless aad is what it was encrypted with
+func DecryptDataBufferWithAad(key []byte, sealKeys *SealKeys, ciphertext []byte, aad []byte) (plaintext []byte, nonce []byte, err error) {
+	if len(ciphertext) <= 12 {
+		return nil, nil, fmt.Errorf("error: DecryptDataBuffer: ciphertext too short to be valid")
+	}
+	if !IsSealedBuffer(ciphertext) {
+		return decryptBufferReturningNonce(key, ciphertext, aad)
+	}
+
+	if sealKeys.CanOpen() {
+		if plaintext, nonce, err = OpenSealedBuffer(sealKeys.PrivateKey, ciphertext, aad); err == nil {
+			return plaintext, nonce, nil
+		}
+	}
+	if plaintext, nonce, err2 := decryptBufferReturningNonce(key, ciphertext, aad); err2 == nil {
+		return plaintext, nonce, nil
+	}
+	if !sealKeys.CanOpen() {
+		return nil, nil, ErrPrivateKeyRequired
+	}
+	return nil, nil, err
+}
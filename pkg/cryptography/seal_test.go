@@ -0,0 +1,67 @@
+package cryptography
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealAndOpenBuffer(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := bytes.Repeat([]byte("write-only backups "), 100)
+
+	sealKeys, err := GenerateSealKeys()
+	assert.NoError(t, err)
+	writeOnlyKeys := &SealKeys{PublicKey: sealKeys.PublicKey}
+	assert.True(t, sealKeys.CanOpen())
+	assert.False(t, writeOnlyKeys.CanOpen())
+
+	// Sealing only needs the public key, but opening needs the private key
+	sealed, err := EncryptDataBuffer(key, writeOnlyKeys, plaintext, true)
+	assert.NoError(t, err)
+	assert.True(t, IsSealedBuffer(sealed))
+	_, _, err = DecryptDataBuffer(key, writeOnlyKeys, sealed)
+	assert.ErrorIs(t, err, ErrPrivateKeyRequired)
+	_, _, err = DecryptDataBuffer(key, nil, sealed)
+	assert.ErrorIs(t, err, ErrPrivateKeyRequired)
+	opened, _, err := DecryptDataBuffer(key, sealKeys, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	// Another key pair's private key cannot open it
+	otherSealKeys, err := GenerateSealKeys()
+	assert.NoError(t, err)
+	_, _, err = OpenSealedBuffer(otherSealKeys.PrivateKey, sealed, nil)
+	assert.Error(t, err)
+
+	// Data encrypted before write-only mode was turned on can still be read
+	encrypted, err := EncryptDataBuffer(key, nil, plaintext, true)
+	assert.NoError(t, err)
+	decrypted, _, err := DecryptDataBuffer(key, sealKeys, encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+}
+
+func TestPackedEntryAad(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := []byte("a small file")
+	sealKeys, err := GenerateSealKeys()
+	assert.NoError(t, err)
+
+	for _, sk := range []*SealKeys{nil, sealKeys} {
+		ciphertext, err := EncryptDataBufferWithAad(key, sk, plaintext, true, PackedEntryAad("chunk", 100))
+		assert.NoError(t, err)
+		decrypted, _, err := DecryptDataBufferWithAad(key, sk, ciphertext, PackedEntryAad("chunk", 100))
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+
+		// An entry moved to another offset or chunk, or read as unbound, does not decrypt
+		_, _, err = DecryptDataBufferWithAad(key, sk, ciphertext, PackedEntryAad("chunk", 200))
+		assert.Error(t, err)
+		_, _, err = DecryptDataBufferWithAad(key, sk, ciphertext, PackedEntryAad("other", 100))
+		assert.Error(t, err)
+		_, _, err = DecryptDataBuffer(key, sk, ciphertext)
+		assert.Error(t, err)
+	}
+}
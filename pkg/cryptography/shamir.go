@@ -0,0 +1,120 @@
+package cryptography
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Shamir's secret sharing over GF(2^8), one polynomial per byte of the secret. A share is the
+// polynomials evaluated at its x coordinate (1-255).
+
+var (
+	ErrShamirDuplicateShare = errors.New("shamir: the same share was given twice")
+	ErrShamirShareLengths   = errors.New("shamir: shares are not all the same length")
+)
+
+// Log and exp tables for GF(2^8) with the AES polynomial x^8+x^4+x^3+x+1 and generator 3
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfExp[i+255] = x
+		gfLog[x] = byte(i)
+		// multiply x by the generator 3, i.e., x*2 ^ x
+		x2 := x << 1
+		if x&0x80 != 0 {
+			x2 ^= 0x1b
+		}
+		x ^= x2
+	}
+}
+
+func gfMul(a byte, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a byte, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// SplitSecret splits secret into n shares, any threshold of which can rebuild it with
+// CombineShares. Returns the x coordinate of each share along with its bytes.
+func SplitSecret(secret []byte, n int, threshold int) (xs []byte, shares [][]byte, err error) {
+	if threshold < 2 || threshold > n || n > 255 {
+		return nil, nil, fmt.Errorf("shamir: need 2 <= threshold <= shares <= 255 (got %d of %d)", threshold, n)
+	}
+
+	xs = make([]byte, n)
+	shares = make([][]byte, n)
+	for i := 0; i < n; i++ {
+		xs[i] = byte(i + 1)
+		shares[i] = make([]byte, len(secret))
+	}
+
+	coeffs := make([]byte, threshold)
+	for b, secretByte := range secret {
+		// random polynomial of degree threshold-1 whose constant term is the secret byte
+		coeffs[0] = secretByte
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, nil, err
+		}
+		for i, x := range xs {
+			// Horner's method
+			y := byte(0)
+			for c := threshold - 1; c >= 0; c-- {
+				y = gfMul(y, x) ^ coeffs[c]
+			}
+			shares[i][b] = y
+		}
+	}
+	return xs, shares, nil
+}
+
+// CombineShares rebuilds a secret from shares produced by SplitSecret. It cannot tell whether
+// enough shares were given: too few (or mismatched) shares silently yield garbage.
+func CombineShares(xs []byte, shares [][]byte) ([]byte, error) {
+	if len(xs) != len(shares) || len(shares) == 0 {
+		return nil, fmt.Errorf("shamir: no shares given")
+	}
+	for i := range xs {
+		if xs[i] == 0 {
+			return nil, fmt.Errorf("shamir: invalid share x coordinate 0")
+		}
+		if len(shares[i]) != len(shares[0]) {
+			return nil, ErrShamirShareLengths
+		}
+		for j := 0; j < i; j++ {
+			if xs[i] == xs[j] {
+				return nil, ErrShamirDuplicateShare
+			}
+		}
+	}
+
+	// Lagrange interpolation at x=0
+	secret := make([]byte, len(shares[0]))
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i != j {
+				// (0 - x_j) / (x_i - x_j), where subtraction is xor
+				basis = gfMul(basis, gfDiv(xs[j], xs[i]^xs[j]))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(shares[i][b], basis)
+		}
+	}
+	return secret, nil
+}
@@ -12,6 +12,8 @@ import (
 type DB struct {
 	dbConn      *sql.DB
 	sqlite3Conn *sqlite3.SQLiteConn
+	stateKey    []byte // encrypts sensitive columns once set by UnlockState
+	stateLocked bool   // sensitive columns are encrypted and need stateKey
 }
 
 var (
@@ -72,6 +74,12 @@ func (dbSrc *DB) BackupTo(dbDst *DB) {
 	if err != nil {
 		log.Fatal("error: BackupTo: ", err)
 	}
+
+	// The copy is encrypted the same way, so it needs the same key
+	if dbDst.stateKey == nil {
+		dbDst.stateKey = dbSrc.stateKey
+		dbDst.stateLocked = dbSrc.stateLocked
+	}
 }
 
 func (db *DB) querySingleRowCount(sql string) (int, error) {
@@ -176,13 +184,24 @@ func (db *DB) createTables() error {
 // (true,last_backup,id,nil) if found.  Returns (false,0,0,err) if not
 // found.
 func (db *DB) HasDirEnt(rootDirName string, relPath string) (isFound bool, lastBackupUnix int64, id int, err error) {
+	sealedRootDirName, err := db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("error: HasDirEnt: %v", err)
+		return false, 0, 0, err
+	}
+	sealedRelPath, err := db.sealPath(relPath)
+	if err != nil {
+		log.Printf("error: HasDirEnt: %v", err)
+		return false, 0, 0, err
+	}
+
 	stmt, err := db.dbConn.Prepare("select id, last_backup from dirents where rootdir = ? AND relpath = ?")
 	if err != nil {
 		log.Printf("error: HasDirEnt (1): %v", err)
 		return false, 0, 0, err
 	}
 	defer stmt.Close()
-	err = stmt.QueryRow(rootDirName, relPath).Scan(&id, &lastBackupUnix)
+	err = stmt.QueryRow(sealedRootDirName, sealedRelPath).Scan(&id, &lastBackupUnix)
 	if errors.Is(err, sql.ErrNoRows) {
 		return false, 0, 0, nil
 	} else if err != nil {
@@ -193,9 +212,109 @@ func (db *DB) HasDirEnt(rootDirName string, relPath string) (isFound bool, lastB
 	}
 }
 
+// What a dir entry looked like when it was last queued for backup. Any difference means it has
+// changed, even if its mtime hasn't moved forward (edits within the same second as a backup, or
+// files put in place with old mtimes by rsync -t or tar).
+type DirEntFingerprint struct {
+	Size    int64
+	Inode   uint64
+	MTimeNs int64
+	CTimeNs int64
+	Mode    uint32
+}
+
+type DirEntState struct {
+	Id             int
+	LastBackupUnix int64
+	Fingerprint    *DirEntFingerprint // nil if not recorded yet
+	ContentHash    []byte             // nil if not recorded yet
+}
+
+// Looks up a dir entry like HasDirEnt, along with its fingerprint. Returns nil if not found.
+func (db *DB) GetDirEntState(rootDirName string, relPath string) (*DirEntState, error) {
+	sealedRootDirName, err := db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("error: GetDirEntState: %v", err)
+		return nil, err
+	}
+	sealedRelPath, err := db.sealPath(relPath)
+	if err != nil {
+		log.Printf("error: GetDirEntState: %v", err)
+		return nil, err
+	}
+
+	var state DirEntState
+	var size, inode, mtimeNs, ctimeNs, mode sql.NullInt64
+	var sealedContentHash []byte
+	err = db.dbConn.QueryRow("select id, last_backup, size, inode, mtime_ns, ctime_ns, mode, content_hash from dirents where rootdir = ? AND relpath = ?", sealedRootDirName, sealedRelPath).Scan(
+		&state.Id, &state.LastBackupUnix, &size, &inode, &mtimeNs, &ctimeNs, &mode, &sealedContentHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		log.Printf("error: GetDirEntState: %v", err)
+		return nil, err
+	}
+
+	if size.Valid && inode.Valid && mtimeNs.Valid && ctimeNs.Valid && mode.Valid {
+		state.Fingerprint = &DirEntFingerprint{
+			Size:    size.Int64,
+			Inode:   uint64(inode.Int64),
+			MTimeNs: mtimeNs.Int64,
+			CTimeNs: ctimeNs.Int64,
+			Mode:    uint32(mode.Int64),
+		}
+	}
+	if len(sealedContentHash) > 0 {
+		if state.ContentHash, err = db.openBlob(sealedContentHash); err != nil {
+			log.Printf("error: GetDirEntState: %v", err)
+			return nil, err
+		}
+	}
+	return &state, nil
+}
+
+// Records the fingerprint of a dir entry. A nil contentHash clears any recorded content hash.
+func (db *DB) SetDirEntFingerprint(dirEntId int, fp DirEntFingerprint, contentHash []byte) error {
+	sealedContentHash, err := db.sealBlob(contentHash)
+	if err != nil {
+		log.Printf("error: SetDirEntFingerprint: %v", err)
+		return err
+	}
+	if len(sealedContentHash) == 0 {
+		sealedContentHash = nil
+	}
+
+	_, err = db.dbConn.Exec("update dirents set size = ?, inode = ?, mtime_ns = ?, ctime_ns = ?, mode = ?, content_hash = ? where id = ?",
+		fp.Size, int64(fp.Inode), fp.MTimeNs, fp.CTimeNs, fp.Mode, sealedContentHash, dirEntId)
+	if err != nil {
+		log.Printf("error: SetDirEntFingerprint: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Returns the index entry kept for a dir entry by KeepBackupJournalIndexEntries, or nil if none was
+func (db *DB) GetDirEntIndexEntry(dirEntId int) ([]byte, error) {
+	var sealedIndexEntry []byte
+	err := db.dbConn.QueryRow("select index_entry from dirents where id = ?", dirEntId).Scan(&sealedIndexEntry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		log.Printf("error: GetDirEntIndexEntry: %v", err)
+		return nil, err
+	}
+	indexEntry, err := db.openBlob(sealedIndexEntry)
+	if err != nil {
+		log.Printf("error: GetDirEntIndexEntry: %v", err)
+		return nil, err
+	}
+	return indexEntry, nil
+}
+
 type InsertDirEntStmt struct {
 	stmt *sql.Stmt
 	tx   *sql.Tx
+	db   *DB
 }
 
 func NewInsertDirEntStmt(db *DB) (*InsertDirEntStmt, error) {
@@ -211,7 +330,7 @@ func NewInsertDirEntStmt(db *DB) (*InsertDirEntStmt, error) {
 		return nil, err
 	}
 
-	return &InsertDirEntStmt{stmt: stmt, tx: tx}, nil
+	return &InsertDirEntStmt{stmt: stmt, tx: tx, db: db}, nil
 }
 
 func (idst *InsertDirEntStmt) Close() {
@@ -222,7 +341,18 @@ func (idst *InsertDirEntStmt) Close() {
 
 // Inserts a new path into dirent table and returns id of row.
 func (idst *InsertDirEntStmt) InsertDirEnt(rootDirName string, relPath string, lastBackupUnix int64) error {
-	_, err := idst.stmt.Exec(rootDirName, relPath, lastBackupUnix)
+	sealedRootDirName, err := idst.db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("Error: InsertDirEnt: %v", err)
+		return err
+	}
+	sealedRelPath, err := idst.db.sealPath(relPath)
+	if err != nil {
+		log.Printf("Error: InsertDirEnt: %v", err)
+		return err
+	}
+
+	_, err = idst.stmt.Exec(sealedRootDirName, sealedRelPath, lastBackupUnix)
 	if err != nil {
 		log.Printf("Error: InsertDirEnt: %v", err)
 		return err
@@ -234,20 +364,30 @@ func (idst *InsertDirEntStmt) InsertDirEnt(rootDirName string, relPath string, l
 func (db *DB) GetAllKnownPaths(rootDirName string) (map[string]int, error) {
 	paths := make(map[string]int, 0)
 
-	rows, err := db.dbConn.Query("select id, rootdir, relpath from dirents where rootdir = '" + rootDirName + "'")
+	sealedRootDirName, err := db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("error: GetAllKnownPaths: %v", err)
+		return nil, err
+	}
+
+	rows, err := db.dbConn.Query("select id, relpath from dirents where rootdir = ?", sealedRootDirName)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var id int
-		var rootdir string
-		var relpath string
-		err = rows.Scan(&id, &rootdir, &relpath)
+		var sealedRelPath string
+		err = rows.Scan(&id, &sealedRelPath)
 		if err != nil {
 			log.Fatal(err)
 		}
-		paths[rootdir+"/"+relpath] = id
+		relpath, err := db.openPath(sealedRelPath)
+		if err != nil {
+			log.Printf("error: GetAllKnownPaths: %v", err)
+			return nil, err
+		}
+		paths[rootDirName+"/"+relpath] = id
 	}
 	err = rows.Err()
 	if err != nil {
@@ -274,6 +414,17 @@ func (db *DB) UpdateLastBackupTime(dirEntId int) error {
 	return nil
 }
 
+// Resets the last backup time of one dir entry to zero, so that it is backed up again next time
+// whatever its fingerprint. Used when backing it up failed.
+func (db *DB) ResetLastBackedUpTime(dirEntId int) error {
+	_, err := db.dbConn.Exec("update dirents set last_backup = 0 where id = ?", dirEntId)
+	if err != nil {
+		log.Printf("Error: ResetLastBackedUpTime: %v", err)
+		return err
+	}
+	return nil
+}
+
 func (db *DB) getDirEntById(dirEntId int) (id int, rootDirName string, relPath string, lastBackupUnix int64, err error) {
 	stmt, err := db.dbConn.Prepare("select id, rootdir, relpath, last_backup from dirents where id = ?")
 	if err != nil {
@@ -282,13 +433,22 @@ func (db *DB) getDirEntById(dirEntId int) (id int, rootDirName string, relPath s
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRow(dirEntId).Scan(&id, &rootDirName, &relPath, &lastBackupUnix)
+	var sealedRootDirName, sealedRelPath string
+	err = stmt.QueryRow(dirEntId).Scan(&id, &sealedRootDirName, &sealedRelPath, &lastBackupUnix)
 	if errors.Is(err, sql.ErrNoRows) {
 		return 0, "", "", 0, err
 	} else if err != nil {
 		log.Printf("error: getDirEntById: %v", err)
 		return 0, "", "", 0, err
 	}
+	if rootDirName, err = db.openPath(sealedRootDirName); err != nil {
+		log.Printf("error: getDirEntById: %v", err)
+		return 0, "", "", 0, err
+	}
+	if relPath, err = db.openPath(sealedRelPath); err != nil {
+		log.Printf("error: getDirEntById: %v", err)
+		return 0, "", "", 0, err
+	}
 
 	return id, rootDirName, relPath, lastBackupUnix, nil
 }
@@ -307,6 +467,17 @@ func (db *DB) GetDirEntPaths(dirEntId int) (rootDirName string, relPath string,
 }
 
 func (db *DB) DeleteDirEntByPath(rootDirName string, relPath string) error {
+	sealedRootDirName, err := db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("Error: DeleteDirEntByPath: %v", err)
+		return err
+	}
+	sealedRelPath, err := db.sealPath(relPath)
+	if err != nil {
+		log.Printf("Error: DeleteDirEntByPath: %v", err)
+		return err
+	}
+
 	stmt, err := db.dbConn.Prepare("delete from dirents where rootdir = ? AND relpath = ?")
 	if err != nil {
 		log.Printf("Error: DeleteDirEntByPath: %v", err)
@@ -314,7 +485,7 @@ func (db *DB) DeleteDirEntByPath(rootDirName string, relPath string) error {
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(rootDirName, relPath)
+	_, err = stmt.Exec(sealedRootDirName, sealedRelPath)
 	if err != nil {
 		log.Printf("Error: DeleteDirEntByPath: %v", err)
 		return err
@@ -325,6 +496,12 @@ func (db *DB) DeleteDirEntByPath(rootDirName string, relPath string) error {
 // Resets the last backup time for every rel path in a particular backup to zero, thus ensuring
 // that a full backup will be done the next time a backup command runs.
 func (db *DB) ResetLastBackedUpTimeForEntireBackup(rootDirName string) error {
+	sealedRootDirName, err := db.sealPath(rootDirName)
+	if err != nil {
+		log.Printf("Error: ResetLastBackedUpTimeForEntireBackup: %v", err)
+		return err
+	}
+
 	stmt, err := db.dbConn.Prepare("UPDATE dirents SET last_backup=0 WHERE rootdir = ?")
 	if err != nil {
 		log.Printf("Error: ResetLastBackedUpTimeForEntireBackup: %v", err)
@@ -332,7 +509,7 @@ func (db *DB) ResetLastBackedUpTimeForEntireBackup(rootDirName string) error {
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(rootDirName)
+	_, err = stmt.Exec(sealedRootDirName)
 	if err != nil {
 		log.Printf("Error: ResetLastBackedUpTimeForEntireBackup: %v", err)
 		return err
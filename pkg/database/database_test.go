@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fsctl/tless/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -248,3 +249,283 @@ func TestBackupJournalFunctions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, false, hasDirty)
 }
+
+func TestRotateKeyJournal(t *testing.T) {
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	assert.NoError(t, db.PerformDbMigrations(util.NewVLog(nil, func() bool { return false })))
+	version, err := db.getDbVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 8, version)
+
+	assert.NoError(t, db.InsertRotateKeyJournalTasks(1, []string{"chunks/a", "chunks/b"}))
+	has, err := db.HasRotateKeyJournal(1)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = db.HasRotateKeyJournal(2)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	task, err := db.ClaimNextRotateKeyJournalTask()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CompleteRotateKeyJournalTask(task))
+	_, err = db.ClaimNextRotateKeyJournalTask()
+	assert.NoError(t, err)
+	_, err = db.ClaimNextRotateKeyJournalTask()
+	assert.True(t, errors.Is(err, ErrNoWork))
+
+	// An interrupted task becomes claimable again
+	assert.NoError(t, db.ResetAllInProgressRotateKeyJournalTasks())
+	finished, total, err := db.GetRotateKeyJournalCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), finished)
+	assert.Equal(t, int64(2), total)
+	task2, err := db.ClaimNextRotateKeyJournalTask()
+	assert.NoError(t, err)
+	assert.NotEqual(t, task.ChunkName, task2.ChunkName)
+
+	assert.NoError(t, db.WipeRotateKeyJournal())
+	_, total, err = db.GetRotateKeyJournalCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestManifestVersions(t *testing.T) {
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(util.NewVLog(nil, func() bool { return false })))
+
+	version, err := db.GetLastSeenManifestVersion("bucket-a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), version)
+
+	assert.NoError(t, db.SetLastSeenManifestVersion("bucket-a", 5))
+	assert.NoError(t, db.SetLastSeenManifestVersion("bucket-a", 7))
+	assert.NoError(t, db.SetLastSeenManifestVersion("bucket-b", 1))
+	version, err = db.GetLastSeenManifestVersion("bucket-a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), version)
+	version, err = db.GetLastSeenManifestVersion("bucket-b")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), version)
+}
+
+func TestSpaceUsagePadding(t *testing.T) {
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(util.NewVLog(nil, func() bool { return false })))
+
+	// The day's peak comes with the padding of that same report
+	now := time.Now().Unix()
+	assert.NoError(t, db.AddSpaceUsageReport(now, 1000, 10))
+	assert.NoError(t, db.AddSpaceUsageReport(now, 3000, 30))
+	assert.NoError(t, db.AddSpaceUsageReport(now, 2000, 20))
+	peaks, err := db.GetPeakDailySpaceUsage(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(peaks))
+	assert.Equal(t, int64(3000), peaks[0].ByteCnt)
+	assert.Equal(t, int64(30), peaks[0].PaddingByteCnt)
+
+	assert.NoError(t, db.AddObjectPadding("chunks/a", 5))
+	assert.NoError(t, db.AddObjectPadding("chunks/b", 7))
+	assert.NoError(t, db.AddObjectPadding("chunks/a", 6))
+	assert.NoError(t, db.DeleteObjectPadding([]string{"chunks/b"}))
+	objPadding, err := db.GetObjectPadding()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"chunks/a": 6}, objPadding)
+}
+
+func TestLocalStateEncryption(t *testing.T) {
+	dbPath := t.TempDir() + "/state.db"
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := NewDB(dbPath)
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	// Plaintext rows written before the state was first unlocked get encrypted in place
+	dirEntStmt, err := NewInsertDirEntStmt(db)
+	assert.NoError(t, err)
+	assert.NoError(t, dirEntStmt.InsertDirEnt("root", "secret/plans.txt", 0))
+	dirEntStmt.Close()
+	hmacKey := make([]byte, 32)
+	assert.NoError(t, db.UnlockState(hmacKey, vlog))
+
+	dirEntStmt, err = NewInsertDirEntStmt(db)
+	assert.NoError(t, err)
+	assert.NoError(t, dirEntStmt.InsertDirEnt("root", "secret/other.txt", 0))
+	dirEntStmt.Close()
+	insertBJTxn, err := db.NewInsertBackupJournalStmt("/home/me/root")
+	assert.NoError(t, err)
+	assert.NoError(t, insertBJTxn.InsertBackupJournalRow(1, Unstarted, Updated))
+	insertBJTxn.Close()
+	bjt, err := db.ClaimNextBackupJournalTask()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CompleteBackupJournalTask(bjt, []byte(`{"secret/plans.txt":[]}`)))
+
+	// Nothing sensitive is stored as plaintext
+	var cnt int
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM dirents WHERE rootdir = 'root' OR relpath LIKE '%secret%'").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM backup_info WHERE dirpath LIKE '%home%'").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM backup_journal WHERE CAST(index_entry AS TEXT) LIKE '%secret%'").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+
+	// ...but it all reads back
+	paths, err := db.GetAllKnownPaths("root")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"root/secret/plans.txt": 1, "root/secret/other.txt": 2}, paths)
+	isFound, _, id, err := db.HasDirEnt("root", "secret/other.txt")
+	assert.NoError(t, err)
+	assert.True(t, isFound)
+	assert.Equal(t, 2, id)
+	dirPath, _, err := db.GetJournaledBackupInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/me/root", dirPath)
+	indexEntries, err := db.GetAllBackupJournalRowIndexEntries()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte(`{"secret/plans.txt":[]}`)}, indexEntries)
+
+	// Reopened without the key, the state is locked rather than misread as plaintext
+	db2, err := NewDB(dbPath)
+	assert.NoError(t, err)
+	defer db2.Close()
+	assert.NoError(t, db2.PerformDbMigrations(vlog))
+	_, err = db2.GetAllKnownPaths("root")
+	assert.ErrorIs(t, err, ErrStateLocked)
+
+	// With a different key, the state is left alone and stays locked
+	otherHmacKey := make([]byte, 32)
+	otherHmacKey[0] = 1
+	assert.ErrorIs(t, db2.UnlockState(otherHmacKey, vlog), ErrStateKeyMismatch)
+	_, err = db2.GetAllKnownPaths("root")
+	assert.ErrorIs(t, err, ErrStateLocked)
+
+	// until it is reset on purpose, after which backups can carry on
+	assert.NoError(t, db2.ResetState())
+	assert.NoError(t, db2.UnlockState(otherHmacKey, vlog))
+	paths, err = db2.GetAllKnownPaths("root")
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+	hasDirty, err := db2.HasDirtyBackupJournal()
+	assert.NoError(t, err)
+	assert.False(t, hasDirty)
+}
+
+func TestDirEntFingerprint(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+	assert.NoError(t, db.UnlockState(make([]byte, 32), vlog))
+
+	dirEntStmt, err := NewInsertDirEntStmt(db)
+	assert.NoError(t, err)
+	assert.NoError(t, dirEntStmt.InsertDirEnt("root", "file", 0))
+	dirEntStmt.Close()
+
+	// Entries start out without a fingerprint
+	state, err := db.GetDirEntState("root", "file")
+	assert.NoError(t, err)
+	assert.Equal(t, &DirEntState{Id: 1, LastBackupUnix: 0}, state)
+	state, err = db.GetDirEntState("root", "nonexistent")
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	fp := DirEntFingerprint{Size: 10, Inode: 1 << 63, MTimeNs: 1600000000123456789, CTimeNs: 1600000001123456789}
+	assert.NoError(t, db.SetDirEntFingerprint(1, fp, []byte("hash")))
+	assert.NoError(t, db.UpdateLastBackupTime(1))
+	state, err = db.GetDirEntState("root", "file")
+	assert.NoError(t, err)
+	assert.Equal(t, fp, *state.Fingerprint)
+	assert.Equal(t, []byte("hash"), state.ContentHash)
+	assert.Greater(t, state.LastBackupUnix, int64(0))
+
+	// Content hashes are encrypted
+	var cnt int
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM dirents WHERE content_hash = CAST('hash' AS BLOB)").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+
+	assert.NoError(t, db.SetDirEntFingerprint(1, fp, nil))
+	assert.NoError(t, db.ResetLastBackedUpTime(1))
+	state, err = db.GetDirEntState("root", "file")
+	assert.NoError(t, err)
+	assert.Nil(t, state.ContentHash)
+	assert.Equal(t, int64(0), state.LastBackupUnix)
+}
+
+func TestMovedBackupJournalRow(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+	assert.NoError(t, db.UnlockState(make([]byte, 32), vlog))
+
+	insertBJTxn, err := db.NewInsertBackupJournalStmt("/home/me/root")
+	assert.NoError(t, err)
+	assert.NoError(t, insertBJTxn.InsertMovedBackupJournalRow(1, Unstarted, "secret/old.txt"))
+	insertBJTxn.Close()
+
+	// The old path is encrypted like the others
+	var cnt int
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM backup_journal WHERE moved_from LIKE '%secret%'").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+
+	bjt, err := db.ClaimNextBackupJournalTask()
+	assert.NoError(t, err)
+	assert.Equal(t, Moved, bjt.ChangeType)
+	assert.Equal(t, int64(1), bjt.DirEntId)
+	assert.Equal(t, "secret/old.txt", bjt.MovedFrom)
+}
+
+func TestKeepBackupJournalIndexEntries(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+	assert.NoError(t, db.UnlockState(make([]byte, 32), vlog))
+
+	dirEntStmt, err := NewInsertDirEntStmt(db)
+	assert.NoError(t, err)
+	assert.NoError(t, dirEntStmt.InsertDirEnt("root", "kept", 0))
+	assert.NoError(t, dirEntStmt.InsertDirEnt("root", "failed", 0))
+	dirEntStmt.Close()
+	insertBJTxn, err := db.NewInsertBackupJournalStmt("/home/me/root")
+	assert.NoError(t, err)
+	assert.NoError(t, insertBJTxn.InsertBackupJournalRow(1, Unstarted, Updated))
+	assert.NoError(t, insertBJTxn.InsertBackupJournalRow(2, Unstarted, Updated))
+	insertBJTxn.Close()
+
+	// Nothing is kept until the journal's index has been written
+	bjt, err := db.ClaimNextBackupJournalTask()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CompleteBackupJournalTask(bjt, []byte("secret entry")))
+	bjt, err = db.ClaimNextBackupJournalTask()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CompleteBackupJournalTask(bjt, nil))
+	indexEntry, err := db.GetDirEntIndexEntry(1)
+	assert.NoError(t, err)
+	assert.Empty(t, indexEntry)
+
+	assert.NoError(t, db.KeepBackupJournalIndexEntries())
+	assert.NoError(t, db.WipeBackupJournal())
+	indexEntry, err = db.GetDirEntIndexEntry(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret entry"), indexEntry)
+	indexEntry, err = db.GetDirEntIndexEntry(2)
+	assert.NoError(t, err)
+	assert.Empty(t, indexEntry)
+
+	// and what is kept is encrypted
+	var cnt int
+	assert.NoError(t, db.dbConn.QueryRow("SELECT COUNT(*) FROM dirents WHERE index_entry = CAST('secret entry' AS BLOB)").Scan(&cnt))
+	assert.Equal(t, 0, cnt)
+}
@@ -23,6 +23,7 @@ const (
 	Updated   ChangeType = 1
 	Unchanged ChangeType = 2
 	Deleted   ChangeType = 3
+	Moved     ChangeType = 4 // unchanged, but found at a new path; reuses the old path's chunks
 )
 
 var (
@@ -36,11 +37,19 @@ var (
 
 type InsertBackupJournalStmt struct {
 	stmt          *sql.Stmt
+	movedStmt     *sql.Stmt // prepared on first use
 	tx            *sql.Tx
 	backupsInfoId int64
+	db            *DB
 }
 
 func (db *DB) NewInsertBackupJournalStmt(backupDirPath string) (*InsertBackupJournalStmt, error) {
+	sealedBackupDirPath, err := db.sealString(backupDirPath)
+	if err != nil {
+		log.Printf("error: NewInsertBackupJournalStmt: %v", err)
+		return nil, err
+	}
+
 	// First insert the backup_info row so we have its id
 	stmtInfoInsert, err := db.dbConn.Prepare("INSERT INTO backup_info (dirpath, snapshot_time) VALUES (?, strftime('%s','now'))")
 	if err != nil {
@@ -49,7 +58,7 @@ func (db *DB) NewInsertBackupJournalStmt(backupDirPath string) (*InsertBackupJou
 	}
 	defer stmtInfoInsert.Close()
 
-	result, err := stmtInfoInsert.Exec(backupDirPath)
+	result, err := stmtInfoInsert.Exec(sealedBackupDirPath)
 	if err != nil {
 		log.Printf("error: NewInsertBackupJournalStmt: %v", err)
 		return nil, err
@@ -74,13 +83,16 @@ func (db *DB) NewInsertBackupJournalStmt(backupDirPath string) (*InsertBackupJou
 		return nil, err
 	}
 
-	return &InsertBackupJournalStmt{stmt: stmt, tx: tx, backupsInfoId: backupsInfoId}, nil
+	return &InsertBackupJournalStmt{stmt: stmt, tx: tx, backupsInfoId: backupsInfoId, db: db}, nil
 }
 
 func (ibst *InsertBackupJournalStmt) Close() {
 	ibst.tx.Commit()
 
 	ibst.stmt.Close()
+	if ibst.movedStmt != nil {
+		ibst.movedStmt.Close()
+	}
 }
 
 // Inserts a single backup_journal row as part of larger transaction
@@ -94,15 +106,40 @@ func (ibst *InsertBackupJournalStmt) InsertBackupJournalRow(dirEntId int64, stat
 	return nil
 }
 
+// Inserts a single Moved backup_journal row, for an entry that was at movedFromRelPath before
+func (ibst *InsertBackupJournalStmt) InsertMovedBackupJournalRow(dirEntId int64, status JournalStatus, movedFromRelPath string) error {
+	sealedMovedFrom, err := ibst.db.sealString(movedFromRelPath)
+	if err != nil {
+		log.Printf("error: InsertMovedBackupJournalRow: %v", err)
+		return err
+	}
+
+	if ibst.movedStmt == nil {
+		ibst.movedStmt, err = ibst.tx.Prepare("INSERT INTO backup_journal (backup_info_id, dirent_id, status, change_type, moved_from) values (?, ?, ?, ?, ?)")
+		if err != nil {
+			log.Printf("error: InsertMovedBackupJournalRow: %v", err)
+			return err
+		}
+	}
+	_, err = ibst.movedStmt.Exec(ibst.backupsInfoId, dirEntId, status, Moved, sealedMovedFrom)
+	if err != nil {
+		log.Printf("error: InsertMovedBackupJournalRow: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 type BackupJournalTask struct {
 	id         int64
 	DirEntId   int64
 	ChangeType ChangeType
+	MovedFrom  string // old rel path, for Moved tasks only
 }
 
 func (db *DB) ClaimNextBackupJournalTask() (backupJournalTask *BackupJournalTask, err error) {
 	for {
-		id, dirEntId, changeType, err := db.selectNextBackupJournalCandidateTask()
+		id, dirEntId, changeType, movedFrom, err := db.selectNextBackupJournalCandidateTask()
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNoWork
 		} else if err != nil {
@@ -128,6 +165,7 @@ func (db *DB) ClaimNextBackupJournalTask() (backupJournalTask *BackupJournalTask
 					id:         id,
 					DirEntId:   dirEntId,
 					ChangeType: changeType,
+					MovedFrom:  movedFrom,
 				}, nil
 			} else {
 				continue
@@ -139,28 +177,45 @@ func (db *DB) ClaimNextBackupJournalTask() (backupJournalTask *BackupJournalTask
 	}
 }
 
-func (db *DB) selectNextBackupJournalCandidateTask() (id int64, dirEntId int64, changeType ChangeType, err error) {
+func (db *DB) selectNextBackupJournalCandidateTask() (id int64, dirEntId int64, changeType ChangeType, movedFrom string, err error) {
 	stmt, err := db.dbConn.Prepare("SELECT id, dirent_id, change_type FROM backup_journal WHERE status = ? LIMIT 1")
 	if err != nil {
 		log.Printf("error: selectNextBackupJournalCandidateTask: %v", err)
-		return 0, 0, 0, err
+		return 0, 0, 0, "", err
 	}
 	defer stmt.Close()
 
 	err = stmt.QueryRow(Unstarted).Scan(&id, &dirEntId, &changeType)
 	if errors.Is(err, sql.ErrNoRows) {
-		return 0, 0, 0, err
+		return 0, 0, 0, "", err
 	} else if err != nil {
 		log.Printf("error: selectNextBackupJournalCandidateTask: %v", err)
-		return 0, 0, 0, err
+		return 0, 0, 0, "", err
+	}
+	if changeType == Moved {
+		var sealedMovedFrom string
+		if err = db.dbConn.QueryRow("SELECT moved_from FROM backup_journal WHERE id = ?", id).Scan(&sealedMovedFrom); err != nil {
+			log.Printf("error: selectNextBackupJournalCandidateTask: %v", err)
+			return 0, 0, 0, "", err
+		}
+		if movedFrom, err = db.openString(sealedMovedFrom); err != nil {
+			log.Printf("error: selectNextBackupJournalCandidateTask: %v", err)
+			return 0, 0, 0, "", err
+		}
 	}
 
-	return id, dirEntId, changeType, nil
+	return id, dirEntId, changeType, movedFrom, nil
 }
 
 // Marks backupJournalTask as Finished.  If this was the last task that was not yet complete,
 // returns true for isJournalComplete.
 func (db *DB) CompleteBackupJournalTask(backupJournalTask *BackupJournalTask, indexEntry []byte) (err error) {
+	sealedIndexEntry, err := db.sealBlob(indexEntry)
+	if err != nil {
+		log.Printf("error: CompleteBackupJournalTask: %v", err)
+		return err
+	}
+
 	// Mark this task as done
 	stmt, err := db.dbConn.Prepare("UPDATE backup_journal SET status = ?, index_entry = ? WHERE id = ?")
 	if err != nil {
@@ -169,7 +224,7 @@ func (db *DB) CompleteBackupJournalTask(backupJournalTask *BackupJournalTask, in
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(Finished, indexEntry, backupJournalTask.id)
+	_, err = stmt.Exec(Finished, sealedIndexEntry, backupJournalTask.id)
 	if err != nil {
 		log.Printf("error: CompleteBackupJournalTask: %v", err)
 		return err
@@ -187,6 +242,19 @@ func (db *DB) WipeBackupJournal() error {
 	}
 }
 
+// Copies the index entry of every finished task to its dir entry, where GetDirEntIndexEntry finds
+// it. Call this once the journal's snapshot index has been written.
+func (db *DB) KeepBackupJournalIndexEntries() error {
+	_, err := db.dbConn.Exec(`UPDATE dirents SET index_entry = (
+		SELECT index_entry FROM backup_journal WHERE backup_journal.dirent_id = dirents.id
+	) WHERE id IN (SELECT dirent_id FROM backup_journal WHERE index_entry IS NOT NULL)`)
+	if err != nil {
+		log.Printf("error: KeepBackupJournalIndexEntries: %v", err)
+		return err
+	}
+	return nil
+}
+
 // Call this function when finishing an incomplete backup journal on startup. It rolls all the
 // InProgress tasks back to Unstarted.
 func (db *DB) ResetAllInProgressBackupJournalTasks() error {
@@ -328,7 +396,8 @@ func (db *DB) GetJournaledBackupInfo() (backupDirPath string, snapshotUnixTime i
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRow().Scan(&backupDirPath, &snapshotUnixTime)
+	var sealedBackupDirPath string
+	err = stmt.QueryRow().Scan(&sealedBackupDirPath, &snapshotUnixTime)
 	if errors.Is(err, sql.ErrNoRows) {
 		// Sometimes we get called when backup_journal is empty, so just suppress any error message
 		// and return the error for caller to handle
@@ -337,6 +406,10 @@ func (db *DB) GetJournaledBackupInfo() (backupDirPath string, snapshotUnixTime i
 		log.Printf("error: GetJournaledBackupInfo: %v", err)
 		return "", 0, err
 	}
+	if backupDirPath, err = db.openString(sealedBackupDirPath); err != nil {
+		log.Printf("error: GetJournaledBackupInfo: %v", err)
+		return "", 0, err
+	}
 
 	return backupDirPath, snapshotUnixTime, nil
 }
@@ -414,6 +487,10 @@ func (db *DB) GetAllBackupJournalRowIndexEntries() (indexEntries [][]byte, err e
 			log.Printf("error: GetAllBackupJournalRowIndexEntries: %v", err)
 			return nil, err
 		}
+		if indexEntry, err = db.openBlob(indexEntry); err != nil {
+			log.Printf("error: GetAllBackupJournalRowIndexEntries: %v", err)
+			return nil, err
+		}
 		indexEntries = append(indexEntries, indexEntry)
 	}
 
@@ -0,0 +1,371 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"log"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// The paths in dirents, backup_info and backup_journal and the index entries in backup_journal
+// would give away the whole file inventory to anyone who gets hold of the disk, so once
+// UnlockState has been called they are stored encrypted under a key derived from the bucket's HMAC
+// key. The paths in dirents are encrypted deterministically so that they can still be looked up by
+// equality. Content hashes in dirents are encrypted too, since they would confirm the presence of
+// known files, and so are the index entries kept there; the rest of a dirent's fingerprint (size,
+// inode, mode and times) is left as is.
+
+var (
+	// ErrStateLocked is returned when encrypted local state is accessed before UnlockState
+	ErrStateLocked = errors.New("local state is encrypted and has not been unlocked")
+
+	// ErrStateKeyMismatch is returned by UnlockState if the local state was encrypted with a
+	// different key, as happens when switching to another bucket
+	ErrStateKeyMismatch = errors.New("local state was encrypted with a different key")
+)
+
+// Unlocks the encrypted local state with the key derived from hmacKey. Local state that is still
+// plaintext (from before this version, or from a fresh database) is encrypted first. If the state
+// was encrypted with a different key, as happens when switching to another bucket, an error
+// wrapping ErrStateKeyMismatch is returned rather than throwing the state away: it is up to the
+// user to call ResetState if that is really what they want.
+func (db *DB) UnlockState(hmacKey []byte, vlog *util.VLog) error {
+	if err := db.unlockState(hmacKey); err != nil {
+		log.Printf("error: UnlockState: %v", err)
+		return err
+	}
+	vlog.Println("notice: UnlockState: local state unlocked")
+	return nil
+}
+
+func (db *DB) unlockState(hmacKey []byte) error {
+	key := cryptography.DeriveLocalStateKey(hmacKey)
+	checkValue := cryptography.ComputeChunkName(key, []byte("tless local state check"))
+
+	var storedCheckValue string
+	err := db.dbConn.QueryRow("SELECT check_value FROM local_state_key LIMIT 1").Scan(&storedCheckValue)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err = db.encryptPlaintextState(key, checkValue); err != nil {
+			return err
+		}
+		// Rebuild the file so that no plaintext is left behind in free pages
+		if _, err = db.dbConn.Exec("VACUUM"); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if storedCheckValue != checkValue {
+		return ErrStateKeyMismatch
+	}
+
+	db.stateKey = key
+	db.stateLocked = true
+	return nil
+}
+
+// Encrypts every sensitive column in place and records checkValue, all in one transaction
+func (db *DB) encryptPlaintextState(key []byte, checkValue string) error {
+	tx, err := db.dbConn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sealer := &DB{stateKey: key}
+
+	// dirents
+	type dirent struct {
+		id               int64
+		rootdir, relpath string
+	}
+	dirents := make([]dirent, 0)
+	rows, err := tx.Query("SELECT id, rootdir, relpath FROM dirents")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var d dirent
+		if err = rows.Scan(&d.id, &d.rootdir, &d.relpath); err != nil {
+			rows.Close()
+			return err
+		}
+		dirents = append(dirents, d)
+	}
+	rows.Close()
+	for _, d := range dirents {
+		rootdir, err := sealer.sealPath(d.rootdir)
+		if err != nil {
+			return err
+		}
+		relpath, err := sealer.sealPath(d.relpath)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE dirents SET rootdir = ?, relpath = ? WHERE id = ?", rootdir, relpath, d.id); err != nil {
+			return err
+		}
+	}
+
+	// dirents content hashes
+	contentHashes := make(map[int64][]byte)
+	rows, err = tx.Query("SELECT id, content_hash FROM dirents WHERE content_hash IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var contentHash []byte
+		if err = rows.Scan(&id, &contentHash); err != nil {
+			rows.Close()
+			return err
+		}
+		contentHashes[id] = contentHash
+	}
+	rows.Close()
+	for id, contentHash := range contentHashes {
+		sealed, err := sealer.sealBlob(contentHash)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE dirents SET content_hash = ? WHERE id = ?", sealed, id); err != nil {
+			return err
+		}
+	}
+
+	// dirents index entries
+	direntIndexEntries := make(map[int64][]byte)
+	rows, err = tx.Query("SELECT id, index_entry FROM dirents WHERE index_entry IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var indexEntry []byte
+		if err = rows.Scan(&id, &indexEntry); err != nil {
+			rows.Close()
+			return err
+		}
+		direntIndexEntries[id] = indexEntry
+	}
+	rows.Close()
+	for id, indexEntry := range direntIndexEntries {
+		sealed, err := sealer.sealBlob(indexEntry)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE dirents SET index_entry = ? WHERE id = ?", sealed, id); err != nil {
+			return err
+		}
+	}
+
+	// backup_info
+	dirpaths := make(map[int64]string)
+	rows, err = tx.Query("SELECT id, dirpath FROM backup_info WHERE dirpath IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var dirpath string
+		if err = rows.Scan(&id, &dirpath); err != nil {
+			rows.Close()
+			return err
+		}
+		dirpaths[id] = dirpath
+	}
+	rows.Close()
+	for id, dirpath := range dirpaths {
+		sealed, err := sealer.sealString(dirpath)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE backup_info SET dirpath = ? WHERE id = ?", sealed, id); err != nil {
+			return err
+		}
+	}
+
+	// backup_journal moved from paths
+	movedFroms := make(map[int64]string)
+	rows, err = tx.Query("SELECT id, moved_from FROM backup_journal WHERE moved_from IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var movedFrom string
+		if err = rows.Scan(&id, &movedFrom); err != nil {
+			rows.Close()
+			return err
+		}
+		movedFroms[id] = movedFrom
+	}
+	rows.Close()
+	for id, movedFrom := range movedFroms {
+		sealed, err := sealer.sealString(movedFrom)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE backup_journal SET moved_from = ? WHERE id = ?", sealed, id); err != nil {
+			return err
+		}
+	}
+
+	// backup_journal index entries
+	indexEntries := make(map[int64][]byte)
+	rows, err = tx.Query("SELECT id, index_entry FROM backup_journal WHERE index_entry IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var indexEntry []byte
+		if err = rows.Scan(&id, &indexEntry); err != nil {
+			rows.Close()
+			return err
+		}
+		indexEntries[id] = indexEntry
+	}
+	rows.Close()
+	for id, indexEntry := range indexEntries {
+		sealed, err := sealer.sealBlob(indexEntry)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE backup_journal SET index_entry = ? WHERE id = ?", sealed, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("INSERT INTO local_state_key (check_value) VALUES (?)", checkValue); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Deletes all encrypted local state: the record of what was backed up when, and any interrupted
+// backup journal. This is the way out when the key is no longer available (see
+// 'tless backup --reset-local-state'). Nothing in the cloud is affected, but the next backup has
+// to look at every file again.
+func (db *DB) ResetState() error {
+	sqlStmt := `
+	DELETE FROM backup_journal;
+	DELETE FROM backup_info;
+	DELETE FROM dirents;
+	DELETE FROM local_state_key;
+	`
+	if _, err := db.dbConn.Exec(sqlStmt); err != nil {
+		log.Printf("error: ResetState: %v", err)
+		return err
+	}
+	db.stateKey = nil
+	db.stateLocked = false
+	return nil
+}
+
+// Records whether the local state is encrypted, so that it isn't mistaken for plaintext before
+// UnlockState is called
+func (db *DB) loadStateLocked() error {
+	cnt, err := db.querySingleRowCount("SELECT COUNT(*) FROM local_state_key")
+	if err != nil {
+		return err
+	}
+	db.stateLocked = cnt > 0
+	return nil
+}
+
+// Encrypts a path deterministically (see EncryptDeterministic). Without a key, the path is
+// stored as plaintext unless the state is locked.
+func (db *DB) sealPath(path string) (string, error) {
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return "", ErrStateLocked
+		}
+		return path, nil
+	}
+	ciphertext, err := cryptography.EncryptDeterministic(db.stateKey, []byte(path))
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(ciphertext), nil
+}
+
+func (db *DB) openPath(sealed string) (string, error) {
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return "", ErrStateLocked
+		}
+		return sealed, nil
+	}
+	ciphertext, err := base64.URLEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := cryptography.DecryptDeterministic(db.stateKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypts a string that is never looked up by equality, with a random nonce
+func (db *DB) sealString(s string) (string, error) {
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return "", ErrStateLocked
+		}
+		return s, nil
+	}
+	ciphertext, err := cryptography.EncryptBuffer(db.stateKey, []byte(s))
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(ciphertext), nil
+}
+
+func (db *DB) openString(sealed string) (string, error) {
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return "", ErrStateLocked
+		}
+		return sealed, nil
+	}
+	ciphertext, err := base64.URLEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := cryptography.DecryptBuffer(db.stateKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypts a blob with a random nonce. Empty blobs are left as they are.
+func (db *DB) sealBlob(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return b, nil
+	}
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return nil, ErrStateLocked
+		}
+		return b, nil
+	}
+	return cryptography.EncryptBuffer(db.stateKey, b)
+}
+
+func (db *DB) openBlob(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return b, nil
+	}
+	if db.stateKey == nil {
+		if db.stateLocked {
+			return nil, ErrStateLocked
+		}
+		return b, nil
+	}
+	return cryptography.DecryptBuffer(db.stateKey, b)
+}
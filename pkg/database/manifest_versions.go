@@ -0,0 +1,43 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+)
+
+// Returns the highest snapshot manifest version seen in bucket, or 0 if none has been seen
+func (db *DB) GetLastSeenManifestVersion(bucket string) (uint64, error) {
+	stmt, err := db.dbConn.Prepare("SELECT version FROM manifest_versions WHERE bucket = ?")
+	if err != nil {
+		log.Printf("error: GetLastSeenManifestVersion: %v", err)
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var version int64
+	err = stmt.QueryRow(bucket).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		log.Printf("error: GetLastSeenManifestVersion: %v", err)
+		return 0, err
+	}
+	return uint64(version), nil
+}
+
+// Records version as the highest snapshot manifest version seen in bucket
+func (db *DB) SetLastSeenManifestVersion(bucket string, version uint64) error {
+	stmt, err := db.dbConn.Prepare("INSERT INTO manifest_versions (bucket, version) VALUES (?, ?) ON CONFLICT(bucket) DO UPDATE SET version = excluded.version")
+	if err != nil {
+		log.Printf("error: SetLastSeenManifestVersion: %v", err)
+		return err
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.Exec(bucket, int64(version)); err != nil {
+		log.Printf("error: SetLastSeenManifestVersion: %v", err)
+		return err
+	}
+	return nil
+}
@@ -34,6 +34,61 @@ var (
 		bandwidth_used INTEGER
 	);
 	`
+
+	createTableRotateKeyJournal = `
+	DROP TABLE IF EXISTS rotate_key_journal;
+	CREATE TABLE rotate_key_journal (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		key_generation INTEGER,
+		chunk_name TEXT,
+		status INTEGER
+	);
+	`
+
+	createTableLocalStateKey = `
+	DROP TABLE IF EXISTS local_state_key;
+	CREATE TABLE local_state_key (
+		check_value TEXT  /* proves which key the sensitive columns are encrypted with */
+	);
+	`
+
+	// NULLs mean the entry hasn't been fingerprinted yet
+	addDirEntFingerprintColumns = `
+	ALTER TABLE dirents ADD COLUMN size INTEGER;
+	ALTER TABLE dirents ADD COLUMN inode INTEGER;
+	ALTER TABLE dirents ADD COLUMN mtime_ns INTEGER;
+	ALTER TABLE dirents ADD COLUMN ctime_ns INTEGER;
+	ALTER TABLE dirents ADD COLUMN content_hash BLOB;  /* only recorded by --verify-content backups */
+	`
+
+	addMoveDetectionColumns = `
+	ALTER TABLE dirents ADD COLUMN mode INTEGER;
+	ALTER TABLE backup_journal ADD COLUMN moved_from TEXT;  /* old rel path of a Moved entry */
+	`
+
+	// Only filled in by write-only backups, which cannot read the previous snapshot back
+	addDirEntIndexEntryColumn = `
+	ALTER TABLE dirents ADD COLUMN index_entry BLOB;  /* entry in the last snapshot written */
+	`
+
+	// Padding is encrypted along with the data, so it can't be told from object sizes in the
+	// bucket and is recorded here as objects are uploaded
+	createTableObjectPadding = `
+	DROP TABLE IF EXISTS object_padding;
+	CREATE TABLE object_padding (
+		name TEXT PRIMARY KEY,  /* object name in the bucket */
+		padding INTEGER
+	);
+	ALTER TABLE space_usage_history ADD COLUMN padding_used INTEGER NOT NULL DEFAULT 0;
+	`
+
+	createTableManifestVersions = `
+	DROP TABLE IF EXISTS manifest_versions;
+	CREATE TABLE manifest_versions (
+		bucket TEXT PRIMARY KEY,
+		version INTEGER
+	);
+	`
 )
 
 func (db *DB) PerformDbMigrations(vlog *util.VLog) error {
@@ -50,6 +105,8 @@ func (db *DB) PerformDbMigrations(vlog *util.VLog) error {
 		db.DropAllTables()
 		db.CreateTablesIfNotExist()
 		// now we're at db version 0
+		fallthrough
+	case 0:
 		vlog.Println("notice: PerformDbMigrations: at ver 0 (migrating forward)")
 		err = db.migrateToVer1()
 		if err != nil {
@@ -57,17 +114,71 @@ func (db *DB) PerformDbMigrations(vlog *util.VLog) error {
 			return err
 		}
 		vlog.Println("notice: PerformDbMigrations: now at ver 1")
-	case 0:
-		vlog.Println("notice: PerformDbMigrations: at ver 0 (migrating forward)")
-		err = db.migrateToVer1()
+		fallthrough
+	case 1:
+		err = db.migrateToVer2()
 		if err != nil {
-			log.Println("error: PerformDbMigrations: failed to migrate to v1", err)
+			log.Println("error: PerformDbMigrations: failed to migrate to v2", err)
 			return err
 		}
-		vlog.Println("notice: PerformDbMigrations: now at ver 1")
-	case 1:
-		// No versions higher than 1 yet
-		vlog.Println("notice: PerformDbMigrations: at ver 1 (latest)")
+		vlog.Println("notice: PerformDbMigrations: now at ver 2")
+		fallthrough
+	case 2:
+		err = db.migrateToVer3()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v3", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 3")
+		fallthrough
+	case 3:
+		// Existing plaintext rows are encrypted by the first UnlockState, which has the key
+		err = db.migrateToVer4()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v4", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 4")
+		fallthrough
+	case 4:
+		err = db.migrateToVer5()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v5", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 5")
+		fallthrough
+	case 5:
+		err = db.migrateToVer6()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v6", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 6")
+		fallthrough
+	case 6:
+		err = db.migrateToVer7()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v7", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 7")
+		fallthrough
+	case 7:
+		err = db.migrateToVer8()
+		if err != nil {
+			log.Println("error: PerformDbMigrations: failed to migrate to v8", err)
+			return err
+		}
+		vlog.Println("notice: PerformDbMigrations: now at ver 8")
+	case 8:
+		// No versions higher than 8 yet
+		vlog.Println("notice: PerformDbMigrations: at ver 8 (latest)")
+	}
+
+	if err = db.loadStateLocked(); err != nil {
+		log.Println("error: PerformDbMigrations: could not tell whether local state is encrypted", err)
+		return err
 	}
 
 	return nil
@@ -139,3 +250,122 @@ func (db *DB) migrateToVer1() error {
 
 	return nil
 }
+
+func (db *DB) migrateToVer2() error {
+	// Create the journal used by key rotation
+	_, err := db.dbConn.Exec(createTableRotateKeyJournal)
+	if err != nil {
+		log.Printf("error: migrateToVer2: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 2")
+	if err != nil {
+		log.Printf("error: migrateToVer2: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer3() error {
+	// Create the table remembering the last snapshot manifest version seen in each bucket
+	_, err := db.dbConn.Exec(createTableManifestVersions)
+	if err != nil {
+		log.Printf("error: migrateToVer3: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 3")
+	if err != nil {
+		log.Printf("error: migrateToVer3: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer4() error {
+	// Create the table recording which key the local state is encrypted with
+	_, err := db.dbConn.Exec(createTableLocalStateKey)
+	if err != nil {
+		log.Printf("error: migrateToVer4: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 4")
+	if err != nil {
+		log.Printf("error: migrateToVer4: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer5() error {
+	// Add the columns that detect changes the mtime alone misses
+	_, err := db.dbConn.Exec(addDirEntFingerprintColumns)
+	if err != nil {
+		log.Printf("error: migrateToVer5: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 5")
+	if err != nil {
+		log.Printf("error: migrateToVer5: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer6() error {
+	// Add the columns that let moved entries reuse their old chunks
+	_, err := db.dbConn.Exec(addMoveDetectionColumns)
+	if err != nil {
+		log.Printf("error: migrateToVer6: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 6")
+	if err != nil {
+		log.Printf("error: migrateToVer6: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer7() error {
+	// Add the column that lets write-only backups reuse the extents of unchanged entries
+	_, err := db.dbConn.Exec(addDirEntIndexEntryColumn)
+	if err != nil {
+		log.Printf("error: migrateToVer7: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 7")
+	if err != nil {
+		log.Printf("error: migrateToVer7: %q\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DB) migrateToVer8() error {
+	// Add the table that lets space usage reports include padding
+	_, err := db.dbConn.Exec(createTableObjectPadding)
+	if err != nil {
+		log.Printf("error: migrateToVer8: %q\n", err)
+		return err
+	}
+
+	_, err = db.dbConn.Exec("UPDATE version SET version = 8")
+	if err != nil {
+		log.Printf("error: migrateToVer8: %q\n", err)
+		return err
+	}
+
+	return nil
+}
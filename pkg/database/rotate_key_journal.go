@@ -0,0 +1,171 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+)
+
+type RotateKeyJournalTask struct {
+	id        int64
+	ChunkName string
+}
+
+// Replaces whatever is in the rotate_key_journal with one Unstarted row per chunk for key generation
+// keyGeneration.
+func (db *DB) InsertRotateKeyJournalTasks(keyGeneration int, chunkNames []string) error {
+	if err := db.WipeRotateKeyJournal(); err != nil {
+		return err
+	}
+
+	tx, err := db.dbConn.Begin()
+	if err != nil {
+		log.Printf("error: InsertRotateKeyJournalTasks: %v", err)
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO rotate_key_journal (key_generation, chunk_name, status) VALUES (?, ?, ?)")
+	if err != nil {
+		log.Printf("error: InsertRotateKeyJournalTasks: %v", err)
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, chunkName := range chunkNames {
+		if _, err = stmt.Exec(keyGeneration, chunkName, Unstarted); err != nil {
+			log.Printf("error: InsertRotateKeyJournalTasks: %v", err)
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("error: InsertRotateKeyJournalTasks: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Returns true if the journal holds rows for key generation keyGeneration, i.e., a rotation to that
+// generation was started on this machine and can be resumed.
+func (db *DB) HasRotateKeyJournal(keyGeneration int) (bool, error) {
+	stmt, err := db.dbConn.Prepare("SELECT COUNT(*) FROM rotate_key_journal WHERE key_generation = ?")
+	if err != nil {
+		log.Printf("error: HasRotateKeyJournal: %v", err)
+		return false, err
+	}
+	defer stmt.Close()
+
+	var count int64
+	if err = stmt.QueryRow(keyGeneration).Scan(&count); err != nil {
+		log.Printf("error: HasRotateKeyJournal: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (db *DB) ClaimNextRotateKeyJournalTask() (*RotateKeyJournalTask, error) {
+	for {
+		var task RotateKeyJournalTask
+		err := db.selectNextRotateKeyJournalCandidateTask(&task)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoWork
+		} else if err != nil {
+			log.Printf("error: ClaimNextRotateKeyJournalTask: %v", err)
+			return nil, err
+		}
+
+		stmt, err := db.dbConn.Prepare("UPDATE rotate_key_journal SET status = ? WHERE id = ? AND status = ?")
+		if err != nil {
+			log.Printf("error: ClaimNextRotateKeyJournalTask: %v", err)
+			return nil, err
+		}
+		result, err := stmt.Exec(InProgress, task.id, Unstarted)
+		stmt.Close()
+		if err != nil {
+			log.Printf("error: ClaimNextRotateKeyJournalTask: %v", err)
+			return nil, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			log.Printf("error: ClaimNextRotateKeyJournalTask: %v", err)
+			return nil, err
+		}
+		if rowsAffected == 1 {
+			return &task, nil
+		}
+	}
+}
+
+func (db *DB) selectNextRotateKeyJournalCandidateTask(task *RotateKeyJournalTask) error {
+	stmt, err := db.dbConn.Prepare("SELECT id, chunk_name FROM rotate_key_journal WHERE status = ? LIMIT 1")
+	if err != nil {
+		log.Printf("error: selectNextRotateKeyJournalCandidateTask: %v", err)
+		return err
+	}
+	defer stmt.Close()
+
+	return stmt.QueryRow(Unstarted).Scan(&task.id, &task.ChunkName)
+}
+
+func (db *DB) CompleteRotateKeyJournalTask(task *RotateKeyJournalTask) error {
+	stmt, err := db.dbConn.Prepare("UPDATE rotate_key_journal SET status = ? WHERE id = ?")
+	if err != nil {
+		log.Printf("error: CompleteRotateKeyJournalTask: %v", err)
+		return err
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.Exec(Finished, task.id); err != nil {
+		log.Printf("error: CompleteRotateKeyJournalTask: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Rolls InProgress tasks left over from an interrupted rotation back to Unstarted
+func (db *DB) ResetAllInProgressRotateKeyJournalTasks() error {
+	stmt, err := db.dbConn.Prepare("UPDATE rotate_key_journal SET status = ? WHERE status = ?")
+	if err != nil {
+		log.Printf("error: ResetAllInProgressRotateKeyJournalTasks: %v", err)
+		return err
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.Exec(Unstarted, InProgress); err != nil {
+		log.Printf("error: ResetAllInProgressRotateKeyJournalTasks: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetRotateKeyJournalCounts() (finishedCount int64, totalCount int64, err error) {
+	stmt, err := db.dbConn.Prepare("SELECT COUNT(*), COALESCE(SUM(status = ?), 0) FROM rotate_key_journal")
+	if err != nil {
+		log.Printf("error: GetRotateKeyJournalCounts: %v", err)
+		return 0, 0, err
+	}
+	defer stmt.Close()
+
+	if err = stmt.QueryRow(Finished).Scan(&totalCount, &finishedCount); err != nil {
+		log.Printf("error: GetRotateKeyJournalCounts: %v", err)
+		return 0, 0, err
+	}
+	return finishedCount, totalCount, nil
+}
+
+// Deletes all journal rows
+func (db *DB) WipeRotateKeyJournal() error {
+	stmt, err := db.dbConn.Prepare("DELETE FROM rotate_key_journal")
+	if err != nil {
+		log.Printf("error: WipeRotateKeyJournal: %v", err)
+		return err
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.Exec(); err != nil {
+		log.Printf("error: WipeRotateKeyJournal: %v", err)
+		return err
+	}
+	return nil
+}
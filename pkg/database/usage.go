@@ -5,15 +5,16 @@ import (
 	"log"
 )
 
-func (db *DB) AddSpaceUsageReport(unixtime int64, bcount int64) error {
+// Records that the bucket used bcount bytes at unixtime, paddingBcount of them padding
+func (db *DB) AddSpaceUsageReport(unixtime int64, bcount int64, paddingBcount int64) error {
 	// Insert the new entry
-	stmtIns, err := db.dbConn.Prepare("INSERT INTO space_usage_history (date, space_used) VALUES (?, ?)")
+	stmtIns, err := db.dbConn.Prepare("INSERT INTO space_usage_history (date, space_used, padding_used) VALUES (?, ?, ?)")
 	if err != nil {
 		log.Println("error: AddSpaceUsageReport: ", err)
 		return err
 	}
 	defer stmtIns.Close()
-	_, err = stmtIns.Exec(unixtime, bcount)
+	_, err = stmtIns.Exec(unixtime, bcount, paddingBcount)
 	if err != nil {
 		log.Println("error: AddSpaceUsageReport: ", err)
 		return err
@@ -66,12 +67,14 @@ func (db *DB) AddBandwidthUsageReport(unixtime int64, bcount int64) error {
 }
 
 type PeakDailySpaceUsage struct {
-	DateYMD string
-	ByteCnt int64
+	DateYMD        string
+	ByteCnt        int64
+	PaddingByteCnt int64 // of ByteCnt
 }
 
 func (db *DB) GetPeakDailySpaceUsage(prevNMonths int) ([]PeakDailySpaceUsage, error) {
-	query := fmt.Sprintf("SELECT DATE(date,'unixepoch', 'localtime'), MAX(space_used) FROM space_usage_history WHERE DATE(date,'unixepoch', 'localtime') > DATE('now','localtime','-%d month','start of month') GROUP BY DATE(date,'unixepoch', 'localtime') ORDER BY date ASC;", prevNMonths)
+	// padding_used comes from the same row as MAX(space_used)
+	query := fmt.Sprintf("SELECT DATE(date,'unixepoch', 'localtime'), MAX(space_used), padding_used FROM space_usage_history WHERE DATE(date,'unixepoch', 'localtime') > DATE('now','localtime','-%d month','start of month') GROUP BY DATE(date,'unixepoch', 'localtime') ORDER BY date ASC;", prevNMonths)
 	rows, err := db.dbConn.Query(query)
 	if err != nil {
 		log.Println("error: GetPeakDailySpaceUsage: ", err)
@@ -82,14 +85,16 @@ func (db *DB) GetPeakDailySpaceUsage(prevNMonths int) ([]PeakDailySpaceUsage, er
 	for rows.Next() {
 		var ymd string
 		var peakSpaceUsesd int64
-		err = rows.Scan(&ymd, &peakSpaceUsesd)
+		var paddingUsed int64
+		err = rows.Scan(&ymd, &peakSpaceUsesd, &paddingUsed)
 		if err != nil {
 			log.Println("error: GetPeakDailySpaceUsage: ", err)
 			return nil, err
 		}
 		ret = append(ret, PeakDailySpaceUsage{
-			DateYMD: ymd,
-			ByteCnt: peakSpaceUsesd,
+			DateYMD:        ymd,
+			ByteCnt:        peakSpaceUsesd,
+			PaddingByteCnt: paddingUsed,
 		})
 	}
 	err = rows.Err()
@@ -136,3 +141,55 @@ func (db *DB) GetTotalDailyBandwidthUsage(prevNMonths int) ([]TotalDailyBandwidt
 
 	return ret, nil
 }
+
+// Records that the object objName was uploaded with paddingBcount bytes of padding
+func (db *DB) AddObjectPadding(objName string, paddingBcount int64) error {
+	_, err := db.dbConn.Exec("INSERT INTO object_padding (name, padding) VALUES (?, ?) ON CONFLICT(name) DO UPDATE SET padding = excluded.padding", objName, paddingBcount)
+	if err != nil {
+		log.Println("error: AddObjectPadding: ", err)
+		return err
+	}
+	return nil
+}
+
+// Returns the padding recorded for each object uploaded from here, keyed by object name
+func (db *DB) GetObjectPadding() (map[string]int64, error) {
+	rows, err := db.dbConn.Query("SELECT name, padding FROM object_padding")
+	if err != nil {
+		log.Println("error: GetObjectPadding: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]int64)
+	for rows.Next() {
+		var objName string
+		var paddingBcount int64
+		if err = rows.Scan(&objName, &paddingBcount); err != nil {
+			log.Println("error: GetObjectPadding: ", err)
+			return nil, err
+		}
+		ret[objName] = paddingBcount
+	}
+	if err = rows.Err(); err != nil {
+		log.Println("error: GetObjectPadding: ", err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Forgets the padding of objects that are no longer in the bucket
+func (db *DB) DeleteObjectPadding(objNames []string) error {
+	tx, err := db.dbConn.Begin()
+	if err != nil {
+		log.Println("error: DeleteObjectPadding: ", err)
+		return err
+	}
+	defer tx.Rollback()
+	for _, objName := range objNames {
+		if _, err = tx.Exec("DELETE FROM object_padding WHERE name = ?", objName); err != nil {
+			log.Println("error: DeleteObjectPadding: ", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -3,13 +3,18 @@
 package fstraverse
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/aybabtme/uniplot/histogram"
@@ -29,6 +34,7 @@ var (
 type BackupIdsQueueItem struct {
 	Id         int
 	ChangeType database.ChangeType
+	MovedFrom  string // old rel path, for Moved items only
 }
 
 type BackupIdsQueue struct {
@@ -49,13 +55,100 @@ func getMTimeUnix(dirent fs.DirEntry) (int64, error) {
 	return info.ModTime().Unix(), nil
 }
 
+// Returns what identifies this version of a dir entry, short of reading its contents
+func fingerprintOf(info fs.FileInfo) database.DirEntFingerprint {
+	fp := database.DirEntFingerprint{
+		Size:    info.Size(),
+		MTimeNs: info.ModTime().UnixNano(),
+		Mode:    uint32(info.Mode()),
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		fp.Inode = uint64(st.Ino)
+		_, fp.CTimeNs = util.StatATimeCTimeNs(st)
+	}
+	return fp
+}
+
+func hashFileContents(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
 type dirEntryInsert struct {
 	rootPath           string
 	relPath            string
 	lastBackupUnixtime int64
+	fingerprint        database.DirEntFingerprint
+	contentHash        []byte
+	isRegular          bool
+	movedFrom          string
+}
+
+// Identifies a file that was moved, either by its inode or by its contents. Its size, mtime and mode
+// have to match as well. The moved entry only reuses the old entry's contents; its metadata is
+// backed up anew, since its owner, xattrs and ctime need not match.
+type movedFileKey struct {
+	inode       uint64
+	contentHash string
+	size        int64
+	mTimeNs     int64
+	mode        uint32
 }
 
-func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLock *sync.Mutex, backupIdsQueue *BackupIdsQueue, excludes []string, checkAndHandleTraversalCancelation CheckAndHandleTraversalCancelationFuncType, vlog *util.VLog) ([]util.ReportedEvent, error) {
+// Indexes the regular files among the deleted paths in knownPaths by inode and by content hash, so
+// that new paths can be matched against them. Only files whose last backup succeeded qualify.
+func indexDeletedFiles(db *database.DB, dbLock *sync.Mutex, rootDirName string, knownPaths map[string]int) map[movedFileKey]string {
+	deletedFiles := make(map[movedFileKey]string)
+	for knownPath := range knownPaths {
+		relPath := strings.TrimPrefix(knownPath, rootDirName+"/")
+		util.LockIf(dbLock)
+		state, err := db.GetDirEntState(rootDirName, relPath)
+		util.UnlockIf(dbLock)
+		if err != nil || state == nil || state.Fingerprint == nil || state.LastBackupUnix == 0 || !fs.FileMode(state.Fingerprint.Mode).IsRegular() {
+			continue
+		}
+		fp := state.Fingerprint
+		deletedFiles[movedFileKey{inode: fp.Inode, size: fp.Size, mTimeNs: fp.MTimeNs, mode: fp.Mode}] = relPath
+		if state.ContentHash != nil {
+			deletedFiles[movedFileKey{contentHash: string(state.ContentHash), size: fp.Size, mTimeNs: fp.MTimeNs, mode: fp.Mode}] = relPath
+		}
+	}
+	return deletedFiles
+}
+
+// Returns the deleted path that ins was moved from, or "" if it is a new file
+func findMovedFrom(deletedFiles map[movedFileKey]string, ins dirEntryInsert) string {
+	if !ins.isRegular {
+		return ""
+	}
+	fp := ins.fingerprint
+	if relPath, ok := deletedFiles[movedFileKey{inode: fp.Inode, size: fp.Size, mTimeNs: fp.MTimeNs, mode: fp.Mode}]; ok {
+		return relPath
+	}
+	if ins.contentHash != nil {
+		if relPath, ok := deletedFiles[movedFileKey{contentHash: string(ins.contentHash), size: fp.Size, mTimeNs: fp.MTimeNs, mode: fp.Mode}]; ok {
+			return relPath
+		}
+	}
+	return ""
+}
+
+// Walks rootPath queueing every dir entry for backup as Updated or Unchanged, and removes the
+// ones it finds from knownPaths, leaving the deleted ones. An entry is considered changed if its
+// mtime is newer than its last backup or if its size, inode, ctime, mode or nanosecond mtime
+// differ from when it was last queued. If verifyContent is true, the contents of files that look
+// unchanged are hashed as well, to catch changes that leave all of those alone. New files that
+// have the inode (or content hash), size, mtime and mode of a deleted file are queued as Moved.
+func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLock *sync.Mutex, backupIdsQueue *BackupIdsQueue, excludes []string, verifyContent bool, checkAndHandleTraversalCancelation CheckAndHandleTraversalCancelationFuncType, vlog *util.VLog) ([]util.ReportedEvent, error) {
 	rootPath = util.StripTrailingSlashes(rootPath)
 	rootDirName := filepath.Base(rootPath)
 
@@ -114,8 +207,8 @@ func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLoc
 			return nil
 		}
 
-		// filter out socket, FIFO and device files
-		if finfo.Mode()&fs.ModeDevice != 0 || finfo.Mode()&fs.ModeSocket != 0 || finfo.Mode()&fs.ModeIrregular != 0 || finfo.Mode()&fs.ModeNamedPipe != 0 {
+		// filter out sockets and irregular files
+		if isSpecialFile(finfo.Mode()) {
 			return nil
 		}
 
@@ -138,39 +231,72 @@ func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLoc
 
 		// Is dirent already in our list of previously seen dirents?
 		// If so:
-		//	 - Check whether mtime is newer than last_backup?
+		//	 - Check whether it was never backed up, its mtime is newer than last_backup or its
+		//     fingerprint has changed
 		//   - If yes, enqueue it for backup.
 		//   - If no, do nothing with this dirent.
+		//   - Either way, record its fingerprint for next time. A changed entry's last_backup is
+		//     reset to 0 along with it, so that it still counts as changed until its backup
+		//     finishes, even if this backup is canceled or crashes first.
 		// If not:
 		//   - Insert it into dirents with last_backup set to 0
 		//   - Enqueue it for backup.
 		util.LockIf(dbLock)
-		hasDirEnt, lastBackupUnix, id, err := db.HasDirEnt(rootDirName, relPath)
+		state, err := db.GetDirEntState(rootDirName, relPath)
 		util.UnlockIf(dbLock)
 		if err != nil {
 			log.Printf("Error while searching for %s/%s, skipping this dirent", rootDirName, relPath)
 			return nil
 		}
 
-		// // Debugging
-		// if mtimeUnix > lastBackupUnix {
-		// 	log.Printf("CHANGED> %s\n", relPath)
-		// }
+		fp := fingerprintOf(finfo)
+		var contentHash []byte = nil
+		if verifyContent && finfo.Mode().IsRegular() {
+			if contentHash, err = hashFileContents(path); err != nil {
+				log.Printf("error: Traverse: could not hash contents of '%s': %v", path, err)
+			}
+		}
 
-		if hasDirEnt {
-			if mtimeUnix > lastBackupUnix {
-				backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
-					Id:         id,
-					ChangeType: database.Updated,
-				})
-			} else {
-				backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
-					Id:         id,
-					ChangeType: database.Unchanged,
-				})
+		if state != nil {
+			isChanged := state.LastBackupUnix == 0 || mtimeUnix > state.LastBackupUnix ||
+				(state.Fingerprint != nil && *state.Fingerprint != fp)
+			if !isChanged && verifyContent && finfo.Mode().IsRegular() && (contentHash == nil ||
+				(state.ContentHash != nil && !bytes.Equal(contentHash, state.ContentHash))) {
+				vlog.Printf("Contents of '%s' changed without its size, inode or times changing", path)
+				isChanged = true
+			}
+
+			// Without verifyContent, a recorded content hash is only kept while the entry is unchanged
+			if !verifyContent && !isChanged {
+				contentHash = state.ContentHash
+			}
+			if isChanged && state.LastBackupUnix != 0 {
+				util.LockIf(dbLock)
+				err = db.ResetLastBackedUpTime(state.Id)
+				util.UnlockIf(dbLock)
+				if err != nil {
+					log.Printf("error: Traverse: could not reset last backup time of '%s': %v", path, err)
+				}
+			}
+			if state.Fingerprint == nil || *state.Fingerprint != fp || !bytes.Equal(contentHash, state.ContentHash) {
+				util.LockIf(dbLock)
+				err = db.SetDirEntFingerprint(state.Id, fp, contentHash)
+				util.UnlockIf(dbLock)
+				if err != nil {
+					log.Printf("error: Traverse: could not record fingerprint of '%s': %v", path, err)
+				}
 			}
+
+			changeType := database.Unchanged
+			if isChanged {
+				changeType = database.Updated
+			}
+			backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
+				Id:         state.Id,
+				ChangeType: changeType,
+			})
 		} else {
-			pendingDirEntryInserts = append(pendingDirEntryInserts, dirEntryInsert{rootPath: rootDirName, relPath: relPath, lastBackupUnixtime: 0})
+			pendingDirEntryInserts = append(pendingDirEntryInserts, dirEntryInsert{rootPath: rootDirName, relPath: relPath, lastBackupUnixtime: 0, fingerprint: fp, contentHash: contentHash, isRegular: finfo.Mode().IsRegular()})
 		}
 
 		// Check for cancelation and return ErrTraversalCanceled if it occurs
@@ -191,6 +317,14 @@ func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLoc
 		}
 	}
 
+	// Whatever is left in knownPaths was deleted, unless it turns up again at one of the new paths
+	if len(knownPaths) > 0 {
+		deletedFiles := indexDeletedFiles(db, dbLock, rootDirName, knownPaths)
+		for i := range pendingDirEntryInserts {
+			pendingDirEntryInserts[i].movedFrom = findMovedFrom(deletedFiles, pendingDirEntryInserts[i])
+		}
+	}
+
 	// Do all the dir entry inserts, get the ids and enqueue them for backup
 	err = doPendingDirEntryInserts(db, dbLock, pendingDirEntryInserts)
 	if err != nil {
@@ -199,14 +333,25 @@ func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLoc
 	for _, ins := range pendingDirEntryInserts {
 		util.LockIf(dbLock)
 		_, _, id, err := db.HasDirEnt(ins.rootPath, ins.relPath)
-		util.UnlockIf(dbLock)
 		if err != nil {
 			log.Printf("error: Traverse: db.HasDirEnt: %v\n", err)
+		} else if err = db.SetDirEntFingerprint(id, ins.fingerprint, ins.contentHash); err != nil {
+			log.Printf("error: Traverse: db.SetDirEntFingerprint: %v\n", err)
+		}
+		util.UnlockIf(dbLock)
+		if ins.movedFrom != "" {
+			vlog.Printf("Found '%s/%s' moved from '%s/%s'", ins.rootPath, ins.relPath, ins.rootPath, ins.movedFrom)
+			backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
+				Id:         id,
+				ChangeType: database.Moved,
+				MovedFrom:  ins.movedFrom,
+			})
+		} else {
+			backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
+				Id:         id,
+				ChangeType: database.Updated,
+			})
 		}
-		backupIdsQueue.Items = append(backupIdsQueue.Items, BackupIdsQueueItem{
-			Id:         id,
-			ChangeType: database.Updated,
-		})
 	}
 
 	// print summary statistics
@@ -229,6 +374,67 @@ func Traverse(rootPath string, knownPaths map[string]int, db *database.DB, dbLoc
 	return reportedEvents, nil
 }
 
+// Walks rootPath like Traverse and returns the info of every dir entry that a backup would
+// consider, keyed by relPath, without touching the database or enqueueing anything.
+func TraverseDryRun(rootPath string, excludes []string, vlog *util.VLog) (map[string]fs.FileInfo, []util.ReportedEvent, error) {
+	rootPath = util.StripTrailingSlashes(rootPath)
+
+	dirEntInfos := make(map[string]fs.FileInfo)
+	reportedEvents := make([]util.ReportedEvent, 0)
+
+	err := filepath.WalkDir(rootPath, func(path string, dirent fs.DirEntry, err error) error {
+		if isExcluded(path, excludes) {
+			return nil
+		}
+		if err != nil {
+			log.Println("error: TraverseDryRun: WalkDirFunc: ", err)
+			if dirent != nil && dirent.IsDir() && strings.Contains(err.Error(), "operation not permitted") {
+				reportedEvents = append(reportedEvents, util.ReportedEvent{
+					Kind:     util.ERR_OP_NOT_PERMITTED,
+					Path:     path,
+					IsDir:    true,
+					Datetime: time.Now().Unix(),
+					Msg:      "",
+				})
+			}
+			return fs.SkipDir
+		}
+
+		if path == rootPath {
+			return nil
+		}
+		relPath := relativizePath(path, rootPath)
+		if relPath == rootPath || relPath == "" {
+			return nil
+		}
+
+		finfo, err := dirent.Info()
+		if err != nil {
+			log.Printf("error: TraverseDryRun: could not get file info on '%s'", path)
+			return nil
+		}
+		if isSpecialFile(finfo.Mode()) {
+			return nil
+		}
+
+		dirEntInfos[relPath] = finfo
+		return nil
+	})
+	if err != nil {
+		log.Printf("error: TraverseDryRun: %v\n", err)
+		return nil, nil, err
+	}
+	vlog.Printf("TraverseDryRun: found %d dir entries under '%s'", len(dirEntInfos), rootPath)
+
+	return dirEntInfos, reportedEvents, nil
+}
+
+// Returns true for sockets and other irregular files, which are never backed up. FIFOs and devices
+// are backed up as metadata only.
+func isSpecialFile(mode fs.FileMode) bool {
+	return mode&fs.ModeSocket != 0 || mode&fs.ModeIrregular != 0
+}
+
 // Returns true if path is excluded from backup by one of the elements in excludes.
 // There are two types of excludes:  (1) path prefixes and (2) shell globs. A path prefix like
 // "/usr" excludes every path beginning with "/usr".  A shell glob, which is identified by
@@ -1,8 +1,14 @@
 package fstraverse
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +43,138 @@ func TestIsExcluded(t *testing.T) {
 	path = "/Users/minterwute/anyfile"
 	assert.True(t, isExcluded(path, excludes))
 }
+
+func TestTraverseChangeDetection(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "state.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	rootPath := filepath.Join(t.TempDir(), "root")
+	assert.NoError(t, os.Mkdir(rootPath, 0755))
+	oldMTime := time.Unix(1500000000, 0)
+	for _, name := range []string{"a", "b"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(rootPath, name), []byte("contents"), 0644))
+		assert.NoError(t, os.Chtimes(filepath.Join(rootPath, name), oldMTime, oldMTime))
+	}
+
+	// Traverses and returns the change types by rel path, marking everything backed up
+	traverse := func(verifyContent bool) map[string]database.ChangeType {
+		knownPaths, err := db.GetAllKnownPaths("root")
+		assert.NoError(t, err)
+		var queue BackupIdsQueue
+		_, err = Traverse(rootPath, knownPaths, db, nil, &queue, nil, verifyContent, nil, vlog)
+		assert.NoError(t, err)
+		changes := make(map[string]database.ChangeType)
+		for _, item := range queue.Items {
+			_, relPath, err := db.GetDirEntPaths(item.Id)
+			assert.NoError(t, err)
+			changes[relPath] = item.ChangeType
+			assert.NoError(t, db.UpdateLastBackupTime(item.Id))
+		}
+		return changes
+	}
+
+	assert.Equal(t, map[string]database.ChangeType{"a": database.Updated, "b": database.Updated}, traverse(false))
+	assert.Equal(t, map[string]database.ChangeType{"a": database.Unchanged, "b": database.Unchanged}, traverse(false))
+
+	// Replaced with the same size and old mtime, like rsync -t would
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "a"), []byte("CONTENTS"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(rootPath, "a"), oldMTime, oldMTime))
+	assert.Equal(t, map[string]database.ChangeType{"a": database.Updated, "b": database.Unchanged}, traverse(false))
+
+	// A failed backup is retried even though the entry's fingerprint is recorded
+	state, err := db.GetDirEntState("root", "a")
+	assert.NoError(t, err)
+	assert.NoError(t, db.ResetLastBackedUpTime(state.Id))
+	assert.Equal(t, database.Updated, traverse(false)["a"])
+
+	// So is a change whose backup never got to run, as when the backup is canceled
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "a"), []byte("Contents"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(rootPath, "a"), oldMTime, oldMTime))
+	knownPaths, err := db.GetAllKnownPaths("root")
+	assert.NoError(t, err)
+	_, err = Traverse(rootPath, knownPaths, db, nil, &BackupIdsQueue{}, nil, false, nil, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]database.ChangeType{"a": database.Updated, "b": database.Unchanged}, traverse(false))
+
+	// A change invisible to the fingerprint is only caught by hashing the contents, once a
+	// hash has been recorded
+	assert.Equal(t, map[string]database.ChangeType{"a": database.Unchanged, "b": database.Unchanged}, traverse(true))
+	state, err = db.GetDirEntState("root", "b")
+	assert.NoError(t, err)
+	assert.NotNil(t, state.ContentHash)
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "b"), []byte("CONTENTS"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(rootPath, "b"), oldMTime, oldMTime))
+	info, err := os.Lstat(filepath.Join(rootPath, "b"))
+	assert.NoError(t, err)
+	assert.NoError(t, db.SetDirEntFingerprint(state.Id, fingerprintOf(info), state.ContentHash))
+	assert.Equal(t, database.Unchanged, traverse(false)["b"])
+	assert.Equal(t, database.Updated, traverse(true)["b"])
+	assert.Equal(t, database.Unchanged, traverse(true)["b"])
+}
+
+func TestTraverseMoveDetection(t *testing.T) {
+	vlog := util.NewVLog(nil, func() bool { return false })
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "state.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	rootPath := filepath.Join(t.TempDir(), "root")
+	assert.NoError(t, os.Mkdir(rootPath, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "a"), []byte("contents of a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "b"), []byte("contents of b"), 0644))
+
+	// Traverses and returns the queue items by rel path and the deleted rel paths, marking
+	// everything backed up and purging the deleted paths like a backup would
+	traverse := func(verifyContent bool) (map[string]BackupIdsQueueItem, map[string]int) {
+		knownPaths, err := db.GetAllKnownPaths("root")
+		assert.NoError(t, err)
+		var queue BackupIdsQueue
+		_, err = Traverse(rootPath, knownPaths, db, nil, &queue, nil, verifyContent, nil, vlog)
+		assert.NoError(t, err)
+		items := make(map[string]BackupIdsQueueItem)
+		for _, item := range queue.Items {
+			_, relPath, err := db.GetDirEntPaths(item.Id)
+			assert.NoError(t, err)
+			items[relPath] = item
+			assert.NoError(t, db.UpdateLastBackupTime(item.Id))
+		}
+		for knownPath := range knownPaths {
+			assert.NoError(t, db.DeleteDirEntByPath("root", strings.TrimPrefix(knownPath, "root/")))
+		}
+		return items, knownPaths
+	}
+	traverse(true)
+
+	// A file renamed into a new directory keeps its inode
+	assert.NoError(t, os.Mkdir(filepath.Join(rootPath, "dir"), 0755))
+	assert.NoError(t, os.Rename(filepath.Join(rootPath, "a"), filepath.Join(rootPath, "dir", "a")))
+	items, deleted := traverse(false)
+	assert.Equal(t, database.Moved, items["dir/a"].ChangeType)
+	assert.Equal(t, "a", items["dir/a"].MovedFrom)
+	assert.Equal(t, database.Updated, items["dir"].ChangeType)
+	assert.Equal(t, database.Unchanged, items["b"].ChangeType)
+	assert.Contains(t, deleted, "root/a")
+
+	// A file copied elsewhere has a new inode, so it is only recognized by its content hash
+	info, err := os.Stat(filepath.Join(rootPath, "b"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "dir", "b"), []byte("contents of b"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(rootPath, "dir", "b"), info.ModTime(), info.ModTime()))
+	assert.NoError(t, os.Remove(filepath.Join(rootPath, "b")))
+	items, _ = traverse(true)
+	assert.Equal(t, database.Moved, items["dir/b"].ChangeType)
+	assert.Equal(t, "b", items["dir/b"].MovedFrom)
+
+	// A file with different contents is new even if everything else matches
+	info, err = os.Stat(filepath.Join(rootPath, "dir", "b"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(rootPath, "c"), []byte("contents of c"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(rootPath, "c"), info.ModTime(), info.ModTime()))
+	assert.NoError(t, os.Remove(filepath.Join(rootPath, "dir", "b")))
+	items, _ = traverse(true)
+	assert.Equal(t, database.Updated, items["c"].ChangeType)
+}
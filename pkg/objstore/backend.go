@@ -0,0 +1,69 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Backend is the storage that an ObjStore reads and writes objects in. Object names use "/" as
+// a separator, and a non-recursive listing returns each "subdirectory" under prefix as a single
+// entry ending in "/" (with size 0), as S3 does.
+type Backend interface {
+	PutObject(ctx context.Context, bucket string, objectName string, buf []byte) (eTag string, err error)
+	GetObject(ctx context.Context, bucket string, objectName string) ([]byte, error)
+	GetObjectRange(ctx context.Context, bucket string, objectName string, offset int64, length int64) ([]byte, error)
+	ListObjects(ctx context.Context, bucket string, prefix string, recursive bool) (map[string]int64, error)
+	DeleteObject(ctx context.Context, bucket string, objectName string) error
+	RenameObject(ctx context.Context, bucket string, objectNameSrc string, objectNameDst string) error
+	ListBuckets(ctx context.Context) ([]string, error)
+	MakeBucket(ctx context.Context, bucket string, region string) error
+}
+
+var (
+	ErrNoSuchBucket      = errors.New("the bucket does not exist")
+	ErrNoSuchObject      = errors.New("the object does not exist")
+	ErrUnsupportedScheme = errors.New("unsupported endpoint scheme (use s3:// or file://)")
+)
+
+// Creates the backend named by endpoint:
+//   - "file:///mnt/backup" stores each bucket as a subdirectory of /mnt/backup
+//   - "s3://host:port" or "s3://host:port/bucket" is an S3-compatible object store
+//   - "host:port" (no scheme) is also an S3-compatible object store
+func NewBackend(ctx context.Context, endpoint string, accessKeyId string, secretAccessKey string, isTrustSelfSignedCerts bool) (Backend, error) {
+	if !strings.Contains(endpoint, "://") {
+		return newS3Backend(endpoint, accessKeyId, secretAccessKey, isTrustSelfSignedCerts)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse endpoint '%s': %v", endpoint, err)
+	}
+	switch u.Scheme {
+	case "s3":
+		return newS3Backend(u.Host, accessKeyId, secretAccessKey, isTrustSelfSignedCerts)
+	case "file":
+		return newLocalBackend(u.Path)
+	default:
+		return nil, ErrUnsupportedScheme
+	}
+}
+
+// Returns the bucket named in an "s3://host:port/bucket" endpoint, or "" if there isn't one
+func BucketFromEndpoint(endpoint string) string {
+	if !strings.HasPrefix(endpoint, "s3://") {
+		return ""
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(u.Path, "/")
+}
+
+// Returns true if endpoint is an object store that needs an access key and secret
+func IsCredentialedEndpoint(endpoint string) bool {
+	return !strings.HasPrefix(endpoint, "file://")
+}
@@ -0,0 +1,135 @@
+package objstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testBackendThroughObjStore(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	objst := NewObjStoreWithBackend(backend)
+	bucket := "test-bucket"
+
+	// bucket ops
+	_, err := objst.GetObjList(ctx, bucket, "", true, nil)
+	assert.ErrorIs(t, err, ErrNoSuchBucket)
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	assert.Error(t, objst.MakeBucket(ctx, bucket, ""))
+	buckets, err := objst.ListBuckets(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{bucket}, buckets)
+	ok, err := objst.IsReachable(ctx, bucket, nil)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	// upload and download
+	objs := map[string][]byte{
+		"metadata":         []byte("{}"),
+		"chunks/aaa":       []byte("0123456789"),
+		"chunks/bbb":       []byte("abc"),
+		"backup1/@snap1":   []byte("index1"),
+		"backup1/@snap2":   []byte("index2"),
+		"backup2/sub/@ss3": []byte("index3"),
+	}
+	for name, buf := range objs {
+		assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, name, buf, ComputeETag(buf)))
+	}
+	for name, buf := range objs {
+		readBack, err := objst.DownloadObjToBuffer(ctx, bucket, name)
+		assert.NoError(t, err)
+		assert.Equal(t, buf, readBack)
+	}
+	_, err = objst.DownloadObjToBuffer(ctx, bucket, "chunks/doesnotexist")
+	assert.ErrorIs(t, err, ErrNoSuchObject)
+
+	// range get, including a range running past end of object
+	readBack, err := objst.DownloadObjRangeToBuffer(ctx, bucket, "chunks/aaa", 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("234"), readBack)
+	readBack, err = objst.DownloadObjRangeToBuffer(ctx, bucket, "chunks/aaa", 8, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("89"), readBack)
+	_, err = objst.DownloadObjRangeToBuffer(ctx, bucket, "chunks/aaa", 0, 0)
+	assert.Error(t, err)
+
+	// recursive and non-recursive listing
+	m, err := objst.GetObjList(ctx, bucket, "chunks/", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"chunks/aaa": 10, "chunks/bbb": 3}, m)
+	m, err = objst.GetObjList(ctx, bucket, "", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"metadata": 2, "chunks/": 0, "backup1/": 0, "backup2/": 0}, m)
+	m, err = objst.GetObjList(ctx, bucket, "backup", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"backup1/": 0, "backup2/": 0}, m)
+	m, err = objst.GetObjList(ctx, bucket, "backup2/", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"backup2/sub/": 0}, m)
+	m, err = objst.GetObjList(ctx, bucket, "backup1/@snap", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(m))
+	m, err = objst.GetObjList(ctx, bucket, "nothere/", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(m))
+
+	topLevel, err := objst.GetObjListTopLevel(ctx, bucket, []string{"metadata", "chunks"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backup1", "backup2"}, topLevel)
+	topTwoLevels, err := objst.GetObjListTopTwoLevels(ctx, bucket, []string{"metadata", "chunks"}, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"backup1": {"@snap1", "@snap2"}, "backup2": {"sub"}}, topTwoLevels)
+
+	// rename and delete
+	assert.NoError(t, objst.RenameObj(ctx, bucket, "backup1/@snap1", "backup3/@snap1"))
+	_, err = objst.DownloadObjToBuffer(ctx, bucket, "backup1/@snap1")
+	assert.Error(t, err)
+	readBack, err = objst.DownloadObjToBuffer(ctx, bucket, "backup3/@snap1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("index1"), readBack)
+	assert.NoError(t, objst.DeleteObj(ctx, bucket, "backup2/sub/@ss3"))
+	topLevel, err = objst.GetObjListTopLevel(ctx, bucket, []string{"metadata", "chunks"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backup1", "backup3"}, topLevel)
+}
+
+func TestMemBackend(t *testing.T) {
+	testBackendThroughObjStore(t, NewMemBackend())
+}
+
+func TestLocalBackend(t *testing.T) {
+	backend, err := newLocalBackend(t.TempDir())
+	assert.NoError(t, err)
+	testBackendThroughObjStore(t, backend)
+
+	_, err = newLocalBackend("relative/path")
+	assert.Error(t, err)
+}
+
+func TestNewBackend(t *testing.T) {
+	ctx := context.Background()
+
+	backend, err := NewBackend(ctx, "file://"+t.TempDir(), "", "", false)
+	assert.NoError(t, err)
+	_, ok := backend.(*localBackend)
+	assert.True(t, ok)
+
+	backend, err = NewBackend(ctx, "s3://127.0.0.1:9000/mybucket", "id", "secret", false)
+	assert.NoError(t, err)
+	_, ok = backend.(*s3Backend)
+	assert.True(t, ok)
+
+	backend, err = NewBackend(ctx, "127.0.0.1:9000", "id", "secret", false)
+	assert.NoError(t, err)
+	_, ok = backend.(*s3Backend)
+	assert.True(t, ok)
+
+	_, err = NewBackend(ctx, "ftp://127.0.0.1", "", "", false)
+	assert.ErrorIs(t, err, ErrUnsupportedScheme)
+
+	assert.Equal(t, "mybucket", BucketFromEndpoint("s3://127.0.0.1:9000/mybucket"))
+	assert.Equal(t, "", BucketFromEndpoint("127.0.0.1:9000"))
+	assert.False(t, IsCredentialedEndpoint("file:///mnt/backup"))
+	assert.True(t, IsCredentialedEndpoint("s3://127.0.0.1:9000"))
+}
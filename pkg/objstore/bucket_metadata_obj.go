@@ -21,10 +21,16 @@ const (
 )
 
 var (
-	SupportedBucketVersions = []int{1}
+	// Version 2 buckets record their Argon2id parameters in BucketMetadata.Kdf. Version 3 buckets may
+	// wrap a key-encryption key instead of encKey (see BucketMetadata.EncryptedDataEncKeyB64).
+	SupportedBucketVersions = []int{1, 2, 3}
+	CurrentBucketVersion    = 3
 
 	ErrCantConnect           = errors.New("cannot connect to cloud provider")
 	ErrNoMetadataButNotEmpty = errors.New("the bucket does not contain a metadata file but is also not empty")
+	ErrKeyRotationInProgress = errors.New("an encryption key rotation is in progress and must be finished first")
+	ErrAdditionalKeySlots    = errors.New("the bucket has additional key slots from before its first key rotation; revoke them before rotating the key and add them back afterwards")
+	ErrWriteOnlyMode         = errors.New("the bucket is in write-only mode, so its private key passphrase is needed to re-encrypt it")
 )
 
 type BucketMetadata struct {
@@ -32,6 +38,74 @@ type BucketMetadata struct {
 	Version             int
 	EncryptedEncKeyB64  string
 	EncryptedHmacKeyB64 string
+
+	// Argon2id parameters used with Salt (nil means cryptography.DefaultKdfParams)
+	Kdf *cryptography.KdfParams `json:",omitempty"`
+
+	// If set, the passphrase and every key slot wrap a key-encryption key in place of encKey, and
+	// this is encKey encrypted with the key-encryption key. Rotating the key then only replaces
+	// this, leaving the key slots alone. Buckets made by older versions get one at their first
+	// key rotation.
+	EncryptedDataEncKeyB64 string `json:",omitempty"`
+
+	// Generation of encKey (0 for the key the bucket was created with). While a key rotation is in
+	// progress, EncryptedNextEncKeyB64 holds the next generation (encrypted the same way as encKey,
+	// except in rotations begun by older versions, where it is encrypted with the primary
+	// passphrase's pdKey) and objects in the bucket may be encrypted under either one.
+	KeyGeneration          int    `json:",omitempty"`
+	EncryptedNextEncKeyB64 string `json:",omitempty"`
+
+	// Additional passphrases and recovery keys, each of which also unlocks the keys above
+	KeySlots []KeySlot `json:",omitempty"`
+
+	// Write-only mode: chunks and indexes are sealed to this X25519 public key. Its private key is
+	// encrypted under a separate passphrase, so the master password alone cannot read them.
+	SealPublicKeyB64           string                  `json:",omitempty"`
+	SealPrivateKeySalt         string                  `json:",omitempty"`
+	SealPrivateKeyKdf          *cryptography.KdfParams `json:",omitempty"`
+	EncryptedSealPrivateKeyB64 string                  `json:",omitempty"`
+}
+
+func (bMdata *BucketMetadata) IsRotatingKey() bool {
+	return bMdata.EncryptedNextEncKeyB64 != ""
+}
+
+func (bMdata *BucketMetadata) hasKeyEncryptionKey() bool {
+	return bMdata.EncryptedDataEncKeyB64 != ""
+}
+
+// Returns encKey given the key that the passphrases wrap
+func (bMdata *BucketMetadata) unwrapDataEncKey(kek []byte) ([]byte, error) {
+	if !bMdata.hasKeyEncryptionKey() {
+		return kek, nil
+	}
+	return decryptKeyB64(kek, bMdata.EncryptedDataEncKeyB64)
+}
+
+// Base64 decodes encryptedKeyB64 and decrypts it with key
+func decryptKeyB64(key []byte, encryptedKeyB64 string) ([]byte, error) {
+	encryptedKey, err := base64.URLEncoding.DecodeString(encryptedKeyB64)
+	if err != nil {
+		return nil, err
+	}
+	return cryptography.DecryptBuffer(key, encryptedKey)
+}
+
+// Encrypts plaintextKey with key and base64 encodes it
+func encryptKeyB64(key []byte, plaintextKey []byte) (string, error) {
+	encryptedKey, err := cryptography.EncryptBuffer(key, plaintextKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(encryptedKey), nil
+}
+
+// Returns params, or the defaults if the metadata predates recording them
+func kdfParamsOrDefault(params *cryptography.KdfParams) cryptography.KdfParams {
+	if params == nil {
+		return cryptography.DefaultKdfParams
+	}
+	return *params
 }
 
 func (objst *ObjStore) isBucketEmpty(ctx context.Context, bucket string, vlog *util.VLog) (bool, error) {
@@ -47,59 +121,133 @@ func (objst *ObjStore) isBucketEmpty(ctx context.Context, bucket string, vlog *u
 	}
 }
 
-func (objst *ObjStore) readBucketMetadataFile(ctx context.Context, bucket string, masterPassword string, vlog *util.VLog) (bMdataPtr *BucketMetadata, encKey []byte, hmacKey []byte, err error) {
+func (objst *ObjStore) downloadBucketMetadataFile(ctx context.Context, bucket string) (*BucketMetadata, error) {
 	buf, err := objst.DownloadObjToBuffer(ctx, bucket, MetadataObjName)
 	if err != nil {
-		log.Println("error: readBucketMetadataFile: cannot download bucket metadata file: ", err)
-		return nil, nil, nil, err
+		log.Println("error: downloadBucketMetadataFile: cannot download bucket metadata file: ", err)
+		return nil, err
 	}
 
 	var bMdata BucketMetadata
 	if err = json.Unmarshal(buf, &bMdata); err != nil {
-		log.Println("error: readBucketMetadataFile: cannot unmarshall bucket metadata json: ", err)
-		return nil, nil, nil, err
+		log.Println("error: downloadBucketMetadataFile: cannot unmarshall bucket metadata json: ", err)
+		return nil, err
+	}
+	return &bMdata, nil
+}
+
+// Returns the bucket metadata and the keys it holds, unlocked with whichever key slot masterPassword
+// belongs to (slotIdx is -1 for the primary passphrase, else an index into bMdata.KeySlots). kek is
+// the key that the passphrases wrap, which is encKey itself in buckets without a key-encryption
+// key. nextEncKey is nil unless a key rotation is in progress.
+func (objst *ObjStore) readBucketMetadataFile(ctx context.Context, bucket string, masterPassword string, vlog *util.VLog) (bMdata *BucketMetadata, slotIdx int, kek []byte, encKey []byte, hmacKey []byte, nextEncKey []byte, err error) {
+	bMdata, err = objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		return nil, 0, nil, nil, nil, nil, err
 	}
 
+	// Try the primary passphrase first, then any additional key slots
+	slotIdx = -1
+	pdKey, kek, hmacKey, err := unwrapKeys(bMdata.Salt, kdfParamsOrDefault(bMdata.Kdf), masterPassword, bMdata.EncryptedEncKeyB64, bMdata.EncryptedHmacKeyB64, vlog)
+	for i := 0; err != nil && i < len(bMdata.KeySlots); i++ {
+		slot := bMdata.KeySlots[i]
+		var err2 error
+		if pdKey, kek, hmacKey, err2 = unwrapKeys(slot.Salt, kdfParamsOrDefault(slot.Kdf), masterPassword, slot.EncryptedEncKeyB64, slot.EncryptedHmacKeyB64, vlog); err2 == nil {
+			slotIdx = i
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, 0, nil, nil, nil, nil, err
+	}
+	encKey, err = bMdata.unwrapDataEncKey(kek)
+	if err != nil {
+		vlog.Printf("error: readBucketMetadataFile: could not decrypt encrypted encryption key: %v", err)
+		return nil, 0, nil, nil, nil, nil, err
+	}
+
+	// Decrypt the next generation's key too if a key rotation is in progress
+	if bMdata.IsRotatingKey() {
+		nextKeyKey := pdKey
+		if bMdata.hasKeyEncryptionKey() {
+			nextKeyKey = kek
+		}
+		nextEncKey, err = decryptKeyB64(nextKeyKey, bMdata.EncryptedNextEncKeyB64)
+		if err != nil {
+			vlog.Printf("error: readBucketMetadataFile: could not decrypt next encrypted encryption key: %v", err)
+			return nil, 0, nil, nil, nil, nil, err
+		}
+	}
+
+	return bMdata, slotIdx, kek, encKey, hmacKey, nextEncKey, nil
+}
+
+// Derives the password-derived key (pdKey) from salt and password and uses it to decrypt the two
+// encrypted keys (the first of which is the key-encryption key in buckets that have one)
+func unwrapKeys(salt string, kdfParams cryptography.KdfParams, password string, encryptedEncKeyB64 string, encryptedHmacKeyB64 string, vlog *util.VLog) (pdKey []byte, encKey []byte, hmacKey []byte, err error) {
 	// Is salt valid?  Needed for pbKey derivation in next step
-	if len(bMdata.Salt) < util.SaltLen {
-		err = fmt.Errorf("error: readBucketMetadataFile: the bucket salt retrieved is too short (%d chars) to be valid (%d chars required): ", len(bMdata.Salt), util.SaltLen)
+	if len(salt) < util.SaltLen {
+		err = fmt.Errorf("error: unwrapKeys: the bucket salt retrieved is too short (%d chars) to be valid (%d chars required): ", len(salt), util.SaltLen)
 		return nil, nil, nil, err
 	}
 
-	// Derive the password-derived key (pdKey) which we'll need to decrypt the two encrypted keys
-	// in bucket
-	pdKey, err := cryptography.DeriveKey(bMdata.Salt, masterPassword)
+	pdKey, err = cryptography.DeriveKeyWithParams(salt, password, kdfParams)
 	if err != nil {
-		e := fmt.Errorf("error: readBucketMetadataFile: could not derive pdKey: %v", err)
+		e := fmt.Errorf("error: unwrapKeys: could not derive pdKey: %v", err)
 		vlog.Println(e.Error())
 		return nil, nil, nil, e
 	}
 
 	// Un-base64 the two encrypted keys from bucket
-	encryptedEncKey, err := base64.URLEncoding.DecodeString(bMdata.EncryptedEncKeyB64)
+	encryptedEncKey, err := base64.URLEncoding.DecodeString(encryptedEncKeyB64)
 	if err != nil {
-		vlog.Printf("error: readBucketMetadataFile: could not base64 decode encrypted encryption key base64 string: %v", err)
+		vlog.Printf("error: unwrapKeys: could not base64 decode encrypted encryption key base64 string: %v", err)
 		return nil, nil, nil, err
 	}
-	encryptedHmacKey, err := base64.URLEncoding.DecodeString(bMdata.EncryptedHmacKeyB64)
+	encryptedHmacKey, err := base64.URLEncoding.DecodeString(encryptedHmacKeyB64)
 	if err != nil {
-		vlog.Printf("error: readBucketMetadataFile: could not base64 decode encrypted HMAC key base64 string: %v", err)
+		vlog.Printf("error: unwrapKeys: could not base64 decode encrypted HMAC key base64 string: %v", err)
 		return nil, nil, nil, err
 	}
 
 	// Decrypt the encrypted keys in bucket using pdKey
 	encKey, err = cryptography.DecryptBuffer(pdKey, encryptedEncKey)
 	if err != nil {
-		vlog.Printf("error: readBucketMetadataFile: could not decrypt encrypted encryption key: %v", err)
+		vlog.Printf("error: unwrapKeys: could not decrypt encrypted encryption key: %v", err)
 		return nil, nil, nil, err
 	}
 	hmacKey, err = cryptography.DecryptBuffer(pdKey, encryptedHmacKey)
 	if err != nil {
-		vlog.Printf("error: readBucketMetadataFile: could not decrypt encrypted HMAC key: %v", err)
+		vlog.Printf("error: unwrapKeys: could not decrypt encrypted HMAC key: %v", err)
 		return nil, nil, nil, err
 	}
 
-	return &bMdata, encKey, hmacKey, nil
+	return pdKey, encKey, hmacKey, nil
+}
+
+// Generates a new salt, derives a pdKey from it and password, and encrypts the two keys with it
+func wrapKeys(password string, kdfParams cryptography.KdfParams, encKey []byte, hmacKey []byte, vlog *util.VLog) (salt string, encryptedEncKeyB64 string, encryptedHmacKeyB64 string, err error) {
+	salt = util.GenerateRandomSalt()
+
+	pdKey, err := cryptography.DeriveKeyWithParams(salt, password, kdfParams)
+	if err != nil {
+		e := fmt.Errorf("error: wrapKeys: could not derive pdKey: %v", err)
+		vlog.Println(e.Error())
+		return "", "", "", e
+	}
+
+	encryptedEncKey, err := cryptography.EncryptBuffer(pdKey, encKey)
+	if err != nil {
+		log.Println("error: wrapKeys: cannot encrypt encKey: ", err)
+		return "", "", "", err
+	}
+	encryptedHmacKey, err := cryptography.EncryptBuffer(pdKey, hmacKey)
+	if err != nil {
+		log.Println("error: wrapKeys: cannot encrypt hmacKey: ", err)
+		return "", "", "", err
+	}
+
+	return salt, base64.URLEncoding.EncodeToString(encryptedEncKey), base64.URLEncoding.EncodeToString(encryptedHmacKey), nil
 }
 
 func (objst *ObjStore) writeBucketMetadataFile(ctx context.Context, bucket string, bMdata *BucketMetadata, vlog *util.VLog) error {
@@ -129,7 +277,7 @@ func (objst *ObjStore) GetOrCreateBucketMetadata(ctx context.Context, bucket str
 	}
 	for objName := range mObjs {
 		if objName == MetadataObjName {
-			bMdata, encKey, hmacKey, err = objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
+			bMdata, _, _, encKey, hmacKey, _, err = objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
 			if err != nil {
 				if strings.Contains(err.Error(), "connect") {
 					return "", 0, nil, nil, ErrCantConnect
@@ -153,24 +301,35 @@ func (objst *ObjStore) GetOrCreateBucketMetadata(ctx context.Context, bucket str
 			salt = util.GenerateRandomSalt()
 
 			// derive pdKey using new salt
-			pdKey, err := cryptography.DeriveKey(salt, masterPassword)
+			kdfParams := cryptography.DefaultKdfParams
+			pdKey, err := cryptography.DeriveKeyWithParams(salt, masterPassword, kdfParams)
 			if err != nil {
 				e := fmt.Errorf("error: readBucketMetadataFile: could not derive pdKey: %v", err)
 				vlog.Println(e.Error())
 				return "", 0, nil, nil, e
 			}
 
-			// generate two random keys and encrypt+base64 both with pdKey
+			// generate a random key-encryption key and two random keys, encrypt+base64 the
+			// key-encryption key and hmacKey with pdKey and encKey with the key-encryption key
+			kek := make([]byte, 32)
+			if _, err := io.ReadFull(rand.Reader, kek); err != nil {
+				return "", 0, nil, nil, err
+			}
+			encryptedKekB64, err := encryptKeyB64(pdKey, kek)
+			if err != nil {
+				log.Println("error: readBucketMetadataFile: could not encrypt buffer with key-encryption key")
+				return "", 0, nil, nil, err
+			}
+
 			encKey = make([]byte, 32)
 			if _, err := io.ReadFull(rand.Reader, encKey); err != nil {
 				return "", 0, nil, nil, err
 			}
-			encryptedEncKey, err := cryptography.EncryptBuffer(pdKey, encKey)
+			encryptedDataEncKeyB64, err := encryptKeyB64(kek, encKey)
 			if err != nil {
 				log.Println("error: readBucketMetadataFile: could not encrypt buffer with encKey")
 				return "", 0, nil, nil, err
 			}
-			encryptedEncKeyB64 := base64.URLEncoding.EncodeToString(encryptedEncKey)
 
 			hmacKey = make([]byte, 32)
 			if _, err := io.ReadFull(rand.Reader, hmacKey); err != nil {
@@ -184,16 +343,18 @@ func (objst *ObjStore) GetOrCreateBucketMetadata(ctx context.Context, bucket str
 			encryptedHmacKeyB64 := base64.URLEncoding.EncodeToString(encryptedHmacKey)
 
 			bMdata = &BucketMetadata{
-				Salt:                salt,
-				Version:             1,
-				EncryptedEncKeyB64:  encryptedEncKeyB64,
-				EncryptedHmacKeyB64: encryptedHmacKeyB64,
+				Salt:                   salt,
+				Version:                CurrentBucketVersion,
+				EncryptedEncKeyB64:     encryptedKekB64,
+				EncryptedHmacKeyB64:    encryptedHmacKeyB64,
+				Kdf:                    &kdfParams,
+				EncryptedDataEncKeyB64: encryptedDataEncKeyB64,
 			}
 			if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
 				log.Println("error: GetOrCreateBucketMetadata: cannot write new bucket metadata file: ", err)
 				return "", 0, nil, nil, err
 			}
-			bMdata, encKey, hmacKey, err = objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
+			bMdata, _, _, encKey, hmacKey, _, err = objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
 			if err != nil {
 				log.Println("error: GetOrCreateBucketMetadata: cannot read bucket metadata file that we just wrote: ", err)
 				return "", 0, nil, nil, err
@@ -206,9 +367,10 @@ func (objst *ObjStore) GetOrCreateBucketMetadata(ctx context.Context, bucket str
 }
 
 // Verifies the keys by trying to decrypt bucket metadata and seeing if we get the same result.
-// Further verify encKey by trying to decrypt any snapshot names in bucket.
+// Further verify encKey by trying to decrypt any snapshot names in bucket (or, mid key rotation,
+// the next generation's key).
 func (objst *ObjStore) VerifyKeys(ctx context.Context, bucket string, masterPassword string, encKey []byte, hmacKey []byte, vlog *util.VLog) error {
-	_, encKeyReadBack, hmacKeyReadBack, err := objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
+	_, _, _, encKeyReadBack, hmacKeyReadBack, nextEncKey, err := objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
 	if err != nil {
 		log.Printf("error: VerifyKeysAndSalt: could not read bucket metadata: %v", err)
 		return err
@@ -232,6 +394,9 @@ func (objst *ObjStore) VerifyKeys(ctx context.Context, bucket string, masterPass
 	}
 	if len(topLevelObjs) > 0 {
 		decObjName, err := cryptography.DecryptFilename(encKey, topLevelObjs[0])
+		if err != nil && nextEncKey != nil {
+			decObjName, err = cryptography.DecryptFilename(nextEncKey, topLevelObjs[0])
+		}
 		if err != nil {
 			log.Printf("Could not decrypt '%s': key is probably wrong (%v)", topLevelObjs[0], err)
 			return err
@@ -244,42 +409,199 @@ func (objst *ObjStore) VerifyKeys(ctx context.Context, bucket string, masterPass
 	return nil
 }
 
-// Reencrypts encKey and hmacKey under new pdKey (derived from newPassword + new salt) and saves encrypted keys to cloud bucket
-func (objst *ObjStore) ChangePassword(ctx context.Context, bucket string, encKey []byte, hmacKey []byte, newPassword string, vlog *util.VLog) error {
-	// generate the new salt we're going to use when writing new bucket
-	salt := util.GenerateRandomSalt()
+// Reencrypts the keys that oldPassword unlocks under new pdKey (derived from newPassword + new salt) and
+// saves encrypted keys to cloud bucket, replacing the key slot that oldPassword unlocks. If kdfParams is
+// non-nil, the new pdKey is derived with those Argon2id parameters; otherwise the slot keeps its current ones.
+func (objst *ObjStore) ChangePassword(ctx context.Context, bucket string, oldPassword string, newPassword string, kdfParams *cryptography.KdfParams, vlog *util.VLog) error {
+	if kdfParams != nil {
+		if err := kdfParams.Validate(); err != nil {
+			log.Println("error: ChangePassword: ", err)
+			return err
+		}
+	}
 
-	// derive pdKey using new salt
-	pdKey, err := cryptography.DeriveKey(salt, newPassword)
+	bMdata, slotIdx, kek, _, hmacKey, _, err := objst.readBucketMetadataFile(ctx, bucket, oldPassword, vlog)
 	if err != nil {
-		e := fmt.Errorf("error: ChangePassword: could not derive pdKey: %v", err)
-		vlog.Println(e.Error())
-		return e
+		log.Println("error: ChangePassword: cannot read bucket metadata file: ", err)
+		return err
+	}
+	if bMdata.IsRotatingKey() {
+		log.Println("error: ChangePassword: ", ErrKeyRotationInProgress)
+		return ErrKeyRotationInProgress
 	}
 
-	encryptedEncKey, err := cryptography.EncryptBuffer(pdKey, encKey)
+	if kdfParams == nil {
+		currentKdfParams := kdfParamsOrDefault(bMdata.Kdf)
+		if slotIdx >= 0 {
+			currentKdfParams = kdfParamsOrDefault(bMdata.KeySlots[slotIdx].Kdf)
+		}
+		kdfParams = &currentKdfParams
+	}
+
+	salt, encryptedEncKeyB64, encryptedHmacKeyB64, err := wrapKeys(newPassword, *kdfParams, kek, hmacKey, vlog)
 	if err != nil {
-		log.Println("error: ChangePassword: cannot encrypt encKey: ", err)
+		log.Println("error: ChangePassword: ", err)
 		return err
 	}
-	encryptedEncKeyB64 := base64.URLEncoding.EncodeToString(encryptedEncKey)
+	if slotIdx < 0 {
+		bMdata.Salt = salt
+		bMdata.Kdf = kdfParams
+		bMdata.EncryptedEncKeyB64 = encryptedEncKeyB64
+		bMdata.EncryptedHmacKeyB64 = encryptedHmacKeyB64
+	} else {
+		bMdata.KeySlots[slotIdx].Salt = salt
+		bMdata.KeySlots[slotIdx].Kdf = kdfParams
+		bMdata.KeySlots[slotIdx].EncryptedEncKeyB64 = encryptedEncKeyB64
+		bMdata.KeySlots[slotIdx].EncryptedHmacKeyB64 = encryptedHmacKeyB64
+	}
 
-	encryptedHmacKey, err := cryptography.EncryptBuffer(pdKey, hmacKey)
+	// The metadata now records its parameters, which makes it a current version bucket just like a
+	// newly created one. Older versions of the program would derive the wrong key from non-default
+	// parameters, so this also makes them refuse the bucket instead.
+	if bMdata.Version < CurrentBucketVersion {
+		bMdata.Version = CurrentBucketVersion
+	}
+
+	if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
+		log.Println("error: ChangePassword: cannot write new bucket metadata file: ", err)
+		return err
+	}
+	return nil
+}
+
+// Returns true if a key rotation was started but not yet finished
+func (objst *ObjStore) IsKeyRotationInProgress(ctx context.Context, bucket string) (bool, error) {
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: IsKeyRotationInProgress: ", err)
+		return false, err
+	}
+	return bMdata.IsRotatingKey(), nil
+}
+
+// Starts a key rotation by generating a new encryption key and saving it in the bucket metadata
+// alongside the current one, or resumes the rotation already in progress. Returns the current key,
+// the key being rotated to, the latter's generation and the HMAC key (which is not rotated). A
+// bucket in write-only mode can only be rotated if sealKeys has its private key unlocked.
+//
+// The new key is encrypted with the key-encryption key, so every key slot keeps working. A bucket
+// made by an older version gets a key-encryption key at its first rotation, which needs the primary
+// passphrase and no other key slots, as those still wrap encKey itself.
+func (objst *ObjStore) BeginKeyRotation(ctx context.Context, bucket string, masterPassword string, sealKeys *cryptography.SealKeys, vlog *util.VLog) (encKey []byte, nextEncKey []byte, nextGeneration int, hmacKey []byte, err error) {
+	bMdata, slotIdx, kek, encKey, hmacKey, nextEncKey, err := objst.readBucketMetadataFile(ctx, bucket, masterPassword, vlog)
+	if err != nil {
+		log.Println("error: BeginKeyRotation: cannot read bucket metadata file: ", err)
+		return nil, nil, 0, nil, err
+	}
+	if bMdata.IsWriteOnly() && !sealKeys.CanOpen() {
+		log.Println("error: BeginKeyRotation: ", ErrWriteOnlyMode)
+		return nil, nil, 0, nil, ErrWriteOnlyMode
+	}
+	if nextEncKey != nil {
+		vlog.Printf("Resuming rotation to key generation %d", bMdata.KeyGeneration+1)
+		return encKey, nextEncKey, bMdata.KeyGeneration + 1, hmacKey, nil
+	}
+
+	if !bMdata.hasKeyEncryptionKey() {
+		if len(bMdata.KeySlots) > 0 || slotIdx >= 0 {
+			// We only know one passphrase, so we could not give the other slots the key-encryption key
+			log.Println("error: BeginKeyRotation: ", ErrAdditionalKeySlots)
+			return nil, nil, 0, nil, ErrAdditionalKeySlots
+		}
+
+		// Wrap a new key-encryption key in place of encKey, and encrypt encKey with it
+		pdKey, err := cryptography.DeriveKeyWithParams(bMdata.Salt, masterPassword, kdfParamsOrDefault(bMdata.Kdf))
+		if err != nil {
+			e := fmt.Errorf("error: BeginKeyRotation: could not derive pdKey: %v", err)
+			vlog.Println(e.Error())
+			return nil, nil, 0, nil, e
+		}
+		kek = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, kek); err != nil {
+			return nil, nil, 0, nil, err
+		}
+		if bMdata.EncryptedEncKeyB64, err = encryptKeyB64(pdKey, kek); err != nil {
+			log.Println("error: BeginKeyRotation: cannot encrypt key-encryption key: ", err)
+			return nil, nil, 0, nil, err
+		}
+		if bMdata.EncryptedDataEncKeyB64, err = encryptKeyB64(kek, encKey); err != nil {
+			log.Println("error: BeginKeyRotation: cannot encrypt encKey: ", err)
+			return nil, nil, 0, nil, err
+		}
+		bMdata.Version = CurrentBucketVersion
+	}
+
+	nextEncKey = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, nextEncKey); err != nil {
+		return nil, nil, 0, nil, err
+	}
+	if bMdata.EncryptedNextEncKeyB64, err = encryptKeyB64(kek, nextEncKey); err != nil {
+		log.Println("error: BeginKeyRotation: cannot encrypt next encKey: ", err)
+		return nil, nil, 0, nil, err
+	}
+
+	// Key slot details are encrypted with encKey, so move them to the next key along with everything else
+	for i := range bMdata.KeySlots {
+		if err := reencryptKeySlotInfo(encKey, nextEncKey, &bMdata.KeySlots[i]); err != nil {
+			log.Printf("error: BeginKeyRotation: cannot re-encrypt details of key slot %d: %v", i, err)
+			return nil, nil, 0, nil, err
+		}
+	}
+
+	if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
+		log.Println("error: BeginKeyRotation: cannot write bucket metadata file: ", err)
+		return nil, nil, 0, nil, err
+	}
+	vlog.Printf("Starting rotation to key generation %d", bMdata.KeyGeneration+1)
+	return encKey, nextEncKey, bMdata.KeyGeneration + 1, hmacKey, nil
+}
+
+// Makes the key being rotated to the current key. Call once every object has been re-encrypted.
+func (objst *ObjStore) FinishKeyRotation(ctx context.Context, bucket string, vlog *util.VLog) error {
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
 	if err != nil {
-		log.Println("error: ChangePassword: cannot encrypt hmacKey: ", err)
+		log.Println("error: FinishKeyRotation: cannot read bucket metadata file: ", err)
 		return err
 	}
-	encryptedHmacKeyB64 := base64.URLEncoding.EncodeToString(encryptedHmacKey)
+	if !bMdata.IsRotatingKey() {
+		return fmt.Errorf("error: FinishKeyRotation: no key rotation is in progress")
+	}
 
-	bMdata := &BucketMetadata{
-		Salt:                salt,
-		Version:             1,
-		EncryptedEncKeyB64:  encryptedEncKeyB64,
-		EncryptedHmacKeyB64: encryptedHmacKeyB64,
+	if bMdata.hasKeyEncryptionKey() {
+		bMdata.EncryptedDataEncKeyB64 = bMdata.EncryptedNextEncKeyB64
+	} else {
+		bMdata.EncryptedEncKeyB64 = bMdata.EncryptedNextEncKeyB64
 	}
+	bMdata.EncryptedNextEncKeyB64 = ""
+	bMdata.KeyGeneration += 1
 	if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
-		log.Println("error: ChangePassword: cannot write new bucket metadata file: ", err)
+		log.Println("error: FinishKeyRotation: cannot write bucket metadata file: ", err)
 		return err
 	}
 	return nil
 }
+
+// Returns the Argon2id parameters of the key slot that password unlocks
+func (objst *ObjStore) GetKdfParams(ctx context.Context, bucket string, password string, vlog *util.VLog) (cryptography.KdfParams, error) {
+	bMdata, slotIdx, _, _, _, _, err := objst.readBucketMetadataFile(ctx, bucket, password, vlog)
+	if err != nil {
+		log.Println("error: GetKdfParams: cannot read bucket metadata file: ", err)
+		return cryptography.KdfParams{}, err
+	}
+	if slotIdx >= 0 {
+		return kdfParamsOrDefault(bMdata.KeySlots[slotIdx].Kdf), nil
+	}
+	return kdfParamsOrDefault(bMdata.Kdf), nil
+}
+
+// Returns the key that the key slots wrap (the key-encryption key, or in buckets made by older
+// versions that have not had a key rotation yet, encKey itself), unlocked with password. New key
+// slots and escrow shares hold this key.
+func (objst *ObjStore) GetKeyEncryptionKey(ctx context.Context, bucket string, password string, vlog *util.VLog) ([]byte, error) {
+	_, _, kek, _, _, _, err := objst.readBucketMetadataFile(ctx, bucket, password, vlog)
+	if err != nil {
+		log.Println("error: GetKeyEncryptionKey: cannot read bucket metadata file: ", err)
+		return nil, err
+	}
+	return kek, nil
+}
@@ -0,0 +1,55 @@
+package objstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangePasswordKdfParams(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := NewObjStoreWithBackend(NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	_, version, encKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, "old password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentBucketVersion, version)
+
+	// New buckets record their parameters
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, cryptography.DefaultKdfParams, *bMdata.Kdf)
+
+	// Upgrade the parameters while changing the password
+	cheap := cryptography.KdfParams{Time: 1, MemoryKiB: 64 * 1024, Threads: 1}
+	assert.Error(t, objst.ChangePassword(ctx, bucket, "old password", "new password", &cryptography.KdfParams{}, vlog))
+	assert.NoError(t, objst.ChangePassword(ctx, bucket, "old password", "new password", &cheap, vlog))
+	params, err := objst.GetKdfParams(ctx, bucket, "new password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, cheap, params)
+	_, _, encKey2, hmacKey2, err := objst.GetOrCreateBucketMetadata(ctx, bucket, "new password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, encKey, encKey2)
+	assert.Equal(t, hmacKey, hmacKey2)
+
+	// Without new parameters, the current ones are kept
+	assert.NoError(t, objst.ChangePassword(ctx, bucket, "new password", "newer password", nil, vlog))
+	params, err = objst.GetKdfParams(ctx, bucket, "newer password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, cheap, params)
+
+	// A version 1 bucket becomes a current one once its parameters are recorded, even default ones
+	bMdata, err = objst.downloadBucketMetadataFile(ctx, bucket)
+	assert.NoError(t, err)
+	bMdata.Version = 1
+	assert.NoError(t, objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog))
+	assert.NoError(t, objst.ChangePassword(ctx, bucket, "newer password", "newest password", &cryptography.DefaultKdfParams, vlog))
+	_, version, _, _, err = objst.GetOrCreateBucketMetadata(ctx, bucket, "newest password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentBucketVersion, version)
+}
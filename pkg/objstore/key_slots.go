@@ -0,0 +1,224 @@
+package objstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Name under which the passphrase stored directly in BucketMetadata is listed
+const PrimaryKeySlotName = "primary"
+
+var (
+	ErrKeySlotNotFound = errors.New("no key slot with that name")
+)
+
+// An additional passphrase (or recovery key) that unlocks the bucket. Like the primary passphrase
+// it has its own salt and its own wrapped copy of the keys (the key-encryption key, if the bucket
+// has one, and the HMAC key). Its name and other details are
+// encrypted with encKey so that the cloud provider learns nothing but the number of slots.
+type KeySlot struct {
+	Salt                string
+	Kdf                 *cryptography.KdfParams `json:",omitempty"`
+	EncryptedEncKeyB64  string
+	EncryptedHmacKeyB64 string
+	EncryptedInfoB64    string
+}
+
+// The plaintext details of a key slot
+type KeySlotInfo struct {
+	Name          string
+	IsRecoveryKey bool  `json:",omitempty"`
+	CreatedUnix   int64 `json:",omitempty"`
+}
+
+func decryptKeySlotInfo(encKey []byte, slot *KeySlot) (*KeySlotInfo, error) {
+	encryptedInfo, err := base64.URLEncoding.DecodeString(slot.EncryptedInfoB64)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := cryptography.DecryptBuffer(encKey, encryptedInfo)
+	if err != nil {
+		return nil, err
+	}
+	var info KeySlotInfo
+	if err = json.Unmarshal(buf, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func reencryptKeySlotInfo(oldEncKey []byte, newEncKey []byte, slot *KeySlot) error {
+	encryptedInfo, err := base64.URLEncoding.DecodeString(slot.EncryptedInfoB64)
+	if err != nil {
+		return err
+	}
+	encryptedInfo, err = cryptography.ReencryptBuffer(oldEncKey, newEncKey, encryptedInfo, nil)
+	if err != nil {
+		return err
+	}
+	slot.EncryptedInfoB64 = base64.URLEncoding.EncodeToString(encryptedInfo)
+	return nil
+}
+
+// Returns the details of every key slot, starting with the primary passphrase
+func (objst *ObjStore) ListKeySlots(ctx context.Context, bucket string, encKey []byte) ([]KeySlotInfo, error) {
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: ListKeySlots: cannot read bucket metadata file: ", err)
+		return nil, err
+	}
+
+	infos := []KeySlotInfo{{Name: PrimaryKeySlotName}}
+	for i := range bMdata.KeySlots {
+		info, err := decryptKeySlotInfo(encKey, &bMdata.KeySlots[i])
+		if err != nil {
+			log.Printf("error: ListKeySlots: cannot decrypt details of key slot %d: %v", i, err)
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	return infos, nil
+}
+
+// Adds a key slot named name in which password (or a recovery key) also unlocks the bucket. kek is the
+// key the other slots wrap (see GetKeyEncryptionKey).
+func (objst *ObjStore) AddKeySlot(ctx context.Context, bucket string, kek []byte, hmacKey []byte, name string, password string, isRecoveryKey bool, vlog *util.VLog) error {
+	name = strings.TrimSpace(name)
+	if name == "" || name == PrimaryKeySlotName {
+		return fmt.Errorf("invalid key slot name '%s'", name)
+	}
+	if password == "" {
+		return fmt.Errorf("passphrase cannot be blank")
+	}
+
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: AddKeySlot: cannot read bucket metadata file: ", err)
+		return err
+	}
+	if bMdata.IsRotatingKey() {
+		log.Println("error: AddKeySlot: ", ErrKeyRotationInProgress)
+		return ErrKeyRotationInProgress
+	}
+	encKey, err := bMdata.unwrapDataEncKey(kek)
+	if err != nil {
+		log.Println("error: AddKeySlot: cannot decrypt encrypted encryption key: ", err)
+		return err
+	}
+	for i := range bMdata.KeySlots {
+		info, err := decryptKeySlotInfo(encKey, &bMdata.KeySlots[i])
+		if err != nil {
+			log.Printf("error: AddKeySlot: cannot decrypt details of key slot %d: %v", i, err)
+			return err
+		}
+		if info.Name == name {
+			return fmt.Errorf("a key slot named '%s' already exists", name)
+		}
+	}
+
+	// New slots get the same Argon2id parameters as the primary passphrase
+	kdfParams := kdfParamsOrDefault(bMdata.Kdf)
+	salt, encryptedEncKeyB64, encryptedHmacKeyB64, err := wrapKeys(password, kdfParams, kek, hmacKey, vlog)
+	if err != nil {
+		log.Println("error: AddKeySlot: ", err)
+		return err
+	}
+	infoBuf, err := json.Marshal(&KeySlotInfo{Name: name, IsRecoveryKey: isRecoveryKey, CreatedUnix: time.Now().Unix()})
+	if err != nil {
+		log.Println("error: AddKeySlot: cannot marshal key slot details: ", err)
+		return err
+	}
+	encryptedInfo, err := cryptography.EncryptBuffer(encKey, infoBuf)
+	if err != nil {
+		log.Println("error: AddKeySlot: cannot encrypt key slot details: ", err)
+		return err
+	}
+	bMdata.KeySlots = append(bMdata.KeySlots, KeySlot{
+		Salt:                salt,
+		Kdf:                 &kdfParams,
+		EncryptedEncKeyB64:  encryptedEncKeyB64,
+		EncryptedHmacKeyB64: encryptedHmacKeyB64,
+		EncryptedInfoB64:    base64.URLEncoding.EncodeToString(encryptedInfo),
+	})
+
+	if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
+		log.Println("error: AddKeySlot: cannot write bucket metadata file: ", err)
+		return err
+	}
+	return nil
+}
+
+// Removes the key slot named name. The primary passphrase cannot be revoked (change it instead),
+// and neither can the slot that currentPassword belongs to, so that the caller cannot lock itself out.
+func (objst *ObjStore) RevokeKeySlot(ctx context.Context, bucket string, currentPassword string, name string, vlog *util.VLog) error {
+	if name == PrimaryKeySlotName {
+		return fmt.Errorf("the primary passphrase cannot be revoked; change it instead")
+	}
+
+	bMdata, currentSlotIdx, _, encKey, _, _, err := objst.readBucketMetadataFile(ctx, bucket, currentPassword, vlog)
+	if err != nil {
+		log.Println("error: RevokeKeySlot: cannot read bucket metadata file: ", err)
+		return err
+	}
+	for i := range bMdata.KeySlots {
+		info, err := decryptKeySlotInfo(encKey, &bMdata.KeySlots[i])
+		if err != nil {
+			log.Printf("error: RevokeKeySlot: cannot decrypt details of key slot %d: %v", i, err)
+			return err
+		}
+		if info.Name != name {
+			continue
+		}
+		if i == currentSlotIdx {
+			return fmt.Errorf("cannot revoke key slot '%s' because it is the one in use", name)
+		}
+
+		bMdata.KeySlots = append(bMdata.KeySlots[:i], bMdata.KeySlots[i+1:]...)
+		if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
+			log.Println("error: RevokeKeySlot: cannot write bucket metadata file: ", err)
+			return err
+		}
+		return nil
+	}
+	return ErrKeySlotNotFound
+}
+
+// Checks a key obtained without a passphrase (e.g., rebuilt from escrow shares) against the bucket by
+// using it as kek (see GetKeyEncryptionKey) to decrypt the key slot details and a backup name
+func (objst *ObjStore) VerifyRecoveredKeys(ctx context.Context, bucket string, kek []byte) error {
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: VerifyRecoveredKeys: cannot read bucket metadata file: ", err)
+		return err
+	}
+	encKey, err := bMdata.unwrapDataEncKey(kek)
+	if err != nil {
+		return fmt.Errorf("keys do not belong to this bucket (cannot decrypt the encryption key: %v)", err)
+	}
+	for i := range bMdata.KeySlots {
+		if _, err := decryptKeySlotInfo(encKey, &bMdata.KeySlots[i]); err != nil {
+			return fmt.Errorf("keys do not belong to this bucket (cannot decrypt key slot %d: %v)", i, err)
+		}
+	}
+
+	topLevelObjs, err := objst.GetObjListTopLevel(ctx, bucket, []string{"metadata", "chunks"})
+	if err != nil {
+		log.Println("error: VerifyRecoveredKeys: GetObjListTopLevel: ", err)
+		return err
+	}
+	if len(topLevelObjs) > 0 {
+		if _, err := cryptography.DecryptFilename(encKey, topLevelObjs[0]); err != nil {
+			return fmt.Errorf("keys do not belong to this bucket (cannot decrypt '%s': %v)", topLevelObjs[0], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package objstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySlots(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := NewObjStoreWithBackend(NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	_, _, encKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, "primary password", vlog)
+	assert.NoError(t, err)
+
+	kek, err := objst.GetKeyEncryptionKey(ctx, bucket, "primary password", vlog)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encKey, kek)
+	recoveryKey := util.GenerateRecoveryKey()
+	assert.NoError(t, objst.AddKeySlot(ctx, bucket, kek, hmacKey, "alice", "alice's password", false, vlog))
+	assert.NoError(t, objst.AddKeySlot(ctx, bucket, kek, hmacKey, "recovery", recoveryKey, true, vlog))
+	assert.Error(t, objst.AddKeySlot(ctx, bucket, kek, hmacKey, "alice", "another password", false, vlog))
+
+	// Each slot unlocks the same keys
+	_, _, encKey2, hmacKey2, err := objst.GetOrCreateBucketMetadata(ctx, bucket, recoveryKey, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, encKey, encKey2)
+	assert.Equal(t, hmacKey, hmacKey2)
+
+	infos, err := objst.ListKeySlots(ctx, bucket, encKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(infos))
+	assert.Equal(t, PrimaryKeySlotName, infos[0].Name)
+	assert.Equal(t, "alice", infos[1].Name)
+	assert.False(t, infos[1].IsRecoveryKey)
+	assert.Equal(t, "recovery", infos[2].Name)
+	assert.True(t, infos[2].IsRecoveryKey)
+
+	// The cloud provider cannot see slot names
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	assert.NoError(t, err)
+	assert.NotContains(t, bMdata.KeySlots[1].EncryptedInfoB64, "recovery")
+
+	assert.NoError(t, objst.RevokeKeySlot(ctx, bucket, recoveryKey, "alice", vlog))
+	assert.ErrorIs(t, objst.RevokeKeySlot(ctx, bucket, "primary password", "alice", vlog), ErrKeySlotNotFound)
+	infos, err = objst.ListKeySlots(ctx, bucket, encKey)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{PrimaryKeySlotName, "recovery"}, []string{infos[0].Name, infos[1].Name})
+}
+
+func TestKeyRotationWithKeySlots(t *testing.T) {
+	ctx := context.Background()
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := NewObjStoreWithBackend(NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	_, _, encKey, hmacKey, err := objst.GetOrCreateBucketMetadata(ctx, bucket, "primary password", vlog)
+	assert.NoError(t, err)
+	kek, err := objst.GetKeyEncryptionKey(ctx, bucket, "primary password", vlog)
+	assert.NoError(t, err)
+	assert.NoError(t, objst.AddKeySlot(ctx, bucket, kek, hmacKey, "alice", "alice's password", false, vlog))
+
+	// Any slot can rotate the key, and every slot unlocks the new key afterwards
+	oldKey, newKey, generation, _, err := objst.BeginKeyRotation(ctx, bucket, "alice's password", nil, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, encKey, oldKey)
+	assert.Equal(t, 1, generation)
+	assert.NoError(t, objst.FinishKeyRotation(ctx, bucket, vlog))
+	for _, password := range []string{"primary password", "alice's password"} {
+		_, _, encKey2, hmacKey2, err := objst.GetOrCreateBucketMetadata(ctx, bucket, password, vlog)
+		assert.NoError(t, err)
+		assert.Equal(t, newKey, encKey2)
+		assert.Equal(t, hmacKey, hmacKey2)
+	}
+	infos, err := objst.ListKeySlots(ctx, bucket, newKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", infos[1].Name)
+
+	// A bucket made by an older version gets a key-encryption key at its first rotation, once its
+	// other key slots, which wrap encKey itself, are gone
+	legacyBucket := "legacy-bucket"
+	assert.NoError(t, objst.MakeBucket(ctx, legacyBucket, ""))
+	salt, encryptedEncKeyB64, encryptedHmacKeyB64, err := wrapKeys("primary password", cryptography.DefaultKdfParams, encKey, hmacKey, vlog)
+	assert.NoError(t, err)
+	slotSalt, slotEncryptedEncKeyB64, slotEncryptedHmacKeyB64, err := wrapKeys("alice's password", cryptography.DefaultKdfParams, encKey, hmacKey, vlog)
+	assert.NoError(t, err)
+	legacyMdata := &BucketMetadata{Salt: salt, Version: 2, EncryptedEncKeyB64: encryptedEncKeyB64, EncryptedHmacKeyB64: encryptedHmacKeyB64}
+	legacyMdata.KeySlots = []KeySlot{{Salt: slotSalt, EncryptedEncKeyB64: slotEncryptedEncKeyB64, EncryptedHmacKeyB64: slotEncryptedHmacKeyB64}}
+	assert.NoError(t, objst.writeBucketMetadataFile(ctx, legacyBucket, legacyMdata, vlog))
+	_, _, _, _, err = objst.BeginKeyRotation(ctx, legacyBucket, "primary password", nil, vlog)
+	assert.ErrorIs(t, err, ErrAdditionalKeySlots)
+
+	legacyMdata.KeySlots = nil
+	assert.NoError(t, objst.writeBucketMetadataFile(ctx, legacyBucket, legacyMdata, vlog))
+	oldKey, newKey, _, _, err = objst.BeginKeyRotation(ctx, legacyBucket, "primary password", nil, vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, encKey, oldKey)
+	assert.NoError(t, objst.FinishKeyRotation(ctx, legacyBucket, vlog))
+	_, version, encKey2, _, err := objst.GetOrCreateBucketMetadata(ctx, legacyBucket, "primary password", vlog)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentBucketVersion, version)
+	assert.Equal(t, newKey, encKey2)
+	kek, err = objst.GetKeyEncryptionKey(ctx, legacyBucket, "primary password", vlog)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encKey, kek)
+	assert.NotEqual(t, newKey, kek)
+}
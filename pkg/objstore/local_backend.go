@@ -0,0 +1,257 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	localBackendTempPrefix = ".tless-tmp-"
+)
+
+// Stores objects as files in a local directory (eg, a NAS mount or USB disk). Each bucket is a
+// subdirectory of rootPath and object names containing "/" become nested subdirectories.
+type localBackend struct {
+	rootPath string
+}
+
+func newLocalBackend(rootPath string) (*localBackend, error) {
+	if rootPath == "" || !filepath.IsAbs(rootPath) {
+		return nil, fmt.Errorf("local backend path must be absolute (got '%s')", rootPath)
+	}
+	return &localBackend{
+		rootPath: filepath.Clean(rootPath),
+	}, nil
+}
+
+func (b *localBackend) bucketPath(bucket string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, "/\\") || bucket == "." || bucket == ".." {
+		return "", fmt.Errorf("invalid bucket name '%s'", bucket)
+	}
+	bucketPath := filepath.Join(b.rootPath, bucket)
+	if info, err := os.Stat(bucketPath); err != nil || !info.IsDir() {
+		return "", ErrNoSuchBucket
+	}
+	return bucketPath, nil
+}
+
+func (b *localBackend) objectPath(bucket string, objectName string) (string, error) {
+	bucketPath, err := b.bucketPath(bucket)
+	if err != nil {
+		return "", err
+	}
+	objPath := filepath.Join(bucketPath, filepath.FromSlash(objectName))
+	if objectName == "" || !strings.HasPrefix(objPath, bucketPath+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object name '%s'", objectName)
+	}
+	return objPath, nil
+}
+
+func (b *localBackend) PutObject(ctx context.Context, bucket string, objectName string, buf []byte) (string, error) {
+	objPath, err := b.objectPath(bucket, objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(objPath), 0700); err != nil {
+		return "", err
+	}
+
+	// Write to a temp file and rename it into place so that readers never see a partial object
+	f, err := os.CreateTemp(filepath.Dir(objPath), localBackendTempPrefix)
+	if err != nil {
+		return "", err
+	}
+	tempPath := f.Name()
+	defer os.Remove(tempPath)
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tempPath, objPath); err != nil {
+		return "", err
+	}
+
+	return ComputeETag(buf), nil
+}
+
+func (b *localBackend) GetObject(ctx context.Context, bucket string, objectName string) ([]byte, error) {
+	objPath, err := b.objectPath(bucket, objectName)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := os.ReadFile(objPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoSuchObject
+	}
+	return buf, err
+}
+
+func (b *localBackend) GetObjectRange(ctx context.Context, bucket string, objectName string, offset int64, length int64) ([]byte, error) {
+	objPath, err := b.objectPath(bucket, objectName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(objPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoSuchObject
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func (b *localBackend) ListObjects(ctx context.Context, bucket string, prefix string, recursive bool) (map[string]int64, error) {
+	bucketPath, err := b.bucketPath(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only walk the deepest directory that prefix fully names
+	walkRoot := bucketPath
+	if idx := strings.LastIndex(prefix, "/"); idx != -1 {
+		walkRoot = filepath.Join(bucketPath, filepath.FromSlash(prefix[:idx]))
+	}
+
+	mAllObjects := make(map[string]int64, 0)
+	err = filepath.WalkDir(walkRoot, func(path string, dirent fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if dirent.IsDir() {
+			if recursive || path == walkRoot {
+				return nil
+			}
+			// A non-recursive listing stops at the first level below prefix, listing the dirs
+			// there as common prefixes rather than walking into them
+			relPath, err := filepath.Rel(bucketPath, path)
+			if err != nil {
+				return err
+			}
+			if dirPrefix := filepath.ToSlash(relPath) + "/"; strings.HasPrefix(dirPrefix, prefix) {
+				mAllObjects[dirPrefix] = 0
+			}
+			return fs.SkipDir
+		}
+		if strings.HasPrefix(dirent.Name(), localBackendTempPrefix) {
+			return nil
+		}
+		info, err := dirent.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(bucketPath, path)
+		if err != nil {
+			return err
+		}
+		mAllObjects[filepath.ToSlash(relPath)] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterObjectList(mAllObjects, prefix, recursive), nil
+}
+
+func (b *localBackend) DeleteObject(ctx context.Context, bucket string, objectName string) error {
+	objPath, err := b.objectPath(bucket, objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(objPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Remove any directories left empty, but never the bucket itself
+	bucketPath, _ := b.bucketPath(bucket)
+	for dir := filepath.Dir(objPath); dir != bucketPath; dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) RenameObject(ctx context.Context, bucket string, objectNameSrc string, objectNameDst string) error {
+	srcPath, err := b.objectPath(bucket, objectNameSrc)
+	if err != nil {
+		return err
+	}
+	dstPath, err := b.objectPath(bucket, objectNameDst)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0700); err != nil {
+		return err
+	}
+	return os.Rename(srcPath, dstPath)
+}
+
+func (b *localBackend) ListBuckets(ctx context.Context) ([]string, error) {
+	dirents, err := os.ReadDir(b.rootPath)
+	if err != nil {
+		return []string{}, err
+	}
+	ret := make([]string, 0)
+	for _, dirent := range dirents {
+		if dirent.IsDir() {
+			ret = append(ret, dirent.Name())
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (b *localBackend) MakeBucket(ctx context.Context, bucket string, region string) error {
+	if _, err := b.bucketPath(bucket); err == nil {
+		return fmt.Errorf("bucket '%s' already exists", bucket)
+	} else if !errors.Is(err, ErrNoSuchBucket) {
+		return err
+	}
+	return os.Mkdir(filepath.Join(b.rootPath, bucket), 0700)
+}
+
+// Applies S3 listing semantics to the complete list of objects mAllObjects: keeps only names
+// beginning with prefix and, if not recursive, collapses everything below the next "/" after
+// prefix into a single "subdirectory" entry.
+func filterObjectList(mAllObjects map[string]int64, prefix string, recursive bool) map[string]int64 {
+	mObjects := make(map[string]int64, 0)
+	for objName, size := range mAllObjects {
+		if !strings.HasPrefix(objName, prefix) {
+			continue
+		}
+		if !recursive {
+			rest := strings.TrimPrefix(objName, prefix)
+			if idx := strings.Index(rest, "/"); idx != -1 {
+				mObjects[prefix+rest[:idx+1]] = 0
+				continue
+			}
+		}
+		mObjects[objName] = size
+	}
+	return mObjects
+}
@@ -0,0 +1,122 @@
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Keeps all objects in memory; for tests.
+type MemBackend struct {
+	lock    sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func NewMemBackend() *MemBackend {
+	return &MemBackend{
+		buckets: make(map[string]map[string][]byte),
+	}
+}
+
+func (b *MemBackend) PutObject(ctx context.Context, bucket string, objectName string, buf []byte) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return "", ErrNoSuchBucket
+	}
+	objects[objectName] = append([]byte{}, buf...)
+	return ComputeETag(buf), nil
+}
+
+func (b *MemBackend) GetObject(ctx context.Context, bucket string, objectName string) ([]byte, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return nil, ErrNoSuchBucket
+	}
+	buf, ok := objects[objectName]
+	if !ok {
+		return nil, ErrNoSuchObject
+	}
+	return append([]byte{}, buf...), nil
+}
+
+func (b *MemBackend) GetObjectRange(ctx context.Context, bucket string, objectName string, offset int64, length int64) ([]byte, error) {
+	buf, err := b.GetObject(ctx, bucket, objectName)
+	if err != nil {
+		return nil, err
+	}
+	if offset > int64(len(buf)) {
+		return []byte{}, nil
+	}
+	end := offset + length
+	if end > int64(len(buf)) {
+		end = int64(len(buf))
+	}
+	return buf[offset:end], nil
+}
+
+func (b *MemBackend) ListObjects(ctx context.Context, bucket string, prefix string, recursive bool) (map[string]int64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return nil, ErrNoSuchBucket
+	}
+	mAllObjects := make(map[string]int64, len(objects))
+	for objName, buf := range objects {
+		mAllObjects[objName] = int64(len(buf))
+	}
+	return filterObjectList(mAllObjects, prefix, recursive), nil
+}
+
+func (b *MemBackend) DeleteObject(ctx context.Context, bucket string, objectName string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return ErrNoSuchBucket
+	}
+	delete(objects, objectName)
+	return nil
+}
+
+func (b *MemBackend) RenameObject(ctx context.Context, bucket string, objectNameSrc string, objectNameDst string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return ErrNoSuchBucket
+	}
+	buf, ok := objects[objectNameSrc]
+	if !ok {
+		return ErrNoSuchObject
+	}
+	objects[objectNameDst] = buf
+	delete(objects, objectNameSrc)
+	return nil
+}
+
+func (b *MemBackend) ListBuckets(ctx context.Context) ([]string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	ret := make([]string, 0, len(b.buckets))
+	for bucket := range b.buckets {
+		ret = append(ret, bucket)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (b *MemBackend) MakeBucket(ctx context.Context, bucket string, region string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.buckets[bucket]; ok {
+		return fmt.Errorf("bucket '%s' already exists", bucket)
+	}
+	b.buckets[bucket] = make(map[string][]byte)
+	return nil
+}
@@ -1,16 +1,11 @@
 package objstore
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"crypto/md5"
-	"crypto/tls"
 	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -19,8 +14,6 @@ import (
 
 	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/util"
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 const (
@@ -28,7 +21,7 @@ const (
 )
 
 type ObjStore struct {
-	minioClient *minio.Client
+	backend Backend
 }
 
 var (
@@ -42,21 +35,17 @@ var (
 )
 
 func NewObjStore(ctx context.Context, endpoint string, accessKeyId string, secretAccessKey string, isTrustSelfSignedCerts bool) *ObjStore {
-	// Initialize minio client object.
-	minioClient, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(accessKeyId, secretAccessKey, ""),
-		Secure: true,
-		Transport: &http.Transport{
-			DisableCompression: true,
-			TLSClientConfig:    &tls.Config{InsecureSkipVerify: isTrustSelfSignedCerts},
-		},
-	})
+	backend, err := NewBackend(ctx, endpoint, accessKeyId, secretAccessKey, isTrustSelfSignedCerts)
 	if err != nil {
 		log.Fatalln("error: NewObjStore: ", err)
 	}
 
+	return NewObjStoreWithBackend(backend)
+}
+
+func NewObjStoreWithBackend(backend Backend) *ObjStore {
 	return &ObjStore{
-		minioClient: minioClient,
+		backend: backend,
 	}
 }
 
@@ -71,17 +60,11 @@ func (os *ObjStore) IsReachable(ctx context.Context, bucket string, vlog *util.V
 }
 
 func (os *ObjStore) UploadObjFromBuffer(ctx context.Context, bucket string, objectName string, buffer []byte, expectedETag string) error {
-	// Upload the file
-	contentType := "application/octet-stream"
-
 	backoffSec := 5
 	maxBackoffSec := 5 * 60
 
 	for {
-		reader := bytes.NewReader(buffer)
-		info, err := os.minioClient.PutObject(ctx, bucket, objectName, reader, int64(len(buffer)), minio.PutObjectOptions{
-			ContentType: contentType,
-			PartSize:    ObjStoreMultiPartUploadPartSize})
+		eTag, err := os.backend.PutObject(ctx, bucket, objectName, buffer)
 		if err != nil {
 			// If network became unreachable, try an exponential backoff rather than just erroring out
 			if strings.Contains(err.Error(), "network is unreachable") {
@@ -101,8 +84,8 @@ func (os *ObjStore) UploadObjFromBuffer(ctx context.Context, bucket string, obje
 			atomic.AddInt64(&uploadBytes, int64(len(buffer)))
 			//log.Printf("notice: UploadObjFromBuffer: added %d bytes to upload byte counter", int64(len(buffer)))
 		}
-		if info.ETag != expectedETag {
-			log.Printf("error: UploadObjFromBuffer: ETag returned was '%s', expected '%s'", info.ETag, expectedETag)
+		if eTag != expectedETag {
+			log.Printf("error: UploadObjFromBuffer: ETag returned was '%s', expected '%s'", eTag, expectedETag)
 			return ErrUploadCorrupted
 		}
 		break
@@ -112,87 +95,86 @@ func (os *ObjStore) UploadObjFromBuffer(ctx context.Context, bucket string, obje
 }
 
 func (os *ObjStore) DownloadObjToBuffer(ctx context.Context, bucket string, objectName string) ([]byte, error) {
-	// Upload the file with FPutObject
-	reader, err := os.minioClient.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	ret, err := os.backend.GetObject(ctx, bucket, objectName)
 	if err != nil {
-		log.Println("error: DownloadObjToBuffer (GetObject): ", err)
+		log.Printf("error: DownloadObjToBuffer (GetObject on '%s'): %v", objectName, err)
 		return nil, err
 	}
-	defer reader.Close()
 
-	// Copy reader to byte array
-	var b bytes.Buffer
-	bufWriter := bufio.NewWriter(&b)
+	// Log the download bandwidth
+	atomic.AddInt64(&downloadBytes, int64(len(ret)))
+	//log.Printf("notice: DownloadObjToBuffer: added %d bytes to download byte counter", int64(len(ret)))
 
-	stat, err := reader.Stat()
-	if err != nil {
-		log.Printf("error: DownloadObjToBuffer (Stat on '%s'): %v", objectName, err)
-		return nil, err
+	return ret, nil
+}
+
+// Downloads only the length bytes starting at offset within the object
+func (os *ObjStore) DownloadObjRangeToBuffer(ctx context.Context, bucket string, objectName string, offset int64, length int64) ([]byte, error) {
+	if offset < 0 || length <= 0 {
+		msg := fmt.Sprintf("error: DownloadObjRangeToBuffer: invalid range (offset %d, length %d)", offset, length)
+		log.Println(msg)
+		return nil, fmt.Errorf(msg)
 	}
 
-	n, err := io.CopyN(bufWriter, reader, stat.Size)
+	ret, err := os.backend.GetObjectRange(ctx, bucket, objectName, offset, length)
 	if err != nil {
-		log.Println("error: DownloadObjToBuffer (CopyN): ", err)
+		log.Printf("error: DownloadObjRangeToBuffer (GetObjectRange on '%s'): %v", objectName, err)
 		return nil, err
 	}
-	ret := b.Bytes()
-
-	_ = n
-	//log.Printf("Successfully downloaded %d (=%d) bytes to buffer\n", n, len(ret))
 
 	// Log the download bandwidth
 	atomic.AddInt64(&downloadBytes, int64(len(ret)))
-	//log.Printf("notice: DownloadObjToBuffer: added %d bytes to download byte counter", int64(len(ret)))
 
 	return ret, nil
 }
 
 func (os *ObjStore) GetObjList(ctx context.Context, bucket string, prefix string, recursive bool, vlog *util.VLog) (map[string]int64, error) {
-	mObjects := make(map[string]int64, 0)
-
-	opts := minio.ListObjectsOptions{
-		Recursive: recursive,
-		Prefix:    prefix,
-	}
-
-	for object := range os.minioClient.ListObjects(ctx, bucket, opts) {
-		if object.Err != nil {
-			msg := fmt.Sprintf("warning: GetObjList (ListObjects): %v", object.Err)
-			if vlog != nil {
-				vlog.Println(msg)
-			} else {
-				log.Println(msg)
-			}
-			return nil, object.Err
+	mObjects, err := os.backend.ListObjects(ctx, bucket, prefix, recursive)
+	if err != nil {
+		msg := fmt.Sprintf("warning: GetObjList (ListObjects): %v", err)
+		if vlog != nil {
+			vlog.Println(msg)
+		} else {
+			log.Println(msg)
 		}
-		mObjects[object.Key] = object.Size
+		return nil, err
 	}
 
 	return mObjects, nil
 }
 
+// Returns the keys of a non-recursive listing of prefix, in sorted order
+func (os *ObjStore) listLevel(ctx context.Context, bucket string, prefix string) ([]string, error) {
+	m, err := os.backend.ListObjects(ctx, bucket, prefix, false)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
 // Gets only the top levels objects, i.e., all backup_name directories
 func (os *ObjStore) GetObjListTopLevel(ctx context.Context, bucket string, excludePrefixes []string) ([]string, error) {
 	objects := make([]string, 0)
 
-	opts := minio.ListObjectsOptions{
-		Recursive: false,
-		Prefix:    "",
+	keys, err := os.listLevel(ctx, bucket, "")
+	if err != nil {
+		log.Printf("warning: GetObjListTopLevel: %v", err)
+		return nil, err
 	}
-	for object := range os.minioClient.ListObjects(ctx, bucket, opts) {
-		if object.Err != nil {
-			log.Printf("warning: GetObjListTopTwoLevels: %v", object.Err)
-			return nil, object.Err
-		}
-
+	for _, key := range keys {
 		skip := false
 		for _, exclPrefix := range excludePrefixes {
-			if strings.HasPrefix(object.Key, exclPrefix) {
+			if strings.HasPrefix(key, exclPrefix) {
 				skip = true
 			}
 		}
 		if !skip {
-			objects = append(objects, util.StripTrailingSlashes(object.Key))
+			objects = append(objects, util.StripTrailingSlashes(key))
 		}
 	}
 
@@ -205,39 +187,32 @@ func (os *ObjStore) GetObjListTopTwoLevels(ctx context.Context, bucket string, e
 	mObjects := make(map[string][]string, 0)
 
 	// Get the top level
-	opts := minio.ListObjectsOptions{
-		Recursive: false,
-		Prefix:    "",
+	keys, err := os.listLevel(ctx, bucket, "")
+	if err != nil {
+		log.Printf("warning: GetObjListTopTwoLevels: %v", err)
+		return nil, err
 	}
-	for object := range os.minioClient.ListObjects(ctx, bucket, opts) {
-		if object.Err != nil {
-			log.Printf("warning: GetObjListTopTwoLevels: %v", object.Err)
-			return nil, object.Err
-		}
-
+	for _, key := range keys {
 		skip := false
 		for _, exclPrefix := range excludeTopLevelWithPrefixes {
-			if strings.HasPrefix(object.Key, exclPrefix) {
+			if strings.HasPrefix(key, exclPrefix) {
 				skip = true
 			}
 		}
 		if !skip {
-			mObjects[util.StripTrailingSlashes(object.Key)] = make([]string, 0)
+			mObjects[util.StripTrailingSlashes(key)] = make([]string, 0)
 		}
 	}
 
 	// Loop over all top level objects and get everything at the next level
 	for topLevelName := range mObjects {
-		subOpts := minio.ListObjectsOptions{
-			Recursive: false,
-			Prefix:    topLevelName + "/",
+		subKeys, err := os.listLevel(ctx, bucket, topLevelName+"/")
+		if err != nil {
+			log.Printf("warning: GetObjListTopTwoLevels: %v", err)
+			return nil, err
 		}
-		for subObject := range os.minioClient.ListObjects(ctx, bucket, subOpts) {
-			if subObject.Err != nil {
-				log.Printf("warning: GetObjListTopTwoLevels: %v", subObject.Err)
-				return nil, subObject.Err
-			}
-			subObjKey := strings.TrimPrefix(subObject.Key, topLevelName+"/")
+		for _, subKey := range subKeys {
+			subObjKey := strings.TrimPrefix(subKey, topLevelName+"/")
 
 			skip := false
 			for _, exclPrefix := range excludeSecondLevelWithPrefix {
@@ -255,9 +230,7 @@ func (os *ObjStore) GetObjListTopTwoLevels(ctx context.Context, bucket string, e
 }
 
 func (os *ObjStore) DeleteObj(ctx context.Context, bucket string, objectName string) error {
-	opts := minio.RemoveObjectOptions{}
-	err := os.minioClient.RemoveObject(context.Background(), bucket, objectName, opts)
-	return err
+	return os.backend.DeleteObject(ctx, bucket, objectName)
 }
 
 func (objst *ObjStore) RenameObj(ctx context.Context, bucket string, objectNameSrc string, objectNameDst string) error {
@@ -278,15 +251,7 @@ func (objst *ObjStore) RenameObj(ctx context.Context, bucket string, objectNameS
 		}
 	}
 
-	srcOpts := minio.CopySrcOptions{
-		Bucket: bucket,
-		Object: objectNameSrc,
-	}
-	dstOpts := minio.CopyDestOptions{
-		Bucket: bucket,
-		Object: objectNameDst,
-	}
-	_, err = objst.minioClient.CopyObject(ctx, dstOpts, srcOpts)
+	err = objst.backend.RenameObject(ctx, bucket, objectNameSrc, objectNameDst)
 	if err != nil {
 		return err
 	} else {
@@ -296,29 +261,15 @@ func (objst *ObjStore) RenameObj(ctx context.Context, bucket string, objectNameS
 		log.Printf("notice: RenameObj: added %d, %d bytes to up/download byte counters", objByteCnt, objByteCnt)
 	}
 
-	err = objst.DeleteObj(ctx, bucket, objectNameSrc)
-	return err
+	return nil
 }
 
 func (os *ObjStore) ListBuckets(ctx context.Context) ([]string, error) {
-	buckets, err := os.minioClient.ListBuckets(ctx)
-	if err != nil {
-		return []string{}, err
-	}
-	ret := make([]string, 0)
-	for _, bucketInfo := range buckets {
-		ret = append(ret, bucketInfo.Name)
-	}
-	sort.Strings(ret)
-	return ret, nil
+	return os.backend.ListBuckets(ctx)
 }
 
 func (os *ObjStore) MakeBucket(ctx context.Context, bucketName string, region string) error {
-	err := os.minioClient.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{Region: region, ObjectLocking: false})
-	if err != nil {
-		return err
-	}
-	return nil
+	return os.backend.MakeBucket(ctx, bucketName, region)
 }
 
 // Computes the expected ETag for the entire buffer buf
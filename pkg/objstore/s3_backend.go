@@ -0,0 +1,132 @@
+package objstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type s3Backend struct {
+	minioClient *minio.Client
+}
+
+func newS3Backend(endpoint string, accessKeyId string, secretAccessKey string, isTrustSelfSignedCerts bool) (*s3Backend, error) {
+	// Initialize minio client object.
+	minioClient, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKeyId, secretAccessKey, ""),
+		Secure: true,
+		Transport: &http.Transport{
+			DisableCompression: true,
+			TLSClientConfig:    &tls.Config{InsecureSkipVerify: isTrustSelfSignedCerts},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Backend{
+		minioClient: minioClient,
+	}, nil
+}
+
+func (b *s3Backend) PutObject(ctx context.Context, bucket string, objectName string, buf []byte) (string, error) {
+	reader := bytes.NewReader(buf)
+	info, err := b.minioClient.PutObject(ctx, bucket, objectName, reader, int64(len(buf)), minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+		PartSize:    ObjStoreMultiPartUploadPartSize})
+	if err != nil {
+		return "", err
+	}
+	return info.ETag, nil
+}
+
+func (b *s3Backend) GetObject(ctx context.Context, bucket string, objectName string) ([]byte, error) {
+	return b.getObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+}
+
+func (b *s3Backend) GetObjectRange(ctx context.Context, bucket string, objectName string, offset int64, length int64) ([]byte, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+	return b.getObject(ctx, bucket, objectName, opts)
+}
+
+func (b *s3Backend) getObject(ctx context.Context, bucket string, objectName string, opts minio.GetObjectOptions) ([]byte, error) {
+	reader, err := b.minioClient.GetObject(ctx, bucket, objectName, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Copy reader to byte array
+	var buf bytes.Buffer
+	bufWriter := bufio.NewWriter(&buf)
+	if _, err = io.Copy(bufWriter, reader); err != nil {
+		return nil, err
+	}
+	if err = bufWriter.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *s3Backend) ListObjects(ctx context.Context, bucket string, prefix string, recursive bool) (map[string]int64, error) {
+	mObjects := make(map[string]int64, 0)
+
+	opts := minio.ListObjectsOptions{
+		Recursive: recursive,
+		Prefix:    prefix,
+	}
+	for object := range b.minioClient.ListObjects(ctx, bucket, opts) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		mObjects[object.Key] = object.Size
+	}
+
+	return mObjects, nil
+}
+
+func (b *s3Backend) DeleteObject(ctx context.Context, bucket string, objectName string) error {
+	return b.minioClient.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{})
+}
+
+func (b *s3Backend) RenameObject(ctx context.Context, bucket string, objectNameSrc string, objectNameDst string) error {
+	srcOpts := minio.CopySrcOptions{
+		Bucket: bucket,
+		Object: objectNameSrc,
+	}
+	dstOpts := minio.CopyDestOptions{
+		Bucket: bucket,
+		Object: objectNameDst,
+	}
+	if _, err := b.minioClient.CopyObject(ctx, dstOpts, srcOpts); err != nil {
+		return err
+	}
+	return b.DeleteObject(ctx, bucket, objectNameSrc)
+}
+
+func (b *s3Backend) ListBuckets(ctx context.Context) ([]string, error) {
+	buckets, err := b.minioClient.ListBuckets(ctx)
+	if err != nil {
+		return []string{}, err
+	}
+	ret := make([]string, 0)
+	for _, bucketInfo := range buckets {
+		ret = append(ret, bucketInfo.Name)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+func (b *s3Backend) MakeBucket(ctx context.Context, bucket string, region string) error {
+	return b.minioClient.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region, ObjectLocking: false})
+}
@@ -0,0 +1,108 @@
+package objstore
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+var (
+	ErrWriteOnlyAlreadyEnabled     = errors.New("the bucket is already in write-only mode")
+	ErrWrongPrivateKeyPassphrase   = errors.New("the private key passphrase is wrong")
+	ErrPrivateKeyPassphraseIsBlank = errors.New("the private key passphrase cannot be blank")
+)
+
+func (bMdata *BucketMetadata) IsWriteOnly() bool {
+	return bMdata.SealPublicKeyB64 != ""
+}
+
+// Puts the bucket in write-only mode: generates an X25519 key pair, stores its public key in the
+// bucket metadata and its private key encrypted under privateKeyPassphrase. From then on, chunks
+// and indexes are sealed to the public key and reading them back needs privateKeyPassphrase.
+func (objst *ObjStore) EnableWriteOnlyMode(ctx context.Context, bucket string, privateKeyPassphrase string, vlog *util.VLog) error {
+	if privateKeyPassphrase == "" {
+		return ErrPrivateKeyPassphraseIsBlank
+	}
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: EnableWriteOnlyMode: cannot read bucket metadata file: ", err)
+		return err
+	}
+	if bMdata.IsWriteOnly() {
+		return ErrWriteOnlyAlreadyEnabled
+	}
+	if bMdata.IsRotatingKey() {
+		log.Println("error: EnableWriteOnlyMode: ", ErrKeyRotationInProgress)
+		return ErrKeyRotationInProgress
+	}
+
+	sealKeys, err := cryptography.GenerateSealKeys()
+	if err != nil {
+		log.Println("error: EnableWriteOnlyMode: cannot generate key pair: ", err)
+		return err
+	}
+	salt := util.GenerateRandomSalt()
+	kdfParams := kdfParamsOrDefault(bMdata.Kdf)
+	pdKey, err := cryptography.DeriveKeyWithParams(salt, privateKeyPassphrase, kdfParams)
+	if err != nil {
+		log.Println("error: EnableWriteOnlyMode: could not derive pdKey: ", err)
+		return err
+	}
+	encryptedPrivateKey, err := cryptography.EncryptBuffer(pdKey, sealKeys.PrivateKey)
+	if err != nil {
+		log.Println("error: EnableWriteOnlyMode: cannot encrypt private key: ", err)
+		return err
+	}
+
+	bMdata.SealPublicKeyB64 = base64.URLEncoding.EncodeToString(sealKeys.PublicKey)
+	bMdata.SealPrivateKeySalt = salt
+	bMdata.SealPrivateKeyKdf = &kdfParams
+	bMdata.EncryptedSealPrivateKeyB64 = base64.URLEncoding.EncodeToString(encryptedPrivateKey)
+	if err := objst.writeBucketMetadataFile(ctx, bucket, bMdata, vlog); err != nil {
+		log.Println("error: EnableWriteOnlyMode: cannot write bucket metadata file: ", err)
+		return err
+	}
+	return nil
+}
+
+// Returns the bucket's write-only key pair, or nil if the bucket is not in write-only mode. The
+// private key is only unlocked if privateKeyPassphrase is non-blank.
+func (objst *ObjStore) GetSealKeys(ctx context.Context, bucket string, privateKeyPassphrase string) (*cryptography.SealKeys, error) {
+	bMdata, err := objst.downloadBucketMetadataFile(ctx, bucket)
+	if err != nil {
+		log.Println("error: GetSealKeys: cannot read bucket metadata file: ", err)
+		return nil, err
+	}
+	if !bMdata.IsWriteOnly() {
+		return nil, nil
+	}
+
+	publicKey, err := base64.URLEncoding.DecodeString(bMdata.SealPublicKeyB64)
+	if err != nil {
+		log.Println("error: GetSealKeys: could not base64 decode public key: ", err)
+		return nil, err
+	}
+	sealKeys := &cryptography.SealKeys{PublicKey: publicKey}
+	if privateKeyPassphrase == "" {
+		return sealKeys, nil
+	}
+
+	encryptedPrivateKey, err := base64.URLEncoding.DecodeString(bMdata.EncryptedSealPrivateKeyB64)
+	if err != nil {
+		log.Println("error: GetSealKeys: could not base64 decode encrypted private key: ", err)
+		return nil, err
+	}
+	pdKey, err := cryptography.DeriveKeyWithParams(bMdata.SealPrivateKeySalt, privateKeyPassphrase, kdfParamsOrDefault(bMdata.SealPrivateKeyKdf))
+	if err != nil {
+		log.Println("error: GetSealKeys: could not derive pdKey: ", err)
+		return nil, err
+	}
+	if sealKeys.PrivateKey, err = cryptography.DecryptBuffer(pdKey, encryptedPrivateKey); err != nil {
+		return nil, ErrWrongPrivateKeyPassphrase
+	}
+	return sealKeys, nil
+}
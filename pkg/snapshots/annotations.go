@@ -0,0 +1,232 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+)
+
+// User-supplied tags and the pinned flag of a snapshot. These live in an encrypted object next to
+// the snapshot's index file, at <enc backup name>/!<enc snapshot name>. Pinned snapshots are
+// always kept by prune and can only be deleted with force.
+type SnapshotAnnotations struct {
+	Tags   []string `json:"tags,omitempty"`
+	Pinned bool     `json:"pinned,omitempty"`
+}
+
+func (a *SnapshotAnnotations) IsEmpty() bool {
+	return len(a.Tags) == 0 && !a.Pinned
+}
+
+func (a *SnapshotAnnotations) HasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds and removes tags, keeping the list sorted and free of duplicates
+func (a *SnapshotAnnotations) UpdateTags(addTags []string, removeTags []string) error {
+	for _, tag := range addTags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return fmt.Errorf("tags cannot be blank")
+		}
+		if !a.HasTag(tag) {
+			a.Tags = append(a.Tags, tag)
+		}
+	}
+	for _, tag := range removeTags {
+		tag = strings.TrimSpace(tag)
+		for i := range a.Tags {
+			if a.Tags[i] == tag {
+				a.Tags = append(a.Tags[:i], a.Tags[i+1:]...)
+				break
+			}
+		}
+	}
+	sort.Strings(a.Tags)
+	return nil
+}
+
+func getAnnotationsObjName(key []byte, backupName string, snapshotName string) (string, error) {
+	encBackupName, err := cryptography.EncryptFilename(key, backupName)
+	if err != nil {
+		return "", fmt.Errorf("could not encrypt backup name (%s): %v", backupName, err)
+	}
+	encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+	if err != nil {
+		return "", fmt.Errorf("could not encrypt snapshot name (%s): %v", snapshotName, err)
+	}
+	return encBackupName + "/!" + encSnapshotName, nil
+}
+
+func downloadAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, objName string) (*SnapshotAnnotations, error) {
+	encBuf, err := objst.DownloadObjToBuffer(ctx, bucket, objName)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := cryptography.DecryptBuffer(key, encBuf)
+	if err != nil {
+		return nil, err
+	}
+	var a SnapshotAnnotations
+	if err = json.Unmarshal(buf, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Returns the annotations of a snapshot, which are empty if it has never been tagged or pinned
+func GetSnapshotAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, backupName string, snapshotName string) (*SnapshotAnnotations, error) {
+	objName, err := getAnnotationsObjName(key, backupName, snapshotName)
+	if err != nil {
+		log.Println("error: GetSnapshotAnnotations: ", err)
+		return nil, err
+	}
+
+	m, err := objst.GetObjList(ctx, bucket, objName, false, nil)
+	if err != nil {
+		log.Println("error: GetSnapshotAnnotations: GetObjList: ", err)
+		return nil, err
+	}
+	if _, ok := m[objName]; !ok {
+		return &SnapshotAnnotations{}, nil
+	}
+
+	a, err := downloadAnnotations(ctx, objst, bucket, key, objName)
+	if err != nil {
+		log.Printf("error: GetSnapshotAnnotations: could not read annotations for '%s/%s': %v", backupName, snapshotName, err)
+		return nil, err
+	}
+	return a, nil
+}
+
+// Saves the annotations of a snapshot, or deletes the annotations object if they are empty
+func WriteSnapshotAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, backupName string, snapshotName string, a *SnapshotAnnotations) error {
+	objName, err := getAnnotationsObjName(key, backupName, snapshotName)
+	if err != nil {
+		log.Println("error: WriteSnapshotAnnotations: ", err)
+		return err
+	}
+
+	if a.IsEmpty() {
+		if err = objst.DeleteObj(ctx, bucket, objName); err != nil {
+			log.Println("error: WriteSnapshotAnnotations: DeleteObj: ", err)
+			return err
+		}
+		return nil
+	}
+
+	buf, err := json.Marshal(a)
+	if err != nil {
+		log.Println("error: WriteSnapshotAnnotations: marshal failed: ", err)
+		return err
+	}
+	encBuf, err := cryptography.EncryptBuffer(key, buf)
+	if err != nil {
+		log.Println("error: WriteSnapshotAnnotations: EncryptBuffer: ", err)
+		return err
+	}
+	if err = objst.UploadObjFromBuffer(ctx, bucket, objName, encBuf, objstore.ComputeETag(encBuf)); err != nil {
+		log.Println("error: WriteSnapshotAnnotations: UploadObjFromBuffer: ", err)
+		return err
+	}
+	return nil
+}
+
+// Returns the annotations of every snapshot in backup encBackupName that has any, keyed by
+// plaintext snapshot name
+func getBackupAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, encBackupName string) (map[string]*SnapshotAnnotations, error) {
+	mRet := make(map[string]*SnapshotAnnotations)
+
+	mObjs, err := objst.GetObjList(ctx, bucket, encBackupName+"/!", false, nil)
+	if err != nil {
+		log.Println("error: getBackupAnnotations: GetObjList: ", err)
+		return nil, err
+	}
+	for objName := range mObjs {
+		encSnapshotName := strings.TrimPrefix(objName, encBackupName+"/!")
+		snapshotName, err := cryptography.DecryptFilename(key, encSnapshotName)
+		if err != nil {
+			log.Printf("error: getBackupAnnotations: could not decrypt snapshot name '%s': %v", encSnapshotName, err)
+			return nil, err
+		}
+		a, err := downloadAnnotations(ctx, objst, bucket, key, objName)
+		if err != nil {
+			log.Printf("error: getBackupAnnotations: could not read annotations object '%s': %v", objName, err)
+			return nil, err
+		}
+		mRet[snapshotName] = a
+	}
+	return mRet, nil
+}
+
+// Returns the annotations of every annotated snapshot, keyed by raw snapshot name ("backup/snapshot")
+func GetAllSnapshotAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte) (map[string]*SnapshotAnnotations, error) {
+	topLevelObjs, err := objst.GetObjListTopLevel(ctx, bucket, []string{"metadata", "chunks"})
+	if err != nil {
+		log.Println("error: GetAllSnapshotAnnotations: objst.GetObjListTopLevel: ", err)
+		return nil, err
+	}
+
+	mRet := make(map[string]*SnapshotAnnotations)
+	for _, encBackupName := range topLevelObjs {
+		backupName, err := cryptography.DecryptFilename(key, encBackupName)
+		if err != nil {
+			log.Println("error: GetAllSnapshotAnnotations: DecryptFilename: ", err)
+			return nil, err
+		}
+		mAnnotations, err := getBackupAnnotations(ctx, objst, bucket, key, encBackupName)
+		if err != nil {
+			return nil, err
+		}
+		for snapshotName, a := range mAnnotations {
+			mRet[backupName+"/"+snapshotName] = a
+		}
+	}
+	return mRet, nil
+}
+
+// Reads the annotations of an existing snapshot, applies updateFunc to them and saves the result
+func UpdateSnapshotAnnotations(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, backupName string, snapshotName string, updateFunc func(a *SnapshotAnnotations) error) (*SnapshotAnnotations, error) {
+	encBackupName, err := cryptography.EncryptFilename(key, backupName)
+	if err != nil {
+		log.Printf("error: UpdateSnapshotAnnotations: could not encrypt backup name (%s): %v", backupName, err)
+		return nil, err
+	}
+	encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+	if err != nil {
+		log.Printf("error: UpdateSnapshotAnnotations: could not encrypt snapshot name (%s): %v", snapshotName, err)
+		return nil, err
+	}
+	indexObjName := encBackupName + "/@" + encSnapshotName
+	m, err := objst.GetObjList(ctx, bucket, indexObjName, false, nil)
+	if err != nil {
+		log.Println("error: UpdateSnapshotAnnotations: GetObjList: ", err)
+		return nil, err
+	}
+	if _, ok := m[indexObjName]; !ok {
+		return nil, fmt.Errorf("snapshot %s/%s does not exist", backupName, snapshotName)
+	}
+
+	a, err := GetSnapshotAnnotations(ctx, objst, bucket, key, backupName, snapshotName)
+	if err != nil {
+		return nil, err
+	}
+	if err = updateFunc(a); err != nil {
+		return nil, err
+	}
+	if err = WriteSnapshotAnnotations(ctx, objst, bucket, key, backupName, snapshotName, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
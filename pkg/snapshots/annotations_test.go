@@ -0,0 +1,96 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotAnnotations(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+
+	encBackupName, err := cryptography.EncryptFilename(key, "Documents")
+	assert.NoError(t, err)
+	for _, snapshotName := range []string{"2022-01-01_01.01.01", "2022-01-02_01.01.01"} {
+		encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+		assert.NoError(t, err)
+		snapshotObj := &Snapshot{DecryptedName: snapshotName, RelPaths: map[string]CloudRelPath{}}
+		_, err = SerializeAndWriteSnapshotObj(snapshotObj, key, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+		assert.NoError(t, err)
+	}
+
+	// Tags are sorted and deduplicated
+	a, err := UpdateSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-01_01.01.01", func(a *SnapshotAnnotations) error {
+		return a.UpdateTags([]string{"upgrade", "before", "upgrade"}, nil)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"before", "upgrade"}, a.Tags)
+	_, err = UpdateSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-01_01.01.01", func(a *SnapshotAnnotations) error {
+		a.Pinned = true
+		return a.UpdateTags(nil, []string{"before"})
+	})
+	assert.NoError(t, err)
+
+	a, err = GetSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	assert.Equal(t, &SnapshotAnnotations{Tags: []string{"upgrade"}, Pinned: true}, a)
+	a, err = GetSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-02_01.01.01")
+	assert.NoError(t, err)
+	assert.True(t, a.IsEmpty())
+
+	// Cannot annotate a snapshot that does not exist
+	_, err = UpdateSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-03_01.01.01", func(a *SnapshotAnnotations) error {
+		a.Pinned = true
+		return nil
+	})
+	assert.Error(t, err)
+
+	// The annotations object is not mistaken for a snapshot
+	mSnapshots, err := GetAllSnapshotInfos(ctx, key, objst, bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(mSnapshots["Documents"]))
+	assert.True(t, mSnapshots["Documents"][0].Pinned)
+	assert.False(t, mSnapshots["Documents"][1].Pinned)
+
+	mAnnotations, err := GetAllSnapshotAnnotations(ctx, objst, bucket, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mAnnotations))
+	assert.True(t, mAnnotations["Documents/2022-01-01_01.01.01"].HasTag("upgrade"))
+
+	// Pinned snapshots are only deleted with force, and take their annotations with them
+	ssDel := []SnapshotForDeletion{{BackupDirName: "Documents", SnapshotName: "2022-01-01_01.01.01"}}
+	assert.Error(t, DeleteSnapshots(ctx, key, hmacKey, nil, ssDel, objst, bucket, false, vlog, nil, nil))
+	mSnapshots, err = GetAllSnapshotInfos(ctx, key, objst, bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(mSnapshots["Documents"]))
+
+	assert.NoError(t, DeleteSnapshots(ctx, key, hmacKey, nil, ssDel, objst, bucket, true, vlog, nil, nil))
+	mAnnotations, err = GetAllSnapshotAnnotations(ctx, objst, bucket, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(mAnnotations))
+
+	// Clearing all annotations removes the object
+	_, err = UpdateSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-02_01.01.01", func(a *SnapshotAnnotations) error {
+		return a.UpdateTags([]string{"x"}, nil)
+	})
+	assert.NoError(t, err)
+	_, err = UpdateSnapshotAnnotations(ctx, objst, bucket, key, "Documents", "2022-01-02_01.01.01", func(a *SnapshotAnnotations) error {
+		return a.UpdateTags(nil, []string{"x"})
+	})
+	assert.NoError(t, err)
+	m, err := objst.GetObjList(ctx, bucket, encBackupName+"/!", false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(m))
+}
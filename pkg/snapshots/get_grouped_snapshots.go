@@ -17,6 +17,24 @@ type ChunkExtent struct {
 	ChunkName string
 	Offset    int64
 	Len       int64
+
+	// Non-zero if this extent was encrypted on its own within a packed chunk. Offset and EncLen
+	// then locate its ciphertext within the chunk object (so it can be fetched with a ranged
+	// download) and Len is the length of its plaintext. If zero, the whole chunk object is one
+	// ciphertext and Offset/Len locate the extent within its plaintext.
+	EncLen int64 `json:",omitempty"`
+
+	// True if the ciphertext of a packed entry was encrypted with cryptography.PackedEntryAad,
+	// binding it to ChunkName and Offset. Entries packed before this existed have no such binding.
+	BoundToChunk bool `json:",omitempty"`
+}
+
+// Returns the additional data the extent's ciphertext was encrypted with, or nil if none
+func (ce ChunkExtent) Aad() []byte {
+	if !ce.BoundToChunk {
+		return nil
+	}
+	return cryptography.PackedEntryAad(ce.ChunkName, ce.Offset)
 }
 
 type CloudRelPath struct {
@@ -75,7 +93,31 @@ func (bd BackupDir) GetMostRecentSnapshot() *Snapshot {
 type SetInitialGetGroupedSnapshotsProgress func(finished int64, total int64)
 type UpdateGetGroupedSnapshotsProgress func(finished int64, total int64)
 
-func GetGroupedSnapshots(ctx context.Context, objst *objstore.ObjStore, key []byte, bucket string, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) (map[string]BackupDir, error) {
+func GetGroupedSnapshots(ctx context.Context, objst *objstore.ObjStore, key []byte, sealKeys *cryptography.SealKeys, bucket string, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) (map[string]BackupDir, error) {
+	return getGroupedSnapshots(ctx, objst, key, sealKeys, bucket, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc, nil)
+}
+
+// An index file that could not be retrieved, decrypted or parsed
+type BadIndexFile struct {
+	EncObjName string // name of the object in the bucket
+	Name       string // backupName/snapshotName, or whatever part of it could be decrypted
+	Err        error
+}
+
+// Like GetGroupedSnapshots, but instead of failing on a backup or snapshot whose index file cannot be
+// retrieved, decrypted or parsed, skips it and returns it in badIndexFiles
+func GetGroupedSnapshotsSkippingBadIndexes(ctx context.Context, objst *objstore.ObjStore, key []byte, sealKeys *cryptography.SealKeys, bucket string, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) (groupedObjects map[string]BackupDir, badIndexFiles []BadIndexFile, err error) {
+	badIndexFiles = make([]BadIndexFile, 0)
+	groupedObjects, err = getGroupedSnapshots(ctx, objst, key, sealKeys, bucket, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc, &badIndexFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return groupedObjects, badIndexFiles, nil
+}
+
+// If badIndexFiles is nil, fails on the first index file that cannot be read; otherwise appends it
+// to badIndexFiles and carries on
+func getGroupedSnapshots(ctx context.Context, objst *objstore.ObjStore, key []byte, sealKeys *cryptography.SealKeys, bucket string, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress, badIndexFiles *[]BadIndexFile) (map[string]BackupDir, error) {
 	// setup return map
 	ret := make(map[string]BackupDir)
 
@@ -112,6 +154,10 @@ func GetGroupedSnapshots(ctx context.Context, objst *objstore.ObjStore, key []by
 		backupName, err := cryptography.DecryptFilename(key, encBackupName)
 		if err != nil {
 			log.Printf("error: GetGroupedSnapshots: could not decrypt backup dir name (%s): %v\n", encBackupName, err)
+			if badIndexFiles != nil {
+				*badIndexFiles = append(*badIndexFiles, BadIndexFile{EncObjName: encBackupName, Name: "", Err: err})
+				continue
+			}
 			return nil, err
 		}
 
@@ -138,22 +184,31 @@ func GetGroupedSnapshots(ctx context.Context, objst *objstore.ObjStore, key []by
 			ssName, err := cryptography.DecryptFilename(key, encSsName)
 			if err != nil {
 				log.Printf("error: GetGroupedSnapshots: could not decrypt snapshot name (%s) - skipping: %v\n", encSsName, err)
+				if badIndexFiles != nil {
+					*badIndexFiles = append(*badIndexFiles, BadIndexFile{EncObjName: encObjName, Name: backupName + "/", Err: err})
+				}
 				continue
 			}
 
-			plaintextIndexFileBuf, err := GetSnapshotIndexFile(ctx, objst, bucket, key, encObjName)
-			if err != nil {
-				log.Printf("error: GetGroupedSnapshots: could not retrieve snapshot index file (%s) - skipping: %v\n", ssName, err)
-				return nil, err
+			plaintextIndexFileBuf, err := GetSnapshotIndexFile(ctx, objst, bucket, key, sealKeys, encObjName)
+			if err == nil {
+				// reconstruct object hierarchy for this snapshot, placing into BackupDir objects in map
+				var ssObj *Snapshot
+				ssObj, err = UnmarshalSnapshotObj(plaintextIndexFileBuf)
+				if err == nil {
+					ret[backupName].Snapshots[ssName] = *ssObj
+				} else {
+					log.Printf("error: GetGroupedSnapshots: could not get snapshot obj for '%s': %v\n", ssName, err)
+				}
+			} else {
+				log.Printf("error: GetGroupedSnapshots: could not retrieve snapshot index file (%s): %v\n", ssName, err)
 			}
-
-			// reconstruct object hierarchy for this snapshot, placing into BackupDir objects in map
-			ssObj, err := UnmarshalSnapshotObj(plaintextIndexFileBuf)
 			if err != nil {
-				log.Printf("error: GetGroupedSnapshots: could not get snapshot obj for '%s': %v\n", ssName, err)
-				return nil, err
+				if badIndexFiles == nil {
+					return nil, err
+				}
+				*badIndexFiles = append(*badIndexFiles, BadIndexFile{EncObjName: encObjName, Name: backupName + "/" + ssName, Err: err})
 			}
-			ret[backupName].Snapshots[ssName] = *ssObj
 
 			// update progress if callback supplied
 			finishedSnapshotIndices += 1
@@ -177,7 +232,7 @@ func UnmarshalSnapshotObj(indexFileJsonBuf []byte) (*Snapshot, error) {
 	return &ssObj, nil
 }
 
-func GetSnapshotIndexFile(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, encObjName string) ([]byte, error) {
+func GetSnapshotIndexFile(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, sealKeys *cryptography.SealKeys, encObjName string) ([]byte, error) {
 	// download actual snapshot file
 	buf, err := objst.DownloadObjToBuffer(ctx, bucket, encObjName)
 	if err != nil {
@@ -186,7 +241,7 @@ func GetSnapshotIndexFile(ctx context.Context, objst *objstore.ObjStore, bucket
 	}
 
 	// decrypt and uncompress snapshot file
-	plaintextIndexFileBuf, err := decryptIndexFile(key, buf)
+	plaintextIndexFileBuf, err := decryptIndexFile(key, sealKeys, buf)
 	if err != nil {
 		log.Printf("error: getAllSnapshotIndices: could not decrypt+uncompress snapshot index file '%s': %v\n", encObjName, err)
 		return nil, err
@@ -195,9 +250,44 @@ func GetSnapshotIndexFile(ctx context.Context, objst *objstore.ObjStore, bucket
 	return plaintextIndexFileBuf, nil
 }
 
-func decryptIndexFile(key []byte, encBuf []byte) ([]byte, error) {
+// Downloads and parses the index of a single snapshot, given its plaintext backup and snapshot names
+func GetSnapshot(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, sealKeys *cryptography.SealKeys, backupName string, snapshotName string) (*Snapshot, error) {
+	encBackupName, err := cryptography.EncryptFilename(key, backupName)
+	if err != nil {
+		log.Printf("error: GetSnapshot: cannot encrypt backup name '%s': %v", backupName, err)
+		return nil, err
+	}
+	encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+	if err != nil {
+		log.Printf("error: GetSnapshot: cannot encrypt snapshot name '%s': %v", snapshotName, err)
+		return nil, err
+	}
+
+	ssIndexJson, err := GetSnapshotIndexFile(ctx, objst, bucket, key, sealKeys, encBackupName+"/@"+encSnapshotName)
+	if err != nil {
+		log.Printf("error: GetSnapshot: cannot get snapshot index file for '%s/%s': %v", backupName, snapshotName, err)
+		return nil, err
+	}
+	snapshotObj, err := UnmarshalSnapshotObj(ssIndexJson)
+	if err != nil {
+		log.Printf("error: GetSnapshot: cannot parse snapshot index file for '%s/%s': %v", backupName, snapshotName, err)
+		return nil, err
+	}
+	if snapshotObj.DecryptedName == "" {
+		snapshotObj.DecryptedName = snapshotName
+	}
+	if snapshotObj.Datetime.IsZero() {
+		if t, err := time.Parse("2006-01-02_15.04.05", snapshotName); err == nil {
+			snapshotObj.Datetime = t
+		}
+	}
+
+	return snapshotObj, nil
+}
+
+func decryptIndexFile(key []byte, sealKeys *cryptography.SealKeys, encBuf []byte) ([]byte, error) {
 	// Decrypt
-	decBuf, err := cryptography.DecryptBuffer(key, encBuf)
+	decBuf, _, err := cryptography.DecryptDataBuffer(key, sealKeys, encBuf)
 	if err != nil {
 		log.Println("error: decryptAndUncompressIndexFile: could not decrypt snapshot index file: ", err)
 		return nil, err
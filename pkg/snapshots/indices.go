@@ -14,7 +14,7 @@ import (
 	"github.com/fsctl/tless/pkg/util"
 )
 
-func WriteIndexFile(ctx context.Context, dbLock *sync.Mutex, db *database.DB, objst *objstore.ObjStore, bucket string, key []byte, backupDirName string, snapshotName string) error {
+func WriteIndexFile(ctx context.Context, dbLock *sync.Mutex, db *database.DB, objst *objstore.ObjStore, bucket string, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, backupDirName string, snapshotName string) error {
 	// Get encrypted snapshot name and backup dir
 	encryptedSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
 	if err != nil {
@@ -61,27 +61,38 @@ func WriteIndexFile(ctx context.Context, dbLock *sync.Mutex, db *database.DB, ob
 		}
 	}
 
-	if err = SerializeAndWriteSnapshotObj(&snapshotObj, key, encryptedBackupDirName, encryptedSnapshotName, objst, ctx, bucket); err != nil {
+	paddingLen, err := SerializeAndWriteSnapshotObj(&snapshotObj, key, hmacKey, sealKeys, padding, encryptedBackupDirName, encryptedSnapshotName, objst, ctx, bucket)
+	if err != nil {
 		log.Println("error: writeIndexFile: SerializeAndSaveSnapshotObj failed: ", err)
 		return err
 	}
+	if paddingLen > 0 {
+		util.LockIf(dbLock)
+		err = db.AddObjectPadding(encryptedBackupDirName+"/@"+encryptedSnapshotName, paddingLen)
+		util.UnlockIf(dbLock)
+		if err != nil {
+			log.Println("error: writeIndexFile: could not record padding: ", err)
+		}
+	}
 
 	return nil
 }
 
-func SerializeAndWriteSnapshotObj(snapshotObj *Snapshot, key []byte, encryptedBackupDirName string, encryptedSnapshotName string, objst *objstore.ObjStore, ctx context.Context, bucket string) error {
+// Uploads snapshotObj as the index of encryptedBackupDirName/encryptedSnapshotName and records it
+// in the manifest. Returns the number of bytes of padding the index was uploaded with.
+func SerializeAndWriteSnapshotObj(snapshotObj *Snapshot, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, padding cryptography.Padding, encryptedBackupDirName string, encryptedSnapshotName string, objst *objstore.ObjStore, ctx context.Context, bucket string) (int64, error) {
 	// Serialize fully linked snapshot obj to json bytes
 	buf, err := json.Marshal(snapshotObj)
 	if err != nil {
 		log.Println("error: SerializeAndSaveSnapshotObj: marshal failed: ", err)
-		return err
+		return 0, err
 	}
 
-	// encrypt the json
-	encBuf, err := cryptography.EncryptBuffer(key, buf)
+	// encrypt the json, padded so that its size does not give away the number of files
+	encBuf, paddingLen, err := cryptography.EncryptPaddedDataBuffer(key, sealKeys, buf, true, padding)
 	if err != nil {
-		log.Println("error: SerializeAndSaveSnapshotObj: EncryptBuffer: ", err)
-		return err
+		log.Println("error: SerializeAndSaveSnapshotObj: EncryptPaddedDataBuffer: ", err)
+		return 0, err
 	}
 
 	// form the obj name of index file (enc backup dir / '@' + enc snapshhot name)
@@ -91,8 +102,14 @@ func SerializeAndWriteSnapshotObj(snapshotObj *Snapshot, key []byte, encryptedBa
 	err = objst.UploadObjFromBuffer(ctx, bucket, objName, encBuf, objstore.ComputeETag(encBuf))
 	if err != nil {
 		log.Println("error: SerializeAndSaveSnapshotObj: UploadObjFromBuffer: ", err)
-		return err
+		return 0, err
 	}
 
-	return nil
+	// record it in the manifest
+	if err = addIndexToManifest(ctx, objst, bucket, hmacKey, objName, encBuf); err != nil {
+		log.Println("error: SerializeAndSaveSnapshotObj: addIndexToManifest: ", err)
+		return 0, err
+	}
+
+	return paddingLen, nil
 }
@@ -0,0 +1,326 @@
+package snapshots
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Starts with "metadata" so that listings which skip the bucket metadata skip the manifest too
+const ManifestObjName = "metadata.manifest"
+
+var (
+	ErrManifestBadMac  = errors.New("the snapshot manifest failed authentication")
+	ErrManifestMissing = errors.New("the snapshot manifest is missing from a bucket that has snapshots")
+)
+
+// Serializes the manifest updates made by this process, which would otherwise lose each other's
+// changes when the daemon writes and deletes snapshots at the same time
+var manifestLock sync.Mutex
+
+// Lists every snapshot index in the bucket, so that a cloud provider silently deleting snapshots
+// or replacing the bucket with an older copy can be detected. Version goes up by one on every
+// change. The manifest is stored in plaintext (it only holds names and hashes the provider can
+// see anyway) and authenticated with the HMAC key, which is never rotated.
+type Manifest struct {
+	Version uint64
+	Indexes map[string]string // index object name => hex SHA-256 of the object
+}
+
+type signedManifest struct {
+	Manifest []byte
+	Mac      []byte
+}
+
+func computeManifestMac(hmacKey []byte, buf []byte) []byte {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write([]byte("tless manifest v1"))
+	mac.Write(buf)
+	return mac.Sum(nil)
+}
+
+func hashIndexObj(buf []byte) string {
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the bucket's manifest, or nil if it does not have one yet
+func ReadManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte) (*Manifest, error) {
+	m, err := objst.GetObjList(ctx, bucket, ManifestObjName, false, nil)
+	if err != nil {
+		log.Println("error: ReadManifest: GetObjList: ", err)
+		return nil, err
+	}
+	if _, ok := m[ManifestObjName]; !ok {
+		return nil, nil
+	}
+
+	buf, err := objst.DownloadObjToBuffer(ctx, bucket, ManifestObjName)
+	if err != nil {
+		log.Println("error: ReadManifest: DownloadObjToBuffer: ", err)
+		return nil, err
+	}
+	var signed signedManifest
+	if err = json.Unmarshal(buf, &signed); err != nil {
+		return nil, ErrManifestBadMac
+	}
+	if !hmac.Equal(signed.Mac, computeManifestMac(hmacKey, signed.Manifest)) {
+		return nil, ErrManifestBadMac
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(signed.Manifest, &manifest); err != nil {
+		log.Println("error: ReadManifest: cannot unmarshal manifest: ", err)
+		return nil, err
+	}
+	if manifest.Indexes == nil {
+		manifest.Indexes = make(map[string]string)
+	}
+	return &manifest, nil
+}
+
+func writeManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte, manifest *Manifest) error {
+	manifestBuf, err := json.Marshal(manifest)
+	if err != nil {
+		log.Println("error: writeManifest: cannot marshal manifest: ", err)
+		return err
+	}
+	buf, err := json.Marshal(&signedManifest{Manifest: manifestBuf, Mac: computeManifestMac(hmacKey, manifestBuf)})
+	if err != nil {
+		log.Println("error: writeManifest: cannot marshal signed manifest: ", err)
+		return err
+	}
+	if err = objst.UploadObjFromBuffer(ctx, bucket, ManifestObjName, buf, objstore.ComputeETag(buf)); err != nil {
+		log.Println("error: writeManifest: UploadObjFromBuffer: ", err)
+		return err
+	}
+	return nil
+}
+
+// Returns the names of all snapshot index objects in the bucket
+func listIndexObjNames(ctx context.Context, objst *objstore.ObjStore, bucket string) ([]string, error) {
+	encryptedSnapshotsMap, err := objst.GetObjListTopTwoLevels(ctx, bucket, []string{"metadata", "chunks"}, []string{"!"})
+	if err != nil {
+		return nil, err
+	}
+	objNames := make([]string, 0)
+	for encBackupName, encSnapshotNames := range encryptedSnapshotsMap {
+		for _, encSnapshotName := range encSnapshotNames {
+			if strings.HasPrefix(encSnapshotName, "@") {
+				objNames = append(objNames, encBackupName+"/"+encSnapshotName)
+			}
+		}
+	}
+	return objNames, nil
+}
+
+// Lists and hashes every snapshot index currently in the bucket. Used to start a manifest for
+// buckets that predate manifests, and to rebuild it (after a key rotation has renamed all the
+// indexes, or when the user asks for it).
+func buildManifestIndexes(ctx context.Context, objst *objstore.ObjStore, bucket string) (map[string]string, error) {
+	objNames, err := listIndexObjNames(ctx, objst, bucket)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[string]string)
+	for _, objName := range objNames {
+		buf, err := objst.DownloadObjToBuffer(ctx, bucket, objName)
+		if err != nil {
+			return nil, err
+		}
+		indexes[objName] = hashIndexObj(buf)
+	}
+	return indexes, nil
+}
+
+// Applies update to the bucket's manifest and writes it back as the next version. A missing
+// manifest is never rebuilt here, since it may have been deleted to hide other deletions: it is
+// only started afresh if the bucket has no snapshot indexes besides newObjName (the index just
+// uploaded, if any), and otherwise ErrManifestMissing is returned.
+func updateManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte, newObjName string, update func(manifest *Manifest)) error {
+	manifestLock.Lock()
+	defer manifestLock.Unlock()
+
+	manifest, err := ReadManifest(ctx, objst, bucket, hmacKey)
+	if err != nil {
+		log.Println("error: updateManifest: ", err)
+		return err
+	}
+	if manifest == nil {
+		objNames, err := listIndexObjNames(ctx, objst, bucket)
+		if err != nil {
+			log.Println("error: updateManifest: could not list existing indexes: ", err)
+			return err
+		}
+		for _, objName := range objNames {
+			if objName != newObjName {
+				log.Println("error: updateManifest: ", ErrManifestMissing)
+				return ErrManifestMissing
+			}
+		}
+		manifest = &Manifest{Indexes: make(map[string]string)}
+	}
+
+	update(manifest)
+	manifest.Version += 1
+	return writeManifest(ctx, objst, bucket, hmacKey, manifest)
+}
+
+// Records a snapshot index that was just uploaded
+func addIndexToManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte, objName string, buf []byte) error {
+	return updateManifest(ctx, objst, bucket, hmacKey, objName, func(manifest *Manifest) {
+		manifest.Indexes[objName] = hashIndexObj(buf)
+	})
+}
+
+// Forgets snapshot indexes that were just deleted. Without a manifest there is nothing to forget
+// them from; CheckManifest reports the missing manifest if one was seen before.
+func removeIndexesFromManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte, objNames []string) error {
+	err := updateManifest(ctx, objst, bucket, hmacKey, "", func(manifest *Manifest) {
+		for _, objName := range objNames {
+			delete(manifest.Indexes, objName)
+		}
+	})
+	if errors.Is(err, ErrManifestMissing) {
+		return nil
+	}
+	return err
+}
+
+// Replaces the bucket's manifest with one listing the indexes currently in it, whether or not the
+// old manifest is missing or fails authentication. The new manifest's version is above both the
+// old one's and lastSeenVersion, so that it does not look like a rollback.
+func RebuildManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, hmacKey []byte, lastSeenVersion uint64) error {
+	manifestLock.Lock()
+	defer manifestLock.Unlock()
+
+	version := lastSeenVersion
+	oldManifest, err := ReadManifest(ctx, objst, bucket, hmacKey)
+	if err != nil && !errors.Is(err, ErrManifestBadMac) {
+		log.Println("error: RebuildManifest: ", err)
+		return err
+	}
+	if oldManifest != nil && oldManifest.Version > version {
+		version = oldManifest.Version
+	}
+
+	indexes, err := buildManifestIndexes(ctx, objst, bucket)
+	if err != nil {
+		log.Println("error: RebuildManifest: could not list indexes: ", err)
+		return err
+	}
+	return writeManifest(ctx, objst, bucket, hmacKey, &Manifest{Version: version + 1, Indexes: indexes})
+}
+
+// Describes an index object name as "backup/snapshot" if key can decrypt it
+func describeIndexObjName(key []byte, objName string) string {
+	encBackupName, encSnapshotName, _ := strings.Cut(objName, "/@")
+	backupName, err := cryptography.DecryptFilename(key, encBackupName)
+	if err != nil {
+		return objName
+	}
+	snapshotName, err := cryptography.DecryptFilename(key, encSnapshotName)
+	if err != nil {
+		return objName
+	}
+	return backupName + "/" + snapshotName
+}
+
+// Checks the bucket's manifest against the last version this machine has seen (0 if none) and
+// against the indexes actually in the bucket, also downloading and hashing them if checkHashes is
+// set. Returns a description of each problem found, which means the bucket has been tampered with
+// or rolled back.
+func VerifyManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, hmacKey []byte, lastSeenVersion uint64, checkHashes bool) (manifest *Manifest, problems []string, err error) {
+	manifest, err = ReadManifest(ctx, objst, bucket, hmacKey)
+	if errors.Is(err, ErrManifestBadMac) {
+		return nil, []string{err.Error()}, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	if manifest == nil {
+		if lastSeenVersion > 0 {
+			problems = append(problems, fmt.Sprintf("the snapshot manifest is missing, but version %d was seen before", lastSeenVersion))
+		}
+		return nil, problems, nil
+	}
+
+	if manifest.Version < lastSeenVersion {
+		problems = append(problems, fmt.Sprintf("the snapshot manifest is at version %d, older than version %d seen before (the bucket may have been rolled back)", manifest.Version, lastSeenVersion))
+	}
+
+	objNames, err := listIndexObjNames(ctx, objst, bucket)
+	if err != nil {
+		log.Println("error: VerifyManifest: could not list indexes: ", err)
+		return nil, nil, err
+	}
+	present := make(map[string]bool)
+	for _, objName := range objNames {
+		present[objName] = true
+	}
+	for objName, hash := range manifest.Indexes {
+		if !present[objName] {
+			problems = append(problems, fmt.Sprintf("snapshot '%s' is listed in the manifest but missing from the bucket", describeIndexObjName(key, objName)))
+		} else if checkHashes {
+			buf, err := objst.DownloadObjToBuffer(ctx, bucket, objName)
+			if err != nil {
+				log.Println("error: VerifyManifest: DownloadObjToBuffer: ", err)
+				return nil, nil, err
+			}
+			if hashIndexObj(buf) != hash {
+				problems = append(problems, fmt.Sprintf("the index of snapshot '%s' does not match the manifest", describeIndexObjName(key, objName)))
+			}
+		}
+	}
+	return manifest, problems, nil
+}
+
+// Verifies the bucket's manifest against the version recorded in db and, if it has not gone
+// backwards, records its version as the last one seen. A bucket that has never been seen with a
+// manifest (it predates them, or is new) gets one listing the indexes already in it.
+func CheckManifest(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, hmacKey []byte, dbLock *sync.Mutex, db *database.DB) (problems []string, err error) {
+	util.LockIf(dbLock)
+	lastSeenVersion, err := db.GetLastSeenManifestVersion(bucket)
+	util.UnlockIf(dbLock)
+	if err != nil {
+		log.Println("error: CheckManifest: ", err)
+		return nil, err
+	}
+
+	manifest, problems, err := VerifyManifest(ctx, objst, bucket, key, hmacKey, lastSeenVersion, false)
+	if err != nil {
+		log.Println("error: CheckManifest: ", err)
+		return nil, err
+	}
+	if manifest == nil && len(problems) == 0 && lastSeenVersion == 0 {
+		if err = RebuildManifest(ctx, objst, bucket, hmacKey, 0); err != nil {
+			log.Println("error: CheckManifest: could not start the manifest: ", err)
+			return nil, err
+		}
+		if manifest, err = ReadManifest(ctx, objst, bucket, hmacKey); err != nil {
+			log.Println("error: CheckManifest: ", err)
+			return nil, err
+		}
+	}
+
+	if manifest != nil && manifest.Version > lastSeenVersion {
+		util.LockIf(dbLock)
+		err = db.SetLastSeenManifestVersion(bucket, manifest.Version)
+		util.UnlockIf(dbLock)
+		if err != nil {
+			log.Println("error: CheckManifest: ", err)
+			return nil, err
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,140 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fsctl/tless/pkg/cryptography"
+	"github.com/fsctl/tless/pkg/database"
+	"github.com/fsctl/tless/pkg/objstore"
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	db, err := database.NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	encBackupName, err := cryptography.EncryptFilename(key, "Documents")
+	assert.NoError(t, err)
+	writeSnapshot := func(snapshotName string) string {
+		encSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
+		assert.NoError(t, err)
+		snapshotObj := &Snapshot{DecryptedName: snapshotName, RelPaths: map[string]CloudRelPath{}}
+		_, err = SerializeAndWriteSnapshotObj(snapshotObj, key, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+		assert.NoError(t, err)
+		return encBackupName + "/@" + encSnapshotName
+	}
+
+	// Every snapshot written bumps the version
+	writeSnapshot("2022-01-01_01.01.01")
+	objName2 := writeSnapshot("2022-01-02_01.01.01")
+	problems, err := CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+	lastSeenVersion, err := db.GetLastSeenManifestVersion(bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), lastSeenVersion)
+	oldManifestBuf, err := objst.DownloadObjToBuffer(ctx, bucket, ManifestObjName)
+	assert.NoError(t, err)
+
+	// Deleting a snapshot properly keeps the manifest consistent
+	assert.NoError(t, DeleteSnapshots(ctx, key, hmacKey, nil, []SnapshotForDeletion{{BackupDirName: "Documents", SnapshotName: "2022-01-01_01.01.01"}}, objst, bucket, false, vlog, nil, nil))
+	problems, err = CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// A snapshot deleted behind our back is noticed
+	assert.NoError(t, objst.DeleteObj(ctx, bucket, objName2))
+	problems, err = CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"snapshot 'Documents/2022-01-02_01.01.01' is listed in the manifest but missing from the bucket"}, problems)
+
+	// So is rolling the manifest back to an older version
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, ManifestObjName, oldManifestBuf, objstore.ComputeETag(oldManifestBuf)))
+	problems, err = CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Contains(t, problems, "the snapshot manifest is at version 2, older than version 3 seen before (the bucket may have been rolled back)")
+
+	// And so is a forged manifest
+	_, problems, err = VerifyManifest(ctx, objst, bucket, key, bytes.Repeat([]byte{0x44}, 32), 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ErrManifestBadMac.Error()}, problems)
+
+	// And a missing one, once one has been seen, which is not silently started afresh
+	writeSnapshot("2022-01-03_01.01.01")
+	assert.NoError(t, objst.DeleteObj(ctx, bucket, ManifestObjName))
+	problems, err = CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(problems))
+	encSnapshotName, err := cryptography.EncryptFilename(key, "2022-01-04_01.01.01")
+	assert.NoError(t, err)
+	snapshotObj := &Snapshot{DecryptedName: "2022-01-04_01.01.01", RelPaths: map[string]CloudRelPath{}}
+	_, err = SerializeAndWriteSnapshotObj(snapshotObj, key, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+	assert.ErrorIs(t, err, ErrManifestMissing)
+
+	// until it is rebuilt, at a version above any seen before
+	assert.NoError(t, RebuildManifest(ctx, objst, bucket, hmacKey, 3))
+	problems, err = CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+	lastSeenVersion, err = db.GetLastSeenManifestVersion(bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), lastSeenVersion)
+	writeSnapshot("2022-01-04_01.01.01")
+
+	// A forged manifest can be rebuilt too
+	assert.NoError(t, objst.UploadObjFromBuffer(ctx, bucket, ManifestObjName, []byte("{}"), objstore.ComputeETag([]byte("{}"))))
+	assert.Error(t, DeleteSnapshots(ctx, key, hmacKey, nil, []SnapshotForDeletion{{BackupDirName: "Documents", SnapshotName: "2022-01-04_01.01.01"}}, objst, bucket, false, vlog, nil, nil))
+	assert.NoError(t, RebuildManifest(ctx, objst, bucket, hmacKey, 5))
+	manifest, err := ReadManifest(ctx, objst, bucket, hmacKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), manifest.Version)
+}
+
+func TestManifestOfOlderBucket(t *testing.T) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	hmacKey := bytes.Repeat([]byte{0x43}, 32)
+	bucket := "test-bucket"
+	vlog := util.NewVLog(nil, func() bool { return false })
+
+	objst := objstore.NewObjStoreWithBackend(objstore.NewMemBackend())
+	assert.NoError(t, objst.MakeBucket(ctx, bucket, ""))
+	db, err := database.NewDB(t.TempDir() + "/state.db")
+	assert.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, db.PerformDbMigrations(vlog))
+
+	// A snapshot written before there were manifests
+	encBackupName, err := cryptography.EncryptFilename(key, "Documents")
+	assert.NoError(t, err)
+	encSnapshotName, err := cryptography.EncryptFilename(key, "2022-01-01_01.01.01")
+	assert.NoError(t, err)
+	snapshotObj := &Snapshot{DecryptedName: "2022-01-01_01.01.01", RelPaths: map[string]CloudRelPath{}}
+	_, err = SerializeAndWriteSnapshotObj(snapshotObj, key, hmacKey, nil, cryptography.PaddingNone, encBackupName, encSnapshotName, objst, ctx, bucket)
+	assert.NoError(t, err)
+	assert.NoError(t, objst.DeleteObj(ctx, bucket, ManifestObjName))
+
+	// The first check on a machine that has never seen a manifest starts one
+	problems, err := CheckManifest(ctx, objst, bucket, key, hmacKey, nil, db)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+	manifest, err := ReadManifest(ctx, objst, bucket, hmacKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(manifest.Indexes))
+	lastSeenVersion, err := db.GetLastSeenManifestVersion(bucket)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.Version, lastSeenVersion)
+}
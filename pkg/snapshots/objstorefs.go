@@ -1 +1,93 @@
 package snapshots
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// A node in the directory tree of every backup, snapshot and rel path in the bucket, laid out as
+// backupName/snapshotName/relPath. Used to present snapshots as a read-only filesystem.
+type FsTreeNode struct {
+	Name     string
+	Crp      *CloudRelPath // nil for backup and snapshot dirs and for dirs with no entry of their own
+	Datetime time.Time     // time of the snapshot this node belongs to (zero for backup dirs)
+	Children map[string]*FsTreeNode
+}
+
+func newFsTreeNode(name string, datetime time.Time) *FsTreeNode {
+	return &FsTreeNode{
+		Name:     name,
+		Crp:      nil,
+		Datetime: datetime,
+		Children: make(map[string]*FsTreeNode),
+	}
+}
+
+// Builds the tree of backupName/snapshotName/relPath from the output of GetGroupedSnapshots
+func NewFsTree(groupedObjects map[string]BackupDir) *FsTreeNode {
+	root := newFsTreeNode("", time.Time{})
+
+	for backupName, backupDir := range groupedObjects {
+		backupNode := newFsTreeNode(backupName, time.Time{})
+		root.Children[backupName] = backupNode
+
+		for snapshotName, snapshot := range backupDir.Snapshots {
+			datetime := snapshot.Datetime
+			if datetime.IsZero() {
+				if t, err := time.Parse("2006-01-02_15.04.05", snapshotName); err == nil {
+					datetime = t
+				}
+			}
+			snapshotNode := newFsTreeNode(snapshotName, datetime)
+			backupNode.Children[snapshotName] = snapshotNode
+
+			for relPath, crp := range snapshot.RelPaths {
+				crp := crp
+				node := snapshotNode
+				for _, component := range strings.Split(relPath, "/") {
+					if component == "" || component == "." {
+						continue
+					}
+					child, ok := node.Children[component]
+					if !ok {
+						child = newFsTreeNode(component, datetime)
+						node.Children[component] = child
+					}
+					node = child
+				}
+				if node != snapshotNode {
+					node.Crp = &crp
+				}
+			}
+		}
+	}
+
+	return root
+}
+
+// Returns the names of n's children in sorted order
+func (n *FsTreeNode) SortedChildNames() []string {
+	names := make([]string, 0, len(n.Children))
+	for name := range n.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the node at path (slash separated, relative to n), or nil if there is none
+func (n *FsTreeNode) Find(path string) *FsTreeNode {
+	node := n
+	for _, component := range strings.Split(path, "/") {
+		if component == "" {
+			continue
+		}
+		child, ok := node.Children[component]
+		if !ok {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
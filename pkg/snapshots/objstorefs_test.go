@@ -0,0 +1,35 @@
+package snapshots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFsTree(t *testing.T) {
+	mBackupDirs := SetupBackups(t)
+	ss2022 := mBackupDirs["backupdir"].Snapshots["2022-01-01_01.01.01"]
+	ss2022.RelPaths["sub/dir/file2"] = CloudRelPath{RelPath: "sub/dir/file2", ChunkExtents: []ChunkExtent{{ChunkName: "Uzxyz==", Offset: 0, Len: 10}}}
+	ss2022.RelPaths["sub"] = CloudRelPath{RelPath: "sub", ChunkExtents: []ChunkExtent{{ChunkName: "Uzxyz==", Offset: 10, Len: 10}}}
+	ss2022.RelPaths[""] = CloudRelPath{RelPath: "", ChunkExtents: []ChunkExtent{{ChunkName: "Uzxyz==", Offset: 20, Len: 10}}}
+
+	root := NewFsTree(mBackupDirs)
+	assert.Equal(t, []string{"backupdir"}, root.SortedChildNames())
+	assert.Equal(t, []string{"2020-01-01_01.01.01", "2021-01-01_01.01.01", "2022-01-01_01.01.01"}, root.Children["backupdir"].SortedChildNames())
+
+	// snapshot dirs are synthesized even if the snapshot has an entry for its root
+	ssNode := root.Find("backupdir/2022-01-01_01.01.01")
+	assert.NotNil(t, ssNode)
+	assert.Nil(t, ssNode.Crp)
+	assert.Equal(t, ss2022.Datetime, ssNode.Datetime)
+	assert.Equal(t, []string{"file1", "sub"}, ssNode.SortedChildNames())
+
+	// intermediate dirs without an entry of their own have no Crp
+	assert.Equal(t, "sub", root.Find("backupdir/2022-01-01_01.01.01/sub").Crp.RelPath)
+	assert.Nil(t, root.Find("backupdir/2022-01-01_01.01.01/sub/dir").Crp)
+	assert.Equal(t, "sub/dir/file2", root.Find("backupdir/2022-01-01_01.01.01/sub/dir/file2").Crp.RelPath)
+
+	// an empty snapshot is just an empty dir
+	assert.Equal(t, 0, len(root.Find("backupdir/2021-01-01_01.01.01").Children))
+	assert.Nil(t, root.Find("backupdir/2021-01-01_01.01.01/file1"))
+}
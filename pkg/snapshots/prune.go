@@ -70,24 +70,7 @@ var (
 )
 
 func GetPruneKeepsList(snapshotInfos []SnapshotInfo) []SnapshotInfo {
-	keeps := make([]SnapshotInfo, 0)
-
-	for _, ss := range snapshotInfos {
-		if KeepEverything.TimeIsWithin(ss.TimestampUnix) {
-			keeps = append(keeps, ss)
-			continue
-		}
-		for _, agoRange := range KeepOldestNewest {
-			if agoRange.IsOldestWithin(ss.TimestampUnix, snapshotInfos) {
-				keeps = append(keeps, ss)
-				continue
-			}
-			if agoRange.IsNewestWithin(ss.TimestampUnix, snapshotInfos) {
-				keeps = append(keeps, ss)
-				continue
-			}
-		}
-	}
+	keeps, _ := getDefaultKeepsList(snapshotInfos)
 	return keeps
 }
 
@@ -0,0 +1,128 @@
+package snapshots
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fsctl/tless/pkg/util"
+)
+
+// Why each kept snapshot was kept, keyed by SnapshotInfo.TimestampUnix
+type KeepReasons map[int64][]string
+
+func (kr KeepReasons) add(timestamp int64, reason string) {
+	kr[timestamp] = append(kr[timestamp], reason)
+}
+
+func (kr KeepReasons) addPinned(snapshotInfos []SnapshotInfo) {
+	for _, ss := range snapshotInfos {
+		if ss.Pinned {
+			kr.add(ss.TimestampUnix, "pinned")
+		}
+	}
+}
+
+// Returns the snapshots to keep under policy and why each was kept. An empty policy applies the
+// built-in schedule of GetPruneKeepsList. Pinned snapshots are always kept.
+func GetRetentionKeepsList(snapshotInfos []SnapshotInfo, policy util.RetentionPolicy) ([]SnapshotInfo, KeepReasons, error) {
+	if policy.IsEmpty() {
+		keeps, reasons := getDefaultKeepsList(snapshotInfos)
+		return keeps, reasons, nil
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	reasons := make(KeepReasons)
+	reasons.addPinned(snapshotInfos)
+
+	// Newest first
+	sorted := make([]SnapshotInfo, len(snapshotInfos))
+	copy(sorted, snapshotInfos)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TimestampUnix > sorted[j].TimestampUnix
+	})
+
+	for i := 0; i < policy.KeepLast && i < len(sorted); i++ {
+		reasons.add(sorted[i].TimestampUnix, fmt.Sprintf("last %d", policy.KeepLast))
+	}
+
+	buckets := []struct {
+		n      int
+		name   string
+		period func(t time.Time) string
+	}{
+		{policy.KeepHourly, "hourly", func(t time.Time) string { return t.Format("2006-01-02 15") }},
+		{policy.KeepDaily, "daily", func(t time.Time) string { return t.Format("2006-01-02") }},
+		{policy.KeepWeekly, "weekly", func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{policy.KeepMonthly, "monthly", func(t time.Time) string { return t.Format("2006-01") }},
+		{policy.KeepYearly, "yearly", func(t time.Time) string { return t.Format("2006") }},
+	}
+	for _, b := range buckets {
+		remaining := b.n
+		lastPeriod := ""
+		for _, ss := range sorted {
+			if remaining == 0 {
+				break
+			}
+			period := b.period(time.Unix(ss.TimestampUnix, 0).Local())
+			if period != lastPeriod {
+				reasons.add(ss.TimestampUnix, fmt.Sprintf("%s %d (%s)", b.name, b.n, period))
+				lastPeriod = period
+				remaining -= 1
+			}
+		}
+	}
+
+	if policy.KeepWithin != "" && len(sorted) > 0 {
+		d, _ := util.ParseKeepWithin(policy.KeepWithin)
+		cutoff := d.Before(time.Unix(sorted[0].TimestampUnix, 0)).Unix()
+		for _, ss := range sorted {
+			if ss.TimestampUnix >= cutoff {
+				reasons.add(ss.TimestampUnix, fmt.Sprintf("within %s of newest", policy.KeepWithin))
+			}
+		}
+	}
+
+	keeps := make([]SnapshotInfo, 0)
+	for _, ss := range snapshotInfos {
+		if _, ok := reasons[ss.TimestampUnix]; ok {
+			keeps = append(keeps, ss)
+		}
+	}
+	return keeps, reasons, nil
+}
+
+// The built-in schedule: everything from the past day, then the oldest and newest in each of a
+// few windows back to a year ago
+func getDefaultKeepsList(snapshotInfos []SnapshotInfo) ([]SnapshotInfo, KeepReasons) {
+	reasons := make(KeepReasons)
+	reasons.addPinned(snapshotInfos)
+
+	describeRange := func(tr TimeRangeSecondsAgo) string {
+		return fmt.Sprintf("%d-%d days ago", tr.From/OneDayInSec, tr.BackTo/OneDayInSec)
+	}
+
+	keeps := make([]SnapshotInfo, 0)
+	for _, ss := range snapshotInfos {
+		if KeepEverything.TimeIsWithin(ss.TimestampUnix) {
+			reasons.add(ss.TimestampUnix, "within the past day")
+		}
+		for _, agoRange := range KeepOldestNewest {
+			if agoRange.IsOldestWithin(ss.TimestampUnix, snapshotInfos) {
+				reasons.add(ss.TimestampUnix, "oldest from "+describeRange(agoRange))
+			}
+			if agoRange.IsNewestWithin(ss.TimestampUnix, snapshotInfos) {
+				reasons.add(ss.TimestampUnix, "newest from "+describeRange(agoRange))
+			}
+		}
+		if _, ok := reasons[ss.TimestampUnix]; ok {
+			keeps = append(keeps, ss)
+		}
+	}
+	return keeps, reasons
+}
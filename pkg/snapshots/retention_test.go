@@ -0,0 +1,77 @@
+package snapshots
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fsctl/tless/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRetentionKeepsList(t *testing.T) {
+	at := func(year int, month time.Month, day int, hour int) SnapshotInfo {
+		ts := time.Date(year, month, day, hour, 0, 0, 0, time.Local).Unix()
+		return SnapshotInfo{Name: time.Unix(ts, 0).UTC().Format("2006-01-02_15.04.05"), TimestampUnix: ts}
+	}
+	ssiFullSet := []SnapshotInfo{
+		at(2020, 6, 1, 12),
+		at(2021, 12, 31, 12),
+		at(2022, 1, 1, 12),
+		at(2022, 1, 15, 12),
+		at(2022, 2, 1, 9),
+		at(2022, 2, 1, 10),
+		at(2022, 2, 1, 11),
+		at(2022, 2, 2, 10),
+	}
+	keptNames := func(keeps []SnapshotInfo) []string {
+		names := make([]string, 0)
+		for _, k := range keeps {
+			names = append(names, time.Unix(k.TimestampUnix, 0).Local().Format("2006-01-02 15"))
+		}
+		return names
+	}
+
+	keeps, reasons, err := GetRetentionKeepsList(ssiFullSet, util.RetentionPolicy{KeepLast: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2022-02-01 11", "2022-02-02 10"}, keptNames(keeps))
+	assert.Equal(t, []string{"last 2"}, reasons[ssiFullSet[7].TimestampUnix])
+
+	// Newest per day, for the last 3 days that have a snapshot
+	keeps, _, err = GetRetentionKeepsList(ssiFullSet, util.RetentionPolicy{KeepDaily: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2022-01-15 12", "2022-02-01 11", "2022-02-02 10"}, keptNames(keeps))
+
+	keeps, reasons, err = GetRetentionKeepsList(ssiFullSet, util.RetentionPolicy{KeepMonthly: 3, KeepYearly: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2020-06-01 12", "2021-12-31 12", "2022-01-15 12", "2022-02-02 10"}, keptNames(keeps))
+	assert.Equal(t, []string{"monthly 3 (2022-02)", "yearly 10 (2022)"}, reasons[ssiFullSet[7].TimestampUnix])
+	assert.Equal(t, []string{"monthly 3 (2021-12)", "yearly 10 (2021)"}, reasons[ssiFullSet[1].TimestampUnix])
+
+	// Relative to the newest snapshot, not to now
+	keeps, reasons, err = GetRetentionKeepsList(ssiFullSet, util.RetentionPolicy{KeepWithin: "1d"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2022-02-01 10", "2022-02-01 11", "2022-02-02 10"}, keptNames(keeps))
+	assert.Equal(t, []string{"within 1d of newest"}, reasons[ssiFullSet[5].TimestampUnix])
+
+	// Pinned snapshots are kept regardless of policy
+	pinnedSet := append([]SnapshotInfo{}, ssiFullSet...)
+	pinnedSet[0].Pinned = true
+	keeps, reasons, err = GetRetentionKeepsList(pinnedSet, util.RetentionPolicy{KeepLast: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2020-06-01 12", "2022-02-02 10"}, keptNames(keeps))
+	assert.Equal(t, []string{"pinned"}, reasons[pinnedSet[0].TimestampUnix])
+	// These are all over a year old, so the built-in schedule keeps only the pinned one
+	assert.Equal(t, []string{"2020-06-01 12"}, keptNames(GetPruneKeepsList(pinnedSet)))
+
+	_, _, err = GetRetentionKeepsList(ssiFullSet, util.RetentionPolicy{KeepHourly: -1})
+	assert.Error(t, err)
+
+	// An empty policy falls back to the built-in schedule
+	now := time.Now().Unix()
+	recent := []SnapshotInfo{{TimestampUnix: now - 60}, {TimestampUnix: now - 2*OneDayInSec}}
+	keeps, reasons, err = GetRetentionKeepsList(recent, util.RetentionPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(keeps))
+	assert.Equal(t, []string{"within the past day"}, reasons[now-60])
+	assert.Equal(t, []string{"oldest from 1-3 days ago", "newest from 1-3 days ago"}, reasons[now-2*OneDayInSec])
+}
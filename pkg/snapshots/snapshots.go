@@ -17,12 +17,27 @@ type SnapshotForDeletion struct {
 	SnapshotName  string
 }
 
-func DeleteSnapshots(ctx context.Context, key []byte, deleteSnapshots []SnapshotForDeletion, objst *objstore.ObjStore, bucket string, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) error {
+// Deletes the snapshots and garbage collects their orphaned chunks. Refuses to delete any pinned
+// snapshot unless force is true.
+func DeleteSnapshots(ctx context.Context, key []byte, hmacKey []byte, sealKeys *cryptography.SealKeys, deleteSnapshots []SnapshotForDeletion, objst *objstore.ObjStore, bucket string, force bool, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) error {
+	// Check all pins before deleting anything
+	for _, deleteSnapshot := range deleteSnapshots {
+		annotations, err := GetSnapshotAnnotations(ctx, objst, bucket, key, deleteSnapshot.BackupDirName, deleteSnapshot.SnapshotName)
+		if err != nil {
+			return fmt.Errorf("error: DeleteSnapshot: could not read annotations of snapshot %s/%s: %v", deleteSnapshot.BackupDirName, deleteSnapshot.SnapshotName, err)
+		}
+		if annotations.Pinned && !force {
+			return fmt.Errorf("error: DeleteSnapshot: snapshot %s/%s is pinned (unpin it first or force the deletion)", deleteSnapshot.BackupDirName, deleteSnapshot.SnapshotName)
+		}
+	}
+
+	// Get the encrypted representation of each backupDirName and snapshotName
+	annotationsObjNames := make([]string, 0, len(deleteSnapshots))
+	indexObjNames := make([]string, 0, len(deleteSnapshots))
 	for _, deleteSnapshot := range deleteSnapshots {
 		snapshotName := deleteSnapshot.SnapshotName
 		backupDirName := deleteSnapshot.BackupDirName
 
-		// Get the encrypted representation of backupDirName and snapshotName
 		encryptedSnapshotName, err := cryptography.EncryptFilename(key, snapshotName)
 		if err != nil {
 			return fmt.Errorf("error: DeleteSnapshot: could not encrypt snapshot name (%s): %v", snapshotName, err)
@@ -31,20 +46,36 @@ func DeleteSnapshots(ctx context.Context, key []byte, deleteSnapshots []Snapshot
 		if err != nil {
 			return fmt.Errorf("error: DeleteSnapshot: could not encrypt backup dir name (%s): %v", backupDirName, err)
 		}
+		indexObjNames = append(indexObjNames, encryptedBackupDirName+"/@"+encryptedSnapshotName)
+		annotationsObjNames = append(annotationsObjNames, encryptedBackupDirName+"/!"+encryptedSnapshotName)
+	}
+
+	// Remove them from the manifest first, so that a failed deletion leaves an unlisted index
+	// behind rather than a listed index missing
+	if err := removeIndexesFromManifest(ctx, objst, bucket, hmacKey, indexObjNames); err != nil {
+		return fmt.Errorf("error: DeleteSnapshot: could not update the snapshot manifest: %v", err)
+	}
 
+	for i, indexObjName := range indexObjNames {
 		// Delete index file for snapshot
 		vlog.Println("Deleting snapshot index file(s)")
-		indexObjName := encryptedBackupDirName + "/@" + encryptedSnapshotName
-		err = objst.DeleteObj(ctx, bucket, indexObjName)
+		err := objst.DeleteObj(ctx, bucket, indexObjName)
 		if err != nil {
 			return fmt.Errorf("error: DeleteSnapshot: could not delete old snapshot's index file (%s): %v", indexObjName, err)
 		}
 		vlog.Println("Done deleting snapshot index file(s)")
+
+		// Delete its tags and pin, if any
+		annotationsObjName := annotationsObjNames[i]
+		err = objst.DeleteObj(ctx, bucket, annotationsObjName)
+		if err != nil {
+			return fmt.Errorf("error: DeleteSnapshot: could not delete old snapshot's annotations (%s): %v", annotationsObjName, err)
+		}
 	}
 
 	// Garbage collect orphaned chunks
 	vlog.Println("Garbage collecting orphaned chunks")
-	if err := GCChunks(ctx, objst, bucket, key, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc); err != nil {
+	if err := GCChunks(ctx, objst, bucket, key, sealKeys, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc); err != nil {
 		return fmt.Errorf("error: DeleteSnapshot: could not garbage collect chunks: %v", err)
 	}
 	vlog.Println("Done garbage collecting orphaned chunks")
@@ -72,13 +103,14 @@ type SnapshotInfo struct {
 	Name            string
 	RawSnapshotName string
 	TimestampUnix   int64
+	Pinned          bool
 }
 
 // Returns a map of backup:[]SnapshotInfo, where the snapshot info structs are sorted by timestamp ascending
 // Used by prune (cmd/prune.go) and autoprune (daemon/timer.go) and daemon's ReadAllSnapshotsMetadata RPC
 func GetAllSnapshotInfos(ctx context.Context, key []byte, objst *objstore.ObjStore, bucket string) (map[string][]SnapshotInfo, error) {
 	// Get the backup:snapshots map with encrypted names
-	encryptedSnapshotsMap, err := objst.GetObjListTopTwoLevels(ctx, bucket, []string{"metadata", "chunks"}, []string{})
+	encryptedSnapshotsMap, err := objst.GetObjListTopTwoLevels(ctx, bucket, []string{"metadata", "chunks"}, []string{"!"})
 	if err != nil {
 		log.Println("error: GetAllSnapshotInfos: ", err)
 		return nil, err
@@ -94,6 +126,12 @@ func GetAllSnapshotInfos(ctx context.Context, key []byte, objst *objstore.ObjSto
 		}
 		mRet[backupName] = make([]SnapshotInfo, 0)
 
+		mAnnotations, err := getBackupAnnotations(ctx, objst, bucket, key, encBackupName)
+		if err != nil {
+			log.Println("error: GetAllSnapshotInfos: getBackupAnnotations: ", err)
+			return nil, err
+		}
+
 		for _, encSnapshotName := range encryptedSnapshotsMap[encBackupName] {
 			encSnapshotName = strings.TrimPrefix(encSnapshotName, "@")
 			snapshotName, err := cryptography.DecryptFilename(key, encSnapshotName)
@@ -101,11 +139,15 @@ func GetAllSnapshotInfos(ctx context.Context, key []byte, objst *objstore.ObjSto
 				log.Println("error: GetAllSnapshotInfos: DecryptFilename: ", err)
 				return nil, err
 			}
-			mRet[backupName] = append(mRet[backupName], SnapshotInfo{
+			ssInfo := SnapshotInfo{
 				Name:            snapshotName,
 				RawSnapshotName: backupName + "/" + snapshotName,
 				TimestampUnix:   util.GetUnixTimeFromSnapshotName(snapshotName),
-			})
+			}
+			if a, ok := mAnnotations[snapshotName]; ok {
+				ssInfo.Pinned = a.Pinned
+			}
+			mRet[backupName] = append(mRet[backupName], ssInfo)
 		}
 		sort.Slice(mRet[backupName], func(i, j int) bool {
 			return mRet[backupName][i].TimestampUnix < mRet[backupName][j].TimestampUnix
@@ -115,10 +157,10 @@ func GetAllSnapshotInfos(ctx context.Context, key []byte, objst *objstore.ObjSto
 }
 
 // Garbage collects orphaned chunks
-func GCChunks(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) error {
+func GCChunks(ctx context.Context, objst *objstore.ObjStore, bucket string, key []byte, sealKeys *cryptography.SealKeys, vlog *util.VLog, setInitialGGSProgressFunc SetInitialGetGroupedSnapshotsProgress, updateGGSProgressFunc UpdateGetGroupedSnapshotsProgress) error {
 	// re-read every snapshot file
 	vlog.Println("Getting all snapshots list")
-	groupedObjects, err := GetGroupedSnapshots(ctx, objst, key, bucket, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc)
+	groupedObjects, err := GetGroupedSnapshots(ctx, objst, key, sealKeys, bucket, vlog, setInitialGGSProgressFunc, updateGGSProgressFunc)
 	if err != nil {
 		log.Printf("error: GCChunks: could not get grouped snapshots: %v", err)
 		return err
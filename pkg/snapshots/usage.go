@@ -4,20 +4,40 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 
+	"github.com/fsctl/tless/pkg/database"
 	"github.com/fsctl/tless/pkg/objstore"
 	"github.com/fsctl/tless/pkg/util"
 )
 
-func ComputeTotalCloudSpaceUsage(ctx context.Context, objst *objstore.ObjStore, bucket string, encKey []byte, vlog *util.VLog) (int64, error) {
+// Returns the number of bytes the bucket uses, and how many of them are padding. Padding is
+// encrypted along with the data, so it is counted from what db recorded as objects were uploaded
+// from here (db may be nil, in which case no padding is counted).
+func ComputeTotalCloudSpaceUsage(ctx context.Context, objst *objstore.ObjStore, bucket string, encKey []byte, dbLock *sync.Mutex, db *database.DB, vlog *util.VLog) (int64, int64, error) {
 	sizeAccum := int64(0)
+	objNames := make(map[string]bool)
+
+	// get the recorded padding before listing, so that objects uploaded meanwhile aren't taken
+	// for deleted ones
+	var objPadding map[string]int64
+	if db != nil {
+		var err error
+		util.LockIf(dbLock)
+		objPadding, err = db.GetObjectPadding()
+		util.UnlockIf(dbLock)
+		if err != nil {
+			log.Println("error: ComputeTotalSpaceUsage: could not get padding of objects: ", err)
+			return 0, 0, err
+		}
+	}
 
 	// add size of metadata file
 	metadataFileMap, err := objst.GetObjList(ctx, bucket, "metadata", false, vlog)
 	if err != nil {
 		msg := fmt.Sprintln("error: ComputeTotalSpaceUsage: objst.GetObjListTopLevel failed: ", err)
 		log.Println(msg)
-		return 0, err
+		return 0, 0, err
 	}
 	for _, byteCnt := range metadataFileMap {
 		sizeAccum += byteCnt
@@ -28,17 +48,18 @@ func ComputeTotalCloudSpaceUsage(ctx context.Context, objst *objstore.ObjStore,
 	if err != nil {
 		msg := fmt.Sprintln("error: ComputeTotalSpaceUsage: objst.GetObjListTopLevel failed: ", err)
 		log.Println(msg)
-		return 0, err
+		return 0, 0, err
 	}
 	for _, encBackupName := range topLevelObjs {
 		m, err := objst.GetObjList(ctx, bucket, encBackupName+"/@", false, vlog)
 		if err != nil {
 			msg := fmt.Sprintf("error: ComputeTotalSpaceUsage: could not get list of snapshot index files for '%s': %v\n", encBackupName, err)
 			log.Println(msg)
-			return 0, err
+			return 0, 0, err
 		}
-		for _, byteCnt := range m {
+		for objName, byteCnt := range m {
 			sizeAccum += byteCnt
+			objNames[objName] = true
 		}
 	}
 
@@ -47,11 +68,33 @@ func ComputeTotalCloudSpaceUsage(ctx context.Context, objst *objstore.ObjStore,
 	if err != nil {
 		msg := fmt.Sprintf("error: ComputeTotalSpaceUsage: could not iterate over chunks in cloud: %v", err)
 		log.Println(msg)
-		return 0, err
+		return 0, 0, err
 	}
-	for _, byteCnt := range mCloudChunks {
+	for objName, byteCnt := range mCloudChunks {
 		sizeAccum += byteCnt
+		objNames[objName] = true
+	}
+
+	// add up the padding of the objects still in the bucket, forgetting about the rest
+	paddingAccum := int64(0)
+	if objPadding != nil {
+		goneObjNames := make([]string, 0)
+		for objName, paddingCnt := range objPadding {
+			if objNames[objName] {
+				paddingAccum += paddingCnt
+			} else {
+				goneObjNames = append(goneObjNames, objName)
+			}
+		}
+		if len(goneObjNames) > 0 {
+			util.LockIf(dbLock)
+			err = db.DeleteObjectPadding(goneObjNames)
+			util.UnlockIf(dbLock)
+			if err != nil {
+				log.Println("error: ComputeTotalSpaceUsage: could not forget padding of deleted objects: ", err)
+			}
+		}
 	}
 
-	return sizeAccum, nil
+	return sizeAccum, paddingAccum, nil
 }
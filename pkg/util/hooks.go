@@ -0,0 +1,248 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const DefaultHookTimeout = 10 * time.Minute
+
+// Shell commands run around backups and restores, such as dumping or freezing a database before
+// a backup and thawing it afterwards. Timeout applies to each command (eg "30s", "1h"; default 10m).
+type Hooks struct {
+	PreBackup   string `mapstructure:"pre_backup"`
+	PostBackup  string `mapstructure:"post_backup"`
+	OnSuccess   string `mapstructure:"on_success"`
+	OnFailure   string `mapstructure:"on_failure"`
+	PreRestore  string `mapstructure:"pre_restore"`
+	PostRestore string `mapstructure:"post_restore"`
+	Timeout     string `mapstructure:"timeout"`
+
+	runAs *RunAsUser // user to run the hooks as, if not the current one
+}
+
+// Returns h set to run as runAs, or as the current user if runAs is nil
+func (h Hooks) AsUser(runAs *RunAsUser) Hooks {
+	h.runAs = runAs
+	return h
+}
+
+func (h Hooks) Validate() error {
+	if h.Timeout != "" {
+		if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout '%s' (expected something like '30s' or '10m')", h.Timeout)
+		}
+	}
+	return nil
+}
+
+// Returns the command for the hook named hookName (eg "pre_backup"), or "" if there is none
+func (h Hooks) Command(hookName string) string {
+	switch hookName {
+	case "pre_backup":
+		return h.PreBackup
+	case "post_backup":
+		return h.PostBackup
+	case "on_success":
+		return h.OnSuccess
+	case "on_failure":
+		return h.OnFailure
+	case "pre_restore":
+		return h.PreRestore
+	case "post_restore":
+		return h.PostRestore
+	}
+	return ""
+}
+
+func (h Hooks) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultHookTimeout
+}
+
+// Runs the hook named hookName, if it is set, with env added to its environment. Its output is
+// logged, and it fails if it exits non-zero or runs longer than the timeout.
+func (h Hooks) Run(ctx context.Context, hookName string, env map[string]string, vlog *VLog) error {
+	command := h.Command(hookName)
+	if command == "" {
+		return nil
+	}
+	vlog.Printf("Running %s hook '%s'", hookName, command)
+
+	var output bytes.Buffer
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Env = append(os.Environ(), "TLESS_HOOK="+hookName)
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+env[k])
+	}
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Its own process group, so that a timeout also kills whatever the hook started
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	h.runAs.Apply(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s hook could not start: %v", hookName, err)
+	}
+
+	waitDone := make(chan error, 1)
+	go func() { waitDone <- cmd.Wait() }()
+	timeout := h.TimeoutDuration()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case err = <-waitDone:
+	case <-timer.C:
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-waitDone
+		err = fmt.Errorf("timed out after %v", timeout)
+	case <-ctx.Done():
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-waitDone
+		err = ctx.Err()
+	}
+
+	if out := strings.TrimSpace(output.String()); out != "" {
+		vlog.Printf("%s hook output:\n%s", hookName, out)
+		if err != nil {
+			lines := strings.Split(out, "\n")
+			err = fmt.Errorf("%v: %s", err, lines[len(lines)-1])
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %v", hookName, err)
+	}
+	return nil
+}
+
+// Hooks for the backup of one directory, overriding the default ones
+type HooksOverride struct {
+	Dir   string `mapstructure:"dir"`
+	Hooks `mapstructure:",squash"`
+}
+
+// The [hooks] config section
+type HooksConfig struct {
+	Hooks     `mapstructure:",squash"`
+	Overrides []HooksOverride `mapstructure:"overrides"`
+}
+
+// Returns the hooks for the backup named backupName (the last component of its dir). Each hook
+// set in an override for it replaces the default one; the rest are the defaults.
+func (hc HooksConfig) HooksForBackup(backupName string) Hooks {
+	hooks := hc.Hooks
+	for _, override := range hc.Overrides {
+		if filepath.Base(StripTrailingSlashes(override.Dir)) != backupName {
+			continue
+		}
+		for _, f := range []struct {
+			dst *string
+			src string
+		}{
+			{&hooks.PreBackup, override.PreBackup},
+			{&hooks.PostBackup, override.PostBackup},
+			{&hooks.OnSuccess, override.OnSuccess},
+			{&hooks.OnFailure, override.OnFailure},
+			{&hooks.PreRestore, override.PreRestore},
+			{&hooks.PostRestore, override.PostRestore},
+			{&hooks.Timeout, override.Timeout},
+		} {
+			if f.src != "" {
+				*f.dst = f.src
+			}
+		}
+		break
+	}
+	return hooks
+}
+
+func (hc HooksConfig) Validate() error {
+	if err := hc.Hooks.Validate(); err != nil {
+		return fmt.Errorf("[hooks]: %v", err)
+	}
+	for _, override := range hc.Overrides {
+		if override.Dir == "" {
+			return fmt.Errorf("[[hooks.overrides]]: dir is required")
+		}
+		if err := override.Hooks.Validate(); err != nil {
+			return fmt.Errorf("[[hooks.overrides]] for '%s': %v", override.Dir, err)
+		}
+	}
+	return nil
+}
+
+func generateHooksTemplate(h Hooks) string {
+	template := ""
+	for _, kv := range []struct {
+		key   string
+		value string
+	}{
+		{"pre_backup", h.PreBackup},
+		{"post_backup", h.PostBackup},
+		{"on_success", h.OnSuccess},
+		{"on_failure", h.OnFailure},
+		{"pre_restore", h.PreRestore},
+		{"post_restore", h.PostRestore},
+		{"timeout", h.Timeout},
+	} {
+		if kv.value != "" {
+			template += fmt.Sprintf("%s = %q\n", kv.key, kv.value)
+		}
+	}
+	return template
+}
+
+func generateHooksConfigTemplate(hc *HooksConfig) string {
+	template := `
+[hooks]
+# Shell commands to run around each backup dir's backup and around restores,
+# for example to dump or freeze a live database beforehand and thaw it after:
+#   pre_backup   - before a dir is backed up; if it fails, the dir is skipped
+#   post_backup  - after a dir's backup, even if it failed
+#   on_success   - after post_backup, if the backup succeeded
+#   on_failure   - after post_backup, if the backup (or pre_backup) failed
+#   pre_restore  - before a restore; if it fails, the restore is aborted
+#   post_restore - after a restore
+#   timeout      - how long each command may run, such as "30s" (default 10m)
+# Hooks see TLESS_HOOK, TLESS_BACKUP_NAME, TLESS_BACKUP_DIR, TLESS_SNAPSHOT,
+# TLESS_FILES, TLESS_BYTES, TLESS_DEDUP_BYTES, TLESS_DURATION_SECONDS,
+# TLESS_ERRORS and TLESS_RESULT (restores get TLESS_RESTORE_PATH instead of
+# TLESS_BACKUP_DIR and the stats) in their environment. The daemon runs hooks
+# as you, not as root, so commands that need root have to use sudo.
+# Example:
+#   pre_backup = "pg_dump mydb > /var/backups/mydb.sql"
+#   on_failure = "logger -t tless backup of $TLESS_BACKUP_NAME failed"
+#
+# To use different hooks for one of your backup dirs, add a section like the
+# one below. The hooks it sets replace the ones above for that dir.
+#   [[hooks.overrides]]
+#   dir = "/srv/mail"
+#   pre_backup = "sudo fsfreeze --freeze /srv/mail"
+#   post_backup = "sudo fsfreeze --unfreeze /srv/mail"
+`
+	if hc == nil {
+		return template
+	}
+
+	template += generateHooksTemplate(hc.Hooks)
+	for _, override := range hc.Overrides {
+		template += fmt.Sprintf("\n[[hooks.overrides]]\ndir = \"%s\"\n", override.Dir)
+		template += generateHooksTemplate(override.Hooks)
+	}
+	return template
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooksConfig(t *testing.T) {
+	cfg := &CfgSettings{
+		Hooks: HooksConfig{
+			Hooks: Hooks{PreBackup: "echo \"pre\" > /tmp/x", OnFailure: "notify failed", Timeout: "30s"},
+			Overrides: []HooksOverride{
+				{Dir: "/srv/mail/", Hooks: Hooks{PreBackup: "fsfreeze --freeze /srv/mail", PostBackup: "fsfreeze --unfreeze /srv/mail"}},
+			},
+		},
+	}
+
+	// Round trip through the config template
+	v := viper.New()
+	v.SetConfigType("toml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(GenerateConfigTemplate(cfg))))
+	var hc HooksConfig
+	assert.NoError(t, v.UnmarshalKey("hooks", &hc))
+	assert.Equal(t, cfg.Hooks, hc)
+	assert.NoError(t, hc.Validate())
+
+	// An override replaces only the hooks it sets
+	assert.Equal(t, Hooks{PreBackup: "fsfreeze --freeze /srv/mail", PostBackup: "fsfreeze --unfreeze /srv/mail", OnFailure: "notify failed", Timeout: "30s"}, hc.HooksForBackup("mail"))
+	assert.Equal(t, cfg.Hooks.Hooks, hc.HooksForBackup("Documents"))
+	assert.Equal(t, 30*time.Second, hc.HooksForBackup("mail").TimeoutDuration())
+
+	// The default template has no hooks at all
+	v = viper.New()
+	v.SetConfigType("toml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(GenerateConfigTemplate(nil))))
+	hc = HooksConfig{}
+	assert.NoError(t, v.UnmarshalKey("hooks", &hc))
+	assert.Equal(t, Hooks{}, hc.HooksForBackup("Documents"))
+	assert.Equal(t, DefaultHookTimeout, hc.HooksForBackup("Documents").TimeoutDuration())
+
+	hc.Overrides = []HooksOverride{{Dir: "/a", Hooks: Hooks{Timeout: "soon"}}}
+	assert.Error(t, hc.Validate())
+}
+
+func TestRunHook(t *testing.T) {
+	ctx := context.Background()
+	vlog := NewVLog(nil, func() bool { return false })
+	outFile := filepath.Join(t.TempDir(), "out")
+
+	// Unset hooks do nothing
+	assert.NoError(t, Hooks{}.Run(ctx, "pre_backup", nil, vlog))
+
+	// The hook sees its name and env
+	hooks := Hooks{PostBackup: "echo \"$TLESS_HOOK $TLESS_SNAPSHOT\" > " + outFile}
+	assert.NoError(t, hooks.Run(ctx, "post_backup", map[string]string{"TLESS_SNAPSHOT": "2022-01-01_00.00.00"}, vlog))
+	buf, err := os.ReadFile(outFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "post_backup 2022-01-01_00.00.00\n", string(buf))
+
+	// A non-zero exit fails, with the last line of its output in the error
+	hooks = Hooks{PreBackup: "echo first; echo 'cannot dump db' >&2; exit 3"}
+	err = hooks.Run(ctx, "pre_backup", nil, vlog)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "pre_backup hook failed")
+	assert.Contains(t, err.Error(), "cannot dump db")
+
+	// So does running too long, which also kills what the hook started
+	hooks = Hooks{PreRestore: "sleep 10 & sleep 10", Timeout: "100ms"}
+	start := time.Now()
+	err = hooks.Run(ctx, "pre_restore", nil, vlog)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRunHookAsUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("needs root to run hooks as another user")
+	}
+	vlog := NewVLog(nil, func() bool { return false })
+	runAs, err := RunAsUserIfRoot("nobody")
+	assert.NoError(t, err)
+
+	// The hook runs as the user, with the user's home dir (reported through its failure)
+	hooks := Hooks{PreBackup: "echo \"$(id -u) $HOME\"; exit 1"}.AsUser(runAs)
+	err = hooks.Run(context.Background(), "pre_backup", nil, vlog)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("%d %s", runAs.Uid, runAs.HomeDir))
+}
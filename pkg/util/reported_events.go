@@ -9,6 +9,8 @@ const (
 	INFO_BACKUP_COMPLETED_WITH_ERRORS ReportedEventKind = 4
 	INFO_BACKUP_CANCELED              ReportedEventKind = 5
 	INFO_AUTOPRUNE_COMPLETED          ReportedEventKind = 6
+	ERR_MANIFEST_MISMATCH             ReportedEventKind = 7
+	ERR_HOOK_FAILED                   ReportedEventKind = 8
 )
 
 type ReportedEvent struct {
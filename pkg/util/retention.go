@@ -0,0 +1,178 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How many snapshots prune keeps. Each keep_* rule keeps the newest snapshot in each of the last
+// N hours/days/weeks/months/years that have a snapshot; KeepLast keeps the N newest outright and
+// KeepWithin keeps everything within that long (eg "1y6m", "2w3d", "36h") of the newest snapshot.
+// A snapshot is kept if any rule keeps it.
+type RetentionPolicy struct {
+	KeepLast    int    `mapstructure:"keep_last"`
+	KeepHourly  int    `mapstructure:"keep_hourly"`
+	KeepDaily   int    `mapstructure:"keep_daily"`
+	KeepWeekly  int    `mapstructure:"keep_weekly"`
+	KeepMonthly int    `mapstructure:"keep_monthly"`
+	KeepYearly  int    `mapstructure:"keep_yearly"`
+	KeepWithin  string `mapstructure:"keep_within"`
+}
+
+// An empty policy means prune falls back to its built-in schedule
+func (p RetentionPolicy) IsEmpty() bool {
+	return p == RetentionPolicy{}
+}
+
+func (p RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepHourly < 0 || p.KeepDaily < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 || p.KeepYearly < 0 {
+		return fmt.Errorf("keep_* counts cannot be negative")
+	}
+	if p.KeepWithin != "" {
+		if _, err := ParseKeepWithin(p.KeepWithin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A calendar duration as used by keep_within
+type KeepWithinDuration struct {
+	Years  int
+	Months int
+	Days   int
+	Hours  int
+}
+
+var keepWithinPartRegex = regexp.MustCompile(`(\d+)([ymwdh])`)
+
+// Parses durations like "1y6m", "2w3d" or "36h" (y=years, m=months, w=weeks, d=days, h=hours)
+func ParseKeepWithin(s string) (KeepWithinDuration, error) {
+	var d KeepWithinDuration
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || keepWithinPartRegex.ReplaceAllString(s, "") != "" {
+		return d, fmt.Errorf("invalid keep_within duration '%s' (expected something like '1y6m', '2w3d' or '36h')", s)
+	}
+	for _, match := range keepWithinPartRegex.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return d, fmt.Errorf("invalid keep_within duration '%s': %v", s, err)
+		}
+		switch match[2] {
+		case "y":
+			d.Years += n
+		case "m":
+			d.Months += n
+		case "w":
+			d.Days += 7 * n
+		case "d":
+			d.Days += n
+		case "h":
+			d.Hours += n
+		}
+	}
+	return d, nil
+}
+
+// Returns the earliest time within d of t
+func (d KeepWithinDuration) Before(t time.Time) time.Time {
+	return t.AddDate(-d.Years, -d.Months, -d.Days).Add(-time.Duration(d.Hours) * time.Hour)
+}
+
+// A retention policy for the backup of one directory, overriding the default
+type RetentionOverride struct {
+	Dir             string `mapstructure:"dir"`
+	RetentionPolicy `mapstructure:",squash"`
+}
+
+// The [retention] config section
+type RetentionConfig struct {
+	RetentionPolicy `mapstructure:",squash"`
+	Overrides       []RetentionOverride `mapstructure:"overrides"`
+}
+
+// Returns the policy for the backup named backupName (the last component of its dir)
+func (rc RetentionConfig) PolicyForBackup(backupName string) RetentionPolicy {
+	for _, override := range rc.Overrides {
+		if filepath.Base(StripTrailingSlashes(override.Dir)) == backupName {
+			return override.RetentionPolicy
+		}
+	}
+	return rc.RetentionPolicy
+}
+
+func (rc RetentionConfig) Validate() error {
+	if err := rc.RetentionPolicy.Validate(); err != nil {
+		return fmt.Errorf("[retention]: %v", err)
+	}
+	for _, override := range rc.Overrides {
+		if override.Dir == "" {
+			return fmt.Errorf("[[retention.overrides]]: dir is required")
+		}
+		if err := override.RetentionPolicy.Validate(); err != nil {
+			return fmt.Errorf("[[retention.overrides]] for '%s': %v", override.Dir, err)
+		}
+	}
+	return nil
+}
+
+func generateRetentionPolicyTemplate(p RetentionPolicy) string {
+	template := ""
+	for _, kv := range []struct {
+		key string
+		n   int
+	}{
+		{"keep_last", p.KeepLast},
+		{"keep_hourly", p.KeepHourly},
+		{"keep_daily", p.KeepDaily},
+		{"keep_weekly", p.KeepWeekly},
+		{"keep_monthly", p.KeepMonthly},
+		{"keep_yearly", p.KeepYearly},
+	} {
+		if kv.n != 0 {
+			template += fmt.Sprintf("%s = %d\n", kv.key, kv.n)
+		}
+	}
+	if p.KeepWithin != "" {
+		template += fmt.Sprintf("keep_within = \"%s\"\n", p.KeepWithin)
+	}
+	return template
+}
+
+func generateRetentionConfigTemplate(rc *RetentionConfig) string {
+	template := `
+[retention]
+# Which snapshots prune (and the daemon's daily autoprune) keeps. If none of
+# these are set, prune keeps everything from the past day plus the oldest and
+# newest snapshots from 1-3, 3-7, 7-30 and 30-360 days ago. Otherwise, a
+# snapshot is kept if any of these rules keeps it:
+#   keep_last    - the N most recent snapshots
+#   keep_hourly  - the newest snapshot in each of the last N hours that have one
+#   keep_daily, keep_weekly, keep_monthly, keep_yearly - likewise
+#   keep_within  - every snapshot within this long of the newest one, such as
+#                  "1y6m", "2w3d" or "36h"
+# Example:
+#   keep_daily = 7
+#   keep_weekly = 4
+#   keep_monthly = 12
+#
+# To use a different policy for one of your backup dirs, add a section like:
+#   [[retention.overrides]]
+#   dir = "/home/<yourname>/Photos"
+#   keep_monthly = 120
+`
+	if rc == nil {
+		return template
+	}
+
+	template += generateRetentionPolicyTemplate(rc.RetentionPolicy)
+	for _, override := range rc.Overrides {
+		template += fmt.Sprintf("\n[[retention.overrides]]\ndir = \"%s\"\n", override.Dir)
+		template += generateRetentionPolicyTemplate(override.RetentionPolicy)
+	}
+	return template
+}
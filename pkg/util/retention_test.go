@@ -0,0 +1,62 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeepWithin(t *testing.T) {
+	d, err := ParseKeepWithin("1y6m")
+	assert.NoError(t, err)
+	assert.Equal(t, KeepWithinDuration{Years: 1, Months: 6}, d)
+
+	d, err = ParseKeepWithin("2w3d12h")
+	assert.NoError(t, err)
+	assert.Equal(t, KeepWithinDuration{Days: 17, Hours: 12}, d)
+
+	tm := time.Date(2022, 3, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 3, 14, 0, 0, 0, 0, time.UTC), d.Before(tm))
+
+	for _, bad := range []string{"", "7", "3x", "1d 2h", "-1d"} {
+		_, err = ParseKeepWithin(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRetentionConfig(t *testing.T) {
+	cfg := &CfgSettings{
+		Retention: RetentionConfig{
+			RetentionPolicy: RetentionPolicy{KeepDaily: 7, KeepWeekly: 4, KeepWithin: "2d"},
+			Overrides: []RetentionOverride{
+				{Dir: "/home/me/Photos/", RetentionPolicy: RetentionPolicy{KeepMonthly: 120}},
+			},
+		},
+	}
+
+	// Round trip through the config template
+	v := viper.New()
+	v.SetConfigType("toml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(GenerateConfigTemplate(cfg))))
+	var rc RetentionConfig
+	assert.NoError(t, v.UnmarshalKey("retention", &rc))
+	assert.Equal(t, cfg.Retention, rc)
+	assert.NoError(t, rc.Validate())
+
+	assert.Equal(t, RetentionPolicy{KeepMonthly: 120}, rc.PolicyForBackup("Photos"))
+	assert.Equal(t, RetentionPolicy{KeepDaily: 7, KeepWeekly: 4, KeepWithin: "2d"}, rc.PolicyForBackup("Documents"))
+
+	// The default template has no policy at all
+	v = viper.New()
+	v.SetConfigType("toml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(GenerateConfigTemplate(nil))))
+	rc = RetentionConfig{}
+	assert.NoError(t, v.UnmarshalKey("retention", &rc))
+	assert.True(t, rc.PolicyForBackup("Documents").IsEmpty())
+
+	rc.Overrides = []RetentionOverride{{Dir: "/a", RetentionPolicy: RetentionPolicy{KeepWithin: "soon"}}}
+	assert.Error(t, rc.Validate())
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// The user a daemon running as root runs commands from that user's config file as, so that
+// anyone who can edit their own config can't use it to run commands as root
+type RunAsUser struct {
+	Username string
+	Uid      uint32
+	Gid      uint32
+	Groups   []uint32
+	HomeDir  string
+}
+
+// Returns username to run commands as if this process is running as root, or nil if it isn't, in
+// which case commands run as the user we already are
+func RunAsUserIfRoot(username string) (*RunAsUser, error) {
+	if os.Geteuid() != 0 {
+		return nil, nil
+	}
+	u, err := user.Lookup(username)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup user '%s': %v", username, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert uid string '%s' to int: %v", u.Uid, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert gid string '%s' to int: %v", u.Gid, err)
+	}
+	runAs := &RunAsUser{Username: u.Username, Uid: uint32(uid), Gid: uint32(gid), HomeDir: u.HomeDir}
+	groupIds, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("could not get the groups of user '%s': %v", username, err)
+	}
+	for _, groupId := range groupIds {
+		if g, err := strconv.ParseUint(groupId, 10, 32); err == nil {
+			runAs.Groups = append(runAs.Groups, uint32(g))
+		}
+	}
+	return runAs, nil
+}
+
+// Makes cmd run as u, with u's HOME, USER and LOGNAME in its environment. Does nothing if u is nil.
+func (u *RunAsUser) Apply(cmd *exec.Cmd) {
+	if u == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: u.Uid, Gid: u.Gid, Groups: u.Groups}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = make([]string, 0, len(env)+3)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, "HOME=") && !strings.HasPrefix(kv, "USER=") && !strings.HasPrefix(kv, "LOGNAME=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	cmd.Env = append(cmd.Env, "HOME="+u.HomeDir, "USER="+u.Username, "LOGNAME="+u.Username)
+}
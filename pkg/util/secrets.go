@@ -0,0 +1,124 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A file descriptor can only be read once, so secrets read from one are kept for the rest of the
+// process (the daemon re-reads its config whenever it changes)
+var (
+	fdSecrets     = make(map[int]string)
+	fdSecretsLock sync.Mutex
+)
+
+// Places other than the config file itself that a secret (the master password or the object
+// store's access secret) can be read from, so that it can be kept in a password manager, a
+// systemd credential or a secrets manager instead
+type SecretSources struct {
+	EnvVar   string     // environment variable holding the secret
+	FdEnvVar string     // environment variable holding the number of an inherited file descriptor to read the secret from
+	File     string     // file holding the secret
+	Command  string     // shell command that prints the secret
+	RunAs    *RunAsUser // user to read File and run Command as, if not the current one
+}
+
+// Returns sources for the master password, read from master_password_command and
+// master_password_file in the config file, or the TLESS_MASTER_PASSWORD and
+// TLESS_MASTER_PASSWORD_FD environment variables
+func MasterPasswordSources(command string, file string) SecretSources {
+	return SecretSources{EnvVar: "TLESS_MASTER_PASSWORD", FdEnvVar: "TLESS_MASTER_PASSWORD_FD", File: file, Command: command}
+}
+
+// Returns sources for the object store's access secret, read from access_secret_command and
+// access_secret_file in the config file, or the TLESS_ACCESS_SECRET and TLESS_ACCESS_SECRET_FD
+// environment variables
+func AccessSecretSources(command string, file string) SecretSources {
+	return SecretSources{EnvVar: "TLESS_ACCESS_SECRET", FdEnvVar: "TLESS_ACCESS_SECRET_FD", File: file, Command: command}
+}
+
+// Reads the secret from the first source that is set, in the order environment variable, file
+// descriptor, file, command. The file is read and the command run as RunAs if it is set. A single
+// trailing newline is removed. Returns the secret and a description of where it came from, or two
+// empty strings if no source is set.
+func (ss SecretSources) Resolve() (secret string, source string, err error) {
+	if ss.EnvVar != "" {
+		if val, ok := os.LookupEnv(ss.EnvVar); ok {
+			return val, "environment variable " + ss.EnvVar, nil
+		}
+	}
+
+	if ss.FdEnvVar != "" {
+		if val, ok := os.LookupEnv(ss.FdEnvVar); ok {
+			fd, err := strconv.Atoi(val)
+			if err != nil || fd < 0 {
+				return "", "", fmt.Errorf("%s is not a file descriptor number (value='%s')", ss.FdEnvVar, val)
+			}
+			fdSecretsLock.Lock()
+			defer fdSecretsLock.Unlock()
+			if secret, ok := fdSecrets[fd]; ok {
+				return secret, fmt.Sprintf("file descriptor %d", fd), nil
+			}
+			f := os.NewFile(uintptr(fd), "secret-fd")
+			if f == nil {
+				return "", "", fmt.Errorf("file descriptor %d is not open", fd)
+			}
+			defer f.Close()
+			buf, err := io.ReadAll(f)
+			if err != nil {
+				return "", "", fmt.Errorf("could not read secret from file descriptor %d: %v", fd, err)
+			}
+			fdSecrets[fd] = trimTrailingNewline(string(buf))
+			return fdSecrets[fd], fmt.Sprintf("file descriptor %d", fd), nil
+		}
+	}
+
+	if ss.File != "" {
+		var buf []byte
+		var err error
+		if ss.RunAs != nil {
+			// Read it with that user's permissions rather than ours
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command("/bin/cat", "--", ss.File)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			ss.RunAs.Apply(cmd)
+			if err = cmd.Run(); err != nil {
+				err = fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+			}
+			buf = stdout.Bytes()
+		} else {
+			buf, err = os.ReadFile(ss.File)
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("could not read secret from '%s': %v", ss.File, err)
+		}
+		return trimTrailingNewline(string(buf)), "file " + ss.File, nil
+	}
+
+	if ss.Command != "" {
+		var stdout bytes.Buffer
+		cmd := exec.Command("/bin/sh", "-c", ss.Command)
+		ss.RunAs.Apply(cmd)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", "", fmt.Errorf("command '%s' failed: %v", ss.Command, err)
+		}
+		return trimTrailingNewline(stdout.String()), "command", nil
+	}
+
+	return "", "", nil
+}
+
+func trimTrailingNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecret(t *testing.T) {
+	envVar := "TLESS_TEST_SECRET"
+	fdEnvVar := "TLESS_TEST_SECRET_FD"
+	os.Unsetenv(envVar)
+	os.Unsetenv(fdEnvVar)
+
+	// No sources set
+	secret, source, err := SecretSources{EnvVar: envVar, FdEnvVar: fdEnvVar}.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "", secret)
+	assert.Equal(t, "", source)
+
+	// Command
+	ss := SecretSources{EnvVar: envVar, FdEnvVar: fdEnvVar, Command: "echo from-command"}
+	secret, source, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-command", secret)
+	assert.Equal(t, "command", source)
+
+	// File takes precedence over command
+	ss.File = filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(ss.File, []byte("from file\n"), 0600))
+	secret, _, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "from file", secret)
+
+	// File descriptor takes precedence over file
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	_, err = w.WriteString("from-fd\n")
+	assert.NoError(t, err)
+	w.Close()
+	t.Setenv(fdEnvVar, fmt.Sprintf("%d", r.Fd()))
+	secret, _, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-fd", secret)
+	secret, _, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-fd", secret)
+
+	// Environment variable takes precedence over everything
+	t.Setenv(envVar, "from-env")
+	secret, source, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", secret)
+	assert.True(t, strings.Contains(source, envVar))
+
+	// Failures are errors rather than empty secrets
+	_, _, err = SecretSources{Command: "exit 3"}.Resolve()
+	assert.Error(t, err)
+	_, _, err = SecretSources{File: filepath.Join(t.TempDir(), "missing")}.Resolve()
+	assert.Error(t, err)
+}
+
+func TestGenerateConfigTemplateKeepsExternalSecretsOut(t *testing.T) {
+	template := GenerateConfigTemplate(&CfgSettings{
+		SecretAccessKey:       "s3-secret",
+		SecretAccessKeyFile:   "/run/credentials/tless/s3",
+		SecretAccessKeySource: "file /run/credentials/tless/s3",
+		MasterPassword:        "master-secret",
+		MasterPasswordCommand: `pass show "tless"`,
+		MasterPasswordSource:  "command",
+	})
+	assert.False(t, strings.Contains(template, "s3-secret"))
+	assert.False(t, strings.Contains(template, "master-secret"))
+	assert.True(t, strings.Contains(template, `access_secret_file = "/run/credentials/tless/s3"`))
+	assert.True(t, strings.Contains(template, `master_password_command = "pass show \"tless\""`))
+}
+
+func TestResolveSecretAsUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("needs root to resolve secrets as another user")
+	}
+	runAs, err := RunAsUserIfRoot("nobody")
+	assert.NoError(t, err)
+
+	// The command runs as the user
+	ss := SecretSources{Command: "id -u", RunAs: runAs}
+	secret, _, err := ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d", runAs.Uid), secret)
+
+	// and a file the user can't read can't be used, though we could read it
+	ss.File = filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(ss.File, []byte("root only\n"), 0600))
+	_, _, err = ss.Resolve()
+	assert.Error(t, err)
+	ss.RunAs = nil
+	secret, _, err = ss.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, "root only", secret)
+}
@@ -0,0 +1,8 @@
+package util
+
+import "syscall"
+
+// Returns the access and status change times of st in nanoseconds since the epoch
+func StatATimeCTimeNs(st *syscall.Stat_t) (aTimeNs int64, cTimeNs int64) {
+	return st.Atim.Nano(), st.Ctim.Nano()
+}
@@ -3,6 +3,7 @@ package util
 
 import (
 	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -61,13 +62,26 @@ type CfgSettings struct {
 	Bucket               string
 	TrustSelfSignedCerts bool
 	MasterPassword       string
+	PrivateKeyPassphrase string
 	Salt                 string
 	Dirs                 []string
 	ExcludePaths         []string
+	Padding              string
 	VerboseDaemon        bool
 	CachesPath           string
 	MaxChunkCacheMb      int64
 	ResourceUtilization  string
+	Retention            RetentionConfig
+	Hooks                HooksConfig
+
+	// Where SecretAccessKey and MasterPassword are read from instead of the config file, if
+	// anywhere. The *Source fields describe where they actually came from ("" if the config file).
+	SecretAccessKeyCommand string
+	SecretAccessKeyFile    string
+	SecretAccessKeySource  string
+	MasterPasswordCommand  string
+	MasterPasswordFile     string
+	MasterPasswordSource   string
 }
 
 func GenerateConfigTemplate(configValues *CfgSettings) string {
@@ -77,7 +91,16 @@ func GenerateConfigTemplate(configValues *CfgSettings) string {
 # created for storing backups.
 #
 # You can leave access_secret blank; you then will need to supply it on each
-# run of the program.
+# run of the program. Or, to keep it out of this file, set access_secret_command
+# to a command that prints it (ex: "pass show tless/s3"), access_secret_file to
+# a file holding it, or the TLESS_ACCESS_SECRET environment variable.
+#
+# The endpoint can also be written as a URL to select the storage backend:
+#   "s3://host:port/bucket" - an S3-compatible object store (bucket may be
+#                             given here instead of below)
+#   "file:///mnt/backup"    - a local directory such as a NAS mount or USB
+#                             disk; each bucket is a subdirectory and no
+#                             credentials are needed
 endpoint = "`
 
 	if configValues != nil && configValues.Endpoint != "" {
@@ -99,13 +122,20 @@ access_key_id = "`
 access_secret = "`
 
 	if configValues != nil && configValues.SecretAccessKey != "" {
-		template += configValues.SecretAccessKey
+		// Secrets read from elsewhere are kept out of this file
+		if configValues.SecretAccessKeySource == "" {
+			template += configValues.SecretAccessKey
+		}
 	} else {
 		template += "<your object store password>"
 	}
 
 	template += `"
-bucket = "`
+`
+	if configValues != nil {
+		template += generateSecretSourcesConfigTemplate("access_secret", configValues.SecretAccessKeyCommand, configValues.SecretAccessKeyFile)
+	}
+	template += `bucket = "`
 
 	if configValues != nil && configValues.Bucket != "" {
 		template += configValues.Bucket
@@ -157,6 +187,21 @@ excludes = [ `
 
 	template += ` ]
 
+# Padding hides the exact sizes of your files and the number of files in each
+# backup from your cloud provider, at the cost of extra storage:
+#   "none"  - no padding
+#   "padme" - rounds sizes up by at most 12%
+#   "pow2"  - rounds sizes up to a power of two (up to twice the storage)
+padding = "`
+
+	if configValues != nil && configValues.Padding != "" {
+		template += configValues.Padding
+	} else {
+		template += "none"
+	}
+
+	template += `"
+
 # The 10-word Diceware passphrase below has been randomly generated for you. 
 # It has ~128 bits of entropy and thus is very resistant to brute force 
 # cracking through at least the middle of this century.
@@ -164,17 +209,34 @@ excludes = [ `
 # Note that your passphrase resides in this file but never leaves this machine.
 #
 # You can leave this field blank; you will need to supply your passphrase on
-# each run of the program.
+# each run of the program. Or, to keep it out of this file, set
+# master_password_command to a command that prints it (ex: "pass show tless"),
+# master_password_file to a file holding it, or the TLESS_MASTER_PASSWORD
+# environment variable.
 master_password = "`
 
 	if configValues != nil && configValues.MasterPassword != "" {
-		template += configValues.MasterPassword
+		// Secrets read from elsewhere are kept out of this file
+		if configValues.MasterPasswordSource == "" {
+			template += configValues.MasterPassword
+		}
 	} else {
 		template += GenerateRandomPassphrase(10)
 	}
-
 	template += `"
+`
+	if configValues != nil {
+		template += generateSecretSourcesConfigTemplate("master_password", configValues.MasterPasswordCommand, configValues.MasterPasswordFile)
+	}
+
+	// Only buckets in write-only mode have a private key passphrase, and it is best kept out of
+	// the config file altogether, so there is no placeholder for it
+	if configValues != nil && configValues.PrivateKeyPassphrase != "" {
+		template += `private_key_passphrase = "` + configValues.PrivateKeyPassphrase + `"
+`
+	}
 
+	template += `
 [daemon]
 # This section affects only the daemon.
 verbose = `
@@ -226,6 +288,30 @@ system_resource_utilization = "`
 	template += `"
 `
 
+	if configValues != nil {
+		template += generateRetentionConfigTemplate(&configValues.Retention)
+	} else {
+		template += generateRetentionConfigTemplate(nil)
+	}
+
+	if configValues != nil {
+		template += generateHooksConfigTemplate(&configValues.Hooks)
+	} else {
+		template += generateHooksConfigTemplate(nil)
+	}
+
+	return template
+}
+
+// Returns the <key>_command and <key>_file lines for whichever of command and file are set
+func generateSecretSourcesConfigTemplate(key string, command string, file string) string {
+	template := ""
+	if command != "" {
+		template += fmt.Sprintf("%s_command = %q\n", key, command)
+	}
+	if file != "" {
+		template += fmt.Sprintf("%s_file = %q\n", key, file)
+	}
 	return template
 }
 
@@ -255,6 +341,24 @@ func GenerateRandomPassphrase(numDicewareWords int) string {
 	return strings.Join(list, "-")
 }
 
+// Returns a printable recovery key holding 256 random bits, as groups of four base32 characters
+// (ex: "ABCD-EFGH-...")
+func GenerateRecoveryKey() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalln("error: could not generate random recovery key", err)
+	}
+	s := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+
+	groups := make([]string, 0)
+	for len(s) > 4 {
+		groups = append(groups, s[:4])
+		s = s[4:]
+	}
+	groups = append(groups, s)
+	return strings.Join(groups, "-")
+}
+
 // Turns a slice of strings into a toml format array, i.e.:
 // "string1", "string2", "string3"
 func sliceToCommaSeparatedString(s []string) string {
@@ -39,6 +39,14 @@ func TestGenerateRandomPassphrase(t *testing.T) {
 	}
 }
 
+func TestGenerateRecoveryKey(t *testing.T) {
+	recoveryKey := GenerateRecoveryKey()
+	groups := strings.Split(recoveryKey, "-")
+	assert.Equal(t, 13, len(groups))
+	assert.Equal(t, 52, len(strings.ReplaceAll(recoveryKey, "-", "")))
+	assert.NotEqual(t, recoveryKey, GenerateRecoveryKey())
+}
+
 func TestSliceToCommaSeparatedString(t *testing.T) {
 	s1 := []string{"a"}
 	s1Result := sliceToCommaSeparatedString(s1)
@@ -29,6 +29,8 @@ const (
 	ReportedEvent_InfoBackupCompletedWithErrors ReportedEvent_ReportedEventKind = 3
 	ReportedEvent_InfoBackupCanceled            ReportedEvent_ReportedEventKind = 4
 	ReportedEvent_InfoAutopruneCompleted        ReportedEvent_ReportedEventKind = 5
+	ReportedEvent_ErrManifestMismatch           ReportedEvent_ReportedEventKind = 6
+	ReportedEvent_ErrHookFailed                 ReportedEvent_ReportedEventKind = 7
 )
 
 // Enum value maps for ReportedEvent_ReportedEventKind.
@@ -40,6 +42,8 @@ var (
 		3: "InfoBackupCompletedWithErrors",
 		4: "InfoBackupCanceled",
 		5: "InfoAutopruneCompleted",
+		6: "ErrManifestMismatch",
+		7: "ErrHookFailed",
 	}
 	ReportedEvent_ReportedEventKind_value = map[string]int32{
 		"ErrOperationNotPermitted":      0,
@@ -48,6 +52,8 @@ var (
 		"InfoBackupCompletedWithErrors": 3,
 		"InfoBackupCanceled":            4,
 		"InfoAutopruneCompleted":        5,
+		"ErrManifestMismatch":           6,
+		"ErrHookFailed":                 7,
 	}
 )
 
@@ -87,6 +93,8 @@ const (
 	DaemonStatusResponse_RESTORING     DaemonStatusResponse_State = 3
 	DaemonStatusResponse_NEED_HELLO    DaemonStatusResponse_State = 4
 	DaemonStatusResponse_CLEANING_UP   DaemonStatusResponse_State = 5
+	DaemonStatusResponse_CHECKING      DaemonStatusResponse_State = 6
+	DaemonStatusResponse_ROTATING_KEY  DaemonStatusResponse_State = 7
 )
 
 // Enum value maps for DaemonStatusResponse_State.
@@ -98,6 +106,8 @@ var (
 		3: "RESTORING",
 		4: "NEED_HELLO",
 		5: "CLEANING_UP",
+		6: "CHECKING",
+		7: "ROTATING_KEY",
 	}
 	DaemonStatusResponse_State_value = map[string]int32{
 		"IDLE":          0,
@@ -106,6 +116,8 @@ var (
 		"RESTORING":     3,
 		"NEED_HELLO":    4,
 		"CLEANING_UP":   5,
+		"CHECKING":      6,
+		"ROTATING_KEY":  7,
 	}
 )
 
@@ -182,6 +194,113 @@ func (CheckConnResponse_CheckConnResult) EnumDescriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{8, 0}
 }
 
+type DiffEntry_DiffKind int32
+
+const (
+	DiffEntry_Added    DiffEntry_DiffKind = 0
+	DiffEntry_Removed  DiffEntry_DiffKind = 1
+	DiffEntry_Modified DiffEntry_DiffKind = 2
+)
+
+// Enum value maps for DiffEntry_DiffKind.
+var (
+	DiffEntry_DiffKind_name = map[int32]string{
+		0: "Added",
+		1: "Removed",
+		2: "Modified",
+	}
+	DiffEntry_DiffKind_value = map[string]int32{
+		"Added":    0,
+		"Removed":  1,
+		"Modified": 2,
+	}
+)
+
+func (x DiffEntry_DiffKind) Enum() *DiffEntry_DiffKind {
+	p := new(DiffEntry_DiffKind)
+	*p = x
+	return p
+}
+
+func (x DiffEntry_DiffKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiffEntry_DiffKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_rpc_proto_enumTypes[3].Descriptor()
+}
+
+func (DiffEntry_DiffKind) Type() protoreflect.EnumType {
+	return &file_rpc_rpc_proto_enumTypes[3]
+}
+
+func (x DiffEntry_DiffKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiffEntry_DiffKind.Descriptor instead.
+func (DiffEntry_DiffKind) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{23, 0}
+}
+
+type CheckProblem_CheckProblemKind int32
+
+const (
+	CheckProblem_MissingChunk    CheckProblem_CheckProblemKind = 0
+	CheckProblem_TruncatedChunk  CheckProblem_CheckProblemKind = 1
+	CheckProblem_OrphanChunk     CheckProblem_CheckProblemKind = 2
+	CheckProblem_BadIndexFile    CheckProblem_CheckProblemKind = 3
+	CheckProblem_BadChunkData    CheckProblem_CheckProblemKind = 4
+	CheckProblem_NonceOutOfOrder CheckProblem_CheckProblemKind = 5
+)
+
+// Enum value maps for CheckProblem_CheckProblemKind.
+var (
+	CheckProblem_CheckProblemKind_name = map[int32]string{
+		0: "MissingChunk",
+		1: "TruncatedChunk",
+		2: "OrphanChunk",
+		3: "BadIndexFile",
+		4: "BadChunkData",
+		5: "NonceOutOfOrder",
+	}
+	CheckProblem_CheckProblemKind_value = map[string]int32{
+		"MissingChunk":    0,
+		"TruncatedChunk":  1,
+		"OrphanChunk":     2,
+		"BadIndexFile":    3,
+		"BadChunkData":    4,
+		"NonceOutOfOrder": 5,
+	}
+)
+
+func (x CheckProblem_CheckProblemKind) Enum() *CheckProblem_CheckProblemKind {
+	p := new(CheckProblem_CheckProblemKind)
+	*p = x
+	return p
+}
+
+func (x CheckProblem_CheckProblemKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CheckProblem_CheckProblemKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_rpc_proto_enumTypes[4].Descriptor()
+}
+
+func (CheckProblem_CheckProblemKind) Type() protoreflect.EnumType {
+	return &file_rpc_rpc_proto_enumTypes[4]
+}
+
+func (x CheckProblem_CheckProblemKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CheckProblem_CheckProblemKind.Descriptor instead.
+func (CheckProblem_CheckProblemKind) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{35, 0}
+}
+
 type CheckBucketPasswordResponse_CheckBucketPasswordResult int32
 
 const (
@@ -218,11 +337,11 @@ func (x CheckBucketPasswordResponse_CheckBucketPasswordResult) String() string {
 }
 
 func (CheckBucketPasswordResponse_CheckBucketPasswordResult) Descriptor() protoreflect.EnumDescriptor {
-	return file_rpc_rpc_proto_enumTypes[3].Descriptor()
+	return file_rpc_rpc_proto_enumTypes[5].Descriptor()
 }
 
 func (CheckBucketPasswordResponse_CheckBucketPasswordResult) Type() protoreflect.EnumType {
-	return &file_rpc_rpc_proto_enumTypes[3]
+	return &file_rpc_rpc_proto_enumTypes[5]
 }
 
 func (x CheckBucketPasswordResponse_CheckBucketPasswordResult) Number() protoreflect.EnumNumber {
@@ -231,7 +350,7 @@ func (x CheckBucketPasswordResponse_CheckBucketPasswordResult) Number() protoref
 
 // Deprecated: Use CheckBucketPasswordResponse_CheckBucketPasswordResult.Descriptor instead.
 func (CheckBucketPasswordResponse_CheckBucketPasswordResult) EnumDescriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{33, 0}
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{42, 0}
 }
 
 type HelloRequest struct {
@@ -1384,10 +1503,12 @@ type SnapshotMetadata struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BackupName        string `protobuf:"bytes,1,opt,name=BackupName,proto3" json:"BackupName,omitempty"`
-	SnapshotName      string `protobuf:"bytes,2,opt,name=SnapshotName,proto3" json:"SnapshotName,omitempty"`
-	SnapshotTimestamp int64  `protobuf:"varint,3,opt,name=SnapshotTimestamp,proto3" json:"SnapshotTimestamp,omitempty"`
-	SnapshotRawName   string `protobuf:"bytes,4,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	BackupName        string   `protobuf:"bytes,1,opt,name=BackupName,proto3" json:"BackupName,omitempty"`
+	SnapshotName      string   `protobuf:"bytes,2,opt,name=SnapshotName,proto3" json:"SnapshotName,omitempty"`
+	SnapshotTimestamp int64    `protobuf:"varint,3,opt,name=SnapshotTimestamp,proto3" json:"SnapshotTimestamp,omitempty"`
+	SnapshotRawName   string   `protobuf:"bytes,4,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	IsPinned          bool     `protobuf:"varint,5,opt,name=IsPinned,proto3" json:"IsPinned,omitempty"`
+	Tags              []string `protobuf:"bytes,6,rep,name=Tags,proto3" json:"Tags,omitempty"`
 }
 
 func (x *SnapshotMetadata) Reset() {
@@ -1450,6 +1571,20 @@ func (x *SnapshotMetadata) GetSnapshotRawName() string {
 	return ""
 }
 
+func (x *SnapshotMetadata) GetIsPinned() bool {
+	if x != nil {
+		return x.IsPinned
+	}
+	return false
+}
+
+func (x *SnapshotMetadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 type ReadAllSnapshotsMetadataResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1639,16 +1774,19 @@ func (x *ReadSnapshotPathsResponse) GetPercentDone() float64 {
 	return 0
 }
 
-type DeleteSnapshotsRequest struct {
+// Leave SnapshotNameB blank to diff against the backup dir as it is now
+type DiffRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SnapshotRawNames []string `protobuf:"bytes,1,rep,name=SnapshotRawNames,proto3" json:"SnapshotRawNames,omitempty"`
+	BackupName    string `protobuf:"bytes,1,opt,name=BackupName,proto3" json:"BackupName,omitempty"`
+	SnapshotNameA string `protobuf:"bytes,2,opt,name=SnapshotNameA,proto3" json:"SnapshotNameA,omitempty"`
+	SnapshotNameB string `protobuf:"bytes,3,opt,name=SnapshotNameB,proto3" json:"SnapshotNameB,omitempty"`
 }
 
-func (x *DeleteSnapshotsRequest) Reset() {
-	*x = DeleteSnapshotsRequest{}
+func (x *DiffRequest) Reset() {
+	*x = DiffRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1656,13 +1794,13 @@ func (x *DeleteSnapshotsRequest) Reset() {
 	}
 }
 
-func (x *DeleteSnapshotsRequest) String() string {
+func (x *DiffRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSnapshotsRequest) ProtoMessage() {}
+func (*DiffRequest) ProtoMessage() {}
 
-func (x *DeleteSnapshotsRequest) ProtoReflect() protoreflect.Message {
+func (x *DiffRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1674,30 +1812,44 @@ func (x *DeleteSnapshotsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteSnapshotsRequest.ProtoReflect.Descriptor instead.
-func (*DeleteSnapshotsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffRequest.ProtoReflect.Descriptor instead.
+func (*DiffRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *DeleteSnapshotsRequest) GetSnapshotRawNames() []string {
+func (x *DiffRequest) GetBackupName() string {
 	if x != nil {
-		return x.SnapshotRawNames
+		return x.BackupName
 	}
-	return nil
+	return ""
 }
 
-type DeleteSnapshotsResponse struct {
+func (x *DiffRequest) GetSnapshotNameA() string {
+	if x != nil {
+		return x.SnapshotNameA
+	}
+	return ""
+}
+
+func (x *DiffRequest) GetSnapshotNameB() string {
+	if x != nil {
+		return x.SnapshotNameB
+	}
+	return ""
+}
+
+type DiffEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed  bool    `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
-	ErrMsg      string  `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
-	PercentDone float64 `protobuf:"fixed64,3,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	Kind    DiffEntry_DiffKind `protobuf:"varint,1,opt,name=Kind,proto3,enum=rpc.DiffEntry_DiffKind" json:"Kind,omitempty"`
+	RelPath string             `protobuf:"bytes,2,opt,name=RelPath,proto3" json:"RelPath,omitempty"`
+	Changes []string           `protobuf:"bytes,3,rep,name=Changes,proto3" json:"Changes,omitempty"`
 }
 
-func (x *DeleteSnapshotsResponse) Reset() {
-	*x = DeleteSnapshotsResponse{}
+func (x *DiffEntry) Reset() {
+	*x = DiffEntry{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1705,13 +1857,13 @@ func (x *DeleteSnapshotsResponse) Reset() {
 	}
 }
 
-func (x *DeleteSnapshotsResponse) String() string {
+func (x *DiffEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSnapshotsResponse) ProtoMessage() {}
+func (*DiffEntry) ProtoMessage() {}
 
-func (x *DeleteSnapshotsResponse) ProtoReflect() protoreflect.Message {
+func (x *DiffEntry) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1723,44 +1875,46 @@ func (x *DeleteSnapshotsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteSnapshotsResponse.ProtoReflect.Descriptor instead.
-func (*DeleteSnapshotsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffEntry.ProtoReflect.Descriptor instead.
+func (*DiffEntry) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *DeleteSnapshotsResponse) GetDidSucceed() bool {
+func (x *DiffEntry) GetKind() DiffEntry_DiffKind {
 	if x != nil {
-		return x.DidSucceed
+		return x.Kind
 	}
-	return false
+	return DiffEntry_Added
 }
 
-func (x *DeleteSnapshotsResponse) GetErrMsg() string {
+func (x *DiffEntry) GetRelPath() string {
 	if x != nil {
-		return x.ErrMsg
+		return x.RelPath
 	}
 	return ""
 }
 
-func (x *DeleteSnapshotsResponse) GetPercentDone() float64 {
+func (x *DiffEntry) GetChanges() []string {
 	if x != nil {
-		return x.PercentDone
+		return x.Changes
 	}
-	return 0
+	return nil
 }
 
-type RestoreRequest struct {
+type DiffResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SnapshotRawName  string   `protobuf:"bytes,1,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
-	RestorePath      string   `protobuf:"bytes,2,opt,name=RestorePath,proto3" json:"RestorePath,omitempty"`
-	SelectedRelPaths []string `protobuf:"bytes,3,rep,name=SelectedRelPaths,proto3" json:"SelectedRelPaths,omitempty"`
+	DidSucceed  bool         `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg      string       `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	PercentDone float64      `protobuf:"fixed64,3,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	Entries     []*DiffEntry `protobuf:"bytes,4,rep,name=Entries,proto3" json:"Entries,omitempty"`
+	IsFinal     bool         `protobuf:"varint,5,opt,name=IsFinal,proto3" json:"IsFinal,omitempty"`
 }
 
-func (x *RestoreRequest) Reset() {
-	*x = RestoreRequest{}
+func (x *DiffResponse) Reset() {
+	*x = DiffResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1768,13 +1922,13 @@ func (x *RestoreRequest) Reset() {
 	}
 }
 
-func (x *RestoreRequest) String() string {
+func (x *DiffResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RestoreRequest) ProtoMessage() {}
+func (*DiffResponse) ProtoMessage() {}
 
-func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+func (x *DiffResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1786,43 +1940,58 @@ func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
-func (*RestoreRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffResponse.ProtoReflect.Descriptor instead.
+func (*DiffResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *RestoreRequest) GetSnapshotRawName() string {
+func (x *DiffResponse) GetDidSucceed() bool {
 	if x != nil {
-		return x.SnapshotRawName
+		return x.DidSucceed
 	}
-	return ""
+	return false
 }
 
-func (x *RestoreRequest) GetRestorePath() string {
+func (x *DiffResponse) GetErrMsg() string {
 	if x != nil {
-		return x.RestorePath
+		return x.ErrMsg
 	}
 	return ""
 }
 
-func (x *RestoreRequest) GetSelectedRelPaths() []string {
+func (x *DiffResponse) GetPercentDone() float64 {
 	if x != nil {
-		return x.SelectedRelPaths
+		return x.PercentDone
+	}
+	return 0
+}
+
+func (x *DiffResponse) GetEntries() []*DiffEntry {
+	if x != nil {
+		return x.Entries
 	}
 	return nil
 }
 
-type RestoreResponse struct {
+func (x *DiffResponse) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+// Pinned snapshots are only deleted if Force is set
+type DeleteSnapshotsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	IsStarting bool   `protobuf:"varint,1,opt,name=isStarting,proto3" json:"isStarting,omitempty"`
-	ErrMsg     string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	SnapshotRawNames []string `protobuf:"bytes,1,rep,name=SnapshotRawNames,proto3" json:"SnapshotRawNames,omitempty"`
+	Force            bool     `protobuf:"varint,2,opt,name=Force,proto3" json:"Force,omitempty"`
 }
 
-func (x *RestoreResponse) Reset() {
-	*x = RestoreResponse{}
+func (x *DeleteSnapshotsRequest) Reset() {
+	*x = DeleteSnapshotsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1830,13 +1999,13 @@ func (x *RestoreResponse) Reset() {
 	}
 }
 
-func (x *RestoreResponse) String() string {
+func (x *DeleteSnapshotsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RestoreResponse) ProtoMessage() {}
+func (*DeleteSnapshotsRequest) ProtoMessage() {}
 
-func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteSnapshotsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1848,33 +2017,37 @@ func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RestoreResponse.ProtoReflect.Descriptor instead.
-func (*RestoreResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotsRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *RestoreResponse) GetIsStarting() bool {
+func (x *DeleteSnapshotsRequest) GetSnapshotRawNames() []string {
 	if x != nil {
-		return x.IsStarting
+		return x.SnapshotRawNames
 	}
-	return false
+	return nil
 }
 
-func (x *RestoreResponse) GetErrMsg() string {
+func (x *DeleteSnapshotsRequest) GetForce() bool {
 	if x != nil {
-		return x.ErrMsg
+		return x.Force
 	}
-	return ""
+	return false
 }
 
-type WipeCloudRequest struct {
+type DeleteSnapshotsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	DidSucceed  bool    `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg      string  `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	PercentDone float64 `protobuf:"fixed64,3,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
 }
 
-func (x *WipeCloudRequest) Reset() {
-	*x = WipeCloudRequest{}
+func (x *DeleteSnapshotsResponse) Reset() {
+	*x = DeleteSnapshotsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1882,13 +2055,13 @@ func (x *WipeCloudRequest) Reset() {
 	}
 }
 
-func (x *WipeCloudRequest) String() string {
+func (x *DeleteSnapshotsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WipeCloudRequest) ProtoMessage() {}
+func (*DeleteSnapshotsResponse) ProtoMessage() {}
 
-func (x *WipeCloudRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteSnapshotsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1900,23 +2073,44 @@ func (x *WipeCloudRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WipeCloudRequest.ProtoReflect.Descriptor instead.
-func (*WipeCloudRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSnapshotsResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{26}
 }
 
-type WipeCloudResponse struct {
+func (x *DeleteSnapshotsResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *DeleteSnapshotsResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+func (x *DeleteSnapshotsResponse) GetPercentDone() float64 {
+	if x != nil {
+		return x.PercentDone
+	}
+	return 0
+}
+
+type TagSnapshotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed  bool    `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
-	PercentDone float64 `protobuf:"fixed64,2,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
-	ErrMsg      string  `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	SnapshotRawName string   `protobuf:"bytes,1,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	AddTags         []string `protobuf:"bytes,2,rep,name=AddTags,proto3" json:"AddTags,omitempty"`
+	RemoveTags      []string `protobuf:"bytes,3,rep,name=RemoveTags,proto3" json:"RemoveTags,omitempty"`
 }
 
-func (x *WipeCloudResponse) Reset() {
-	*x = WipeCloudResponse{}
+func (x *TagSnapshotRequest) Reset() {
+	*x = TagSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1924,13 +2118,13 @@ func (x *WipeCloudResponse) Reset() {
 	}
 }
 
-func (x *WipeCloudResponse) String() string {
+func (x *TagSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WipeCloudResponse) ProtoMessage() {}
+func (*TagSnapshotRequest) ProtoMessage() {}
 
-func (x *WipeCloudResponse) ProtoReflect() protoreflect.Message {
+func (x *TagSnapshotRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1942,40 +2136,43 @@ func (x *WipeCloudResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WipeCloudResponse.ProtoReflect.Descriptor instead.
-func (*WipeCloudResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TagSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*TagSnapshotRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *WipeCloudResponse) GetDidSucceed() bool {
+func (x *TagSnapshotRequest) GetSnapshotRawName() string {
 	if x != nil {
-		return x.DidSucceed
+		return x.SnapshotRawName
 	}
-	return false
+	return ""
 }
 
-func (x *WipeCloudResponse) GetPercentDone() float64 {
+func (x *TagSnapshotRequest) GetAddTags() []string {
 	if x != nil {
-		return x.PercentDone
+		return x.AddTags
 	}
-	return 0
+	return nil
 }
 
-func (x *WipeCloudResponse) GetErrMsg() string {
+func (x *TagSnapshotRequest) GetRemoveTags() []string {
 	if x != nil {
-		return x.ErrMsg
+		return x.RemoveTags
 	}
-	return ""
+	return nil
 }
 
-type ListBucketsRequest struct {
+type PinSnapshotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	SnapshotRawName string `protobuf:"bytes,1,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	Pinned          bool   `protobuf:"varint,2,opt,name=Pinned,proto3" json:"Pinned,omitempty"`
 }
 
-func (x *ListBucketsRequest) Reset() {
-	*x = ListBucketsRequest{}
+func (x *PinSnapshotRequest) Reset() {
+	*x = PinSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1983,13 +2180,13 @@ func (x *ListBucketsRequest) Reset() {
 	}
 }
 
-func (x *ListBucketsRequest) String() string {
+func (x *PinSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBucketsRequest) ProtoMessage() {}
+func (*PinSnapshotRequest) ProtoMessage() {}
 
-func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
+func (x *PinSnapshotRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2001,22 +2198,38 @@ func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBucketsRequest.ProtoReflect.Descriptor instead.
-func (*ListBucketsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PinSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*PinSnapshotRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{28}
 }
 
-type ListBucketsResponse struct {
+func (x *PinSnapshotRequest) GetSnapshotRawName() string {
+	if x != nil {
+		return x.SnapshotRawName
+	}
+	return ""
+}
+
+func (x *PinSnapshotRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+type SnapshotAnnotationsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Buckets []string `protobuf:"bytes,1,rep,name=Buckets,proto3" json:"Buckets,omitempty"`
-	ErrMsg  string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	DidSucceed bool     `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg     string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	Tags       []string `protobuf:"bytes,3,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	IsPinned   bool     `protobuf:"varint,4,opt,name=IsPinned,proto3" json:"IsPinned,omitempty"`
 }
 
-func (x *ListBucketsResponse) Reset() {
-	*x = ListBucketsResponse{}
+func (x *SnapshotAnnotationsResponse) Reset() {
+	*x = SnapshotAnnotationsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2024,13 +2237,13 @@ func (x *ListBucketsResponse) Reset() {
 	}
 }
 
-func (x *ListBucketsResponse) String() string {
+func (x *SnapshotAnnotationsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBucketsResponse) ProtoMessage() {}
+func (*SnapshotAnnotationsResponse) ProtoMessage() {}
 
-func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
+func (x *SnapshotAnnotationsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2042,36 +2255,51 @@ func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBucketsResponse.ProtoReflect.Descriptor instead.
-func (*ListBucketsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SnapshotAnnotationsResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotAnnotationsResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *ListBucketsResponse) GetBuckets() []string {
+func (x *SnapshotAnnotationsResponse) GetDidSucceed() bool {
 	if x != nil {
-		return x.Buckets
+		return x.DidSucceed
 	}
-	return nil
+	return false
 }
 
-func (x *ListBucketsResponse) GetErrMsg() string {
+func (x *SnapshotAnnotationsResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-type MakeBucketRequest struct {
+func (x *SnapshotAnnotationsResponse) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SnapshotAnnotationsResponse) GetIsPinned() bool {
+	if x != nil {
+		return x.IsPinned
+	}
+	return false
+}
+
+type RestoreRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BucketName string `protobuf:"bytes,1,opt,name=BucketName,proto3" json:"BucketName,omitempty"`
-	Region     string `protobuf:"bytes,2,opt,name=Region,proto3" json:"Region,omitempty"`
+	SnapshotRawName  string   `protobuf:"bytes,1,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	RestorePath      string   `protobuf:"bytes,2,opt,name=RestorePath,proto3" json:"RestorePath,omitempty"`
+	SelectedRelPaths []string `protobuf:"bytes,3,rep,name=SelectedRelPaths,proto3" json:"SelectedRelPaths,omitempty"`
 }
 
-func (x *MakeBucketRequest) Reset() {
-	*x = MakeBucketRequest{}
+func (x *RestoreRequest) Reset() {
+	*x = RestoreRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2079,13 +2307,13 @@ func (x *MakeBucketRequest) Reset() {
 	}
 }
 
-func (x *MakeBucketRequest) String() string {
+func (x *RestoreRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MakeBucketRequest) ProtoMessage() {}
+func (*RestoreRequest) ProtoMessage() {}
 
-func (x *MakeBucketRequest) ProtoReflect() protoreflect.Message {
+func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2097,36 +2325,43 @@ func (x *MakeBucketRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MakeBucketRequest.ProtoReflect.Descriptor instead.
-func (*MakeBucketRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *MakeBucketRequest) GetBucketName() string {
+func (x *RestoreRequest) GetSnapshotRawName() string {
 	if x != nil {
-		return x.BucketName
+		return x.SnapshotRawName
 	}
 	return ""
 }
 
-func (x *MakeBucketRequest) GetRegion() string {
+func (x *RestoreRequest) GetRestorePath() string {
 	if x != nil {
-		return x.Region
+		return x.RestorePath
 	}
 	return ""
 }
 
-type MakeBucketResponse struct {
+func (x *RestoreRequest) GetSelectedRelPaths() []string {
+	if x != nil {
+		return x.SelectedRelPaths
+	}
+	return nil
+}
+
+type RestoreResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed bool   `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	IsStarting bool   `protobuf:"varint,1,opt,name=isStarting,proto3" json:"isStarting,omitempty"`
 	ErrMsg     string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
 }
 
-func (x *MakeBucketResponse) Reset() {
-	*x = MakeBucketResponse{}
+func (x *RestoreResponse) Reset() {
+	*x = RestoreResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2134,13 +2369,13 @@ func (x *MakeBucketResponse) Reset() {
 	}
 }
 
-func (x *MakeBucketResponse) String() string {
+func (x *RestoreResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MakeBucketResponse) ProtoMessage() {}
+func (*RestoreResponse) ProtoMessage() {}
 
-func (x *MakeBucketResponse) ProtoReflect() protoreflect.Message {
+func (x *RestoreResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2152,36 +2387,33 @@ func (x *MakeBucketResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MakeBucketResponse.ProtoReflect.Descriptor instead.
-func (*MakeBucketResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RestoreResponse.ProtoReflect.Descriptor instead.
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *MakeBucketResponse) GetDidSucceed() bool {
+func (x *RestoreResponse) GetIsStarting() bool {
 	if x != nil {
-		return x.DidSucceed
+		return x.IsStarting
 	}
 	return false
 }
 
-func (x *MakeBucketResponse) GetErrMsg() string {
+func (x *RestoreResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-type CheckBucketPasswordRequest struct {
+type WipeCloudRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	BucketName string `protobuf:"bytes,1,opt,name=BucketName,proto3" json:"BucketName,omitempty"`
-	Password   string `protobuf:"bytes,2,opt,name=Password,proto3" json:"Password,omitempty"`
 }
 
-func (x *CheckBucketPasswordRequest) Reset() {
-	*x = CheckBucketPasswordRequest{}
+func (x *WipeCloudRequest) Reset() {
+	*x = WipeCloudRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2189,13 +2421,13 @@ func (x *CheckBucketPasswordRequest) Reset() {
 	}
 }
 
-func (x *CheckBucketPasswordRequest) String() string {
+func (x *WipeCloudRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckBucketPasswordRequest) ProtoMessage() {}
+func (*WipeCloudRequest) ProtoMessage() {}
 
-func (x *CheckBucketPasswordRequest) ProtoReflect() protoreflect.Message {
+func (x *WipeCloudRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2207,36 +2439,23 @@ func (x *CheckBucketPasswordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckBucketPasswordRequest.ProtoReflect.Descriptor instead.
-func (*CheckBucketPasswordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WipeCloudRequest.ProtoReflect.Descriptor instead.
+func (*WipeCloudRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *CheckBucketPasswordRequest) GetBucketName() string {
-	if x != nil {
-		return x.BucketName
-	}
-	return ""
-}
-
-func (x *CheckBucketPasswordRequest) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
-}
-
-type CheckBucketPasswordResponse struct {
+type WipeCloudResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result CheckBucketPasswordResponse_CheckBucketPasswordResult `protobuf:"varint,1,opt,name=Result,proto3,enum=rpc.CheckBucketPasswordResponse_CheckBucketPasswordResult" json:"Result,omitempty"`
-	ErrMsg string                                                `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	DidSucceed  bool    `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	PercentDone float64 `protobuf:"fixed64,2,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	ErrMsg      string  `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
 }
 
-func (x *CheckBucketPasswordResponse) Reset() {
-	*x = CheckBucketPasswordResponse{}
+func (x *WipeCloudResponse) Reset() {
+	*x = WipeCloudResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2244,13 +2463,13 @@ func (x *CheckBucketPasswordResponse) Reset() {
 	}
 }
 
-func (x *CheckBucketPasswordResponse) String() string {
+func (x *WipeCloudResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckBucketPasswordResponse) ProtoMessage() {}
+func (*WipeCloudResponse) ProtoMessage() {}
 
-func (x *CheckBucketPasswordResponse) ProtoReflect() protoreflect.Message {
+func (x *WipeCloudResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2262,33 +2481,42 @@ func (x *CheckBucketPasswordResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckBucketPasswordResponse.ProtoReflect.Descriptor instead.
-func (*CheckBucketPasswordResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WipeCloudResponse.ProtoReflect.Descriptor instead.
+func (*WipeCloudResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *CheckBucketPasswordResponse) GetResult() CheckBucketPasswordResponse_CheckBucketPasswordResult {
+func (x *WipeCloudResponse) GetDidSucceed() bool {
 	if x != nil {
-		return x.Result
+		return x.DidSucceed
 	}
-	return CheckBucketPasswordResponse_SUCCESS
+	return false
 }
 
-func (x *CheckBucketPasswordResponse) GetErrMsg() string {
+func (x *WipeCloudResponse) GetPercentDone() float64 {
+	if x != nil {
+		return x.PercentDone
+	}
+	return 0
+}
+
+func (x *WipeCloudResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-type GetUsageHistoryRequest struct {
+type CheckRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	ReadDataPercent float64 `protobuf:"fixed64,1,opt,name=ReadDataPercent,proto3" json:"ReadDataPercent,omitempty"`
 }
 
-func (x *GetUsageHistoryRequest) Reset() {
-	*x = GetUsageHistoryRequest{}
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2296,13 +2524,13 @@ func (x *GetUsageHistoryRequest) Reset() {
 	}
 }
 
-func (x *GetUsageHistoryRequest) String() string {
+func (x *CheckRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUsageHistoryRequest) ProtoMessage() {}
+func (*CheckRequest) ProtoMessage() {}
 
-func (x *GetUsageHistoryRequest) ProtoReflect() protoreflect.Message {
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2314,22 +2542,32 @@ func (x *GetUsageHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUsageHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetUsageHistoryRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{34}
 }
 
-type DailyUsage struct {
+func (x *CheckRequest) GetReadDataPercent() float64 {
+	if x != nil {
+		return x.ReadDataPercent
+	}
+	return 0
+}
+
+type CheckProblem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DayYmd    string `protobuf:"bytes,1,opt,name=DayYmd,proto3" json:"DayYmd,omitempty"`
-	ByteCount int64  `protobuf:"varint,2,opt,name=ByteCount,proto3" json:"ByteCount,omitempty"`
+	Kind      CheckProblem_CheckProblemKind `protobuf:"varint,1,opt,name=Kind,proto3,enum=rpc.CheckProblem_CheckProblemKind" json:"Kind,omitempty"`
+	Snapshot  string                        `protobuf:"bytes,2,opt,name=Snapshot,proto3" json:"Snapshot,omitempty"`
+	RelPath   string                        `protobuf:"bytes,3,opt,name=RelPath,proto3" json:"RelPath,omitempty"`
+	ChunkName string                        `protobuf:"bytes,4,opt,name=ChunkName,proto3" json:"ChunkName,omitempty"`
+	Msg       string                        `protobuf:"bytes,5,opt,name=Msg,proto3" json:"Msg,omitempty"`
 }
 
-func (x *DailyUsage) Reset() {
-	*x = DailyUsage{}
+func (x *CheckProblem) Reset() {
+	*x = CheckProblem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2337,13 +2575,13 @@ func (x *DailyUsage) Reset() {
 	}
 }
 
-func (x *DailyUsage) String() string {
+func (x *CheckProblem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DailyUsage) ProtoMessage() {}
+func (*CheckProblem) ProtoMessage() {}
 
-func (x *DailyUsage) ProtoReflect() protoreflect.Message {
+func (x *CheckProblem) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2355,38 +2593,67 @@ func (x *DailyUsage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DailyUsage.ProtoReflect.Descriptor instead.
-func (*DailyUsage) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckProblem.ProtoReflect.Descriptor instead.
+func (*CheckProblem) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *DailyUsage) GetDayYmd() string {
+func (x *CheckProblem) GetKind() CheckProblem_CheckProblemKind {
 	if x != nil {
-		return x.DayYmd
+		return x.Kind
+	}
+	return CheckProblem_MissingChunk
+}
+
+func (x *CheckProblem) GetSnapshot() string {
+	if x != nil {
+		return x.Snapshot
 	}
 	return ""
 }
 
-func (x *DailyUsage) GetByteCount() int64 {
+func (x *CheckProblem) GetRelPath() string {
 	if x != nil {
-		return x.ByteCount
+		return x.RelPath
 	}
-	return 0
+	return ""
 }
 
-type GetUsageHistoryResponse struct {
+func (x *CheckProblem) GetChunkName() string {
+	if x != nil {
+		return x.ChunkName
+	}
+	return ""
+}
+
+func (x *CheckProblem) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// Partial responses carry only progress; the final response has the results
+type CheckResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed          bool          `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
-	ErrMsg              string        `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
-	PeakSpaceUsage      []*DailyUsage `protobuf:"bytes,3,rep,name=PeakSpaceUsage,proto3" json:"PeakSpaceUsage,omitempty"`
-	TotalBandwidthUsage []*DailyUsage `protobuf:"bytes,4,rep,name=TotalBandwidthUsage,proto3" json:"TotalBandwidthUsage,omitempty"`
-}
-
-func (x *GetUsageHistoryResponse) Reset() {
-	*x = GetUsageHistoryResponse{}
+	DidSucceed       bool            `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	PercentDone      float64         `protobuf:"fixed64,2,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	ErrMsg           string          `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	IsFinal          bool            `protobuf:"varint,4,opt,name=IsFinal,proto3" json:"IsFinal,omitempty"`
+	SnapshotsChecked int64           `protobuf:"varint,5,opt,name=SnapshotsChecked,proto3" json:"SnapshotsChecked,omitempty"`
+	ChunksReferenced int64           `protobuf:"varint,6,opt,name=ChunksReferenced,proto3" json:"ChunksReferenced,omitempty"`
+	ChunksInBucket   int64           `protobuf:"varint,7,opt,name=ChunksInBucket,proto3" json:"ChunksInBucket,omitempty"`
+	ChunksRead       int64           `protobuf:"varint,8,opt,name=ChunksRead,proto3" json:"ChunksRead,omitempty"`
+	BytesRead        int64           `protobuf:"varint,9,opt,name=BytesRead,proto3" json:"BytesRead,omitempty"`
+	Problems         []*CheckProblem `protobuf:"bytes,10,rep,name=Problems,proto3" json:"Problems,omitempty"`
+	OrphanChunks     []*CheckProblem `protobuf:"bytes,11,rep,name=OrphanChunks,proto3" json:"OrphanChunks,omitempty"`
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_rpc_rpc_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2394,13 +2661,13 @@ func (x *GetUsageHistoryResponse) Reset() {
 	}
 }
 
-func (x *GetUsageHistoryResponse) String() string {
+func (x *CheckResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUsageHistoryResponse) ProtoMessage() {}
+func (*CheckResponse) ProtoMessage() {}
 
-func (x *GetUsageHistoryResponse) ProtoReflect() protoreflect.Message {
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_rpc_rpc_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2412,62 +2679,1116 @@ func (x *GetUsageHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUsageHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetUsageHistoryResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
 	return file_rpc_rpc_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *GetUsageHistoryResponse) GetDidSucceed() bool {
+func (x *CheckResponse) GetDidSucceed() bool {
 	if x != nil {
 		return x.DidSucceed
 	}
 	return false
 }
 
-func (x *GetUsageHistoryResponse) GetErrMsg() string {
+func (x *CheckResponse) GetPercentDone() float64 {
+	if x != nil {
+		return x.PercentDone
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-func (x *GetUsageHistoryResponse) GetPeakSpaceUsage() []*DailyUsage {
+func (x *CheckResponse) GetIsFinal() bool {
 	if x != nil {
-		return x.PeakSpaceUsage
+		return x.IsFinal
+	}
+	return false
+}
+
+func (x *CheckResponse) GetSnapshotsChecked() int64 {
+	if x != nil {
+		return x.SnapshotsChecked
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetChunksReferenced() int64 {
+	if x != nil {
+		return x.ChunksReferenced
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetChunksInBucket() int64 {
+	if x != nil {
+		return x.ChunksInBucket
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetChunksRead() int64 {
+	if x != nil {
+		return x.ChunksRead
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetBytesRead() int64 {
+	if x != nil {
+		return x.BytesRead
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetProblems() []*CheckProblem {
+	if x != nil {
+		return x.Problems
 	}
 	return nil
 }
 
-func (x *GetUsageHistoryResponse) GetTotalBandwidthUsage() []*DailyUsage {
+func (x *CheckResponse) GetOrphanChunks() []*CheckProblem {
+	if x != nil {
+		return x.OrphanChunks
+	}
+	return nil
+}
+
+type ListBucketsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListBucketsRequest) Reset() {
+	*x = ListBucketsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBucketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsRequest) ProtoMessage() {}
+
+func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsRequest.ProtoReflect.Descriptor instead.
+func (*ListBucketsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{37}
+}
+
+type ListBucketsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Buckets []string `protobuf:"bytes,1,rep,name=Buckets,proto3" json:"Buckets,omitempty"`
+	ErrMsg  string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+}
+
+func (x *ListBucketsResponse) Reset() {
+	*x = ListBucketsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBucketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsResponse) ProtoMessage() {}
+
+func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsResponse.ProtoReflect.Descriptor instead.
+func (*ListBucketsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListBucketsResponse) GetBuckets() []string {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *ListBucketsResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+type MakeBucketRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BucketName string `protobuf:"bytes,1,opt,name=BucketName,proto3" json:"BucketName,omitempty"`
+	Region     string `protobuf:"bytes,2,opt,name=Region,proto3" json:"Region,omitempty"`
+}
+
+func (x *MakeBucketRequest) Reset() {
+	*x = MakeBucketRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MakeBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeBucketRequest) ProtoMessage() {}
+
+func (x *MakeBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeBucketRequest.ProtoReflect.Descriptor instead.
+func (*MakeBucketRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *MakeBucketRequest) GetBucketName() string {
+	if x != nil {
+		return x.BucketName
+	}
+	return ""
+}
+
+func (x *MakeBucketRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type MakeBucketResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DidSucceed bool   `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg     string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+}
+
+func (x *MakeBucketResponse) Reset() {
+	*x = MakeBucketResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MakeBucketResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeBucketResponse) ProtoMessage() {}
+
+func (x *MakeBucketResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeBucketResponse.ProtoReflect.Descriptor instead.
+func (*MakeBucketResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *MakeBucketResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *MakeBucketResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+type CheckBucketPasswordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BucketName string `protobuf:"bytes,1,opt,name=BucketName,proto3" json:"BucketName,omitempty"`
+	Password   string `protobuf:"bytes,2,opt,name=Password,proto3" json:"Password,omitempty"`
+}
+
+func (x *CheckBucketPasswordRequest) Reset() {
+	*x = CheckBucketPasswordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckBucketPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckBucketPasswordRequest) ProtoMessage() {}
+
+func (x *CheckBucketPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckBucketPasswordRequest.ProtoReflect.Descriptor instead.
+func (*CheckBucketPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CheckBucketPasswordRequest) GetBucketName() string {
+	if x != nil {
+		return x.BucketName
+	}
+	return ""
+}
+
+func (x *CheckBucketPasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type CheckBucketPasswordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result CheckBucketPasswordResponse_CheckBucketPasswordResult `protobuf:"varint,1,opt,name=Result,proto3,enum=rpc.CheckBucketPasswordResponse_CheckBucketPasswordResult" json:"Result,omitempty"`
+	ErrMsg string                                                `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+}
+
+func (x *CheckBucketPasswordResponse) Reset() {
+	*x = CheckBucketPasswordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckBucketPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckBucketPasswordResponse) ProtoMessage() {}
+
+func (x *CheckBucketPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckBucketPasswordResponse.ProtoReflect.Descriptor instead.
+func (*CheckBucketPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CheckBucketPasswordResponse) GetResult() CheckBucketPasswordResponse_CheckBucketPasswordResult {
+	if x != nil {
+		return x.Result
+	}
+	return CheckBucketPasswordResponse_SUCCESS
+}
+
+func (x *CheckBucketPasswordResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+type GetUsageHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetUsageHistoryRequest) Reset() {
+	*x = GetUsageHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUsageHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageHistoryRequest) ProtoMessage() {}
+
+func (x *GetUsageHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{43}
+}
+
+type DailyUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DayYmd           string `protobuf:"bytes,1,opt,name=DayYmd,proto3" json:"DayYmd,omitempty"`
+	ByteCount        int64  `protobuf:"varint,2,opt,name=ByteCount,proto3" json:"ByteCount,omitempty"`
+	PaddingByteCount int64  `protobuf:"varint,3,opt,name=PaddingByteCount,proto3" json:"PaddingByteCount,omitempty"` // of ByteCount; only set for space usage
+}
+
+func (x *DailyUsage) Reset() {
+	*x = DailyUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DailyUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyUsage) ProtoMessage() {}
+
+func (x *DailyUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyUsage.ProtoReflect.Descriptor instead.
+func (*DailyUsage) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DailyUsage) GetDayYmd() string {
+	if x != nil {
+		return x.DayYmd
+	}
+	return ""
+}
+
+func (x *DailyUsage) GetByteCount() int64 {
+	if x != nil {
+		return x.ByteCount
+	}
+	return 0
+}
+
+func (x *DailyUsage) GetPaddingByteCount() int64 {
+	if x != nil {
+		return x.PaddingByteCount
+	}
+	return 0
+}
+
+type GetUsageHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DidSucceed          bool          `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg              string        `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	PeakSpaceUsage      []*DailyUsage `protobuf:"bytes,3,rep,name=PeakSpaceUsage,proto3" json:"PeakSpaceUsage,omitempty"`
+	TotalBandwidthUsage []*DailyUsage `protobuf:"bytes,4,rep,name=TotalBandwidthUsage,proto3" json:"TotalBandwidthUsage,omitempty"`
+}
+
+func (x *GetUsageHistoryResponse) Reset() {
+	*x = GetUsageHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUsageHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageHistoryResponse) ProtoMessage() {}
+
+func (x *GetUsageHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetUsageHistoryResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *GetUsageHistoryResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+func (x *GetUsageHistoryResponse) GetPeakSpaceUsage() []*DailyUsage {
+	if x != nil {
+		return x.PeakSpaceUsage
+	}
+	return nil
+}
+
+func (x *GetUsageHistoryResponse) GetTotalBandwidthUsage() []*DailyUsage {
+	if x != nil {
+		return x.TotalBandwidthUsage
+	}
+	return nil
+}
+
+type GetSnapshotSpaceUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetSnapshotSpaceUsageRequest) Reset() {
+	*x = GetSnapshotSpaceUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSnapshotSpaceUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSnapshotSpaceUsageRequest) ProtoMessage() {}
+
+func (x *GetSnapshotSpaceUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSnapshotSpaceUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetSnapshotSpaceUsageRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{46}
+}
+
+type Chunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	ByteCount int64  `protobuf:"varint,2,opt,name=ByteCount,proto3" json:"ByteCount,omitempty"`
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *Chunk) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Chunk) GetByteCount() int64 {
+	if x != nil {
+		return x.ByteCount
+	}
+	return 0
+}
+
+type SnapshotUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BackupName         string   `protobuf:"bytes,1,opt,name=BackupName,proto3" json:"BackupName,omitempty"`
+	SnapshotName       string   `protobuf:"bytes,2,opt,name=SnapshotName,proto3" json:"SnapshotName,omitempty"`
+	SnapshotRawName    string   `protobuf:"bytes,3,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
+	IndexFileByteCount int64    `protobuf:"varint,4,opt,name=IndexFileByteCount,proto3" json:"IndexFileByteCount,omitempty"`
+	Chunks             []*Chunk `protobuf:"bytes,5,rep,name=Chunks,proto3" json:"Chunks,omitempty"`
+}
+
+func (x *SnapshotUsage) Reset() {
+	*x = SnapshotUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotUsage) ProtoMessage() {}
+
+func (x *SnapshotUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotUsage.ProtoReflect.Descriptor instead.
+func (*SnapshotUsage) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SnapshotUsage) GetBackupName() string {
+	if x != nil {
+		return x.BackupName
+	}
+	return ""
+}
+
+func (x *SnapshotUsage) GetSnapshotName() string {
+	if x != nil {
+		return x.SnapshotName
+	}
+	return ""
+}
+
+func (x *SnapshotUsage) GetSnapshotRawName() string {
+	if x != nil {
+		return x.SnapshotRawName
+	}
+	return ""
+}
+
+func (x *SnapshotUsage) GetIndexFileByteCount() int64 {
+	if x != nil {
+		return x.IndexFileByteCount
+	}
+	return 0
+}
+
+func (x *SnapshotUsage) GetChunks() []*Chunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type GetSnapshotSpaceUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DidSucceed    bool             `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg        string           `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	SnapshotUsage []*SnapshotUsage `protobuf:"bytes,3,rep,name=SnapshotUsage,proto3" json:"SnapshotUsage,omitempty"`
+	PercentDone   float64          `protobuf:"fixed64,4,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+}
+
+func (x *GetSnapshotSpaceUsageResponse) Reset() {
+	*x = GetSnapshotSpaceUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSnapshotSpaceUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSnapshotSpaceUsageResponse) ProtoMessage() {}
+
+func (x *GetSnapshotSpaceUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSnapshotSpaceUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetSnapshotSpaceUsageResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetSnapshotSpaceUsageResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *GetSnapshotSpaceUsageResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+func (x *GetSnapshotSpaceUsageResponse) GetSnapshotUsage() []*SnapshotUsage {
+	if x != nil {
+		return x.SnapshotUsage
+	}
+	return nil
+}
+
+func (x *GetSnapshotSpaceUsageResponse) GetPercentDone() float64 {
+	if x != nil {
+		return x.PercentDone
+	}
+	return 0
+}
+
+type LogStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LogPath        string `protobuf:"bytes,1,opt,name=LogPath,proto3" json:"LogPath,omitempty"`
+	StartingOffset int64  `protobuf:"varint,2,opt,name=StartingOffset,proto3" json:"StartingOffset,omitempty"`
+}
+
+func (x *LogStreamRequest) Reset() {
+	*x = LogStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogStreamRequest) ProtoMessage() {}
+
+func (x *LogStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogStreamRequest.ProtoReflect.Descriptor instead.
+func (*LogStreamRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *LogStreamRequest) GetLogPath() string {
+	if x != nil {
+		return x.LogPath
+	}
+	return ""
+}
+
+func (x *LogStreamRequest) GetStartingOffset() int64 {
+	if x != nil {
+		return x.StartingOffset
+	}
+	return 0
+}
+
+type LogStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DidSucceed  bool     `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg      string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	LogLines    []string `protobuf:"bytes,3,rep,name=LogLines,proto3" json:"LogLines,omitempty"`
+	NextOffset  int64    `protobuf:"varint,4,opt,name=NextOffset,proto3" json:"NextOffset,omitempty"`
+	PercentDone float64  `protobuf:"fixed64,5,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+}
+
+func (x *LogStreamResponse) Reset() {
+	*x = LogStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogStreamResponse) ProtoMessage() {}
+
+func (x *LogStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogStreamResponse.ProtoReflect.Descriptor instead.
+func (*LogStreamResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *LogStreamResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *LogStreamResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+func (x *LogStreamResponse) GetLogLines() []string {
+	if x != nil {
+		return x.LogLines
+	}
+	return nil
+}
+
+func (x *LogStreamResponse) GetNextOffset() int64 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *LogStreamResponse) GetPercentDone() float64 {
+	if x != nil {
+		return x.PercentDone
+	}
+	return 0
+}
+
+type ChangePasswordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OldPassword      string `protobuf:"bytes,1,opt,name=OldPassword,proto3" json:"OldPassword,omitempty"`
+	NewPassword      string `protobuf:"bytes,2,opt,name=NewPassword,proto3" json:"NewPassword,omitempty"`
+	UpdateConfigFile bool   `protobuf:"varint,3,opt,name=UpdateConfigFile,proto3" json:"UpdateConfigFile,omitempty"`
+	// Argon2id parameters for the new password; if KdfTime is 0 the current ones are kept
+	KdfTime      uint32 `protobuf:"varint,4,opt,name=KdfTime,proto3" json:"KdfTime,omitempty"`
+	KdfMemoryKiB uint32 `protobuf:"varint,5,opt,name=KdfMemoryKiB,proto3" json:"KdfMemoryKiB,omitempty"`
+	KdfThreads   uint32 `protobuf:"varint,6,opt,name=KdfThreads,proto3" json:"KdfThreads,omitempty"`
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetUpdateConfigFile() bool {
+	if x != nil {
+		return x.UpdateConfigFile
+	}
+	return false
+}
+
+func (x *ChangePasswordRequest) GetKdfTime() uint32 {
+	if x != nil {
+		return x.KdfTime
+	}
+	return 0
+}
+
+func (x *ChangePasswordRequest) GetKdfMemoryKiB() uint32 {
+	if x != nil {
+		return x.KdfMemoryKiB
+	}
+	return 0
+}
+
+func (x *ChangePasswordRequest) GetKdfThreads() uint32 {
+	if x != nil {
+		return x.KdfThreads
+	}
+	return 0
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DidSucceed bool   `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg     string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ChangePasswordResponse) GetDidSucceed() bool {
+	if x != nil {
+		return x.DidSucceed
+	}
+	return false
+}
+
+func (x *ChangePasswordResponse) GetErrMsg() string {
 	if x != nil {
-		return x.TotalBandwidthUsage
+		return x.ErrMsg
 	}
-	return nil
+	return ""
 }
 
-type GetSnapshotSpaceUsageRequest struct {
+type RotateKeyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *GetSnapshotSpaceUsageRequest) Reset() {
-	*x = GetSnapshotSpaceUsageRequest{}
+func (x *RotateKeyRequest) Reset() {
+	*x = RotateKeyRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[37]
+		mi := &file_rpc_rpc_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSnapshotSpaceUsageRequest) String() string {
+func (x *RotateKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSnapshotSpaceUsageRequest) ProtoMessage() {}
+func (*RotateKeyRequest) ProtoMessage() {}
 
-func (x *GetSnapshotSpaceUsageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[37]
+func (x *RotateKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2478,37 +3799,39 @@ func (x *GetSnapshotSpaceUsageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSnapshotSpaceUsageRequest.ProtoReflect.Descriptor instead.
-func (*GetSnapshotSpaceUsageRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use RotateKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateKeyRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{54}
 }
 
-type Chunk struct {
+type RotateKeyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name      string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	ByteCount int64  `protobuf:"varint,2,opt,name=ByteCount,proto3" json:"ByteCount,omitempty"`
+	DidSucceed  bool    `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	PercentDone float64 `protobuf:"fixed64,2,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	ErrMsg      string  `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	IsFinal     bool    `protobuf:"varint,4,opt,name=IsFinal,proto3" json:"IsFinal,omitempty"`
 }
 
-func (x *Chunk) Reset() {
-	*x = Chunk{}
+func (x *RotateKeyResponse) Reset() {
+	*x = RotateKeyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[38]
+		mi := &file_rpc_rpc_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Chunk) String() string {
+func (x *RotateKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Chunk) ProtoMessage() {}
+func (*RotateKeyResponse) ProtoMessage() {}
 
-func (x *Chunk) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[38]
+func (x *RotateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2519,54 +3842,62 @@ func (x *Chunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
-func (*Chunk) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use RotateKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *Chunk) GetName() string {
+func (x *RotateKeyResponse) GetDidSucceed() bool {
 	if x != nil {
-		return x.Name
+		return x.DidSucceed
 	}
-	return ""
+	return false
 }
 
-func (x *Chunk) GetByteCount() int64 {
+func (x *RotateKeyResponse) GetPercentDone() float64 {
 	if x != nil {
-		return x.ByteCount
+		return x.PercentDone
 	}
 	return 0
 }
 
-type SnapshotUsage struct {
+func (x *RotateKeyResponse) GetErrMsg() string {
+	if x != nil {
+		return x.ErrMsg
+	}
+	return ""
+}
+
+func (x *RotateKeyResponse) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+type ListKeySlotsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	BackupName         string   `protobuf:"bytes,1,opt,name=BackupName,proto3" json:"BackupName,omitempty"`
-	SnapshotName       string   `protobuf:"bytes,2,opt,name=SnapshotName,proto3" json:"SnapshotName,omitempty"`
-	SnapshotRawName    string   `protobuf:"bytes,3,opt,name=SnapshotRawName,proto3" json:"SnapshotRawName,omitempty"`
-	IndexFileByteCount int64    `protobuf:"varint,4,opt,name=IndexFileByteCount,proto3" json:"IndexFileByteCount,omitempty"`
-	Chunks             []*Chunk `protobuf:"bytes,5,rep,name=Chunks,proto3" json:"Chunks,omitempty"`
 }
 
-func (x *SnapshotUsage) Reset() {
-	*x = SnapshotUsage{}
+func (x *ListKeySlotsRequest) Reset() {
+	*x = ListKeySlotsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[39]
+		mi := &file_rpc_rpc_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SnapshotUsage) String() string {
+func (x *ListKeySlotsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SnapshotUsage) ProtoMessage() {}
+func (*ListKeySlotsRequest) ProtoMessage() {}
 
-func (x *SnapshotUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[39]
+func (x *ListKeySlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2577,74 +3908,101 @@ func (x *SnapshotUsage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SnapshotUsage.ProtoReflect.Descriptor instead.
-func (*SnapshotUsage) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use ListKeySlotsRequest.ProtoReflect.Descriptor instead.
+func (*ListKeySlotsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *SnapshotUsage) GetBackupName() string {
-	if x != nil {
-		return x.BackupName
+type KeySlotInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	IsRecoveryKey bool   `protobuf:"varint,2,opt,name=IsRecoveryKey,proto3" json:"IsRecoveryKey,omitempty"`
+	CreatedUnix   int64  `protobuf:"varint,3,opt,name=CreatedUnix,proto3" json:"CreatedUnix,omitempty"`
+}
+
+func (x *KeySlotInfo) Reset() {
+	*x = KeySlotInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_rpc_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *SnapshotUsage) GetSnapshotName() string {
-	if x != nil {
-		return x.SnapshotName
+func (x *KeySlotInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeySlotInfo) ProtoMessage() {}
+
+func (x *KeySlotInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *SnapshotUsage) GetSnapshotRawName() string {
+// Deprecated: Use KeySlotInfo.ProtoReflect.Descriptor instead.
+func (*KeySlotInfo) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *KeySlotInfo) GetName() string {
 	if x != nil {
-		return x.SnapshotRawName
+		return x.Name
 	}
 	return ""
 }
 
-func (x *SnapshotUsage) GetIndexFileByteCount() int64 {
+func (x *KeySlotInfo) GetIsRecoveryKey() bool {
 	if x != nil {
-		return x.IndexFileByteCount
+		return x.IsRecoveryKey
 	}
-	return 0
+	return false
 }
 
-func (x *SnapshotUsage) GetChunks() []*Chunk {
+func (x *KeySlotInfo) GetCreatedUnix() int64 {
 	if x != nil {
-		return x.Chunks
+		return x.CreatedUnix
 	}
-	return nil
+	return 0
 }
 
-type GetSnapshotSpaceUsageResponse struct {
+type ListKeySlotsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed    bool             `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
-	ErrMsg        string           `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
-	SnapshotUsage []*SnapshotUsage `protobuf:"bytes,3,rep,name=SnapshotUsage,proto3" json:"SnapshotUsage,omitempty"`
-	PercentDone   float64          `protobuf:"fixed64,4,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	DidSucceed bool           `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg     string         `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	KeySlots   []*KeySlotInfo `protobuf:"bytes,3,rep,name=KeySlots,proto3" json:"KeySlots,omitempty"`
 }
 
-func (x *GetSnapshotSpaceUsageResponse) Reset() {
-	*x = GetSnapshotSpaceUsageResponse{}
+func (x *ListKeySlotsResponse) Reset() {
+	*x = ListKeySlotsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[40]
+		mi := &file_rpc_rpc_proto_msgTypes[58]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSnapshotSpaceUsageResponse) String() string {
+func (x *ListKeySlotsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSnapshotSpaceUsageResponse) ProtoMessage() {}
+func (*ListKeySlotsResponse) ProtoMessage() {}
 
-func (x *GetSnapshotSpaceUsageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[40]
+func (x *ListKeySlotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[58]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2655,65 +4013,60 @@ func (x *GetSnapshotSpaceUsageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSnapshotSpaceUsageResponse.ProtoReflect.Descriptor instead.
-func (*GetSnapshotSpaceUsageResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use ListKeySlotsResponse.ProtoReflect.Descriptor instead.
+func (*ListKeySlotsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *GetSnapshotSpaceUsageResponse) GetDidSucceed() bool {
+func (x *ListKeySlotsResponse) GetDidSucceed() bool {
 	if x != nil {
 		return x.DidSucceed
 	}
 	return false
 }
 
-func (x *GetSnapshotSpaceUsageResponse) GetErrMsg() string {
+func (x *ListKeySlotsResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-func (x *GetSnapshotSpaceUsageResponse) GetSnapshotUsage() []*SnapshotUsage {
+func (x *ListKeySlotsResponse) GetKeySlots() []*KeySlotInfo {
 	if x != nil {
-		return x.SnapshotUsage
+		return x.KeySlots
 	}
 	return nil
 }
 
-func (x *GetSnapshotSpaceUsageResponse) GetPercentDone() float64 {
-	if x != nil {
-		return x.PercentDone
-	}
-	return 0
-}
-
-type LogStreamRequest struct {
+// If IsRecoveryKey is set, Passphrase is ignored and a recovery key is generated and returned
+type AddKeySlotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	LogPath        string `protobuf:"bytes,1,opt,name=LogPath,proto3" json:"LogPath,omitempty"`
-	StartingOffset int64  `protobuf:"varint,2,opt,name=StartingOffset,proto3" json:"StartingOffset,omitempty"`
+	Name          string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Passphrase    string `protobuf:"bytes,2,opt,name=Passphrase,proto3" json:"Passphrase,omitempty"`
+	IsRecoveryKey bool   `protobuf:"varint,3,opt,name=IsRecoveryKey,proto3" json:"IsRecoveryKey,omitempty"`
 }
 
-func (x *LogStreamRequest) Reset() {
-	*x = LogStreamRequest{}
+func (x *AddKeySlotRequest) Reset() {
+	*x = AddKeySlotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[41]
+		mi := &file_rpc_rpc_proto_msgTypes[59]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LogStreamRequest) String() string {
+func (x *AddKeySlotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogStreamRequest) ProtoMessage() {}
+func (*AddKeySlotRequest) ProtoMessage() {}
 
-func (x *LogStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[41]
+func (x *AddKeySlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[59]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2724,54 +4077,59 @@ func (x *LogStreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogStreamRequest.ProtoReflect.Descriptor instead.
-func (*LogStreamRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use AddKeySlotRequest.ProtoReflect.Descriptor instead.
+func (*AddKeySlotRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *LogStreamRequest) GetLogPath() string {
+func (x *AddKeySlotRequest) GetName() string {
 	if x != nil {
-		return x.LogPath
+		return x.Name
 	}
 	return ""
 }
 
-func (x *LogStreamRequest) GetStartingOffset() int64 {
+func (x *AddKeySlotRequest) GetPassphrase() string {
 	if x != nil {
-		return x.StartingOffset
+		return x.Passphrase
 	}
-	return 0
+	return ""
 }
 
-type LogStreamResponse struct {
+func (x *AddKeySlotRequest) GetIsRecoveryKey() bool {
+	if x != nil {
+		return x.IsRecoveryKey
+	}
+	return false
+}
+
+type AddKeySlotResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DidSucceed  bool     `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
-	ErrMsg      string   `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
-	LogLines    []string `protobuf:"bytes,3,rep,name=LogLines,proto3" json:"LogLines,omitempty"`
-	NextOffset  int64    `protobuf:"varint,4,opt,name=NextOffset,proto3" json:"NextOffset,omitempty"`
-	PercentDone float64  `protobuf:"fixed64,5,opt,name=PercentDone,proto3" json:"PercentDone,omitempty"`
+	DidSucceed  bool   `protobuf:"varint,1,opt,name=DidSucceed,proto3" json:"DidSucceed,omitempty"`
+	ErrMsg      string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	RecoveryKey string `protobuf:"bytes,3,opt,name=RecoveryKey,proto3" json:"RecoveryKey,omitempty"`
 }
 
-func (x *LogStreamResponse) Reset() {
-	*x = LogStreamResponse{}
+func (x *AddKeySlotResponse) Reset() {
+	*x = AddKeySlotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[42]
+		mi := &file_rpc_rpc_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *LogStreamResponse) String() string {
+func (x *AddKeySlotResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogStreamResponse) ProtoMessage() {}
+func (*AddKeySlotResponse) ProtoMessage() {}
 
-func (x *LogStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[42]
+func (x *AddKeySlotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2782,73 +4140,57 @@ func (x *LogStreamResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogStreamResponse.ProtoReflect.Descriptor instead.
-func (*LogStreamResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use AddKeySlotResponse.ProtoReflect.Descriptor instead.
+func (*AddKeySlotResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *LogStreamResponse) GetDidSucceed() bool {
+func (x *AddKeySlotResponse) GetDidSucceed() bool {
 	if x != nil {
 		return x.DidSucceed
 	}
 	return false
 }
 
-func (x *LogStreamResponse) GetErrMsg() string {
+func (x *AddKeySlotResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
 	return ""
 }
 
-func (x *LogStreamResponse) GetLogLines() []string {
-	if x != nil {
-		return x.LogLines
-	}
-	return nil
-}
-
-func (x *LogStreamResponse) GetNextOffset() int64 {
-	if x != nil {
-		return x.NextOffset
-	}
-	return 0
-}
-
-func (x *LogStreamResponse) GetPercentDone() float64 {
+func (x *AddKeySlotResponse) GetRecoveryKey() string {
 	if x != nil {
-		return x.PercentDone
+		return x.RecoveryKey
 	}
-	return 0
+	return ""
 }
 
-type ChangePasswordRequest struct {
+type RevokeKeySlotRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	OldPassword      string `protobuf:"bytes,1,opt,name=OldPassword,proto3" json:"OldPassword,omitempty"`
-	NewPassword      string `protobuf:"bytes,2,opt,name=NewPassword,proto3" json:"NewPassword,omitempty"`
-	UpdateConfigFile bool   `protobuf:"varint,3,opt,name=UpdateConfigFile,proto3" json:"UpdateConfigFile,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
 }
 
-func (x *ChangePasswordRequest) Reset() {
-	*x = ChangePasswordRequest{}
+func (x *RevokeKeySlotRequest) Reset() {
+	*x = RevokeKeySlotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[43]
+		mi := &file_rpc_rpc_proto_msgTypes[61]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChangePasswordRequest) String() string {
+func (x *RevokeKeySlotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChangePasswordRequest) ProtoMessage() {}
+func (*RevokeKeySlotRequest) ProtoMessage() {}
 
-func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[43]
+func (x *RevokeKeySlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[61]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2859,33 +4201,19 @@ func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
-func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{43}
-}
-
-func (x *ChangePasswordRequest) GetOldPassword() string {
-	if x != nil {
-		return x.OldPassword
-	}
-	return ""
+// Deprecated: Use RevokeKeySlotRequest.ProtoReflect.Descriptor instead.
+func (*RevokeKeySlotRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *ChangePasswordRequest) GetNewPassword() string {
+func (x *RevokeKeySlotRequest) GetName() string {
 	if x != nil {
-		return x.NewPassword
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ChangePasswordRequest) GetUpdateConfigFile() bool {
-	if x != nil {
-		return x.UpdateConfigFile
-	}
-	return false
-}
-
-type ChangePasswordResponse struct {
+type RevokeKeySlotResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -2894,23 +4222,23 @@ type ChangePasswordResponse struct {
 	ErrMsg     string `protobuf:"bytes,2,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
 }
 
-func (x *ChangePasswordResponse) Reset() {
-	*x = ChangePasswordResponse{}
+func (x *RevokeKeySlotResponse) Reset() {
+	*x = RevokeKeySlotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[44]
+		mi := &file_rpc_rpc_proto_msgTypes[62]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChangePasswordResponse) String() string {
+func (x *RevokeKeySlotResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChangePasswordResponse) ProtoMessage() {}
+func (*RevokeKeySlotResponse) ProtoMessage() {}
 
-func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[44]
+func (x *RevokeKeySlotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_rpc_proto_msgTypes[62]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2921,19 +4249,19 @@ func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
-func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use RevokeKeySlotResponse.ProtoReflect.Descriptor instead.
+func (*RevokeKeySlotResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *ChangePasswordResponse) GetDidSucceed() bool {
+func (x *RevokeKeySlotResponse) GetDidSucceed() bool {
 	if x != nil {
 		return x.DidSucceed
 	}
 	return false
 }
 
-func (x *ChangePasswordResponse) GetErrMsg() string {
+func (x *RevokeKeySlotResponse) GetErrMsg() string {
 	if x != nil {
 		return x.ErrMsg
 	}
@@ -2949,7 +4277,7 @@ type GeneratePassphraseRequest struct {
 func (x *GeneratePassphraseRequest) Reset() {
 	*x = GeneratePassphraseRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[45]
+		mi := &file_rpc_rpc_proto_msgTypes[63]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2962,7 +4290,7 @@ func (x *GeneratePassphraseRequest) String() string {
 func (*GeneratePassphraseRequest) ProtoMessage() {}
 
 func (x *GeneratePassphraseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[45]
+	mi := &file_rpc_rpc_proto_msgTypes[63]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2975,7 +4303,7 @@ func (x *GeneratePassphraseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GeneratePassphraseRequest.ProtoReflect.Descriptor instead.
 func (*GeneratePassphraseRequest) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{45}
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{63}
 }
 
 type GeneratePassphraseResponse struct {
@@ -2991,7 +4319,7 @@ type GeneratePassphraseResponse struct {
 func (x *GeneratePassphraseResponse) Reset() {
 	*x = GeneratePassphraseResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_rpc_rpc_proto_msgTypes[46]
+		mi := &file_rpc_rpc_proto_msgTypes[64]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3004,7 +4332,7 @@ func (x *GeneratePassphraseResponse) String() string {
 func (*GeneratePassphraseResponse) ProtoMessage() {}
 
 func (x *GeneratePassphraseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rpc_rpc_proto_msgTypes[46]
+	mi := &file_rpc_rpc_proto_msgTypes[64]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3017,7 +4345,7 @@ func (x *GeneratePassphraseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GeneratePassphraseResponse.ProtoReflect.Descriptor instead.
 func (*GeneratePassphraseResponse) Descriptor() ([]byte, []int) {
-	return file_rpc_rpc_proto_rawDescGZIP(), []int{46}
+	return file_rpc_rpc_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *GeneratePassphraseResponse) GetDidSucceed() bool {
@@ -3062,7 +4390,7 @@ var file_rpc_rpc_proto_rawDesc = []byte{
 	0x6d, 0x6d, 0x69, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
 	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x15, 0x0a, 0x13, 0x44, 0x61,
 	0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x22, 0xe7, 0x02, 0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76,
+	0x74, 0x22, 0x93, 0x03, 0x0a, 0x0d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76,
 	0x65, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0e, 0x32, 0x24, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64,
 	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76,
@@ -3072,7 +4400,7 @@ var file_rpc_rpc_proto_rawDesc = []byte{
 	0x52, 0x05, 0x49, 0x73, 0x44, 0x69, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x65, 0x74,
 	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x44, 0x61, 0x74, 0x65, 0x74,
 	0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x4d, 0x73, 0x67, 0x22, 0xc3, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x03, 0x4d, 0x73, 0x67, 0x22, 0xef, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
 	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x18, 0x45,
 	0x72, 0x72, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f, 0x74, 0x50, 0x65,
 	0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x49, 0x6e, 0x66,
@@ -3084,198 +4412,307 @@ var file_rpc_rpc_proto_rawDesc = []byte{
 	0x72, 0x72, 0x6f, 0x72, 0x73, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x6e, 0x66, 0x6f, 0x42,
 	0x61, 0x63, 0x6b, 0x75, 0x70, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x65, 0x64, 0x10, 0x04, 0x12,
 	0x1a, 0x0a, 0x16, 0x49, 0x6e, 0x66, 0x6f, 0x41, 0x75, 0x74, 0x6f, 0x70, 0x72, 0x75, 0x6e, 0x65,
-	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x05, 0x22, 0xa3, 0x02, 0x0a, 0x14,
-	0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x65, 0x6d, 0x6f,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x10, 0x0a,
-	0x03, 0x6d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12,
-	0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x02, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12,
-	0x3a, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65,
-	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0e, 0x72, 0x65, 0x70,
-	0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x64, 0x0a, 0x05, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x11,
-	0x0a, 0x0d, 0x43, 0x48, 0x45, 0x43, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x10,
-	0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x41, 0x43, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x55, 0x50, 0x10,
-	0x02, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x45, 0x53, 0x54, 0x4f, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x03,
-	0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x45, 0x45, 0x44, 0x5f, 0x48, 0x45, 0x4c, 0x4c, 0x4f, 0x10, 0x04,
-	0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4c, 0x45, 0x41, 0x4e, 0x49, 0x4e, 0x47, 0x5f, 0x55, 0x50, 0x10,
-	0x05, 0x22, 0xbe, 0x01, 0x0a, 0x10, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
-	0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79,
-	0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1e,
-	0x0a, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x32,
-	0x0a, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65,
-	0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x54, 0x72,
-	0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72,
-	0x74, 0x73, 0x22, 0x9a, 0x01, 0x0a, 0x11, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43,
-	0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x4d, 0x73, 0x67, 0x22, 0x29, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e,
-	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43, 0x43, 0x45,
-	0x53, 0x53, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x22,
-	0x13, 0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0xf4, 0x03, 0x0a, 0x12, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x45,
-	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45,
-	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x41, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b,
-	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66,
-	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65,
-	0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x53, 0x61, 0x6c, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x53,
-	0x61, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x69, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x45,
+	0x72, 0x72, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x4d, 0x69, 0x73, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x72, 0x72, 0x48, 0x6f, 0x6f, 0x6b, 0x46,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x07, 0x22, 0xc4, 0x02, 0x0a, 0x14, 0x44, 0x61, 0x65, 0x6d,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x37, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x3a, 0x0a, 0x0e, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0e, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x43,
+	0x48, 0x45, 0x43, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x10, 0x01, 0x12, 0x0e,
+	0x0a, 0x0a, 0x42, 0x41, 0x43, 0x4b, 0x49, 0x4e, 0x47, 0x5f, 0x55, 0x50, 0x10, 0x02, 0x12, 0x0d,
+	0x0a, 0x09, 0x52, 0x45, 0x53, 0x54, 0x4f, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0e, 0x0a,
+	0x0a, 0x4e, 0x45, 0x45, 0x44, 0x5f, 0x48, 0x45, 0x4c, 0x4c, 0x4f, 0x10, 0x04, 0x12, 0x0f, 0x0a,
+	0x0b, 0x43, 0x4c, 0x45, 0x41, 0x4e, 0x49, 0x4e, 0x47, 0x5f, 0x55, 0x50, 0x10, 0x05, 0x12, 0x0c,
+	0x0a, 0x08, 0x43, 0x48, 0x45, 0x43, 0x4b, 0x49, 0x4e, 0x47, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c,
+	0x52, 0x4f, 0x54, 0x41, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x10, 0x07, 0x22, 0xbe,
+	0x01, 0x0a, 0x10, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a,
+	0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x42,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x54,
+	0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65,
+	0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74,
+	0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x22,
+	0x9a, 0x01, 0x0a, 0x11, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73,
+	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73,
+	0x67, 0x22, 0x29, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10,
+	0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x22, 0x13, 0x0a, 0x11,
+	0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0xf4, 0x03, 0x0a, 0x12, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b,
+	0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79,
+	0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x32, 0x0a, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14,
+	0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43,
+	0x65, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4d, 0x61,
+	0x73, 0x74, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x53, 0x61, 0x6c, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x53, 0x61, 0x6c, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x44, 0x69, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
+	0x44, 0x69, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x61,
+	0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x43, 0x61, 0x63, 0x68, 0x65, 0x4d, 0x62, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x4d, 0x62, 0x12, 0x30, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x13, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x74, 0x69, 0x6c, 0x69,
+	0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x49, 0x73, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49, 0x73, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0xae, 0x03, 0x0a, 0x12, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x41,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74,
+	0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x4d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x69, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
 	0x09, 0x52, 0x04, 0x44, 0x69, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78, 0x63, 0x6c, 0x75,
-	0x64, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x45, 0x78, 0x63, 0x6c, 0x75,
-	0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x18, 0x0a,
+	0x64, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x45, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x18, 0x09,
 	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x0a, 0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x0a, 0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x12, 0x28, 0x0a,
 	0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x43, 0x61, 0x63, 0x68, 0x65, 0x4d, 0x62,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b,
 	0x43, 0x61, 0x63, 0x68, 0x65, 0x4d, 0x62, 0x12, 0x30, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d,
+	0x72, 0x63, 0x65, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x74,
-	0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x49, 0x73, 0x56,
-	0x61, 0x6c, 0x69, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49, 0x73, 0x56, 0x61,
-	0x6c, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x0f, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0xae, 0x03, 0x0a, 0x12,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c,
-	0x0a, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09,
-	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x75,
-	0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x54, 0x72,
-	0x75, 0x73, 0x74, 0x53, 0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72,
-	0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x54, 0x72, 0x75, 0x73, 0x74, 0x53,
-	0x65, 0x6c, 0x66, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x73, 0x12, 0x26,
-	0x0a, 0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x69, 0x72, 0x73, 0x18, 0x07,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x44, 0x69, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78,
-	0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x45, 0x78,
-	0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73,
-	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x56, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65,
-	0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x61, 0x63, 0x68, 0x65, 0x73, 0x50, 0x61, 0x74, 0x68,
-	0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x43, 0x61, 0x63, 0x68,
-	0x65, 0x4d, 0x62, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x43, 0x68,
-	0x75, 0x6e, 0x6b, 0x43, 0x61, 0x63, 0x68, 0x65, 0x4d, 0x62, 0x12, 0x30, 0x0a, 0x13, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-	0x65, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x13,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63,
-	0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x39, 0x0a, 0x0d, 0x42,
-	0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f,
-	0x46, 0x6f, 0x72, 0x63, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x46, 0x75, 0x6c, 0x6c,
-	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x22, 0x48, 0x0a, 0x0e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d,
-	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67,
-	0x22, 0x0f, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x22, 0x48, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e,
-	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x21, 0x0a, 0x1f, 0x52,
-	0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x4d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xae,
-	0x01, 0x0a, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e,
-	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x22,
-	0x9d, 0x01, 0x0a, 0x20, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65,
-	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63,
-	0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x41, 0x0a, 0x10,
+	0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x13, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x39, 0x0a, 0x0d, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x46, 0x75, 0x6c, 0x6c, 0x42, 0x61, 0x63,
+	0x6b, 0x75, 0x70, 0x22, 0x48, 0x0a, 0x0e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x0f, 0x0a,
+	0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48,
+	0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67,
+	0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x21, 0x0a, 0x1f, 0x52, 0x65, 0x61, 0x64,
+	0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xde, 0x01, 0x0a, 0x10,
 	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x6e, 0x61,
-	0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x10, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22,
-	0x5e, 0x0a, 0x18, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50,
-	0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x42,
-	0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22,
-	0x91, 0x01, 0x0a, 0x19, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
+	0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61,
+	0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x49, 0x73, 0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x49, 0x73, 0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x22, 0x9d, 0x01, 0x0a,
+	0x20, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x41, 0x0a, 0x10, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x10, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x5e, 0x0a, 0x18,
+	0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x91, 0x01, 0x0a,
+	0x19, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69,
+	0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72,
+	0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d,
+	0x73, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65,
+	0x22, 0x79, 0x0a, 0x0b, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x24, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x41,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x41, 0x12, 0x24, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x22, 0x9e, 0x01, 0x0a, 0x09,
+	0x44, 0x69, 0x66, 0x66, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x2b, 0x0a, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x69,
+	0x66, 0x66, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x4b, 0x69, 0x6e, 0x64,
+	0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x30, 0x0a, 0x08, 0x44, 0x69,
+	0x66, 0x66, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10,
+	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0c,
+	0x0a, 0x08, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x64, 0x10, 0x02, 0x22, 0xac, 0x01, 0x0a,
+	0x0c, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
 	0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a,
 	0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45,
-	0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68,
-	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68,
-	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44,
-	0x6f, 0x6e, 0x65, 0x22, 0x44, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61,
-	0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a,
+	0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x44, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x45, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44,
+	0x69, 0x66, 0x66, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x49, 0x73, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x49, 0x73, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0x5a, 0x0a, 0x16, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
 	0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x73, 0x0a, 0x17, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65,
-	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63,
-	0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x20, 0x0a, 0x0b,
-	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x22, 0x88,
-	0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77,
-	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52,
-	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2a, 0x0a,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x73, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65,
+	0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x22, 0x78, 0x0a, 0x12,
+	0x54, 0x61, 0x67, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61,
+	0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x41, 0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x41,
+	0x64, 0x64, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x54, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x54, 0x61, 0x67, 0x73, 0x22, 0x56, 0x0a, 0x12, 0x50, 0x69, 0x6e, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52,
+	0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x22, 0x85,
+	0x01, 0x0a, 0x1b, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x41, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x73,
+	0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x49, 0x73,
+	0x50, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x22, 0x88, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2a, 0x0a, 0x10, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
 	0x10, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68,
-	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
-	0x64, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0x49, 0x0a, 0x0f, 0x52, 0x65, 0x73,
-	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x69, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0a, 0x69, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06,
-	0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72,
-	0x72, 0x4d, 0x73, 0x67, 0x22, 0x12, 0x0a, 0x10, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75,
-	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x6d, 0x0a, 0x11, 0x57, 0x69, 0x70, 0x65,
-	0x43, 0x6c, 0x6f, 0x75, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x20, 0x0a,
-	0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x42,
+	0x73, 0x22, 0x49, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x73, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69,
+	0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x12, 0x0a, 0x10,
+	0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x6d, 0x0a, 0x11, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63,
+	0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75,
+	0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x44, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22,
+	0x38, 0x0a, 0x0c, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x28, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x44, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x44, 0x61,
+	0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0xb1, 0x02, 0x0a, 0x0c, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x12, 0x36, 0x0a, 0x04, 0x4b, 0x69,
+	0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x2e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x4b, 0x69,
+	0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x52, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x73, 0x67, 0x22, 0x82, 0x01, 0x0a, 0x10, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x10, 0x0a,
+	0x0c, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x10, 0x00, 0x12,
+	0x12, 0x0a, 0x0e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x61, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x46, 0x69, 0x6c, 0x65, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x61, 0x64, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x4e, 0x6f, 0x6e, 0x63,
+	0x65, 0x4f, 0x75, 0x74, 0x4f, 0x66, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x05, 0x22, 0xa7, 0x03,
+	0x0a, 0x0d, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x49, 0x73, 0x46,
+	0x69, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49, 0x73, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x12,
+	0x2a, 0x0a, 0x10, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x73, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x73, 0x49, 0x6e, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x49, 0x6e, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x61,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52,
+	0x65, 0x61, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x61,
+	0x64, 0x12, 0x2d, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73, 0x18, 0x0a, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50,
+	0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x73,
+	0x12, 0x35, 0x0a, 0x0c, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x52, 0x0c, 0x4f, 0x72, 0x70, 0x68, 0x61,
+	0x6e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0x14, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x42,
 	0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a,
 	0x13, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18,
@@ -3314,196 +4751,285 @@ var file_rpc_rpc_proto_rawDesc = []byte{
 	0x49, 0x4e, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x54, 0x49, 0x42, 0x4c, 0x45, 0x5f, 0x42, 0x55, 0x43,
 	0x4b, 0x45, 0x54, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x22, 0x18, 0x0a,
 	0x16, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x42, 0x0a, 0x0a, 0x44, 0x61, 0x69, 0x6c, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x6e, 0x0a, 0x0a, 0x44, 0x61, 0x69, 0x6c, 0x79,
 	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x44, 0x61, 0x79, 0x59, 0x6d, 0x64, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x44, 0x61, 0x79, 0x59, 0x6d, 0x64, 0x12, 0x1c, 0x0a,
 	0x09, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x09, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xcd, 0x01, 0x0a, 0x17,
-	0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75,
-	0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64,
-	0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73,
-	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12,
-	0x37, 0x0a, 0x0e, 0x50, 0x65, 0x61, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
-	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61,
-	0x69, 0x6c, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0e, 0x50, 0x65, 0x61, 0x6b, 0x53, 0x70,
-	0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x13, 0x54, 0x6f, 0x74, 0x61,
-	0x6c, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x69, 0x6c,
-	0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x13, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x61, 0x6e,
-	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x55, 0x73, 0x61, 0x67, 0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x47,
-	0x65, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55,
-	0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x05, 0x43,
-	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x79, 0x74, 0x65,
-	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x42, 0x79, 0x74,
-	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xd1, 0x01, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b,
-	0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61,
-	0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52,
-	0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x46,
-	0x69, 0x6c, 0x65, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x12, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x79, 0x74,
-	0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x75,
-	0x6e, 0x6b, 0x52, 0x06, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x1d, 0x47,
-	0x65, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55,
-	0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06,
-	0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72,
-	0x72, 0x4d, 0x73, 0x67, 0x12, 0x38, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
-	0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20,
-	0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65,
-	0x22, 0x54, 0x0a, 0x10, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x50, 0x61, 0x74, 0x68, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4c, 0x6f, 0x67, 0x50, 0x61, 0x74, 0x68, 0x12, 0x26,
-	0x0a, 0x0e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67,
-	0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0xa9, 0x01, 0x0a, 0x11, 0x4c, 0x6f, 0x67, 0x53, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06,
-	0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72,
-	0x72, 0x4d, 0x73, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73,
-	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73,
-	0x12, 0x1e, 0x0a, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74,
-	0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f,
-	0x6e, 0x65, 0x22, 0x87, 0x01, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b,
-	0x4f, 0x6c, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0b, 0x4f, 0x6c, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x20,
-	0x0a, 0x0b, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
-	0x12, 0x2a, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x46, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x22, 0x50, 0x0a, 0x16,
-	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63,
-	0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53,
-	0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x1b,
-	0x0a, 0x19, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68,
-	0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x74, 0x0a, 0x1a, 0x47,
-	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64,
-	0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44,
-	0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72,
-	0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73,
-	0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73,
-	0x65, 0x32, 0xbd, 0x0c, 0x0a, 0x09, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x74, 0x6c, 0x12,
-	0x30, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x48,
-	0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x36, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x13, 0x2e, 0x72,
-	0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x14, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x06, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x09, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68,
-	0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64,
-	0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x2e, 0x72,
-	0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43,
+	0x52, 0x09, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x50,
+	0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x50, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x79,
+	0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xcd, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63,
+	0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x37, 0x0a, 0x0e, 0x50,
+	0x65, 0x61, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x52, 0x0e, 0x50, 0x65, 0x61, 0x6b, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x13, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x55, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x13, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x55, 0x73, 0x61, 0x67, 0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0xd1, 0x01, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x61, 0x77, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x46, 0x69, 0x6c, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x79, 0x74, 0x65, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x06,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53,
+	0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69,
+	0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d,
+	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67,
+	0x12, 0x38, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x22, 0x54, 0x0a, 0x10,
+	0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x4c, 0x6f, 0x67, 0x50, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x0e, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x22, 0xa9, 0x01, 0x0a, 0x11, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53,
+	0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69,
+	0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d,
+	0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67,
+	0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x4e, 0x65, 0x78, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x4e, 0x65, 0x78, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x20, 0x0a, 0x0b,
+	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e, 0x65, 0x22, 0xe5,
+	0x01, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x6c, 0x64, 0x50,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f,
+	0x6c, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x4e, 0x65,
+	0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x4e, 0x65, 0x77, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4b, 0x64, 0x66, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x4b, 0x64, 0x66, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x4b, 0x64, 0x66, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4b,
+	0x69, 0x42, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x4b, 0x64, 0x66, 0x4d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x4b, 0x69, 0x42, 0x12, 0x1e, 0x0a, 0x0a, 0x4b, 0x64, 0x66, 0x54, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x4b, 0x64, 0x66, 0x54,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x22, 0x50, 0x0a, 0x16, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x22, 0x12, 0x0a, 0x10, 0x52, 0x6f, 0x74, 0x61,
+	0x74, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x87, 0x01, 0x0a,
+	0x11, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65,
+	0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x44, 0x6f, 0x6e,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x44, 0x6f, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x18, 0x0a, 0x07,
+	0x49, 0x73, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x49,
+	0x73, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65,
+	0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x69, 0x0a,
+	0x0b, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x49, 0x73, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x49, 0x73, 0x52, 0x65, 0x63, 0x6f, 0x76,
+	0x65, 0x72, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x55, 0x6e, 0x69, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x55, 0x6e, 0x69, 0x78, 0x22, 0x7c, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74,
+	0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x2c, 0x0a, 0x08, 0x4b, 0x65, 0x79, 0x53,
+	0x6c, 0x6f, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x4b, 0x65,
+	0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x22, 0x6d, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x4b, 0x65, 0x79,
+	0x53, 0x6c, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x12,
+	0x24, 0x0a, 0x0d, 0x49, 0x73, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x49, 0x73, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x4b, 0x65, 0x79, 0x22, 0x6e, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x53,
+	0x6c, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44,
+	0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45,
+	0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72,
+	0x4d, 0x73, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x4b, 0x65, 0x79, 0x22, 0x2a, 0x0a, 0x14, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4b,
+	0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x4f, 0x0a, 0x15, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4b, 0x65, 0x79, 0x53, 0x6c,
+	0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x44, 0x69,
+	0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x72,
+	0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45, 0x72, 0x72, 0x4d,
+	0x73, 0x67, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61,
+	0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x74, 0x0a, 0x1a, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x70,
+	0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x44, 0x69, 0x64, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x45, 0x72, 0x72, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x45,
+	0x72, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72,
+	0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x61, 0x73, 0x73, 0x70,
+	0x68, 0x72, 0x61, 0x73, 0x65, 0x32, 0xcc, 0x10, 0x0a, 0x09, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e,
+	0x43, 0x74, 0x6c, 0x12, 0x30, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x11, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a,
+	0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61,
+	0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c,
+	0x0a, 0x09, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x12, 0x15, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f, 0x6e, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x6f,
+	0x6e, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x10,
+	0x52, 0x65, 0x61, 0x64, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x13, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x44, 0x61,
+	0x65, 0x6d, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x17, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x43,
 	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x4a, 0x0a, 0x13, 0x57, 0x72, 0x69, 0x74, 0x65, 0x54, 0x6f, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x72, 0x69,
-	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x06, 0x42,
-	0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x63, 0x6b,
-	0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e,
-	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x39, 0x0a, 0x0c, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
-	0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x69, 0x0a, 0x18, 0x52,
-	0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x4d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x24, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65,
-	0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x4d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e,
-	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x1d, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61,
-	0x74, 0x68, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61, 0x74,
-	0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x50,
-	0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x73, 0x12, 0x1b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e,
-	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01,
-	0x12, 0x38, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x13, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x14, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3a, 0x0a, 0x0d, 0x43, 0x61,
-	0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x12, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x09, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c,
-	0x6f, 0x75, 0x64, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c,
-	0x6f, 0x75, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75,
-	0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0a, 0x4d, 0x61,
-	0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d,
-	0x61, 0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x13, 0x43,
-	0x68, 0x65, 0x63, 0x6b, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
-	0x72, 0x64, 0x12, 0x1f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x75,
-	0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42,
-	0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x53, 0x6e,
+	0x33, 0x0a, 0x06, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0c, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x69, 0x0a, 0x18, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x24, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x25, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x11, 0x52, 0x65,
+	0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12,
+	0x1d, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x50, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x2f, 0x0a, 0x04, 0x44, 0x69, 0x66, 0x66, 0x12, 0x10, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0b, 0x54, 0x61, 0x67, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x12, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x67, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x41, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x4a, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x12, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x69, 0x6e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x38, 0x0a,
+	0x07, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3a, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x09, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64,
+	0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x69, 0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x69,
+	0x70, 0x65, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x32, 0x0a, 0x05, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x11, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x42,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0a, 0x4d,
+	0x61, 0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x61, 0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x42, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x13,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x12, 0x1f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x21, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6e,
 	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x21, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
-	0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x6e, 0x61,
-	0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0f, 0x47,
-	0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1b,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73,
-	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
-	0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x09, 0x4c,
-	0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c,
-	0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0e, 0x43,
-	0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1a, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
-	0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x72, 0x70, 0x63, 0x2e,
-	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x12, 0x47, 0x65, 0x6e, 0x65,
-	0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x12, 0x1e,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73,
-	0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73,
-	0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x42, 0x1c, 0x5a, 0x1a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x66, 0x73, 0x63, 0x74, 0x6c, 0x2f, 0x74, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x72, 0x70, 0x63, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12,
+	0x1b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x09,
+	0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0e,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x1a,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x09, 0x52, 0x6f, 0x74,
+	0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x74,
+	0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x45, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
+	0x74, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65,
+	0x79, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x3f, 0x0a, 0x0a, 0x41, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x12, 0x16,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64,
+	0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x48, 0x0a, 0x0d, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4b, 0x65, 0x79, 0x53, 0x6c,
+	0x6f, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4b,
+	0x65, 0x79, 0x53, 0x6c, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4b, 0x65, 0x79, 0x53, 0x6c, 0x6f,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x12, 0x47,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73,
+	0x65, 0x12, 0x1e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x42, 0x1c, 0x5a, 0x1a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x66, 0x73, 0x63, 0x74, 0x6c, 0x2f, 0x74, 0x6c, 0x65, 0x73, 0x73, 0x2f, 0x72,
+	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -3518,121 +5044,163 @@ func file_rpc_rpc_proto_rawDescGZIP() []byte {
 	return file_rpc_rpc_proto_rawDescData
 }
 
-var file_rpc_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
+var file_rpc_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 65)
 var file_rpc_rpc_proto_goTypes = []interface{}{
 	(ReportedEvent_ReportedEventKind)(0),                       // 0: rpc.ReportedEvent.ReportedEventKind
 	(DaemonStatusResponse_State)(0),                            // 1: rpc.DaemonStatusResponse.State
 	(CheckConnResponse_CheckConnResult)(0),                     // 2: rpc.CheckConnResponse.CheckConnResult
-	(CheckBucketPasswordResponse_CheckBucketPasswordResult)(0), // 3: rpc.CheckBucketPasswordResponse.CheckBucketPasswordResult
-	(*HelloRequest)(nil),                                       // 4: rpc.HelloRequest
-	(*HelloResponse)(nil),                                      // 5: rpc.HelloResponse
-	(*VersionRequest)(nil),                                     // 6: rpc.VersionRequest
-	(*VersionResponse)(nil),                                    // 7: rpc.VersionResponse
-	(*DaemonStatusRequest)(nil),                                // 8: rpc.DaemonStatusRequest
-	(*ReportedEvent)(nil),                                      // 9: rpc.ReportedEvent
-	(*DaemonStatusResponse)(nil),                               // 10: rpc.DaemonStatusResponse
-	(*CheckConnRequest)(nil),                                   // 11: rpc.CheckConnRequest
-	(*CheckConnResponse)(nil),                                  // 12: rpc.CheckConnResponse
-	(*ReadConfigRequest)(nil),                                  // 13: rpc.ReadConfigRequest
-	(*ReadConfigResponse)(nil),                                 // 14: rpc.ReadConfigResponse
-	(*WriteConfigRequest)(nil),                                 // 15: rpc.WriteConfigRequest
-	(*WriteConfigResponse)(nil),                                // 16: rpc.WriteConfigResponse
-	(*BackupRequest)(nil),                                      // 17: rpc.BackupRequest
-	(*BackupResponse)(nil),                                     // 18: rpc.BackupResponse
-	(*CancelRequest)(nil),                                      // 19: rpc.CancelRequest
-	(*CancelResponse)(nil),                                     // 20: rpc.CancelResponse
-	(*ReadAllSnapshotsMetadataRequest)(nil),                    // 21: rpc.ReadAllSnapshotsMetadataRequest
-	(*SnapshotMetadata)(nil),                                   // 22: rpc.SnapshotMetadata
-	(*ReadAllSnapshotsMetadataResponse)(nil),                   // 23: rpc.ReadAllSnapshotsMetadataResponse
-	(*ReadSnapshotPathsRequest)(nil),                           // 24: rpc.ReadSnapshotPathsRequest
-	(*ReadSnapshotPathsResponse)(nil),                          // 25: rpc.ReadSnapshotPathsResponse
-	(*DeleteSnapshotsRequest)(nil),                             // 26: rpc.DeleteSnapshotsRequest
-	(*DeleteSnapshotsResponse)(nil),                            // 27: rpc.DeleteSnapshotsResponse
-	(*RestoreRequest)(nil),                                     // 28: rpc.RestoreRequest
-	(*RestoreResponse)(nil),                                    // 29: rpc.RestoreResponse
-	(*WipeCloudRequest)(nil),                                   // 30: rpc.WipeCloudRequest
-	(*WipeCloudResponse)(nil),                                  // 31: rpc.WipeCloudResponse
-	(*ListBucketsRequest)(nil),                                 // 32: rpc.ListBucketsRequest
-	(*ListBucketsResponse)(nil),                                // 33: rpc.ListBucketsResponse
-	(*MakeBucketRequest)(nil),                                  // 34: rpc.MakeBucketRequest
-	(*MakeBucketResponse)(nil),                                 // 35: rpc.MakeBucketResponse
-	(*CheckBucketPasswordRequest)(nil),                         // 36: rpc.CheckBucketPasswordRequest
-	(*CheckBucketPasswordResponse)(nil),                        // 37: rpc.CheckBucketPasswordResponse
-	(*GetUsageHistoryRequest)(nil),                             // 38: rpc.GetUsageHistoryRequest
-	(*DailyUsage)(nil),                                         // 39: rpc.DailyUsage
-	(*GetUsageHistoryResponse)(nil),                            // 40: rpc.GetUsageHistoryResponse
-	(*GetSnapshotSpaceUsageRequest)(nil),                       // 41: rpc.GetSnapshotSpaceUsageRequest
-	(*Chunk)(nil),                                              // 42: rpc.Chunk
-	(*SnapshotUsage)(nil),                                      // 43: rpc.SnapshotUsage
-	(*GetSnapshotSpaceUsageResponse)(nil),                      // 44: rpc.GetSnapshotSpaceUsageResponse
-	(*LogStreamRequest)(nil),                                   // 45: rpc.LogStreamRequest
-	(*LogStreamResponse)(nil),                                  // 46: rpc.LogStreamResponse
-	(*ChangePasswordRequest)(nil),                              // 47: rpc.ChangePasswordRequest
-	(*ChangePasswordResponse)(nil),                             // 48: rpc.ChangePasswordResponse
-	(*GeneratePassphraseRequest)(nil),                          // 49: rpc.GeneratePassphraseRequest
-	(*GeneratePassphraseResponse)(nil),                         // 50: rpc.GeneratePassphraseResponse
+	(DiffEntry_DiffKind)(0),                                    // 3: rpc.DiffEntry.DiffKind
+	(CheckProblem_CheckProblemKind)(0),                         // 4: rpc.CheckProblem.CheckProblemKind
+	(CheckBucketPasswordResponse_CheckBucketPasswordResult)(0), // 5: rpc.CheckBucketPasswordResponse.CheckBucketPasswordResult
+	(*HelloRequest)(nil),                                       // 6: rpc.HelloRequest
+	(*HelloResponse)(nil),                                      // 7: rpc.HelloResponse
+	(*VersionRequest)(nil),                                     // 8: rpc.VersionRequest
+	(*VersionResponse)(nil),                                    // 9: rpc.VersionResponse
+	(*DaemonStatusRequest)(nil),                                // 10: rpc.DaemonStatusRequest
+	(*ReportedEvent)(nil),                                      // 11: rpc.ReportedEvent
+	(*DaemonStatusResponse)(nil),                               // 12: rpc.DaemonStatusResponse
+	(*CheckConnRequest)(nil),                                   // 13: rpc.CheckConnRequest
+	(*CheckConnResponse)(nil),                                  // 14: rpc.CheckConnResponse
+	(*ReadConfigRequest)(nil),                                  // 15: rpc.ReadConfigRequest
+	(*ReadConfigResponse)(nil),                                 // 16: rpc.ReadConfigResponse
+	(*WriteConfigRequest)(nil),                                 // 17: rpc.WriteConfigRequest
+	(*WriteConfigResponse)(nil),                                // 18: rpc.WriteConfigResponse
+	(*BackupRequest)(nil),                                      // 19: rpc.BackupRequest
+	(*BackupResponse)(nil),                                     // 20: rpc.BackupResponse
+	(*CancelRequest)(nil),                                      // 21: rpc.CancelRequest
+	(*CancelResponse)(nil),                                     // 22: rpc.CancelResponse
+	(*ReadAllSnapshotsMetadataRequest)(nil),                    // 23: rpc.ReadAllSnapshotsMetadataRequest
+	(*SnapshotMetadata)(nil),                                   // 24: rpc.SnapshotMetadata
+	(*ReadAllSnapshotsMetadataResponse)(nil),                   // 25: rpc.ReadAllSnapshotsMetadataResponse
+	(*ReadSnapshotPathsRequest)(nil),                           // 26: rpc.ReadSnapshotPathsRequest
+	(*ReadSnapshotPathsResponse)(nil),                          // 27: rpc.ReadSnapshotPathsResponse
+	(*DiffRequest)(nil),                                        // 28: rpc.DiffRequest
+	(*DiffEntry)(nil),                                          // 29: rpc.DiffEntry
+	(*DiffResponse)(nil),                                       // 30: rpc.DiffResponse
+	(*DeleteSnapshotsRequest)(nil),                             // 31: rpc.DeleteSnapshotsRequest
+	(*DeleteSnapshotsResponse)(nil),                            // 32: rpc.DeleteSnapshotsResponse
+	(*TagSnapshotRequest)(nil),                                 // 33: rpc.TagSnapshotRequest
+	(*PinSnapshotRequest)(nil),                                 // 34: rpc.PinSnapshotRequest
+	(*SnapshotAnnotationsResponse)(nil),                        // 35: rpc.SnapshotAnnotationsResponse
+	(*RestoreRequest)(nil),                                     // 36: rpc.RestoreRequest
+	(*RestoreResponse)(nil),                                    // 37: rpc.RestoreResponse
+	(*WipeCloudRequest)(nil),                                   // 38: rpc.WipeCloudRequest
+	(*WipeCloudResponse)(nil),                                  // 39: rpc.WipeCloudResponse
+	(*CheckRequest)(nil),                                       // 40: rpc.CheckRequest
+	(*CheckProblem)(nil),                                       // 41: rpc.CheckProblem
+	(*CheckResponse)(nil),                                      // 42: rpc.CheckResponse
+	(*ListBucketsRequest)(nil),                                 // 43: rpc.ListBucketsRequest
+	(*ListBucketsResponse)(nil),                                // 44: rpc.ListBucketsResponse
+	(*MakeBucketRequest)(nil),                                  // 45: rpc.MakeBucketRequest
+	(*MakeBucketResponse)(nil),                                 // 46: rpc.MakeBucketResponse
+	(*CheckBucketPasswordRequest)(nil),                         // 47: rpc.CheckBucketPasswordRequest
+	(*CheckBucketPasswordResponse)(nil),                        // 48: rpc.CheckBucketPasswordResponse
+	(*GetUsageHistoryRequest)(nil),                             // 49: rpc.GetUsageHistoryRequest
+	(*DailyUsage)(nil),                                         // 50: rpc.DailyUsage
+	(*GetUsageHistoryResponse)(nil),                            // 51: rpc.GetUsageHistoryResponse
+	(*GetSnapshotSpaceUsageRequest)(nil),                       // 52: rpc.GetSnapshotSpaceUsageRequest
+	(*Chunk)(nil),                                              // 53: rpc.Chunk
+	(*SnapshotUsage)(nil),                                      // 54: rpc.SnapshotUsage
+	(*GetSnapshotSpaceUsageResponse)(nil),                      // 55: rpc.GetSnapshotSpaceUsageResponse
+	(*LogStreamRequest)(nil),                                   // 56: rpc.LogStreamRequest
+	(*LogStreamResponse)(nil),                                  // 57: rpc.LogStreamResponse
+	(*ChangePasswordRequest)(nil),                              // 58: rpc.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),                             // 59: rpc.ChangePasswordResponse
+	(*RotateKeyRequest)(nil),                                   // 60: rpc.RotateKeyRequest
+	(*RotateKeyResponse)(nil),                                  // 61: rpc.RotateKeyResponse
+	(*ListKeySlotsRequest)(nil),                                // 62: rpc.ListKeySlotsRequest
+	(*KeySlotInfo)(nil),                                        // 63: rpc.KeySlotInfo
+	(*ListKeySlotsResponse)(nil),                               // 64: rpc.ListKeySlotsResponse
+	(*AddKeySlotRequest)(nil),                                  // 65: rpc.AddKeySlotRequest
+	(*AddKeySlotResponse)(nil),                                 // 66: rpc.AddKeySlotResponse
+	(*RevokeKeySlotRequest)(nil),                               // 67: rpc.RevokeKeySlotRequest
+	(*RevokeKeySlotResponse)(nil),                              // 68: rpc.RevokeKeySlotResponse
+	(*GeneratePassphraseRequest)(nil),                          // 69: rpc.GeneratePassphraseRequest
+	(*GeneratePassphraseResponse)(nil),                         // 70: rpc.GeneratePassphraseResponse
 }
 var file_rpc_rpc_proto_depIdxs = []int32{
 	0,  // 0: rpc.ReportedEvent.Kind:type_name -> rpc.ReportedEvent.ReportedEventKind
 	1,  // 1: rpc.DaemonStatusResponse.status:type_name -> rpc.DaemonStatusResponse.State
-	9,  // 2: rpc.DaemonStatusResponse.reportedEvents:type_name -> rpc.ReportedEvent
+	11, // 2: rpc.DaemonStatusResponse.reportedEvents:type_name -> rpc.ReportedEvent
 	2,  // 3: rpc.CheckConnResponse.result:type_name -> rpc.CheckConnResponse.CheckConnResult
-	22, // 4: rpc.ReadAllSnapshotsMetadataResponse.SnapshotMetadata:type_name -> rpc.SnapshotMetadata
-	3,  // 5: rpc.CheckBucketPasswordResponse.Result:type_name -> rpc.CheckBucketPasswordResponse.CheckBucketPasswordResult
-	39, // 6: rpc.GetUsageHistoryResponse.PeakSpaceUsage:type_name -> rpc.DailyUsage
-	39, // 7: rpc.GetUsageHistoryResponse.TotalBandwidthUsage:type_name -> rpc.DailyUsage
-	42, // 8: rpc.SnapshotUsage.Chunks:type_name -> rpc.Chunk
-	43, // 9: rpc.GetSnapshotSpaceUsageResponse.SnapshotUsage:type_name -> rpc.SnapshotUsage
-	4,  // 10: rpc.DaemonCtl.Hello:input_type -> rpc.HelloRequest
-	6,  // 11: rpc.DaemonCtl.Version:input_type -> rpc.VersionRequest
-	8,  // 12: rpc.DaemonCtl.Status:input_type -> rpc.DaemonStatusRequest
-	11, // 13: rpc.DaemonCtl.CheckConn:input_type -> rpc.CheckConnRequest
-	13, // 14: rpc.DaemonCtl.ReadDaemonConfig:input_type -> rpc.ReadConfigRequest
-	15, // 15: rpc.DaemonCtl.WriteToDaemonConfig:input_type -> rpc.WriteConfigRequest
-	17, // 16: rpc.DaemonCtl.Backup:input_type -> rpc.BackupRequest
-	19, // 17: rpc.DaemonCtl.CancelBackup:input_type -> rpc.CancelRequest
-	21, // 18: rpc.DaemonCtl.ReadAllSnapshotsMetadata:input_type -> rpc.ReadAllSnapshotsMetadataRequest
-	24, // 19: rpc.DaemonCtl.ReadSnapshotPaths:input_type -> rpc.ReadSnapshotPathsRequest
-	26, // 20: rpc.DaemonCtl.DeleteSnapshots:input_type -> rpc.DeleteSnapshotsRequest
-	28, // 21: rpc.DaemonCtl.Restore:input_type -> rpc.RestoreRequest
-	19, // 22: rpc.DaemonCtl.CancelRestore:input_type -> rpc.CancelRequest
-	30, // 23: rpc.DaemonCtl.WipeCloud:input_type -> rpc.WipeCloudRequest
-	32, // 24: rpc.DaemonCtl.ListBuckets:input_type -> rpc.ListBucketsRequest
-	34, // 25: rpc.DaemonCtl.MakeBucket:input_type -> rpc.MakeBucketRequest
-	36, // 26: rpc.DaemonCtl.CheckBucketPassword:input_type -> rpc.CheckBucketPasswordRequest
-	41, // 27: rpc.DaemonCtl.GetSnapshotSpaceUsage:input_type -> rpc.GetSnapshotSpaceUsageRequest
-	38, // 28: rpc.DaemonCtl.GetUsageHistory:input_type -> rpc.GetUsageHistoryRequest
-	45, // 29: rpc.DaemonCtl.LogStream:input_type -> rpc.LogStreamRequest
-	47, // 30: rpc.DaemonCtl.ChangePassword:input_type -> rpc.ChangePasswordRequest
-	49, // 31: rpc.DaemonCtl.GeneratePassphrase:input_type -> rpc.GeneratePassphraseRequest
-	5,  // 32: rpc.DaemonCtl.Hello:output_type -> rpc.HelloResponse
-	7,  // 33: rpc.DaemonCtl.Version:output_type -> rpc.VersionResponse
-	10, // 34: rpc.DaemonCtl.Status:output_type -> rpc.DaemonStatusResponse
-	12, // 35: rpc.DaemonCtl.CheckConn:output_type -> rpc.CheckConnResponse
-	14, // 36: rpc.DaemonCtl.ReadDaemonConfig:output_type -> rpc.ReadConfigResponse
-	16, // 37: rpc.DaemonCtl.WriteToDaemonConfig:output_type -> rpc.WriteConfigResponse
-	18, // 38: rpc.DaemonCtl.Backup:output_type -> rpc.BackupResponse
-	20, // 39: rpc.DaemonCtl.CancelBackup:output_type -> rpc.CancelResponse
-	23, // 40: rpc.DaemonCtl.ReadAllSnapshotsMetadata:output_type -> rpc.ReadAllSnapshotsMetadataResponse
-	25, // 41: rpc.DaemonCtl.ReadSnapshotPaths:output_type -> rpc.ReadSnapshotPathsResponse
-	27, // 42: rpc.DaemonCtl.DeleteSnapshots:output_type -> rpc.DeleteSnapshotsResponse
-	29, // 43: rpc.DaemonCtl.Restore:output_type -> rpc.RestoreResponse
-	20, // 44: rpc.DaemonCtl.CancelRestore:output_type -> rpc.CancelResponse
-	31, // 45: rpc.DaemonCtl.WipeCloud:output_type -> rpc.WipeCloudResponse
-	33, // 46: rpc.DaemonCtl.ListBuckets:output_type -> rpc.ListBucketsResponse
-	35, // 47: rpc.DaemonCtl.MakeBucket:output_type -> rpc.MakeBucketResponse
-	37, // 48: rpc.DaemonCtl.CheckBucketPassword:output_type -> rpc.CheckBucketPasswordResponse
-	44, // 49: rpc.DaemonCtl.GetSnapshotSpaceUsage:output_type -> rpc.GetSnapshotSpaceUsageResponse
-	40, // 50: rpc.DaemonCtl.GetUsageHistory:output_type -> rpc.GetUsageHistoryResponse
-	46, // 51: rpc.DaemonCtl.LogStream:output_type -> rpc.LogStreamResponse
-	48, // 52: rpc.DaemonCtl.ChangePassword:output_type -> rpc.ChangePasswordResponse
-	50, // 53: rpc.DaemonCtl.GeneratePassphrase:output_type -> rpc.GeneratePassphraseResponse
-	32, // [32:54] is the sub-list for method output_type
-	10, // [10:32] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	24, // 4: rpc.ReadAllSnapshotsMetadataResponse.SnapshotMetadata:type_name -> rpc.SnapshotMetadata
+	3,  // 5: rpc.DiffEntry.Kind:type_name -> rpc.DiffEntry.DiffKind
+	29, // 6: rpc.DiffResponse.Entries:type_name -> rpc.DiffEntry
+	4,  // 7: rpc.CheckProblem.Kind:type_name -> rpc.CheckProblem.CheckProblemKind
+	41, // 8: rpc.CheckResponse.Problems:type_name -> rpc.CheckProblem
+	41, // 9: rpc.CheckResponse.OrphanChunks:type_name -> rpc.CheckProblem
+	5,  // 10: rpc.CheckBucketPasswordResponse.Result:type_name -> rpc.CheckBucketPasswordResponse.CheckBucketPasswordResult
+	50, // 11: rpc.GetUsageHistoryResponse.PeakSpaceUsage:type_name -> rpc.DailyUsage
+	50, // 12: rpc.GetUsageHistoryResponse.TotalBandwidthUsage:type_name -> rpc.DailyUsage
+	53, // 13: rpc.SnapshotUsage.Chunks:type_name -> rpc.Chunk
+	54, // 14: rpc.GetSnapshotSpaceUsageResponse.SnapshotUsage:type_name -> rpc.SnapshotUsage
+	63, // 15: rpc.ListKeySlotsResponse.KeySlots:type_name -> rpc.KeySlotInfo
+	6,  // 16: rpc.DaemonCtl.Hello:input_type -> rpc.HelloRequest
+	8,  // 17: rpc.DaemonCtl.Version:input_type -> rpc.VersionRequest
+	10, // 18: rpc.DaemonCtl.Status:input_type -> rpc.DaemonStatusRequest
+	13, // 19: rpc.DaemonCtl.CheckConn:input_type -> rpc.CheckConnRequest
+	15, // 20: rpc.DaemonCtl.ReadDaemonConfig:input_type -> rpc.ReadConfigRequest
+	17, // 21: rpc.DaemonCtl.WriteToDaemonConfig:input_type -> rpc.WriteConfigRequest
+	19, // 22: rpc.DaemonCtl.Backup:input_type -> rpc.BackupRequest
+	21, // 23: rpc.DaemonCtl.CancelBackup:input_type -> rpc.CancelRequest
+	23, // 24: rpc.DaemonCtl.ReadAllSnapshotsMetadata:input_type -> rpc.ReadAllSnapshotsMetadataRequest
+	26, // 25: rpc.DaemonCtl.ReadSnapshotPaths:input_type -> rpc.ReadSnapshotPathsRequest
+	31, // 26: rpc.DaemonCtl.DeleteSnapshots:input_type -> rpc.DeleteSnapshotsRequest
+	28, // 27: rpc.DaemonCtl.Diff:input_type -> rpc.DiffRequest
+	33, // 28: rpc.DaemonCtl.TagSnapshot:input_type -> rpc.TagSnapshotRequest
+	34, // 29: rpc.DaemonCtl.PinSnapshot:input_type -> rpc.PinSnapshotRequest
+	36, // 30: rpc.DaemonCtl.Restore:input_type -> rpc.RestoreRequest
+	21, // 31: rpc.DaemonCtl.CancelRestore:input_type -> rpc.CancelRequest
+	38, // 32: rpc.DaemonCtl.WipeCloud:input_type -> rpc.WipeCloudRequest
+	40, // 33: rpc.DaemonCtl.Check:input_type -> rpc.CheckRequest
+	43, // 34: rpc.DaemonCtl.ListBuckets:input_type -> rpc.ListBucketsRequest
+	45, // 35: rpc.DaemonCtl.MakeBucket:input_type -> rpc.MakeBucketRequest
+	47, // 36: rpc.DaemonCtl.CheckBucketPassword:input_type -> rpc.CheckBucketPasswordRequest
+	52, // 37: rpc.DaemonCtl.GetSnapshotSpaceUsage:input_type -> rpc.GetSnapshotSpaceUsageRequest
+	49, // 38: rpc.DaemonCtl.GetUsageHistory:input_type -> rpc.GetUsageHistoryRequest
+	56, // 39: rpc.DaemonCtl.LogStream:input_type -> rpc.LogStreamRequest
+	58, // 40: rpc.DaemonCtl.ChangePassword:input_type -> rpc.ChangePasswordRequest
+	60, // 41: rpc.DaemonCtl.RotateKey:input_type -> rpc.RotateKeyRequest
+	62, // 42: rpc.DaemonCtl.ListKeySlots:input_type -> rpc.ListKeySlotsRequest
+	65, // 43: rpc.DaemonCtl.AddKeySlot:input_type -> rpc.AddKeySlotRequest
+	67, // 44: rpc.DaemonCtl.RevokeKeySlot:input_type -> rpc.RevokeKeySlotRequest
+	69, // 45: rpc.DaemonCtl.GeneratePassphrase:input_type -> rpc.GeneratePassphraseRequest
+	7,  // 46: rpc.DaemonCtl.Hello:output_type -> rpc.HelloResponse
+	9,  // 47: rpc.DaemonCtl.Version:output_type -> rpc.VersionResponse
+	12, // 48: rpc.DaemonCtl.Status:output_type -> rpc.DaemonStatusResponse
+	14, // 49: rpc.DaemonCtl.CheckConn:output_type -> rpc.CheckConnResponse
+	16, // 50: rpc.DaemonCtl.ReadDaemonConfig:output_type -> rpc.ReadConfigResponse
+	18, // 51: rpc.DaemonCtl.WriteToDaemonConfig:output_type -> rpc.WriteConfigResponse
+	20, // 52: rpc.DaemonCtl.Backup:output_type -> rpc.BackupResponse
+	22, // 53: rpc.DaemonCtl.CancelBackup:output_type -> rpc.CancelResponse
+	25, // 54: rpc.DaemonCtl.ReadAllSnapshotsMetadata:output_type -> rpc.ReadAllSnapshotsMetadataResponse
+	27, // 55: rpc.DaemonCtl.ReadSnapshotPaths:output_type -> rpc.ReadSnapshotPathsResponse
+	32, // 56: rpc.DaemonCtl.DeleteSnapshots:output_type -> rpc.DeleteSnapshotsResponse
+	30, // 57: rpc.DaemonCtl.Diff:output_type -> rpc.DiffResponse
+	35, // 58: rpc.DaemonCtl.TagSnapshot:output_type -> rpc.SnapshotAnnotationsResponse
+	35, // 59: rpc.DaemonCtl.PinSnapshot:output_type -> rpc.SnapshotAnnotationsResponse
+	37, // 60: rpc.DaemonCtl.Restore:output_type -> rpc.RestoreResponse
+	22, // 61: rpc.DaemonCtl.CancelRestore:output_type -> rpc.CancelResponse
+	39, // 62: rpc.DaemonCtl.WipeCloud:output_type -> rpc.WipeCloudResponse
+	42, // 63: rpc.DaemonCtl.Check:output_type -> rpc.CheckResponse
+	44, // 64: rpc.DaemonCtl.ListBuckets:output_type -> rpc.ListBucketsResponse
+	46, // 65: rpc.DaemonCtl.MakeBucket:output_type -> rpc.MakeBucketResponse
+	48, // 66: rpc.DaemonCtl.CheckBucketPassword:output_type -> rpc.CheckBucketPasswordResponse
+	55, // 67: rpc.DaemonCtl.GetSnapshotSpaceUsage:output_type -> rpc.GetSnapshotSpaceUsageResponse
+	51, // 68: rpc.DaemonCtl.GetUsageHistory:output_type -> rpc.GetUsageHistoryResponse
+	57, // 69: rpc.DaemonCtl.LogStream:output_type -> rpc.LogStreamResponse
+	59, // 70: rpc.DaemonCtl.ChangePassword:output_type -> rpc.ChangePasswordResponse
+	61, // 71: rpc.DaemonCtl.RotateKey:output_type -> rpc.RotateKeyResponse
+	64, // 72: rpc.DaemonCtl.ListKeySlots:output_type -> rpc.ListKeySlotsResponse
+	66, // 73: rpc.DaemonCtl.AddKeySlot:output_type -> rpc.AddKeySlotResponse
+	68, // 74: rpc.DaemonCtl.RevokeKeySlot:output_type -> rpc.RevokeKeySlotResponse
+	70, // 75: rpc.DaemonCtl.GeneratePassphrase:output_type -> rpc.GeneratePassphraseResponse
+	46, // [46:76] is the sub-list for method output_type
+	16, // [16:46] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_rpc_rpc_proto_init() }
@@ -3906,7 +5474,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteSnapshotsRequest); i {
+			switch v := v.(*DiffRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3918,7 +5486,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteSnapshotsResponse); i {
+			switch v := v.(*DiffEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3930,7 +5498,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RestoreRequest); i {
+			switch v := v.(*DiffResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3942,7 +5510,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RestoreResponse); i {
+			switch v := v.(*DeleteSnapshotsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3954,7 +5522,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WipeCloudRequest); i {
+			switch v := v.(*DeleteSnapshotsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3966,7 +5534,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WipeCloudResponse); i {
+			switch v := v.(*TagSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3978,7 +5546,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListBucketsRequest); i {
+			switch v := v.(*PinSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3990,7 +5558,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListBucketsResponse); i {
+			switch v := v.(*SnapshotAnnotationsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4002,7 +5570,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MakeBucketRequest); i {
+			switch v := v.(*RestoreRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4014,7 +5582,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MakeBucketResponse); i {
+			switch v := v.(*RestoreResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4026,7 +5594,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckBucketPasswordRequest); i {
+			switch v := v.(*WipeCloudRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4038,7 +5606,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckBucketPasswordResponse); i {
+			switch v := v.(*WipeCloudResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4050,7 +5618,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUsageHistoryRequest); i {
+			switch v := v.(*CheckRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4062,7 +5630,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DailyUsage); i {
+			switch v := v.(*CheckProblem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4074,7 +5642,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUsageHistoryResponse); i {
+			switch v := v.(*CheckResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4086,7 +5654,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetSnapshotSpaceUsageRequest); i {
+			switch v := v.(*ListBucketsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4098,7 +5666,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Chunk); i {
+			switch v := v.(*ListBucketsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4110,7 +5678,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SnapshotUsage); i {
+			switch v := v.(*MakeBucketRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4122,7 +5690,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetSnapshotSpaceUsageResponse); i {
+			switch v := v.(*MakeBucketResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4134,7 +5702,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LogStreamRequest); i {
+			switch v := v.(*CheckBucketPasswordRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4146,7 +5714,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LogStreamResponse); i {
+			switch v := v.(*CheckBucketPasswordResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4158,7 +5726,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChangePasswordRequest); i {
+			switch v := v.(*GetUsageHistoryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4170,7 +5738,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChangePasswordResponse); i {
+			switch v := v.(*DailyUsage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4182,7 +5750,7 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GeneratePassphraseRequest); i {
+			switch v := v.(*GetUsageHistoryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4194,6 +5762,222 @@ func file_rpc_rpc_proto_init() {
 			}
 		}
 		file_rpc_rpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSnapshotSpaceUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSnapshotSpaceUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangePasswordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChangePasswordResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeySlotsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeySlotInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeySlotsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddKeySlotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddKeySlotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeKeySlotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeKeySlotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeneratePassphraseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_rpc_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GeneratePassphraseResponse); i {
 			case 0:
 				return &v.state
@@ -4211,8 +5995,8 @@ func file_rpc_rpc_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_rpc_rpc_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   47,
+			NumEnums:      6,
+			NumMessages:   65,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -41,11 +41,16 @@ type DaemonCtlClient interface {
 	ReadAllSnapshotsMetadata(ctx context.Context, in *ReadAllSnapshotsMetadataRequest, opts ...grpc.CallOption) (*ReadAllSnapshotsMetadataResponse, error)
 	ReadSnapshotPaths(ctx context.Context, in *ReadSnapshotPathsRequest, opts ...grpc.CallOption) (DaemonCtl_ReadSnapshotPathsClient, error)
 	DeleteSnapshots(ctx context.Context, in *DeleteSnapshotsRequest, opts ...grpc.CallOption) (DaemonCtl_DeleteSnapshotsClient, error)
+	Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (DaemonCtl_DiffClient, error)
+	// Commands for tagging and pinning snapshots
+	TagSnapshot(ctx context.Context, in *TagSnapshotRequest, opts ...grpc.CallOption) (*SnapshotAnnotationsResponse, error)
+	PinSnapshot(ctx context.Context, in *PinSnapshotRequest, opts ...grpc.CallOption) (*SnapshotAnnotationsResponse, error)
 	// Restore command
 	Restore(ctx context.Context, opts ...grpc.CallOption) (DaemonCtl_RestoreClient, error)
 	CancelRestore(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	// Special operations
 	WipeCloud(ctx context.Context, in *WipeCloudRequest, opts ...grpc.CallOption) (DaemonCtl_WipeCloudClient, error)
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (DaemonCtl_CheckClient, error)
 	// Bucket operations
 	ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*ListBucketsResponse, error)
 	MakeBucket(ctx context.Context, in *MakeBucketRequest, opts ...grpc.CallOption) (*MakeBucketResponse, error)
@@ -56,6 +61,10 @@ type DaemonCtlClient interface {
 	// Misc RPCs
 	LogStream(ctx context.Context, in *LogStreamRequest, opts ...grpc.CallOption) (DaemonCtl_LogStreamClient, error)
 	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
+	RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (DaemonCtl_RotateKeyClient, error)
+	ListKeySlots(ctx context.Context, in *ListKeySlotsRequest, opts ...grpc.CallOption) (*ListKeySlotsResponse, error)
+	AddKeySlot(ctx context.Context, in *AddKeySlotRequest, opts ...grpc.CallOption) (*AddKeySlotResponse, error)
+	RevokeKeySlot(ctx context.Context, in *RevokeKeySlotRequest, opts ...grpc.CallOption) (*RevokeKeySlotResponse, error)
 	GeneratePassphrase(ctx context.Context, in *GeneratePassphraseRequest, opts ...grpc.CallOption) (*GeneratePassphraseResponse, error)
 }
 
@@ -212,8 +221,58 @@ func (x *daemonCtlDeleteSnapshotsClient) Recv() (*DeleteSnapshotsResponse, error
 	return m, nil
 }
 
+func (c *daemonCtlClient) Diff(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (DaemonCtl_DiffClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[2], "/rpc.DaemonCtl/Diff", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonCtlDiffClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DaemonCtl_DiffClient interface {
+	Recv() (*DiffResponse, error)
+	grpc.ClientStream
+}
+
+type daemonCtlDiffClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonCtlDiffClient) Recv() (*DiffResponse, error) {
+	m := new(DiffResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daemonCtlClient) TagSnapshot(ctx context.Context, in *TagSnapshotRequest, opts ...grpc.CallOption) (*SnapshotAnnotationsResponse, error) {
+	out := new(SnapshotAnnotationsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/TagSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonCtlClient) PinSnapshot(ctx context.Context, in *PinSnapshotRequest, opts ...grpc.CallOption) (*SnapshotAnnotationsResponse, error) {
+	out := new(SnapshotAnnotationsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/PinSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonCtlClient) Restore(ctx context.Context, opts ...grpc.CallOption) (DaemonCtl_RestoreClient, error) {
-	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[2], "/rpc.DaemonCtl/Restore", opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[3], "/rpc.DaemonCtl/Restore", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -256,7 +315,7 @@ func (c *daemonCtlClient) CancelRestore(ctx context.Context, in *CancelRequest,
 }
 
 func (c *daemonCtlClient) WipeCloud(ctx context.Context, in *WipeCloudRequest, opts ...grpc.CallOption) (DaemonCtl_WipeCloudClient, error) {
-	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[3], "/rpc.DaemonCtl/WipeCloud", opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[4], "/rpc.DaemonCtl/WipeCloud", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -287,6 +346,38 @@ func (x *daemonCtlWipeCloudClient) Recv() (*WipeCloudResponse, error) {
 	return m, nil
 }
 
+func (c *daemonCtlClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (DaemonCtl_CheckClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[5], "/rpc.DaemonCtl/Check", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonCtlCheckClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DaemonCtl_CheckClient interface {
+	Recv() (*CheckResponse, error)
+	grpc.ClientStream
+}
+
+type daemonCtlCheckClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonCtlCheckClient) Recv() (*CheckResponse, error) {
+	m := new(CheckResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *daemonCtlClient) ListBuckets(ctx context.Context, in *ListBucketsRequest, opts ...grpc.CallOption) (*ListBucketsResponse, error) {
 	out := new(ListBucketsResponse)
 	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/ListBuckets", in, out, opts...)
@@ -315,7 +406,7 @@ func (c *daemonCtlClient) CheckBucketPassword(ctx context.Context, in *CheckBuck
 }
 
 func (c *daemonCtlClient) GetSnapshotSpaceUsage(ctx context.Context, in *GetSnapshotSpaceUsageRequest, opts ...grpc.CallOption) (DaemonCtl_GetSnapshotSpaceUsageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[4], "/rpc.DaemonCtl/GetSnapshotSpaceUsage", opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[6], "/rpc.DaemonCtl/GetSnapshotSpaceUsage", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -356,7 +447,7 @@ func (c *daemonCtlClient) GetUsageHistory(ctx context.Context, in *GetUsageHisto
 }
 
 func (c *daemonCtlClient) LogStream(ctx context.Context, in *LogStreamRequest, opts ...grpc.CallOption) (DaemonCtl_LogStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[5], "/rpc.DaemonCtl/LogStream", opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[7], "/rpc.DaemonCtl/LogStream", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -396,6 +487,65 @@ func (c *daemonCtlClient) ChangePassword(ctx context.Context, in *ChangePassword
 	return out, nil
 }
 
+func (c *daemonCtlClient) RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (DaemonCtl_RotateKeyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DaemonCtl_ServiceDesc.Streams[8], "/rpc.DaemonCtl/RotateKey", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &daemonCtlRotateKeyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DaemonCtl_RotateKeyClient interface {
+	Recv() (*RotateKeyResponse, error)
+	grpc.ClientStream
+}
+
+type daemonCtlRotateKeyClient struct {
+	grpc.ClientStream
+}
+
+func (x *daemonCtlRotateKeyClient) Recv() (*RotateKeyResponse, error) {
+	m := new(RotateKeyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *daemonCtlClient) ListKeySlots(ctx context.Context, in *ListKeySlotsRequest, opts ...grpc.CallOption) (*ListKeySlotsResponse, error) {
+	out := new(ListKeySlotsResponse)
+	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/ListKeySlots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonCtlClient) AddKeySlot(ctx context.Context, in *AddKeySlotRequest, opts ...grpc.CallOption) (*AddKeySlotResponse, error) {
+	out := new(AddKeySlotResponse)
+	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/AddKeySlot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonCtlClient) RevokeKeySlot(ctx context.Context, in *RevokeKeySlotRequest, opts ...grpc.CallOption) (*RevokeKeySlotResponse, error) {
+	out := new(RevokeKeySlotResponse)
+	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/RevokeKeySlot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonCtlClient) GeneratePassphrase(ctx context.Context, in *GeneratePassphraseRequest, opts ...grpc.CallOption) (*GeneratePassphraseResponse, error) {
 	out := new(GeneratePassphraseResponse)
 	err := c.cc.Invoke(ctx, "/rpc.DaemonCtl/GeneratePassphrase", in, out, opts...)
@@ -428,11 +578,16 @@ type DaemonCtlServer interface {
 	ReadAllSnapshotsMetadata(context.Context, *ReadAllSnapshotsMetadataRequest) (*ReadAllSnapshotsMetadataResponse, error)
 	ReadSnapshotPaths(*ReadSnapshotPathsRequest, DaemonCtl_ReadSnapshotPathsServer) error
 	DeleteSnapshots(*DeleteSnapshotsRequest, DaemonCtl_DeleteSnapshotsServer) error
+	Diff(*DiffRequest, DaemonCtl_DiffServer) error
+	// Commands for tagging and pinning snapshots
+	TagSnapshot(context.Context, *TagSnapshotRequest) (*SnapshotAnnotationsResponse, error)
+	PinSnapshot(context.Context, *PinSnapshotRequest) (*SnapshotAnnotationsResponse, error)
 	// Restore command
 	Restore(DaemonCtl_RestoreServer) error
 	CancelRestore(context.Context, *CancelRequest) (*CancelResponse, error)
 	// Special operations
 	WipeCloud(*WipeCloudRequest, DaemonCtl_WipeCloudServer) error
+	Check(*CheckRequest, DaemonCtl_CheckServer) error
 	// Bucket operations
 	ListBuckets(context.Context, *ListBucketsRequest) (*ListBucketsResponse, error)
 	MakeBucket(context.Context, *MakeBucketRequest) (*MakeBucketResponse, error)
@@ -443,6 +598,10 @@ type DaemonCtlServer interface {
 	// Misc RPCs
 	LogStream(*LogStreamRequest, DaemonCtl_LogStreamServer) error
 	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	RotateKey(*RotateKeyRequest, DaemonCtl_RotateKeyServer) error
+	ListKeySlots(context.Context, *ListKeySlotsRequest) (*ListKeySlotsResponse, error)
+	AddKeySlot(context.Context, *AddKeySlotRequest) (*AddKeySlotResponse, error)
+	RevokeKeySlot(context.Context, *RevokeKeySlotRequest) (*RevokeKeySlotResponse, error)
 	GeneratePassphrase(context.Context, *GeneratePassphraseRequest) (*GeneratePassphraseResponse, error)
 	mustEmbedUnimplementedDaemonCtlServer()
 }
@@ -484,6 +643,15 @@ func (UnimplementedDaemonCtlServer) ReadSnapshotPaths(*ReadSnapshotPathsRequest,
 func (UnimplementedDaemonCtlServer) DeleteSnapshots(*DeleteSnapshotsRequest, DaemonCtl_DeleteSnapshotsServer) error {
 	return status.Errorf(codes.Unimplemented, "method DeleteSnapshots not implemented")
 }
+func (UnimplementedDaemonCtlServer) Diff(*DiffRequest, DaemonCtl_DiffServer) error {
+	return status.Errorf(codes.Unimplemented, "method Diff not implemented")
+}
+func (UnimplementedDaemonCtlServer) TagSnapshot(context.Context, *TagSnapshotRequest) (*SnapshotAnnotationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagSnapshot not implemented")
+}
+func (UnimplementedDaemonCtlServer) PinSnapshot(context.Context, *PinSnapshotRequest) (*SnapshotAnnotationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinSnapshot not implemented")
+}
 func (UnimplementedDaemonCtlServer) Restore(DaemonCtl_RestoreServer) error {
 	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
 }
@@ -493,6 +661,9 @@ func (UnimplementedDaemonCtlServer) CancelRestore(context.Context, *CancelReques
 func (UnimplementedDaemonCtlServer) WipeCloud(*WipeCloudRequest, DaemonCtl_WipeCloudServer) error {
 	return status.Errorf(codes.Unimplemented, "method WipeCloud not implemented")
 }
+func (UnimplementedDaemonCtlServer) Check(*CheckRequest, DaemonCtl_CheckServer) error {
+	return status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
 func (UnimplementedDaemonCtlServer) ListBuckets(context.Context, *ListBucketsRequest) (*ListBucketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListBuckets not implemented")
 }
@@ -514,6 +685,18 @@ func (UnimplementedDaemonCtlServer) LogStream(*LogStreamRequest, DaemonCtl_LogSt
 func (UnimplementedDaemonCtlServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
 }
+func (UnimplementedDaemonCtlServer) RotateKey(*RotateKeyRequest, DaemonCtl_RotateKeyServer) error {
+	return status.Errorf(codes.Unimplemented, "method RotateKey not implemented")
+}
+func (UnimplementedDaemonCtlServer) ListKeySlots(context.Context, *ListKeySlotsRequest) (*ListKeySlotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeySlots not implemented")
+}
+func (UnimplementedDaemonCtlServer) AddKeySlot(context.Context, *AddKeySlotRequest) (*AddKeySlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddKeySlot not implemented")
+}
+func (UnimplementedDaemonCtlServer) RevokeKeySlot(context.Context, *RevokeKeySlotRequest) (*RevokeKeySlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeKeySlot not implemented")
+}
 func (UnimplementedDaemonCtlServer) GeneratePassphrase(context.Context, *GeneratePassphraseRequest) (*GeneratePassphraseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GeneratePassphrase not implemented")
 }
@@ -734,6 +917,63 @@ func (x *daemonCtlDeleteSnapshotsServer) Send(m *DeleteSnapshotsResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _DaemonCtl_Diff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonCtlServer).Diff(m, &daemonCtlDiffServer{stream})
+}
+
+type DaemonCtl_DiffServer interface {
+	Send(*DiffResponse) error
+	grpc.ServerStream
+}
+
+type daemonCtlDiffServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonCtlDiffServer) Send(m *DiffResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DaemonCtl_TagSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonCtlServer).TagSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DaemonCtl/TagSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonCtlServer).TagSnapshot(ctx, req.(*TagSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonCtl_PinSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonCtlServer).PinSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DaemonCtl/PinSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonCtlServer).PinSnapshot(ctx, req.(*PinSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonCtl_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(DaemonCtlServer).Restore(&daemonCtlRestoreServer{stream})
 }
@@ -799,6 +1039,27 @@ func (x *daemonCtlWipeCloudServer) Send(m *WipeCloudResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _DaemonCtl_Check_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CheckRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonCtlServer).Check(m, &daemonCtlCheckServer{stream})
+}
+
+type DaemonCtl_CheckServer interface {
+	Send(*CheckResponse) error
+	grpc.ServerStream
+}
+
+type daemonCtlCheckServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonCtlCheckServer) Send(m *CheckResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _DaemonCtl_ListBuckets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListBucketsRequest)
 	if err := dec(in); err != nil {
@@ -931,6 +1192,81 @@ func _DaemonCtl_ChangePassword_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonCtl_RotateKey_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RotateKeyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonCtlServer).RotateKey(m, &daemonCtlRotateKeyServer{stream})
+}
+
+type DaemonCtl_RotateKeyServer interface {
+	Send(*RotateKeyResponse) error
+	grpc.ServerStream
+}
+
+type daemonCtlRotateKeyServer struct {
+	grpc.ServerStream
+}
+
+func (x *daemonCtlRotateKeyServer) Send(m *RotateKeyResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DaemonCtl_ListKeySlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeySlotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonCtlServer).ListKeySlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DaemonCtl/ListKeySlots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonCtlServer).ListKeySlots(ctx, req.(*ListKeySlotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonCtl_AddKeySlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddKeySlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonCtlServer).AddKeySlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DaemonCtl/AddKeySlot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonCtlServer).AddKeySlot(ctx, req.(*AddKeySlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonCtl_RevokeKeySlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeKeySlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonCtlServer).RevokeKeySlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.DaemonCtl/RevokeKeySlot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonCtlServer).RevokeKeySlot(ctx, req.(*RevokeKeySlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonCtl_GeneratePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GeneratePassphraseRequest)
 	if err := dec(in); err != nil {
@@ -992,6 +1328,14 @@ var DaemonCtl_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReadAllSnapshotsMetadata",
 			Handler:    _DaemonCtl_ReadAllSnapshotsMetadata_Handler,
 		},
+		{
+			MethodName: "TagSnapshot",
+			Handler:    _DaemonCtl_TagSnapshot_Handler,
+		},
+		{
+			MethodName: "PinSnapshot",
+			Handler:    _DaemonCtl_PinSnapshot_Handler,
+		},
 		{
 			MethodName: "CancelRestore",
 			Handler:    _DaemonCtl_CancelRestore_Handler,
@@ -1016,6 +1360,18 @@ var DaemonCtl_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ChangePassword",
 			Handler:    _DaemonCtl_ChangePassword_Handler,
 		},
+		{
+			MethodName: "ListKeySlots",
+			Handler:    _DaemonCtl_ListKeySlots_Handler,
+		},
+		{
+			MethodName: "AddKeySlot",
+			Handler:    _DaemonCtl_AddKeySlot_Handler,
+		},
+		{
+			MethodName: "RevokeKeySlot",
+			Handler:    _DaemonCtl_RevokeKeySlot_Handler,
+		},
 		{
 			MethodName: "GeneratePassphrase",
 			Handler:    _DaemonCtl_GeneratePassphrase_Handler,
@@ -1032,6 +1388,11 @@ var DaemonCtl_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DaemonCtl_DeleteSnapshots_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Diff",
+			Handler:       _DaemonCtl_Diff_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Restore",
 			Handler:       _DaemonCtl_Restore_Handler,
@@ -1042,6 +1403,11 @@ var DaemonCtl_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DaemonCtl_WipeCloud_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Check",
+			Handler:       _DaemonCtl_Check_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "GetSnapshotSpaceUsage",
 			Handler:       _DaemonCtl_GetSnapshotSpaceUsage_Handler,
@@ -1052,6 +1418,11 @@ var DaemonCtl_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DaemonCtl_LogStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RotateKey",
+			Handler:       _DaemonCtl_RotateKey_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc/rpc.proto",
 }